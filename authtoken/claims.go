@@ -0,0 +1,66 @@
+package authtoken
+
+import (
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are data carried by access tokens issued by sso.
+//
+// Registered claims: "jti" is unique id of token, "aud" is id of app
+// token was issued for, "iss" names sso instance.
+type Claims struct {
+	jwt.RegisteredClaims
+
+	UserID        int64    `json:"uid"`
+	Email         string   `json:"email"`
+	EmailVerified bool     `json:"email_verified"`
+	AppID         int64    `json:"app_id"`
+	Roles         []string `json:"roles"`
+	// "iat" in unix milliseconds: "iat" itself has second precision
+	IssuedAtMilli int64 `json:"iat_ms,omitempty"`
+}
+
+// IssueTime returns time token was issued at, with milliseconds if token carries them
+func (c *Claims) IssueTime() time.Time {
+	if c.IssuedAtMilli != 0 {
+		return time.UnixMilli(c.IssuedAtMilli)
+	}
+	if c.IssuedAt == nil {
+		return time.Time{}
+	}
+	return c.IssuedAt.Time
+}
+
+// Audience returns value of "aud" claim for tokens issued for app
+func Audience(appID int64) string {
+	return strconv.FormatInt(appID, 10)
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	UserID        int64
+	Email         string
+	EmailVerified bool
+	AppID         int64
+	Roles         []string
+	TokenID       string
+}
+
+func (p Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+func (c *Claims) Principal() Principal {
+	return Principal{
+		UserID:        c.UserID,
+		Email:         c.Email,
+		EmailVerified: c.EmailVerified,
+		AppID:         c.AppID,
+		Roles:         c.Roles,
+		TokenID:       c.ID,
+	}
+}
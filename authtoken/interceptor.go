@@ -0,0 +1,98 @@
+package authtoken
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// AuthorizationKey is metadata key carrying "Bearer <token>"
+	AuthorizationKey = "authorization"
+	bearerPrefix     = "bearer "
+)
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns caller authenticated by interceptor
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// BearerToken extracts token from incoming "authorization" metadata,
+// returns empty string if there is none
+func BearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, value := range md.Get(AuthorizationKey) {
+		if len(value) > len(bearerPrefix) && strings.EqualFold(value[:len(bearerPrefix)], bearerPrefix) {
+			return strings.TrimSpace(value[len(bearerPrefix):])
+		}
+	}
+
+	return ""
+}
+
+// UnaryServerInterceptor verifies bearer token of request, if there is one,
+// and puts authenticated principal into context.
+// Requests with invalid token are rejected with codes.Unauthenticated,
+// requests without token are passed unauthenticated.
+func UnaryServerInterceptor(v *Verifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		token := BearerToken(ctx)
+		if token == "" {
+			return handler(ctx, req)
+		}
+
+		claims, err := v.Verify(ctx, token)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrTokenExpired):
+				return nil, status.Error(codes.Unauthenticated, "token is expired")
+			case errors.Is(err, ErrTokenInvalid):
+				return nil, status.Error(codes.Unauthenticated, "token is invalid")
+			case errors.Is(err, ErrTokenRevoked):
+				return nil, status.Error(codes.Unauthenticated, "token is revoked")
+			}
+
+			return nil, status.Error(codes.Unavailable, "failed to verify token")
+		}
+
+		return handler(ContextWithPrincipal(ctx, claims.Principal()), req)
+	}
+}
+
+// RequirePrincipal rejects calls of methods with codes.Unauthenticated
+// unless UnaryServerInterceptor authenticated caller.
+// Methods are full gRPC method names, e.g. "/Catalog/CreateListing".
+func RequirePrincipal(methods ...string) grpc.UnaryServerInterceptor {
+	required := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		required[method] = struct{}{}
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := required[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+
+		if _, ok := PrincipalFromContext(ctx); !ok {
+			return nil, status.Error(codes.Unauthenticated, "authorization token is required")
+		}
+
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,93 @@
+package authtoken
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var ErrKeyNotFound = errors.New("signing key not found")
+
+// PublicKey is a key published by sso to verify tokens with
+type PublicKey struct {
+	// Matches "kid" header of tokens
+	ID        string
+	Algorithm string
+	Key       crypto.PublicKey
+}
+
+type KeySource interface {
+	// Key returns key by its id, throws ErrKeyNotFound
+	Key(ctx context.Context, kid string) (PublicKey, error)
+}
+
+type KeyFetcher interface {
+	// SigningKeys returns every published key
+	SigningKeys(ctx context.Context) ([]PublicKey, error)
+}
+
+// CachedKeySet is a KeySource that caches keys fetched from sso.
+//
+// Keys are trusted for ttl and fetched again earlier when token
+// names unknown key, but not more often than once per minRefetch.
+type CachedKeySet struct {
+	fetcher    KeyFetcher
+	ttl        time.Duration
+	minRefetch time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]PublicKey
+	fetchedAt time.Time
+}
+
+const defaultMinRefetch = 10 * time.Second
+
+func NewCachedKeySet(fetcher KeyFetcher, ttl time.Duration) *CachedKeySet {
+	return &CachedKeySet{
+		fetcher:    fetcher,
+		ttl:        ttl,
+		minRefetch: min(defaultMinRefetch, ttl),
+	}
+}
+
+func (s *CachedKeySet) Key(ctx context.Context, kid string) (PublicKey, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := time.Since(s.fetchedAt)
+
+	key, ok := s.keys[kid]
+	if ok && age < s.ttl {
+		return key, nil
+	}
+
+	if !ok && age < s.minRefetch {
+		return key, ErrKeyNotFound
+	}
+
+	keys, err := s.fetcher.SigningKeys(ctx)
+	if err != nil {
+		if ok {
+			// sso is unavailable, stale key is better than nothing
+			return key, nil
+		}
+
+		return key, fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	s.keys = make(map[string]PublicKey, len(keys))
+	for _, k := range keys {
+		s.keys[k.ID] = k
+	}
+	s.fetchedAt = time.Now()
+
+	key, ok = s.keys[kid]
+	if !ok {
+		return key, ErrKeyNotFound
+	}
+
+	return key, nil
+}
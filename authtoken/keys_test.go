@@ -0,0 +1,124 @@
+package authtoken_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeFetcher publishes keys with given ids and counts fetches
+type fakeFetcher struct {
+	mu      sync.Mutex
+	kids    []string
+	err     error
+	fetches int
+}
+
+func (f *fakeFetcher) SigningKeys(context.Context) ([]authtoken.PublicKey, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.fetches++
+	if f.err != nil {
+		return nil, f.err
+	}
+
+	keys := make([]authtoken.PublicKey, 0, len(f.kids))
+	for _, kid := range f.kids {
+		keys = append(keys, authtoken.PublicKey{ID: kid, Algorithm: authtoken.AlgorithmEdDSA})
+	}
+
+	return keys, nil
+}
+
+func (f *fakeFetcher) publish(err error, kids ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.err = err
+	f.kids = kids
+}
+
+func (f *fakeFetcher) fetched() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.fetches
+}
+
+const ttl = 100 * time.Millisecond
+
+func TestCachedKeySet_KnownKeyIsCachedForTTL(t *testing.T) {
+	fetcher := &fakeFetcher{kids: []string{"a"}}
+	keys := authtoken.NewCachedKeySet(fetcher, ttl)
+	ctx := context.Background()
+
+	for range 3 {
+		key, err := keys.Key(ctx, "a")
+		require.NoError(t, err)
+		assert.Equal(t, "a", key.ID)
+	}
+	assert.Equal(t, 1, fetcher.fetched())
+
+	// after ttl keys are fetched again and withdrawn key is forgotten
+	time.Sleep(ttl)
+	fetcher.publish(nil, "b")
+
+	_, err := keys.Key(ctx, "a")
+	require.ErrorIs(t, err, authtoken.ErrKeyNotFound)
+	assert.Equal(t, 2, fetcher.fetched())
+}
+
+func TestCachedKeySet_UnknownKeyRefetchIsRateLimited(t *testing.T) {
+	fetcher := &fakeFetcher{kids: []string{"a"}}
+	keys := authtoken.NewCachedKeySet(fetcher, ttl)
+	ctx := context.Background()
+
+	_, err := keys.Key(ctx, "a")
+	require.NoError(t, err)
+
+	// sso rotated keys, but right after fetch unknown kid doesn't cause another one
+	fetcher.publish(nil, "a", "b")
+
+	for range 3 {
+		_, err = keys.Key(ctx, "b")
+		require.ErrorIs(t, err, authtoken.ErrKeyNotFound)
+	}
+	assert.Equal(t, 1, fetcher.fetched())
+
+	time.Sleep(ttl)
+
+	key, err := keys.Key(ctx, "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", key.ID)
+	assert.Equal(t, 2, fetcher.fetched())
+}
+
+func TestCachedKeySet_StaleKeyWhenFetchFails(t *testing.T) {
+	fetcher := &fakeFetcher{kids: []string{"a"}}
+	keys := authtoken.NewCachedKeySet(fetcher, ttl)
+	ctx := context.Background()
+
+	_, err := keys.Key(ctx, "a")
+	require.NoError(t, err)
+
+	unavailable := errors.New("sso is unavailable")
+	fetcher.publish(unavailable)
+	time.Sleep(ttl)
+
+	// known key outlives ttl while sso is unavailable
+	key, err := keys.Key(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "a", key.ID)
+
+	// unknown key is not found because of failure, not because it doesn't exist
+	_, err = keys.Key(ctx, "b")
+	require.ErrorIs(t, err, unavailable)
+	assert.NotErrorIs(t, err, authtoken.ErrKeyNotFound)
+}
@@ -0,0 +1,162 @@
+package authtoken
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrTokenExpired = errors.New("token is expired")
+	ErrTokenInvalid = errors.New("token is invalid")
+	ErrTokenRevoked = errors.New("token is revoked")
+)
+
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+)
+
+const defaultLeeway = 30 * time.Second
+
+// RevocationChecker tells whether sso revoked valid token before its expiration:
+// by logout, revocation of all sessions of user or disabling user
+type RevocationChecker interface {
+	IsTokenRevoked(ctx context.Context, token string, claims *Claims) (bool, error)
+}
+
+// Verifier checks signature of tokens and validates their
+// "exp", "nbf", "iat", "iss" and "aud" claims
+type Verifier struct {
+	keys        KeySource
+	issuer      string
+	audiences   []string
+	leeway      time.Duration
+	revocations RevocationChecker
+}
+
+type Option func(v *Verifier)
+
+// WithIssuer requires "iss" claim to be equal to issuer
+func WithIssuer(issuer string) Option {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience requires "aud" claim to contain any of audiences.
+// Without it audience is not checked.
+func WithAudience(audiences ...string) Option {
+	return func(v *Verifier) {
+		v.audiences = audiences
+	}
+}
+
+// WithLeeway sets allowed clock skew between sso and verifier, default is 30s
+func WithLeeway(leeway time.Duration) Option {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+// WithRevocationCheck makes verifier reject tokens revoked in sso.
+// Without it revoked tokens are accepted until they expire.
+func WithRevocationCheck(revocations RevocationChecker) Option {
+	return func(v *Verifier) {
+		v.revocations = revocations
+	}
+}
+
+func NewVerifier(keys KeySource, opts ...Option) *Verifier {
+	v := &Verifier{
+		keys:   keys,
+		leeway: defaultLeeway,
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Verify throws ErrTokenExpired, ErrTokenInvalid and ErrTokenRevoked,
+// errors of key source other than ErrKeyNotFound and errors of revocation check are passed through
+func (v *Verifier) Verify(ctx context.Context, token string) (*Claims, error) {
+	var (
+		claims Claims
+		keyErr error
+	)
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{AlgorithmEdDSA, AlgorithmRS256}),
+		jwt.WithLeeway(v.leeway),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	}
+	if v.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(v.issuer))
+	}
+
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, ok := t.Header["kid"].(string)
+		if !ok {
+			return nil, ErrTokenInvalid
+		}
+
+		key, err := v.keys.Key(ctx, kid)
+		if err != nil {
+			keyErr = err
+			return nil, err
+		}
+
+		if key.Algorithm != t.Method.Alg() {
+			return nil, ErrTokenInvalid
+		}
+
+		return key.Key, nil
+	}, opts...)
+
+	if keyErr != nil && !errors.Is(keyErr, ErrKeyNotFound) {
+		return nil, keyErr
+	}
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrTokenExpired
+		}
+
+		return nil, fmt.Errorf("%w: %s", ErrTokenInvalid, err.Error())
+	}
+
+	if claims.ID == "" || claims.UserID == 0 || claims.AppID == 0 {
+		return nil, fmt.Errorf("%w: missing required claims", ErrTokenInvalid)
+	}
+
+	if !slices.Contains(claims.Audience, Audience(claims.AppID)) {
+		return nil, fmt.Errorf("%w: audience doesn't match app_id", ErrTokenInvalid)
+	}
+
+	if len(v.audiences) > 0 && !slices.ContainsFunc(v.audiences, func(aud string) bool {
+		return slices.Contains(claims.Audience, aud)
+	}) {
+		return nil, fmt.Errorf("%w: token is not meant for this audience", ErrTokenInvalid)
+	}
+
+	if v.revocations != nil {
+		revoked, err := v.revocations.IsTokenRevoked(ctx, token, &claims)
+		if err != nil {
+			return nil, err
+		}
+
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return &claims, nil
+}
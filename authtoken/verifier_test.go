@@ -0,0 +1,361 @@
+package authtoken_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	issuer = "sso"
+	appID  = 1
+	edKID  = "ed-key"
+	rsaKID = "rsa-key"
+)
+
+// staticKeys is KeySource over fixed set of keys
+type staticKeys map[string]authtoken.PublicKey
+
+func (k staticKeys) Key(_ context.Context, kid string) (authtoken.PublicKey, error) {
+	key, ok := k[kid]
+	if !ok {
+		return key, authtoken.ErrKeyNotFound
+	}
+	return key, nil
+}
+
+type failingKeys struct{ err error }
+
+func (k failingKeys) Key(context.Context, string) (authtoken.PublicKey, error) {
+	return authtoken.PublicKey{}, k.err
+}
+
+type signer struct {
+	edPrivate  ed25519.PrivateKey
+	edPublic   ed25519.PublicKey
+	rsaPrivate *rsa.PrivateKey
+	keys       staticKeys
+}
+
+func newSigner(t *testing.T) *signer {
+	t.Helper()
+
+	edPublic, edPrivate, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	rsaPrivate, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return &signer{
+		edPrivate:  edPrivate,
+		edPublic:   edPublic,
+		rsaPrivate: rsaPrivate,
+		keys: staticKeys{
+			edKID:  {ID: edKID, Algorithm: authtoken.AlgorithmEdDSA, Key: edPublic},
+			rsaKID: {ID: rsaKID, Algorithm: authtoken.AlgorithmRS256, Key: &rsaPrivate.PublicKey},
+		},
+	}
+}
+
+// validClaims returns claims sso would issue for app right now
+func validClaims() authtoken.Claims {
+	now := time.Now()
+
+	return authtoken.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti",
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{authtoken.Audience(appID)},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		UserID: 42,
+		Email:  "user@test.local",
+		AppID:  appID,
+		Roles:  []string{"buyer"},
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims authtoken.Claims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	s := newSigner(t)
+
+	edPublicDER, err := x509.MarshalPKIXPublicKey(s.edPublic)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		token  func() string
+		opts   []authtoken.Option
+		err    error
+		reason string
+	}{
+		{
+			name:  "valid EdDSA",
+			token: func() string { return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, validClaims()) },
+		},
+		{
+			name:  "valid RS256",
+			token: func() string { return sign(t, jwt.SigningMethodRS256, rsaKID, s.rsaPrivate, validClaims()) },
+		},
+		{
+			name: "expired",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			err: authtoken.ErrTokenExpired,
+		},
+		{
+			name: "expired within leeway",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+		},
+		{
+			name: "expired beyond custom leeway",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-10 * time.Second))
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			opts: []authtoken.Option{authtoken.WithLeeway(time.Second)},
+			err:  authtoken.ErrTokenExpired,
+		},
+		{
+			name: "without exp",
+			token: func() string {
+				c := validClaims()
+				c.ExpiresAt = nil
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			err: authtoken.ErrTokenInvalid,
+		},
+		{
+			name: "not valid yet",
+			token: func() string {
+				c := validClaims()
+				c.NotBefore = jwt.NewNumericDate(time.Now().Add(time.Minute))
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			err: authtoken.ErrTokenInvalid,
+		},
+		{
+			name: "not valid yet within leeway",
+			token: func() string {
+				c := validClaims()
+				c.NotBefore = jwt.NewNumericDate(time.Now().Add(10 * time.Second))
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+		},
+		{
+			name: "issued in future",
+			token: func() string {
+				c := validClaims()
+				c.IssuedAt = jwt.NewNumericDate(time.Now().Add(time.Minute))
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			err: authtoken.ErrTokenInvalid,
+		},
+		{
+			name: "issued in future within leeway",
+			token: func() string {
+				c := validClaims()
+				c.IssuedAt = jwt.NewNumericDate(time.Now().Add(10 * time.Second))
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+		},
+		{
+			name: "other issuer",
+			token: func() string {
+				c := validClaims()
+				c.Issuer = "other sso"
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			err: authtoken.ErrTokenInvalid,
+		},
+		{
+			name: "other issuer when issuer is not checked",
+			token: func() string {
+				c := validClaims()
+				c.Issuer = "other sso"
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			opts: []authtoken.Option{authtoken.WithIssuer("")},
+		},
+		{
+			name:  "expected audience",
+			token: func() string { return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, validClaims()) },
+			opts:  []authtoken.Option{authtoken.WithAudience("5", authtoken.Audience(appID))},
+		},
+		{
+			name:   "other audience",
+			token:  func() string { return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, validClaims()) },
+			opts:   []authtoken.Option{authtoken.WithAudience("5")},
+			err:    authtoken.ErrTokenInvalid,
+			reason: "not meant for this audience",
+		},
+		{
+			name: "audience doesn't match app_id",
+			token: func() string {
+				c := validClaims()
+				c.Audience = jwt.ClaimStrings{"5"}
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			err:    authtoken.ErrTokenInvalid,
+			reason: "audience doesn't match app_id",
+		},
+		{
+			name: "without user",
+			token: func() string {
+				c := validClaims()
+				c.UserID = 0
+				return sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, c)
+			},
+			err:    authtoken.ErrTokenInvalid,
+			reason: "missing required claims",
+		},
+		{
+			name:  "unknown kid",
+			token: func() string { return sign(t, jwt.SigningMethodEdDSA, "unknown", s.edPrivate, validClaims()) },
+			err:   authtoken.ErrTokenInvalid,
+		},
+		{
+			name:  "without kid",
+			token: func() string { return sign(t, jwt.SigningMethodEdDSA, "", s.edPrivate, validClaims()) },
+			err:   authtoken.ErrTokenInvalid,
+		},
+		{
+			name: "signed by other key",
+			token: func() string {
+				_, other, err := ed25519.GenerateKey(rand.Reader)
+				require.NoError(t, err)
+				return sign(t, jwt.SigningMethodEdDSA, edKID, other, validClaims())
+			},
+			err: authtoken.ErrTokenInvalid,
+		},
+		{
+			// HS256 with public key as secret would verify if key were used for any algorithm
+			name:  "HS256 signed with public key",
+			token: func() string { return sign(t, jwt.SigningMethodHS256, edKID, []byte(edPublicDER), validClaims()) },
+			err:   authtoken.ErrTokenInvalid,
+		},
+		{
+			name:  "HS256 signed with raw public key",
+			token: func() string { return sign(t, jwt.SigningMethodHS256, edKID, []byte(s.edPublic), validClaims()) },
+			err:   authtoken.ErrTokenInvalid,
+		},
+		{
+			name:  "algorithm other than key's",
+			token: func() string { return sign(t, jwt.SigningMethodRS256, edKID, s.rsaPrivate, validClaims()) },
+			err:   authtoken.ErrTokenInvalid,
+		},
+		{
+			name: "none algorithm",
+			token: func() string {
+				return sign(t, jwt.SigningMethodNone, edKID, jwt.UnsafeAllowNoneSignatureType, validClaims())
+			},
+			err: authtoken.ErrTokenInvalid,
+		},
+		{
+			name:  "garbage",
+			token: func() string { return "not a token" },
+			err:   authtoken.ErrTokenInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]authtoken.Option{authtoken.WithIssuer(issuer)}, tt.opts...)
+			v := authtoken.NewVerifier(s.keys, opts...)
+
+			claims, err := v.Verify(context.Background(), tt.token())
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				assert.Contains(t, err.Error(), tt.reason)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), claims.UserID)
+			assert.Equal(t, int64(appID), claims.AppID)
+			assert.Equal(t, "jti", claims.Principal().TokenID)
+		})
+	}
+}
+
+func TestVerify_KeySourceErrorIsPassedThrough(t *testing.T) {
+	s := newSigner(t)
+	token := sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, validClaims())
+
+	unavailable := errors.New("sso is unavailable")
+	v := authtoken.NewVerifier(failingKeys{err: unavailable})
+
+	_, err := v.Verify(context.Background(), token)
+	require.ErrorIs(t, err, unavailable)
+	assert.NotErrorIs(t, err, authtoken.ErrTokenInvalid)
+}
+
+// revokedIDs is RevocationChecker over set of revoked token ids
+type revokedIDs map[string]bool
+
+func (r revokedIDs) IsTokenRevoked(_ context.Context, _ string, claims *authtoken.Claims) (bool, error) {
+	return r[claims.ID], nil
+}
+
+type failingRevocations struct{ err error }
+
+func (r failingRevocations) IsTokenRevoked(context.Context, string, *authtoken.Claims) (bool, error) {
+	return false, r.err
+}
+
+func TestVerify_Revocation(t *testing.T) {
+	s := newSigner(t)
+	token := sign(t, jwt.SigningMethodEdDSA, edKID, s.edPrivate, validClaims())
+
+	// without check revoked token is accepted until it expires
+	_, err := authtoken.NewVerifier(s.keys).Verify(context.Background(), token)
+	require.NoError(t, err)
+
+	v := authtoken.NewVerifier(s.keys, authtoken.WithRevocationCheck(revokedIDs{"jti": true}))
+	_, err = v.Verify(context.Background(), token)
+	require.ErrorIs(t, err, authtoken.ErrTokenRevoked)
+
+	v = authtoken.NewVerifier(s.keys, authtoken.WithRevocationCheck(revokedIDs{"other": true}))
+	_, err = v.Verify(context.Background(), token)
+	require.NoError(t, err)
+
+	// sso being unavailable doesn't make token look revoked or valid
+	unavailable := errors.New("sso is unavailable")
+	v = authtoken.NewVerifier(s.keys, authtoken.WithRevocationCheck(failingRevocations{err: unavailable}))
+	_, err = v.Verify(context.Background(), token)
+	require.ErrorIs(t, err, unavailable)
+	assert.NotErrorIs(t, err, authtoken.ErrTokenRevoked)
+}
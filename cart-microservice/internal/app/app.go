@@ -0,0 +1,67 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	grpcapp "github.com/Kry0z1/e-commerce/cart-microservice/internal/app/grpc"
+	cataloggrpc "github.com/Kry0z1/e-commerce/cart-microservice/internal/clients/catalog/grpc"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/config"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/service"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/storage/sqlite"
+	"github.com/Kry0z1/e-commerce/ssoclient"
+	sweeperapp "github.com/Kry0z1/e-commerce/sweeper"
+)
+
+type App struct {
+	GRPCServer *grpcapp.App
+	Sweeper    *sweeperapp.App
+}
+
+func New(
+	log *slog.Logger,
+	grpcPort int,
+	storagePath string,
+	ssoCfg config.SSOConfig,
+	catalogCfg config.CatalogConfig,
+	cartsCfg config.CartsConfig,
+) *App {
+	storage, err := sqlite.New(storagePath)
+	if err != nil {
+		panic(err)
+	}
+
+	ssoClient, err := ssoclient.New(log, ssoCfg.Address, ssoCfg.Timeout, ssoCfg.RetriesCount)
+	if err != nil {
+		panic(err)
+	}
+
+	catalogClient, err := cataloggrpc.New(log, catalogCfg.Address, catalogCfg.Timeout, catalogCfg.RetriesCount)
+	if err != nil {
+		panic(err)
+	}
+
+	verifier := authtoken.NewVerifier(
+		authtoken.NewCachedKeySet(ssoClient, ssoCfg.KeysCacheTTL),
+		authtoken.WithIssuer(ssoCfg.Issuer),
+		authtoken.WithAudience(ssoCfg.Audience...),
+		authtoken.WithLeeway(ssoCfg.Leeway),
+		authtoken.WithRevocationCheck(ssoclient.NewCachedRevocationChecker(ssoClient, ssoCfg.RevocationCacheTTL)),
+	)
+
+	srvc := service.New(
+		log,
+		storage, storage, catalogClient,
+		cartsCfg.GuestTTL, cartsCfg.UserTTL, cartsCfg.MaxItems, cartsCfg.MaxQuantity,
+	)
+
+	grpcApp := grpcapp.New(srvc, verifier, log, grpcPort)
+
+	sweeper := sweeperapp.New(log, srvc.SweepCarts, cartsCfg.SweepInterval)
+	go sweeper.Run()
+
+	return &App{
+		GRPCServer: grpcApp,
+		Sweeper:    sweeper,
+	}
+}
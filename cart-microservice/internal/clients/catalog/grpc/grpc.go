@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/clients/catalog"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/models"
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	grpclog "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	grpcretry "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// Client talks to Catalog service
+type Client struct {
+	api prodcatv1.CatalogClient
+	log *slog.Logger
+}
+
+func New(log *slog.Logger, addr string, timeout time.Duration, retriesCount int) (*Client, error) {
+	const op = "clients.catalog.grpc.New"
+
+	retryOpts := []grpcretry.CallOption{
+		grpcretry.WithCodes(codes.Unavailable, codes.Aborted, codes.DeadlineExceeded),
+		grpcretry.WithMax(uint(retriesCount)),
+		grpcretry.WithPerRetryTimeout(timeout),
+	}
+
+	logOpts := []grpclog.Option{
+		grpclog.WithLogOnEvents(grpclog.PayloadReceived, grpclog.PayloadSent),
+	}
+
+	cc, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			grpclog.UnaryClientInterceptor(InterceptorLogger(log), logOpts...),
+			grpcretry.UnaryClientInterceptor(retryOpts...),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Client{
+		api: prodcatv1.NewCatalogClient(cc),
+		log: log,
+	}, nil
+}
+
+// Product fetches listing with its variants
+func (c *Client) Product(ctx context.Context, listingID int64) (models.Product, error) {
+	const op = "clients.catalog.grpc.Product"
+
+	resp, err := c.api.GetListing(ctx, &prodcatv1.GetListingRequest{Id: listingID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return models.Product{}, catalog.ErrListingNotFound
+		}
+		return models.Product{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	product := models.Product{
+		ListingID: listingID,
+		Title:     resp.GetTitle(),
+		Price:     resp.GetPrice(),
+		Closed:    resp.GetClosed(),
+		Variants:  make(map[int64]models.ProductVariant, len(resp.GetVariants())),
+	}
+	for _, variant := range resp.GetVariants() {
+		product.Variants[variant.GetId()] = models.ProductVariant{
+			SKU:   variant.GetSku(),
+			Price: variant.GetPrice(),
+		}
+	}
+
+	return product, nil
+}
+
+// yoinked
+func InterceptorLogger(l *slog.Logger) grpclog.Logger {
+	return grpclog.LoggerFunc(func(ctx context.Context, lvl grpclog.Level, msg string, fields ...any) {
+		l.Log(ctx, slog.Level(lvl), msg, fields...)
+	})
+}
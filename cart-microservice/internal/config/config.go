@@ -0,0 +1,97 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type Config struct {
+	// one of "local", "prod"
+	Env         string        `yaml:"env" env-default:"local"`
+	StoragePath string        `yaml:"storage_path" env-required:"true"`
+	GRPC        GRPCConfig    `yaml:"grpc" env-required:"true"`
+	SSO         SSOConfig     `yaml:"sso" env-required:"true"`
+	Catalog     CatalogConfig `yaml:"catalog" env-required:"true"`
+	Carts       CartsConfig   `yaml:"carts"`
+}
+
+type GRPCConfig struct {
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type SSOConfig struct {
+	Address      string        `yaml:"address" env-required:"true"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	RetriesCount int           `yaml:"retries_count" env-default:"3"`
+	// How long signing keys fetched from sso are trusted without refetch
+	KeysCacheTTL time.Duration `yaml:"keys_cache_ttl" env-default:"5m"`
+	// Expected "iss" claim of tokens
+	Issuer string `yaml:"issuer" env-default:"sso"`
+	// Ids of apps whose tokens are accepted, empty means any app
+	Audience []string `yaml:"audience"`
+	// Allowed clock skew between sso and cart
+	Leeway time.Duration `yaml:"leeway" env-default:"30s"`
+	// How long answer of sso about token revocation is trusted, revoked token
+	// is accepted for at most this long after revocation
+	RevocationCacheTTL time.Duration `yaml:"revocation_cache_ttl" env-default:"5s"`
+}
+
+type CatalogConfig struct {
+	Address      string        `yaml:"address" env-required:"true"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	RetriesCount int           `yaml:"retries_count" env-default:"3"`
+}
+
+type CartsConfig struct {
+	// Guest cart is deleted after this period without modifications
+	GuestTTL time.Duration `yaml:"guest_ttl" env-default:"168h"`
+	// User cart is deleted after this period without modifications
+	UserTTL time.Duration `yaml:"user_ttl" env-default:"720h"`
+	// How often expired carts are deleted
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"10m"`
+	// Maximum number of distinct items in cart
+	MaxItems int `yaml:"max_items" env-default:"100"`
+	// Maximum quantity of single item
+	MaxQuantity int64 `yaml:"max_quantity" env-default:"99"`
+}
+
+func MustLoad() *Config {
+	path := getConfigPath()
+	return MustLoadPath(path)
+}
+
+func MustLoadPath(path string) *Config {
+	if path == "" {
+		panic("empty config path")
+	}
+
+	var cfg Config
+
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		panic("couldn't read config: " + err.Error())
+	}
+
+	return &cfg
+}
+
+// Gets config path in this priority:
+// param > env > default
+//
+// Environment variable is CONFIG_PATH.
+// Default is empty string.
+func getConfigPath() string {
+	var res string
+
+	flag.StringVar(&res, "config", "", "path to config file")
+	flag.Parse()
+
+	if res == "" {
+		res = os.Getenv("CONFIG_PATH")
+	}
+
+	return res
+}
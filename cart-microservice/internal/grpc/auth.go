@@ -0,0 +1,37 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/models"
+	cartv1 "github.com/Kry0z1/e-commerce/protos/gen/go/cart"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthRequiredMethods are methods that can't be called without access token,
+// every other method serves both users and guests
+var AuthRequiredMethods = []string{
+	cartv1.Cart_MergeCart_FullMethodName,
+}
+
+// owner returns owner of cart: authenticated user if token was passed, guest otherwise
+func owner(ctx context.Context, guestID string) models.Owner {
+	principal, ok := authtoken.PrincipalFromContext(ctx)
+	if ok {
+		return models.Owner{UserID: principal.UserID}
+	}
+
+	return models.Owner{GuestID: guestID}
+}
+
+// caller returns id of user authenticated by interceptors
+func caller(ctx context.Context) (int64, error) {
+	principal, ok := authtoken.PrincipalFromContext(ctx)
+	if !ok {
+		return -1, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return principal.UserID, nil
+}
@@ -0,0 +1,175 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	cartv1 "github.com/Kry0z1/e-commerce/protos/gen/go/cart"
+	"google.golang.org/grpc"
+)
+
+type serverAPI struct {
+	cartv1.UnimplementedCartServer
+	srvc service.Service
+}
+
+func Register(gRPCServer *grpc.Server, srvc service.Service) {
+	cartv1.RegisterCartServer(gRPCServer, &serverAPI{srvc: srvc})
+}
+
+func parseServiceError(err error) error {
+	if err != nil {
+		if errors.Is(err, service.ErrCartNotFound) || errors.Is(err, service.ErrItemNotFound) ||
+			errors.Is(err, service.ErrListingNotFound) || errors.Is(err, service.ErrVariantNotFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrListingClosed) || errors.Is(err, service.ErrVariantRequired) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, service.ErrTooManyItems) || errors.Is(err, service.ErrQuantityTooLarge) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return nil
+}
+
+func (s *serverAPI) AddItem(ctx context.Context, req *cartv1.AddItemRequest) (*cartv1.AddItemResponse, error) {
+	if err := validateItem(req.GetListingId(), req.GetVariantId()); err != nil {
+		return nil, err
+	}
+
+	if req.GetQuantity() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+
+	cartOwner, err := s.srvc.AddItem(
+		ctx,
+		owner(ctx, req.GetGuestId()),
+		req.GetListingId(),
+		req.GetVariantId(),
+		req.GetQuantity(),
+	)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &cartv1.AddItemResponse{GuestId: cartOwner.GuestID}, nil
+}
+
+func (s *serverAPI) UpdateItem(ctx context.Context, req *cartv1.UpdateItemRequest) (*cartv1.UpdateItemResponse, error) {
+	if err := validateItem(req.GetListingId(), req.GetVariantId()); err != nil {
+		return nil, err
+	}
+
+	if req.GetQuantity() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity cannot be less than 0")
+	}
+
+	err := s.srvc.UpdateItem(
+		ctx,
+		owner(ctx, req.GetGuestId()),
+		req.GetListingId(),
+		req.GetVariantId(),
+		req.GetQuantity(),
+	)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &cartv1.UpdateItemResponse{}, nil
+}
+
+func (s *serverAPI) RemoveItem(ctx context.Context, req *cartv1.RemoveItemRequest) (*cartv1.RemoveItemResponse, error) {
+	if err := validateItem(req.GetListingId(), req.GetVariantId()); err != nil {
+		return nil, err
+	}
+
+	err := s.srvc.RemoveItem(ctx, owner(ctx, req.GetGuestId()), req.GetListingId(), req.GetVariantId())
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &cartv1.RemoveItemResponse{}, nil
+}
+
+func (s *serverAPI) GetCart(ctx context.Context, req *cartv1.GetCartRequest) (*cartv1.GetCartResponse, error) {
+	cart, err := s.srvc.Cart(ctx, owner(ctx, req.GetGuestId()))
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	items := make([]*cartv1.CartItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		items = append(items, cartItemToProto(item))
+	}
+
+	var expiresAt int64
+	if !cart.ExpiresAt.IsZero() {
+		expiresAt = cart.ExpiresAt.Unix()
+	}
+
+	return &cartv1.GetCartResponse{
+		Items:     items,
+		Total:     cart.Total,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *serverAPI) MergeCart(ctx context.Context, req *cartv1.MergeCartRequest) (*cartv1.MergeCartResponse, error) {
+	guestID := req.GetGuestId()
+	if guestID == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing guest_id")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.MergeCart(ctx, callerID, guestID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &cartv1.MergeCartResponse{}, nil
+}
+
+func (s *serverAPI) ClearCart(ctx context.Context, req *cartv1.ClearCartRequest) (*cartv1.ClearCartResponse, error) {
+	if err := s.srvc.ClearCart(ctx, owner(ctx, req.GetGuestId())); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &cartv1.ClearCartResponse{}, nil
+}
+
+func validateItem(listingID int64, variantID int64) error {
+	if listingID <= 0 {
+		return status.Error(codes.InvalidArgument, "missing listing_id")
+	}
+
+	if variantID < 0 {
+		return status.Error(codes.InvalidArgument, "variant_id cannot be less than 0")
+	}
+
+	return nil
+}
+
+func cartItemToProto(item models.PricedItem) *cartv1.CartItem {
+	return &cartv1.CartItem{
+		ListingId: item.ListingID,
+		VariantId: item.VariantID,
+		Title:     item.Title,
+		Sku:       item.SKU,
+		Quantity:  item.Quantity,
+		UnitPrice: item.UnitPrice,
+		LineTotal: item.LineTotal,
+		Available: item.Available,
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Owner identifies cart: either UserID or GuestID is set
+type Owner struct {
+	UserID  int64
+	GuestID string
+}
+
+func (o Owner) IsGuest() bool {
+	return o.UserID == 0
+}
+
+type Cart struct {
+	ID        int64
+	Owner     Owner
+	ExpiresAt time.Time
+	Items     []CartItem
+}
+
+type CartItem struct {
+	ListingID int64
+	// 0 if item is listing itself
+	VariantID int64
+	Quantity  int64
+	AddedAt   time.Time
+}
+
+// PricedItem is cart item with current catalog data
+type PricedItem struct {
+	CartItem
+	Title string
+	// Empty if item is listing itself
+	SKU       string
+	UnitPrice int64
+	LineTotal int64
+	// False if listing or variant is gone or listing is closed
+	Available bool
+}
+
+type PricedCart struct {
+	Items     []PricedItem
+	Total     int64
+	ExpiresAt time.Time
+}
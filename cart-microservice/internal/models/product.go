@@ -0,0 +1,16 @@
+package models
+
+// Product is listing as seen by cart: only what's needed for pricing
+type Product struct {
+	ListingID int64
+	Title     string
+	Price     int64
+	Closed    bool
+	// Variant id -> variant
+	Variants map[int64]ProductVariant
+}
+
+type ProductVariant struct {
+	SKU   string
+	Price int64
+}
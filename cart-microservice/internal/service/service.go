@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/clients/catalog"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+var (
+	ErrCartNotFound     = errors.New("cart not found")
+	ErrItemNotFound     = errors.New("item not found in cart")
+	ErrListingNotFound  = errors.New("listing not found")
+	ErrVariantNotFound  = errors.New("variant not found")
+	ErrVariantRequired  = errors.New("listing has variants, variant must be chosen")
+	ErrListingClosed    = errors.New("listing is closed")
+	ErrTooManyItems     = errors.New("too many items in cart")
+	ErrQuantityTooLarge = errors.New("quantity of item is too large")
+)
+
+type CartSaver interface {
+	SaveCart(ctx context.Context, owner models.Owner, expiresAt time.Time) (int64, error)
+	SetItemQuantity(
+		ctx context.Context,
+		cartID int64,
+		listingID int64,
+		variantID int64,
+		quantity int64,
+		expiresAt time.Time,
+	) error
+	// AddItemQuantity adds quantity to item atomically, keeping cart within maxItems and maxQuantity
+	AddItemQuantity(
+		ctx context.Context,
+		cartID int64,
+		listingID int64,
+		variantID int64,
+		quantity int64,
+		maxItems int,
+		maxQuantity int64,
+		expiresAt time.Time,
+	) error
+	RemoveItem(ctx context.Context, cartID int64, listingID int64, variantID int64, expiresAt time.Time) error
+	ClearCart(ctx context.Context, cartID int64, expiresAt time.Time) error
+	MergeCarts(ctx context.Context, from int64, to int64, maxQuantity int64, expiresAt time.Time) error
+	DeleteExpiredCarts(ctx context.Context, now time.Time) (int64, error)
+}
+
+type CartProvider interface {
+	Cart(ctx context.Context, owner models.Owner) (models.Cart, error)
+}
+
+type ProductProvider interface {
+	Product(ctx context.Context, listingID int64) (models.Product, error)
+}
+
+type Service struct {
+	log          *slog.Logger
+	cartSaver    CartSaver
+	cartProvider CartProvider
+	products     ProductProvider
+
+	guestTTL    time.Duration
+	userTTL     time.Duration
+	maxItems    int
+	maxQuantity int64
+}
+
+func New(
+	log *slog.Logger,
+	cartSaver CartSaver,
+	cartProvider CartProvider,
+	products ProductProvider,
+	guestTTL time.Duration,
+	userTTL time.Duration,
+	maxItems int,
+	maxQuantity int64,
+) *Service {
+	return &Service{
+		log:          log,
+		cartSaver:    cartSaver,
+		cartProvider: cartProvider,
+		products:     products,
+		guestTTL:     guestTTL,
+		userTTL:      userTTL,
+		maxItems:     maxItems,
+		maxQuantity:  maxQuantity,
+	}
+}
+
+// AddItem adds quantity of listing (or its variant) to cart of owner.
+// Cart is created if owner has none: guest without id gets new id, it is returned.
+func (s *Service) AddItem(
+	ctx context.Context,
+	owner models.Owner,
+	listingID int64,
+	variantID int64,
+	quantity int64,
+) (models.Owner, error) {
+	const op = "service.AddItem"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", owner.UserID), slog.Int64("listing_id", listingID))
+
+	log.Info("started adding item")
+
+	if err := s.checkProduct(ctx, log, listingID, variantID); err != nil {
+		return owner, knownError(op, err)
+	}
+
+	cart, err := s.cart(ctx, log, owner, true)
+	if err != nil {
+		return owner, knownError(op, err)
+	}
+
+	err = s.cartSaver.AddItemQuantity(
+		ctx, cart.ID, listingID, variantID, quantity,
+		s.maxItems, s.maxQuantity, s.expiry(cart.Owner),
+	)
+	if err != nil {
+		if errors.Is(err, storage.ErrTooManyItems) {
+			log.Info("too many items")
+			return cart.Owner, ErrTooManyItems
+		}
+		if errors.Is(err, storage.ErrQuantityTooLarge) {
+			log.Info("quantity too large")
+			return cart.Owner, ErrQuantityTooLarge
+		}
+		log.Error("failed to add item quantity", ll.Err(err))
+		return cart.Owner, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("adding succeeded")
+	return cart.Owner, nil
+}
+
+// UpdateItem sets quantity of item already present in cart, 0 quantity removes item
+func (s *Service) UpdateItem(
+	ctx context.Context,
+	owner models.Owner,
+	listingID int64,
+	variantID int64,
+	quantity int64,
+) error {
+	const op = "service.UpdateItem"
+
+	if quantity == 0 {
+		return s.RemoveItem(ctx, owner, listingID, variantID)
+	}
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", owner.UserID), slog.Int64("listing_id", listingID))
+
+	log.Info("started updating item")
+
+	if quantity > s.maxQuantity {
+		log.Info("quantity too large")
+		return ErrQuantityTooLarge
+	}
+
+	cart, err := s.cart(ctx, log, owner, false)
+	if err != nil {
+		return knownError(op, err)
+	}
+
+	found := false
+	for _, item := range cart.Items {
+		if item.ListingID == listingID && item.VariantID == variantID {
+			found = true
+		}
+	}
+
+	if !found {
+		log.Info("item not found")
+		return ErrItemNotFound
+	}
+
+	err = s.cartSaver.SetItemQuantity(ctx, cart.ID, listingID, variantID, quantity, s.expiry(owner))
+	if err != nil {
+		log.Error("failed to set item quantity", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("updating succeeded")
+	return nil
+}
+
+func (s *Service) RemoveItem(ctx context.Context, owner models.Owner, listingID int64, variantID int64) error {
+	const op = "service.RemoveItem"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", owner.UserID), slog.Int64("listing_id", listingID))
+
+	log.Info("started removing item")
+
+	cart, err := s.cart(ctx, log, owner, false)
+	if err != nil {
+		return knownError(op, err)
+	}
+
+	if err := s.cartSaver.RemoveItem(ctx, cart.ID, listingID, variantID, s.expiry(owner)); err != nil {
+		if errors.Is(err, storage.ErrItemNotFound) {
+			log.Info("item not found")
+			return ErrItemNotFound
+		}
+		log.Error("failed to remove item", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("removing succeeded")
+	return nil
+}
+
+// Cart returns cart of owner priced by current catalog prices.
+// User without cart gets empty one.
+func (s *Service) Cart(ctx context.Context, owner models.Owner) (models.PricedCart, error) {
+	const op = "service.Cart"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", owner.UserID))
+
+	log.Info("started getting cart")
+
+	var priced models.PricedCart
+
+	cart, err := s.cartProvider.Cart(ctx, owner)
+	if err != nil {
+		if !errors.Is(err, storage.ErrCartNotFound) {
+			log.Error("failed to get cart", ll.Err(err))
+			return priced, fmt.Errorf("%s: %w", op, err)
+		}
+		if owner.IsGuest() {
+			log.Info("cart not found")
+			return priced, ErrCartNotFound
+		}
+	}
+
+	priced.ExpiresAt = cart.ExpiresAt
+
+	products := make(map[int64]*models.Product)
+	for _, item := range cart.Items {
+		product, ok := products[item.ListingID]
+		if !ok {
+			p, err := s.products.Product(ctx, item.ListingID)
+			if err != nil && !errors.Is(err, catalog.ErrListingNotFound) {
+				log.Error("failed to get product", ll.Err(err))
+				return priced, fmt.Errorf("%s: %w", op, err)
+			}
+			if err == nil {
+				product = &p
+			}
+			products[item.ListingID] = product
+		}
+
+		line := priceItem(item, product)
+		if line.Available {
+			priced.Total += line.LineTotal
+		}
+		priced.Items = append(priced.Items, line)
+	}
+
+	log.Info("getting succeeded")
+	return priced, nil
+}
+
+// MergeCart moves items of guest cart into cart of user and deletes guest cart
+func (s *Service) MergeCart(ctx context.Context, userID int64, guestID string) error {
+	const op = "service.MergeCart"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", userID))
+
+	log.Info("started merging carts")
+
+	guestCart, err := s.cart(ctx, log, models.Owner{GuestID: guestID}, false)
+	if err != nil {
+		return knownError(op, err)
+	}
+
+	userCart, err := s.cart(ctx, log, models.Owner{UserID: userID}, true)
+	if err != nil {
+		return knownError(op, err)
+	}
+
+	err = s.cartSaver.MergeCarts(ctx, guestCart.ID, userCart.ID, s.maxQuantity, s.expiry(userCart.Owner))
+	if err != nil {
+		log.Error("failed to merge carts", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("merging succeeded", slog.Int("items", len(guestCart.Items)))
+	return nil
+}
+
+func (s *Service) ClearCart(ctx context.Context, owner models.Owner) error {
+	const op = "service.ClearCart"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("user_id", owner.UserID))
+
+	log.Info("started clearing cart")
+
+	cart, err := s.cart(ctx, log, owner, false)
+	if err != nil {
+		if errors.Is(err, ErrCartNotFound) && !owner.IsGuest() {
+			log.Info("user has no cart")
+			return nil
+		}
+		return knownError(op, err)
+	}
+
+	if err := s.cartSaver.ClearCart(ctx, cart.ID, s.expiry(owner)); err != nil {
+		log.Error("failed to clear cart", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("clearing succeeded")
+	return nil
+}
+
+// SweepCarts deletes expired carts
+func (s *Service) SweepCarts(ctx context.Context) error {
+	const op = "service.SweepCarts"
+
+	log := s.log.With(slog.String("op", op))
+
+	count, err := s.cartSaver.DeleteExpiredCarts(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to delete expired carts", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if count > 0 {
+		log.Info("deleted expired carts", slog.Int64("count", count))
+	}
+
+	return nil
+}
+
+// cart returns cart of owner, creates it if create is set and owner has none
+func (s *Service) cart(ctx context.Context, log *slog.Logger, owner models.Owner, create bool) (models.Cart, error) {
+	if owner.IsGuest() && owner.GuestID == "" {
+		if !create {
+			log.Info("cart not found")
+			return models.Cart{}, ErrCartNotFound
+		}
+		return s.newCart(ctx, log, models.Owner{GuestID: newGuestID()})
+	}
+
+	cart, err := s.cartProvider.Cart(ctx, owner)
+	if err == nil {
+		return cart, nil
+	}
+
+	if !errors.Is(err, storage.ErrCartNotFound) {
+		log.Error("failed to get cart", ll.Err(err))
+		return cart, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	// guests can't choose their ids: unknown id means expired cart
+	if !create || owner.IsGuest() {
+		log.Info("cart not found")
+		return cart, ErrCartNotFound
+	}
+
+	return s.newCart(ctx, log, owner)
+}
+
+func (s *Service) newCart(ctx context.Context, log *slog.Logger, owner models.Owner) (models.Cart, error) {
+	cart := models.Cart{Owner: owner, ExpiresAt: s.expiry(owner)}
+
+	id, err := s.cartSaver.SaveCart(ctx, owner, cart.ExpiresAt)
+	if err != nil {
+		log.Error("failed to save cart", ll.Err(err))
+		return cart, fmt.Errorf("failed to save cart: %w", err)
+	}
+
+	cart.ID = id
+
+	return cart, nil
+}
+
+// checkProduct checks that listing (or its variant) can be put into cart
+func (s *Service) checkProduct(ctx context.Context, log *slog.Logger, listingID int64, variantID int64) error {
+	product, err := s.products.Product(ctx, listingID)
+	if err != nil {
+		if errors.Is(err, catalog.ErrListingNotFound) {
+			log.Info("listing not found")
+			return ErrListingNotFound
+		}
+		log.Error("failed to get product", ll.Err(err))
+		return fmt.Errorf("failed to get product: %w", err)
+	}
+
+	if product.Closed {
+		log.Info("listing is closed")
+		return ErrListingClosed
+	}
+
+	if variantID == 0 && len(product.Variants) > 0 {
+		log.Info("variant required")
+		return ErrVariantRequired
+	}
+
+	if _, ok := product.Variants[variantID]; variantID != 0 && !ok {
+		log.Info("variant not found")
+		return ErrVariantNotFound
+	}
+
+	return nil
+}
+
+func (s *Service) expiry(owner models.Owner) time.Time {
+	if owner.IsGuest() {
+		return time.Now().Add(s.guestTTL)
+	}
+
+	return time.Now().Add(s.userTTL)
+}
+
+// priceItem prices item by product, nil product -> listing is gone
+func priceItem(item models.CartItem, product *models.Product) models.PricedItem {
+	line := models.PricedItem{CartItem: item}
+
+	if product == nil {
+		return line
+	}
+
+	line.Title = product.Title
+	line.UnitPrice = product.Price
+	line.Available = !product.Closed
+
+	if item.VariantID != 0 {
+		variant, ok := product.Variants[item.VariantID]
+		if !ok {
+			line.UnitPrice = 0
+			line.Available = false
+			return line
+		}
+
+		line.SKU = variant.SKU
+		line.UnitPrice = variant.Price
+	}
+
+	line.LineTotal = line.UnitPrice * line.Quantity
+
+	return line
+}
+
+func newGuestID() string {
+	buf := make([]byte, 16)
+	// crypto/rand.Read never returns error
+	_, _ = rand.Read(buf)
+
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+// knownError passes service errors through and wraps unexpected ones
+func knownError(op string, err error) error {
+	for _, known := range []error{
+		ErrCartNotFound, ErrItemNotFound, ErrListingNotFound, ErrVariantNotFound,
+		ErrVariantRequired, ErrListingClosed,
+	} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,399 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+func New(storagePath string) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	db, err := sql.Open("sqlite3", storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+func (s *Storage) Stop() error {
+	return s.db.Close()
+}
+
+// nullable maps zero values of owner fields to NULL
+func nullable(owner models.Owner) (sql.NullInt64, sql.NullString) {
+	return sql.NullInt64{Int64: owner.UserID, Valid: owner.UserID != 0},
+		sql.NullString{String: owner.GuestID, Valid: owner.GuestID != ""}
+}
+
+// Cart returns unexpired cart of owner with its items
+func (s *Storage) Cart(ctx context.Context, owner models.Owner) (models.Cart, error) {
+	const op = "storage.sqlite.Cart"
+
+	var (
+		cart      models.Cart
+		expiresAt int64
+	)
+
+	userID, guestID := nullable(owner)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, expires_at
+		FROM carts
+		WHERE (user_id = ? OR guest_id = ?) AND expires_at > ?
+	`, userID, guestID, time.Now().Unix()).Scan(&cart.ID, &expiresAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return cart, storage.ErrCartNotFound
+		}
+		return cart, fmt.Errorf("%s: %w", op, err)
+	}
+
+	cart.Owner = owner
+	cart.ExpiresAt = time.Unix(expiresAt, 0)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT listing_id, variant_id, quantity, added_at
+		FROM cart_items
+		WHERE cart_id = ?
+		ORDER BY added_at, listing_id, variant_id
+	`, cart.ID)
+	if err != nil {
+		return cart, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item    models.CartItem
+			addedAt int64
+		)
+
+		if err := rows.Scan(&item.ListingID, &item.VariantID, &item.Quantity, &addedAt); err != nil {
+			return cart, fmt.Errorf("%s: %w", op, err)
+		}
+
+		item.AddedAt = time.Unix(addedAt, 0)
+		cart.Items = append(cart.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return cart, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return cart, nil
+}
+
+// SaveCart creates empty cart of owner. Expired cart of the same owner is replaced.
+func (s *Storage) SaveCart(ctx context.Context, owner models.Owner, expiresAt time.Time) (int64, error) {
+	const op = "storage.sqlite.SaveCart"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	userID, guestID := nullable(owner)
+
+	if err := deleteCarts(ctx, tx, `(user_id = ? OR guest_id = ?) AND expires_at <= ?`, userID, guestID, time.Now().Unix()); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO carts(user_id, guest_id, created_at, expires_at)
+		VALUES (?, ?, ?, ?)
+	`, userID, guestID, time.Now().Unix(), expiresAt.Unix())
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// SetItemQuantity puts item into cart or changes its quantity and prolongs cart
+func (s *Storage) SetItemQuantity(
+	ctx context.Context,
+	cartID int64,
+	listingID int64,
+	variantID int64,
+	quantity int64,
+	expiresAt time.Time,
+) error {
+	const op = "storage.sqlite.SetItemQuantity"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cart_items(cart_id, listing_id, variant_id, quantity, added_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (cart_id, listing_id, variant_id) DO UPDATE SET quantity = excluded.quantity
+	`, cartID, listingID, variantID, quantity, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := prolong(ctx, tx, cartID, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// AddItemQuantity adds quantity to item in cart, or puts item into cart, and prolongs cart.
+// Increment and limit checks are made by a single statement, so concurrent additions
+// can't lose each other or go over limits together.
+//
+// Returns ErrTooManyItems if item is new and cart has maxItems items already
+// and ErrQuantityTooLarge if quantity of item would exceed maxQuantity.
+func (s *Storage) AddItemQuantity(
+	ctx context.Context,
+	cartID int64,
+	listingID int64,
+	variantID int64,
+	quantity int64,
+	maxItems int,
+	maxQuantity int64,
+	expiresAt time.Time,
+) error {
+	const op = "storage.sqlite.AddItemQuantity"
+
+	if quantity > maxQuantity {
+		return storage.ErrQuantityTooLarge
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// maxQuantity - excluded.quantity can't overflow unlike sum of quantities
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO cart_items(cart_id, listing_id, variant_id, quantity, added_at)
+		SELECT ?, ?, ?, ?, ?
+		WHERE (SELECT COUNT(*) FROM cart_items WHERE cart_id = ?) < ?
+			OR EXISTS (SELECT 1 FROM cart_items WHERE cart_id = ? AND listing_id = ? AND variant_id = ?)
+		ON CONFLICT (cart_id, listing_id, variant_id)
+		DO UPDATE SET quantity = cart_items.quantity + excluded.quantity
+		WHERE cart_items.quantity <= ? - excluded.quantity
+	`,
+		cartID, listingID, variantID, quantity, time.Now().Unix(),
+		cartID, maxItems,
+		cartID, listingID, variantID,
+		maxQuantity,
+	)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM cart_items WHERE cart_id = ? AND listing_id = ? AND variant_id = ?)
+		`, cartID, listingID, variantID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if exists {
+			return storage.ErrQuantityTooLarge
+		}
+		return storage.ErrTooManyItems
+	}
+
+	if err := prolong(ctx, tx, cartID, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RemoveItem removes item from cart and prolongs cart
+func (s *Storage) RemoveItem(ctx context.Context, cartID int64, listingID int64, variantID int64, expiresAt time.Time) error {
+	const op = "storage.sqlite.RemoveItem"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		DELETE FROM cart_items
+		WHERE cart_id = ? AND listing_id = ? AND variant_id = ?
+	`, cartID, listingID, variantID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return storage.ErrItemNotFound
+	}
+
+	if err := prolong(ctx, tx, cartID, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ClearCart removes all items from cart and prolongs cart
+func (s *Storage) ClearCart(ctx context.Context, cartID int64, expiresAt time.Time) error {
+	const op = "storage.sqlite.ClearCart"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM cart_items WHERE cart_id = ?`, cartID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := prolong(ctx, tx, cartID, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MergeCarts moves items of cart from into cart to and deletes cart from.
+// Quantities of items present in both carts are summed up to maxQuantity.
+func (s *Storage) MergeCarts(ctx context.Context, from int64, to int64, maxQuantity int64, expiresAt time.Time) error {
+	const op = "storage.sqlite.MergeCarts"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO cart_items(cart_id, listing_id, variant_id, quantity, added_at)
+		SELECT ?, listing_id, variant_id, MIN(quantity, ?), added_at
+		FROM cart_items
+		WHERE cart_id = ?
+		ON CONFLICT (cart_id, listing_id, variant_id)
+		DO UPDATE SET quantity = MIN(quantity + excluded.quantity, ?)
+	`, to, maxQuantity, from, maxQuantity)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := deleteCarts(ctx, tx, `id = ?`, from); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := prolong(ctx, tx, to, expiresAt); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteExpiredCarts deletes carts expired before now with their items and returns their count
+func (s *Storage) DeleteExpiredCarts(ctx context.Context, now time.Time) (int64, error) {
+	const op = "storage.sqlite.DeleteExpiredCarts"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var count int64
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM carts WHERE expires_at <= ?`, now.Unix()).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := deleteCarts(ctx, tx, `expires_at <= ?`, now.Unix()); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// deleteCarts deletes carts matching where condition together with their items
+func deleteCarts(ctx context.Context, tx *sql.Tx, where string, args ...any) error {
+	_, err := tx.ExecContext(ctx, `
+		DELETE FROM cart_items
+		WHERE cart_id IN (SELECT id FROM carts WHERE `+where+`)
+	`, args...)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM carts WHERE `+where, args...)
+
+	return err
+}
+
+func prolong(ctx context.Context, tx *sql.Tx, cartID int64, expiresAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		UPDATE carts SET expires_at = ? WHERE id = ?
+	`, expiresAt.Unix(), cartID)
+
+	return err
+}
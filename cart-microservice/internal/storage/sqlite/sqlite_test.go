@@ -0,0 +1,285 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/cart-microservice/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	user  = 1
+	guest = "guest"
+)
+
+// newStorage returns storage over fresh in-memory database with migrations applied
+func newStorage(t *testing.T) *sqlite.Storage {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_busy_timeout=5000", strings.ReplaceAll(t.Name(), "/", "_"))
+
+	// in-memory database lives while at least one connection is open
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migration, err := os.ReadFile("../../../migrations/1_init.up.sql")
+	require.NoError(t, err)
+
+	_, err = db.Exec(string(migration))
+	require.NoError(t, err)
+
+	st, err := sqlite.New(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Stop() })
+
+	return st
+}
+
+func items(cart models.Cart) map[[2]int64]int64 {
+	quantities := make(map[[2]int64]int64, len(cart.Items))
+	for _, item := range cart.Items {
+		quantities[[2]int64{item.ListingID, item.VariantID}] = item.Quantity
+	}
+	return quantities
+}
+
+func TestCart_Items(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+	later := time.Now().Add(time.Hour)
+
+	owner := models.Owner{UserID: user}
+
+	_, err := st.Cart(ctx, owner)
+	require.ErrorIs(t, err, storage.ErrCartNotFound)
+
+	id, err := st.SaveCart(ctx, owner, later)
+	require.NoError(t, err)
+
+	require.NoError(t, st.SetItemQuantity(ctx, id, 1, 0, 2, later))
+	require.NoError(t, st.SetItemQuantity(ctx, id, 1, 5, 1, later))
+	require.NoError(t, st.SetItemQuantity(ctx, id, 2, 0, 1, later))
+	// same item again sets quantity instead of adding to it
+	require.NoError(t, st.SetItemQuantity(ctx, id, 1, 0, 3, later))
+
+	cart, err := st.Cart(ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, id, cart.ID)
+	assert.Equal(t, map[[2]int64]int64{{1, 0}: 3, {1, 5}: 1, {2, 0}: 1}, items(cart))
+
+	require.NoError(t, st.RemoveItem(ctx, id, 1, 5, later))
+	require.ErrorIs(t, st.RemoveItem(ctx, id, 1, 5, later), storage.ErrItemNotFound)
+
+	cart, err = st.Cart(ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, map[[2]int64]int64{{1, 0}: 3, {2, 0}: 1}, items(cart))
+
+	require.NoError(t, st.ClearCart(ctx, id, later))
+
+	cart, err = st.Cart(ctx, owner)
+	require.NoError(t, err)
+	assert.Empty(t, cart.Items)
+
+	// other owner doesn't see the cart
+	_, err = st.Cart(ctx, models.Owner{GuestID: guest})
+	require.ErrorIs(t, err, storage.ErrCartNotFound)
+}
+
+func TestCart_Expiry(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+	later := time.Now().Add(time.Hour)
+
+	owner := models.Owner{GuestID: guest}
+
+	expired, err := st.SaveCart(ctx, owner, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, st.SetItemQuantity(ctx, expired, 1, 0, 1, time.Now().Add(-time.Minute)))
+
+	_, err = st.Cart(ctx, owner)
+	require.ErrorIs(t, err, storage.ErrCartNotFound)
+
+	// expired cart is replaced by a new empty one
+	id, err := st.SaveCart(ctx, owner, later)
+	require.NoError(t, err)
+
+	cart, err := st.Cart(ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, id, cart.ID)
+	assert.Empty(t, cart.Items)
+
+	// modification prolongs cart
+	soon := time.Now().Add(time.Second)
+	require.NoError(t, st.ClearCart(ctx, id, soon))
+	require.NoError(t, st.SetItemQuantity(ctx, id, 1, 0, 1, later))
+
+	cart, err = st.Cart(ctx, owner)
+	require.NoError(t, err)
+	assert.Equal(t, later.Unix(), cart.ExpiresAt.Unix())
+
+	// unexpired cart of owner is not replaced
+	_, err = st.SaveCart(ctx, owner, later)
+	require.Error(t, err)
+}
+
+func TestDeleteExpiredCarts(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	expired, err := st.SaveCart(ctx, models.Owner{UserID: user}, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, st.SetItemQuantity(ctx, expired, 1, 0, 1, now.Add(-time.Minute)))
+
+	alive, err := st.SaveCart(ctx, models.Owner{GuestID: guest}, now.Add(time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, st.SetItemQuantity(ctx, alive, 1, 0, 1, now.Add(time.Hour)))
+
+	count, err := st.DeleteExpiredCarts(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = st.DeleteExpiredCarts(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	cart, err := st.Cart(ctx, models.Owner{GuestID: guest})
+	require.NoError(t, err)
+	assert.Len(t, cart.Items, 1)
+
+	// items of deleted cart don't show up in a new cart that reuses its id
+	id, err := st.SaveCart(ctx, models.Owner{UserID: user}, now.Add(time.Hour))
+	require.NoError(t, err)
+
+	cart, err = st.Cart(ctx, models.Owner{UserID: user})
+	require.NoError(t, err)
+	assert.Equal(t, id, cart.ID)
+	assert.Empty(t, cart.Items)
+}
+
+func TestMergeCarts(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+	later := time.Now().Add(time.Hour)
+
+	const maxQuantity = 5
+
+	from, err := st.SaveCart(ctx, models.Owner{GuestID: guest}, later)
+	require.NoError(t, err)
+	to, err := st.SaveCart(ctx, models.Owner{UserID: user}, later)
+	require.NoError(t, err)
+
+	require.NoError(t, st.SetItemQuantity(ctx, from, 1, 0, 2, later))
+	require.NoError(t, st.SetItemQuantity(ctx, from, 2, 0, 4, later))
+	require.NoError(t, st.SetItemQuantity(ctx, from, 3, 7, 9, later))
+	require.NoError(t, st.SetItemQuantity(ctx, to, 1, 0, 1, later))
+	require.NoError(t, st.SetItemQuantity(ctx, to, 2, 0, 3, later))
+
+	require.NoError(t, st.MergeCarts(ctx, from, to, maxQuantity, later))
+
+	cart, err := st.Cart(ctx, models.Owner{UserID: user})
+	require.NoError(t, err)
+	// summed up, capped at max and copied items are capped too
+	assert.Equal(t, map[[2]int64]int64{{1, 0}: 3, {2, 0}: maxQuantity, {3, 7}: maxQuantity}, items(cart))
+
+	_, err = st.Cart(ctx, models.Owner{GuestID: guest})
+	require.ErrorIs(t, err, storage.ErrCartNotFound)
+}
+
+func TestAddItemQuantity(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+	later := time.Now().Add(time.Hour)
+
+	const (
+		maxItems    = 2
+		maxQuantity = 10
+	)
+
+	id, err := st.SaveCart(ctx, models.Owner{UserID: user}, later)
+	require.NoError(t, err)
+
+	add := func(listingID int64, quantity int64) error {
+		return st.AddItemQuantity(ctx, id, listingID, 0, quantity, maxItems, maxQuantity, later)
+	}
+
+	require.NoError(t, add(1, 3))
+	require.NoError(t, add(1, 4))
+	require.NoError(t, add(2, 1))
+
+	require.ErrorIs(t, add(3, 1), storage.ErrTooManyItems)
+	require.ErrorIs(t, add(1, 4), storage.ErrQuantityTooLarge)
+	require.ErrorIs(t, add(1, maxQuantity+1), storage.ErrQuantityTooLarge)
+	// sum of quantities would wrap to negative
+	require.ErrorIs(t, add(1, math.MaxInt64), storage.ErrQuantityTooLarge)
+	require.NoError(t, add(1, 3))
+
+	cart, err := st.Cart(ctx, models.Owner{UserID: user})
+	require.NoError(t, err)
+	assert.Equal(t, map[[2]int64]int64{{1, 0}: maxQuantity, {2, 0}: 1}, items(cart))
+}
+
+func TestAddItemQuantity_Concurrent(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+	later := time.Now().Add(time.Hour)
+
+	const (
+		maxItems    = 5
+		maxQuantity = 10
+		adders      = 20
+	)
+
+	id, err := st.SaveCart(ctx, models.Owner{UserID: user}, later)
+	require.NoError(t, err)
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		succeeded = make(map[int64]int)
+	)
+
+	// every adder adds one to the same item and one new item
+	for i := range adders {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for _, listingID := range []int64{1, int64(i) + 2} {
+				err := st.AddItemQuantity(ctx, id, listingID, 0, 1, maxItems, maxQuantity, later)
+				if err == nil {
+					mu.Lock()
+					succeeded[listingID]++
+					mu.Unlock()
+					continue
+				}
+				assert.True(t, errors.Is(err, storage.ErrTooManyItems) || errors.Is(err, storage.ErrQuantityTooLarge), err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cart, err := st.Cart(ctx, models.Owner{UserID: user})
+	require.NoError(t, err)
+
+	quantities := items(cart)
+	assert.Len(t, quantities, maxItems)
+	assert.Equal(t, int64(maxQuantity), quantities[[2]int64{1, 0}])
+	assert.Equal(t, maxQuantity, succeeded[1])
+	for listingID, count := range succeeded {
+		assert.Equal(t, int64(count), quantities[[2]int64{listingID, 0}])
+	}
+}
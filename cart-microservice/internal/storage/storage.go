@@ -0,0 +1,11 @@
+package storage
+
+import "errors"
+
+var (
+	ErrCartNotFound = errors.New("cart not found")
+	ErrItemNotFound = errors.New("item not found in cart")
+
+	ErrTooManyItems     = errors.New("too many items in cart")
+	ErrQuantityTooLarge = errors.New("quantity of item is too large")
+)
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS carts
+(
+    id         INTEGER PRIMARY KEY,
+    -- exactly one of user_id, guest_id is set
+    user_id    INTEGER UNIQUE,
+    guest_id   TEXT UNIQUE,
+    created_at INTEGER NOT NULL,
+    -- bumped on every modification
+    expires_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_carts_expires_at ON carts (expires_at);
+
+CREATE TABLE IF NOT EXISTS cart_items
+(
+    cart_id    INTEGER NOT NULL,
+    listing_id INTEGER NOT NULL,
+    -- 0 if item is listing itself
+    variant_id INTEGER NOT NULL DEFAULT 0,
+    quantity   INTEGER NOT NULL,
+    added_at   INTEGER NOT NULL,
+    PRIMARY KEY (cart_id, listing_id, variant_id)
+);
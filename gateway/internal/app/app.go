@@ -0,0 +1,44 @@
+package app
+
+import (
+	"log/slog"
+
+	httpapp "github.com/Kry0z1/e-commerce/gateway/internal/app/http"
+	"github.com/Kry0z1/e-commerce/gateway/internal/config"
+	httpserver "github.com/Kry0z1/e-commerce/gateway/internal/http"
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type App struct {
+	HTTPServer *httpapp.App
+}
+
+func New(
+	log *slog.Logger,
+	httpCfg config.HTTPConfig,
+	ssoCfg config.BackendConfig,
+	catalogCfg config.BackendConfig,
+) *App {
+	ssoConn, err := grpc.NewClient(ssoCfg.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	catalogConn, err := grpc.NewClient(catalogCfg.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		panic(err)
+	}
+
+	router := httpserver.NewRouter(
+		log,
+		ssov1.NewAuthClient(ssoConn), ssoCfg.Timeout,
+		prodcatv1.NewCatalogClient(catalogConn), catalogCfg.Timeout,
+	)
+
+	return &App{
+		HTTPServer: httpapp.New(log, router, httpCfg),
+	}
+}
@@ -0,0 +1,73 @@
+package httpapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/gateway/internal/config"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+type App struct {
+	log             *slog.Logger
+	server          *http.Server
+	port            int
+	shutdownTimeout time.Duration
+}
+
+func New(log *slog.Logger, handler http.Handler, cfg config.HTTPConfig) *App {
+	return &App{
+		log: log,
+		server: &http.Server{
+			Handler:      handler,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+		},
+		port:            cfg.Port,
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+}
+
+func (a *App) Run() error {
+	const op = "app.http.Run"
+
+	l, err := net.Listen("tcp", fmt.Sprintf(":%d", a.port))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	a.log.Info("http server started", slog.String("addr", l.Addr().String()))
+
+	if err := a.server.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (a *App) MustRun() {
+	if err := a.Run(); err != nil {
+		panic(err)
+	}
+}
+
+// Stop waits for running requests to finish, at most shutdown timeout
+func (a *App) Stop() {
+	const op = "app.http.Stop"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("stopping http server", slog.Int("port", a.port))
+
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	if err := a.server.Shutdown(ctx); err != nil {
+		log.Warn("failed to stop http server gracefully", ll.Err(err))
+	}
+}
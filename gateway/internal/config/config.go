@@ -0,0 +1,68 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type Config struct {
+	// one of "local", "prod"
+	Env     string        `yaml:"env" env-default:"local"`
+	HTTP    HTTPConfig    `yaml:"http" env-required:"true"`
+	SSO     BackendConfig `yaml:"sso" env-required:"true"`
+	Catalog BackendConfig `yaml:"catalog" env-required:"true"`
+}
+
+type HTTPConfig struct {
+	Port         int           `yaml:"port" env-default:"8080"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" env-default:"5s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" env-default:"10s"`
+	// How long running requests are awaited on shutdown
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env-default:"10s"`
+}
+
+type BackendConfig struct {
+	Address string `yaml:"address" env-required:"true"`
+	// Deadline of every call to service
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
+func MustLoad() *Config {
+	path := getConfigPath()
+	return MustLoadPath(path)
+}
+
+func MustLoadPath(path string) *Config {
+	if path == "" {
+		panic("empty config path")
+	}
+
+	var cfg Config
+
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		panic("couldn't read config: " + err.Error())
+	}
+
+	return &cfg
+}
+
+// Gets config path in this priority:
+// param > env > default
+//
+// Environment variable is CONFIG_PATH.
+// Default is empty string.
+func getConfigPath() string {
+	var res string
+
+	flag.StringVar(&res, "config", "", "path to config file")
+	flag.Parse()
+
+	if res == "" {
+		res = os.Getenv("CONFIG_PATH")
+	}
+
+	return res
+}
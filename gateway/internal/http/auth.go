@@ -0,0 +1,369 @@
+package httpserver
+
+import (
+	"net/http"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type credentials struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type loginRequest struct {
+	credentials
+	AppID int64 `json:"app_id"`
+}
+
+type tokenPair struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// loginResponse has either tokens or challenge for users with 2FA
+type loginResponse struct {
+	Token          string `json:"token,omitempty"`
+	RefreshToken   string `json:"refresh_token,omitempty"`
+	TOTPRequired   bool   `json:"totp_required"`
+	ChallengeToken string `json:"challenge_token,omitempty"`
+}
+
+type loginVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+type codeRequest struct {
+	Code string `json:"code"`
+}
+
+type totpEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type recoveryCodes struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type refreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type emailRequest struct {
+	Email string `json:"email"`
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type idResponse struct {
+	ID int64 `json:"id"`
+}
+
+type meResponse struct {
+	UserID int64  `json:"user_id"`
+	AppID  int64  `json:"app_id"`
+	Email  string `json:"email"`
+	// Whether email was verified when token was issued
+	EmailVerified bool     `json:"email_verified"`
+	Roles         []string `json:"roles"`
+	// Unix time token expires at
+	ExpiresAt int64 `json:"expires_at"`
+}
+
+func (rt *router) register(w http.ResponseWriter, r *http.Request) {
+	var req credentials
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	resp, err := rt.auth.RegisterUser(ctx, &ssov1.RegisterUserRequest{Email: req.Email, Password: req.Password})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, idResponse{ID: resp.GetId()})
+}
+
+func (rt *router) login(w http.ResponseWriter, r *http.Request) {
+	var req loginRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	var header metadata.MD
+	resp, err := rt.auth.Login(ctx, &ssov1.LoginRequest{Email: req.Email, Password: req.Password, AppId: req.AppID}, grpc.Header(&header))
+	if err != nil {
+		// too many failed attempts: sso is fine, client has to wait
+		if retryAfter := header.Get("retry-after"); len(retryAfter) > 0 {
+			st, _ := status.FromError(err)
+			w.Header().Set("Retry-After", retryAfter[0])
+			writeError(w, st.Code(), st.Message())
+			return
+		}
+
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, loginResponse{
+		Token:          resp.GetToken(),
+		RefreshToken:   resp.GetRefreshToken(),
+		TOTPRequired:   resp.GetTotpRequired(),
+		ChallengeToken: resp.GetChallengeToken(),
+	})
+}
+
+// loginVerify completes login of user with 2FA
+func (rt *router) loginVerify(w http.ResponseWriter, r *http.Request) {
+	var req loginVerifyRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	resp, err := rt.auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{ChallengeToken: req.ChallengeToken, Code: req.Code})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokenPair{Token: resp.GetToken(), RefreshToken: resp.GetRefreshToken()})
+}
+
+func (rt *router) refresh(w http.ResponseWriter, r *http.Request) {
+	var req refreshRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	resp, err := rt.auth.Refresh(ctx, &ssov1.RefreshRequest{RefreshToken: req.RefreshToken})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, tokenPair{Token: resp.GetToken(), RefreshToken: resp.GetRefreshToken()})
+}
+
+// logout revokes token of caller and, if refresh token is passed in body, its session
+func (rt *router) logout(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		writeError(w, codes.Unauthenticated, "authorization token is required")
+		return
+	}
+
+	var req refreshRequest
+	if r.ContentLength != 0 && !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	_, err := rt.auth.Logout(ctx, &ssov1.LogoutRequest{Token: token, RefreshToken: req.RefreshToken})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rt *router) verifyEmail(w http.ResponseWriter, r *http.Request) {
+	var req verifyEmailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	_, err := rt.auth.VerifyEmail(ctx, &ssov1.VerifyEmailRequest{Token: req.Token})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// resendVerification answers the same way whether user with email exists or not
+func (rt *router) resendVerification(w http.ResponseWriter, r *http.Request) {
+	var req emailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	_, err := rt.auth.ResendVerification(ctx, &ssov1.ResendVerificationRequest{Email: req.Email})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// requestPasswordReset answers the same way whether user with email exists or not
+func (rt *router) requestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	var req emailRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	_, err := rt.auth.RequestPasswordReset(ctx, &ssov1.RequestPasswordResetRequest{Email: req.Email})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (rt *router) resetPassword(w http.ResponseWriter, r *http.Request) {
+	var req resetPasswordRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	_, err := rt.auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{Token: req.Token, NewPassword: req.NewPassword})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// me describes caller by token
+func (rt *router) me(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		writeError(w, codes.Unauthenticated, "authorization token is required")
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	resp, err := rt.auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: token})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	if !resp.GetValid() {
+		writeError(w, codes.Unauthenticated, "token is "+resp.GetReason())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, meResponse{
+		UserID:        resp.GetUserId(),
+		AppID:         resp.GetAppId(),
+		Email:         resp.GetEmail(),
+		EmailVerified: resp.GetEmailVerified(),
+		Roles:         resp.GetRoles(),
+		ExpiresAt:     resp.GetExpiresAt(),
+	})
+}
+
+func (rt *router) enrollTOTP(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		writeError(w, codes.Unauthenticated, "authorization token is required")
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	resp, err := rt.auth.EnrollTOTP(ctx, &ssov1.EnrollTOTPRequest{Token: token})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, totpEnrollment{Secret: resp.GetSecret(), URI: resp.GetUri()})
+}
+
+func (rt *router) confirmTOTP(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		writeError(w, codes.Unauthenticated, "authorization token is required")
+		return
+	}
+
+	var req codeRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	resp, err := rt.auth.ConfirmTOTP(ctx, &ssov1.ConfirmTOTPRequest{Token: token, Code: req.Code})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, recoveryCodes{RecoveryCodes: resp.GetRecoveryCodes()})
+}
+
+func (rt *router) disableTOTP(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		writeError(w, codes.Unauthenticated, "authorization token is required")
+		return
+	}
+
+	var req codeRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.authTimeout)
+	defer cancel()
+
+	_, err := rt.auth.DisableTOTP(ctx, &ssov1.DisableTOTPRequest{Token: token, Code: req.Code})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
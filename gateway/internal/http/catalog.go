@@ -0,0 +1,369 @@
+package httpserver
+
+import (
+	"net/http"
+
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	"google.golang.org/grpc/codes"
+)
+
+var sorts = map[string]prodcatv1.ListingSort{
+	"":           prodcatv1.ListingSort_LISTING_SORT_UNSPECIFIED,
+	"newest":     prodcatv1.ListingSort_LISTING_SORT_NEWEST,
+	"price_asc":  prodcatv1.ListingSort_LISTING_SORT_PRICE_ASC,
+	"price_desc": prodcatv1.ListingSort_LISTING_SORT_PRICE_DESC,
+	"title":      prodcatv1.ListingSort_LISTING_SORT_TITLE,
+}
+
+type listing struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	Category    string `json:"category"`
+	CategoryID  int64  `json:"category_id"`
+	Closed      bool   `json:"closed"`
+	// In cents
+	Price   int64 `json:"price"`
+	Creator int64 `json:"creator"`
+	// Unix time
+	CreatedAt int64 `json:"created_at"`
+}
+
+type variant struct {
+	ID                int64             `json:"id"`
+	SKU               string            `json:"sku"`
+	Options           map[string]string `json:"options"`
+	Price             int64             `json:"price"`
+	Quantity          int64             `json:"quantity"`
+	AvailableQuantity int64             `json:"available_quantity"`
+}
+
+type variantOption struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+type listingDetails struct {
+	ID                int64           `json:"id"`
+	Title             string          `json:"title"`
+	Description       string          `json:"description"`
+	Quantity          int64           `json:"quantity"`
+	AvailableQuantity int64           `json:"available_quantity"`
+	Category          string          `json:"category"`
+	CategoryID        int64           `json:"category_id"`
+	Closed            bool            `json:"closed"`
+	Price             int64           `json:"price"`
+	Creator           int64           `json:"creator"`
+	Variants          []variant       `json:"variants"`
+	Options           []variantOption `json:"options"`
+}
+
+type listingsPage struct {
+	Listings      []listing `json:"listings"`
+	NextPageToken string    `json:"next_page_token"`
+}
+
+type searchHit struct {
+	Listing            listing `json:"listing"`
+	TitleHighlight     string  `json:"title_highlight"`
+	DescriptionSnippet string  `json:"description_snippet"`
+	Score              float64 `json:"score"`
+}
+
+type categoryFacet struct {
+	CategoryID int64  `json:"category_id"`
+	Category   string `json:"category"`
+	Count      int64  `json:"count"`
+}
+
+type searchPage struct {
+	Hits          []searchHit     `json:"hits"`
+	NextPageToken string          `json:"next_page_token"`
+	Total         int64           `json:"total"`
+	Categories    []categoryFacet `json:"categories"`
+}
+
+type category struct {
+	ID       int64  `json:"id"`
+	ParentID int64  `json:"parent_id"`
+	Slug     string `json:"slug"`
+	Name     string `json:"name"`
+	Archived bool   `json:"archived"`
+}
+
+type categoriesResponse struct {
+	Categories []category `json:"categories"`
+}
+
+// listingInput is body of listing creation and replacement
+type listingInput struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	CategoryID  int64  `json:"category_id"`
+	Closed      bool   `json:"closed"`
+	Price       int64  `json:"price"`
+}
+
+func (rt *router) listListings(w http.ResponseWriter, r *http.Request) {
+	q := query{values: r.URL.Query()}
+
+	req := &prodcatv1.ListListingsRequest{
+		CategoryId: q.int64("category_id"),
+		MinPrice:   q.int64("min_price"),
+		MaxPrice:   q.int64("max_price"),
+		Creator:    q.int64("creator"),
+		Closed:     q.bool("closed"),
+		InStock:    q.flag("in_stock"),
+		PageSize:   q.int32("page_size"),
+		PageToken:  q.string("page_token"),
+	}
+	if q.err != nil {
+		writeError(w, codes.InvalidArgument, q.err.Error())
+		return
+	}
+
+	sort, ok := sorts[q.string("sort")]
+	if !ok {
+		writeError(w, codes.InvalidArgument, "sort must be one of newest, price_asc, price_desc, title")
+		return
+	}
+	req.Sort = sort
+
+	ctx, cancel := callContext(r, rt.catalogTimeout)
+	defer cancel()
+
+	resp, err := rt.catalog.ListListings(ctx, req)
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	page := listingsPage{
+		Listings:      make([]listing, 0, len(resp.GetListings())),
+		NextPageToken: resp.GetNextPageToken(),
+	}
+	for _, l := range resp.GetListings() {
+		page.Listings = append(page.Listings, listingFromProto(l))
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (rt *router) searchListings(w http.ResponseWriter, r *http.Request) {
+	q := query{values: r.URL.Query()}
+
+	req := &prodcatv1.SearchListingsRequest{
+		Query:         q.string("q"),
+		CategoryId:    q.int64("category_id"),
+		IncludeClosed: q.flag("include_closed"),
+		PageSize:      q.int32("page_size"),
+		PageToken:     q.string("page_token"),
+	}
+	if q.err != nil {
+		writeError(w, codes.InvalidArgument, q.err.Error())
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.catalogTimeout)
+	defer cancel()
+
+	resp, err := rt.catalog.SearchListings(ctx, req)
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	page := searchPage{
+		Hits:          make([]searchHit, 0, len(resp.GetHits())),
+		NextPageToken: resp.GetNextPageToken(),
+		Total:         resp.GetTotal(),
+		Categories:    make([]categoryFacet, 0, len(resp.GetCategories())),
+	}
+	for _, hit := range resp.GetHits() {
+		page.Hits = append(page.Hits, searchHit{
+			Listing:            listingFromProto(hit.GetListing()),
+			TitleHighlight:     hit.GetTitleHighlight(),
+			DescriptionSnippet: hit.GetDescriptionSnippet(),
+			Score:              hit.GetScore(),
+		})
+	}
+	for _, facet := range resp.GetCategories() {
+		page.Categories = append(page.Categories, categoryFacet{
+			CategoryID: facet.GetCategoryId(),
+			Category:   facet.GetCategory(),
+			Count:      facet.GetCount(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (rt *router) getListing(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.catalogTimeout)
+	defer cancel()
+
+	resp, err := rt.catalog.GetListing(ctx, &prodcatv1.GetListingRequest{Id: id})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	details := listingDetails{
+		ID:                id,
+		Title:             resp.GetTitle(),
+		Description:       resp.GetDescription(),
+		Quantity:          resp.GetQuantity(),
+		AvailableQuantity: resp.GetAvailableQuantity(),
+		Category:          resp.GetCategory(),
+		CategoryID:        resp.GetCategoryId(),
+		Closed:            resp.GetClosed(),
+		Price:             resp.GetPrice(),
+		Creator:           resp.GetCreator(),
+		Variants:          make([]variant, 0, len(resp.GetVariants())),
+		Options:           make([]variantOption, 0, len(resp.GetOptions())),
+	}
+	for _, v := range resp.GetVariants() {
+		details.Variants = append(details.Variants, variant{
+			ID:                v.GetId(),
+			SKU:               v.GetSku(),
+			Options:           v.GetOptions(),
+			Price:             v.GetPrice(),
+			Quantity:          v.GetQuantity(),
+			AvailableQuantity: v.GetAvailableQuantity(),
+		})
+	}
+	for _, o := range resp.GetOptions() {
+		details.Options = append(details.Options, variantOption{Name: o.GetName(), Values: o.GetValues()})
+	}
+
+	writeJSON(w, http.StatusOK, details)
+}
+
+func (rt *router) createListing(w http.ResponseWriter, r *http.Request) {
+	var req listingInput
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.catalogTimeout)
+	defer cancel()
+
+	resp, err := rt.catalog.CreateListing(ctx, &prodcatv1.CreateListingRequest{
+		Title:       req.Title,
+		Description: req.Description,
+		Quantity:    req.Quantity,
+		CategoryId:  req.CategoryID,
+		Closed:      req.Closed,
+		Price:       req.Price,
+	})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, idResponse{ID: resp.GetId()})
+}
+
+func (rt *router) updateListing(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	var req listingInput
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.catalogTimeout)
+	defer cancel()
+
+	_, err := rt.catalog.UpdateListing(ctx, &prodcatv1.UpdateListingRequest{
+		Id:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		Quantity:    req.Quantity,
+		CategoryId:  req.CategoryID,
+		Closed:      req.Closed,
+		Price:       req.Price,
+	})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rt *router) deleteListing(w http.ResponseWriter, r *http.Request) {
+	id, ok := pathID(w, r, "id")
+	if !ok {
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.catalogTimeout)
+	defer cancel()
+
+	if _, err := rt.catalog.DeleteListing(ctx, &prodcatv1.DeleteListingRequest{Id: id}); err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (rt *router) listCategories(w http.ResponseWriter, r *http.Request) {
+	q := query{values: r.URL.Query()}
+
+	includeArchived := q.flag("include_archived")
+	if q.err != nil {
+		writeError(w, codes.InvalidArgument, q.err.Error())
+		return
+	}
+
+	ctx, cancel := callContext(r, rt.catalogTimeout)
+	defer cancel()
+
+	resp, err := rt.catalog.ListCategories(ctx, &prodcatv1.ListCategoriesRequest{
+		IncludeArchived: includeArchived,
+	})
+	if err != nil {
+		rt.writeCallError(w, r, err)
+		return
+	}
+
+	categories := categoriesResponse{Categories: make([]category, 0, len(resp.GetCategories()))}
+	for _, c := range resp.GetCategories() {
+		categories.Categories = append(categories.Categories, category{
+			ID:       c.GetId(),
+			ParentID: c.GetParentId(),
+			Slug:     c.GetSlug(),
+			Name:     c.GetName(),
+			Archived: c.GetArchived(),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, categories)
+}
+
+func listingFromProto(l *prodcatv1.Listing) listing {
+	return listing{
+		ID:          l.GetId(),
+		Title:       l.GetTitle(),
+		Description: l.GetDescription(),
+		Quantity:    l.GetQuantity(),
+		Category:    l.GetCategory(),
+		CategoryID:  l.GetCategoryId(),
+		Closed:      l.GetClosed(),
+		Price:       l.GetPrice(),
+		Creator:     l.GetCreator(),
+		CreatedAt:   l.GetCreatedAt(),
+	}
+}
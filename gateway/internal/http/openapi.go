@@ -0,0 +1,16 @@
+package httpserver
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPI describes every route of gateway, keep it in sync with NewRouter
+//
+//go:embed openapi.json
+var openAPI []byte
+
+func serveOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(openAPI)
+}
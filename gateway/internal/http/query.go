@@ -0,0 +1,68 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// query parses optional URL query parameters, remembering first bad one
+type query struct {
+	values url.Values
+	err    error
+}
+
+func (q *query) int64(name string) *int64 {
+	raw := q.values.Get(name)
+	if raw == "" || q.err != nil {
+		return nil
+	}
+
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		q.err = fmt.Errorf("%s must be integer", name)
+		return nil
+	}
+
+	return &v
+}
+
+func (q *query) int32(name string) int32 {
+	raw := q.values.Get(name)
+	if raw == "" || q.err != nil {
+		return 0
+	}
+
+	v, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil {
+		q.err = fmt.Errorf("%s must be integer", name)
+		return 0
+	}
+
+	return int32(v)
+}
+
+func (q *query) bool(name string) *bool {
+	raw := q.values.Get(name)
+	if raw == "" || q.err != nil {
+		return nil
+	}
+
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		q.err = fmt.Errorf("%s must be true or false", name)
+		return nil
+	}
+
+	return &v
+}
+
+// flag is bool parameter false unless passed
+func (q *query) flag(name string) bool {
+	v := q.bool(name)
+	return v != nil && *v
+}
+
+func (q *query) string(name string) string {
+	return q.values.Get(name)
+}
@@ -0,0 +1,281 @@
+// Package httpserver translates REST calls of external clients into gRPC calls of services
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const maxBodySize = 1 << 20
+
+type router struct {
+	log            *slog.Logger
+	auth           ssov1.AuthClient
+	authTimeout    time.Duration
+	catalog        prodcatv1.CatalogClient
+	catalogTimeout time.Duration
+}
+
+// NewRouter returns handler serving all routes of gateway.
+// Every call to service is limited by timeout of that service.
+func NewRouter(
+	log *slog.Logger,
+	auth ssov1.AuthClient,
+	authTimeout time.Duration,
+	catalog prodcatv1.CatalogClient,
+	catalogTimeout time.Duration,
+) http.Handler {
+	r := &router{
+		log:            log,
+		auth:           auth,
+		authTimeout:    authTimeout,
+		catalog:        catalog,
+		catalogTimeout: catalogTimeout,
+	}
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /openapi.json", serveOpenAPI)
+
+	mux.HandleFunc("POST /auth/register", r.register)
+	mux.HandleFunc("POST /auth/login", r.login)
+	mux.HandleFunc("POST /auth/login/verify", r.loginVerify)
+	mux.HandleFunc("POST /auth/refresh", r.refresh)
+	mux.HandleFunc("POST /auth/logout", r.logout)
+	mux.HandleFunc("POST /auth/verify-email", r.verifyEmail)
+	mux.HandleFunc("POST /auth/verify-email/resend", r.resendVerification)
+	mux.HandleFunc("POST /auth/password-reset", r.requestPasswordReset)
+	mux.HandleFunc("POST /auth/password-reset/confirm", r.resetPassword)
+	mux.HandleFunc("GET /auth/me", r.me)
+	mux.HandleFunc("POST /auth/totp", r.enrollTOTP)
+	mux.HandleFunc("POST /auth/totp/confirm", r.confirmTOTP)
+	mux.HandleFunc("POST /auth/totp/disable", r.disableTOTP)
+
+	mux.HandleFunc("GET /listings", r.listListings)
+	mux.HandleFunc("POST /listings", r.createListing)
+	mux.HandleFunc("GET /listings/search", r.searchListings)
+	mux.HandleFunc("GET /listings/{id}", r.getListing)
+	mux.HandleFunc("PUT /listings/{id}", r.updateListing)
+	mux.HandleFunc("DELETE /listings/{id}", r.deleteListing)
+	mux.HandleFunc("GET /categories", r.listCategories)
+
+	return recoverPanics(log, logRequests(log, mux))
+}
+
+type errorResponse struct {
+	// Name of gRPC status code, e.g. "NOT_FOUND"
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Every problem with fields of invalid request, if service listed them
+	Violations []fieldViolation `json:"violations,omitempty"`
+}
+
+type fieldViolation struct {
+	Field       string `json:"field"`
+	Reason      string `json:"reason,omitempty"`
+	Description string `json:"description"`
+}
+
+var codeNames = map[codes.Code]string{
+	codes.Canceled:           "CANCELLED",
+	codes.Unknown:            "UNKNOWN",
+	codes.InvalidArgument:    "INVALID_ARGUMENT",
+	codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+	codes.NotFound:           "NOT_FOUND",
+	codes.AlreadyExists:      "ALREADY_EXISTS",
+	codes.PermissionDenied:   "PERMISSION_DENIED",
+	codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+	codes.FailedPrecondition: "FAILED_PRECONDITION",
+	codes.Aborted:            "ABORTED",
+	codes.OutOfRange:         "OUT_OF_RANGE",
+	codes.Unimplemented:      "UNIMPLEMENTED",
+	codes.Internal:           "INTERNAL",
+	codes.Unavailable:        "UNAVAILABLE",
+	codes.DataLoss:           "DATA_LOSS",
+	codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+// httpStatus follows mapping of google.rpc.Code to HTTP
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		// client closed request, not defined by net/http
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	enc := json.NewEncoder(w)
+	// search highlights carry <mark> tags which must reach client as is
+	enc.SetEscapeHTML(false)
+
+	// client is gone if encoding fails, nothing to do
+	_ = enc.Encode(body)
+}
+
+func writeError(w http.ResponseWriter, code codes.Code, message string) {
+	writeJSON(w, httpStatus(code), errorResponse{Code: codeNames[code], Message: message})
+}
+
+// writeCallError reports error of gRPC call to client
+func (rt *router) writeCallError(w http.ResponseWriter, r *http.Request, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		st = status.New(codes.Internal, "internal error")
+	}
+
+	// details of internal errors must not leak out
+	message := st.Message()
+	switch st.Code() {
+	case codes.Internal, codes.Unknown, codes.DataLoss:
+		rt.log.Error("service failed",
+			slog.String("method", r.Method), slog.String("path", r.URL.Path), ll.Err(err))
+		message = "internal error"
+	case codes.Unavailable:
+		rt.log.Warn("service is unavailable",
+			slog.String("method", r.Method), slog.String("path", r.URL.Path), ll.Err(err))
+		message = "service is unavailable, retry later"
+	}
+
+	writeJSON(w, httpStatus(st.Code()), errorResponse{
+		Code:       codeNames[st.Code()],
+		Message:    message,
+		Violations: fieldViolations(st),
+	})
+}
+
+// fieldViolations returns violations listed in BadRequest details of status
+func fieldViolations(st *status.Status) []fieldViolation {
+	if st.Code() != codes.InvalidArgument {
+		return nil
+	}
+
+	var violations []fieldViolation
+	for _, detail := range st.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+
+		for _, v := range badRequest.GetFieldViolations() {
+			violations = append(violations, fieldViolation{
+				Field:       v.GetField(),
+				Reason:      v.GetReason(),
+				Description: v.GetDescription(),
+			})
+		}
+	}
+
+	return violations
+}
+
+// decodeBody reads JSON body into dst, unknown fields are rejected.
+// Writes error response itself and returns false if body is bad.
+func decodeBody(w http.ResponseWriter, r *http.Request, dst any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(dst); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.Is(err, io.EOF):
+			writeError(w, codes.InvalidArgument, "request body is required")
+		case errors.As(err, &maxBytesErr):
+			writeError(w, codes.InvalidArgument, "request body is too large")
+		default:
+			writeError(w, codes.InvalidArgument, "invalid request body: "+err.Error())
+		}
+		return false
+	}
+
+	if dec.More() {
+		writeError(w, codes.InvalidArgument, "request body must be single JSON object")
+		return false
+	}
+
+	return true
+}
+
+// bearerToken returns token of "Authorization: Bearer <token>" header,
+// empty string if there is none
+func bearerToken(r *http.Request) string {
+	const prefix = "bearer "
+
+	value := r.Header.Get("Authorization")
+	if len(value) > len(prefix) && strings.EqualFold(value[:len(prefix)], prefix) {
+		return strings.TrimSpace(value[len(prefix):])
+	}
+
+	return ""
+}
+
+// callContext makes context of call to service: bounded by timeout
+// and carrying caller's token, if there is one
+func callContext(r *http.Request, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx := r.Context()
+
+	if token := bearerToken(r); token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, authtoken.AuthorizationKey, "Bearer "+token)
+	}
+
+	// services limiting clients, like sso on login, need address of client, not of gateway
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-forwarded-for", host)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// pathID parses positive id out of path wildcard
+func pathID(w http.ResponseWriter, r *http.Request, name string) (int64, bool) {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil || id <= 0 {
+		writeError(w, codes.InvalidArgument, fmt.Sprintf("%s must be positive integer", name))
+		return 0, false
+	}
+
+	return id, true
+}
@@ -0,0 +1,366 @@
+package httpserver_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	httpserver "github.com/Kry0z1/e-commerce/gateway/internal/http"
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeAuth struct {
+	ssov1.AuthClient
+	err   error
+	login *ssov1.LoginResponse
+	// header sent back on login
+	header metadata.MD
+}
+
+func (f *fakeAuth) RegisterUser(context.Context, *ssov1.RegisterUserRequest, ...grpc.CallOption) (*ssov1.RegisterResponse, error) {
+	return &ssov1.RegisterResponse{Id: 1}, f.err
+}
+
+func (f *fakeAuth) Login(_ context.Context, _ *ssov1.LoginRequest, opts ...grpc.CallOption) (*ssov1.LoginResponse, error) {
+	for _, opt := range opts {
+		if h, ok := opt.(grpc.HeaderCallOption); ok && f.header != nil {
+			*h.HeaderAddr = f.header
+		}
+	}
+
+	if f.login != nil {
+		return f.login, f.err
+	}
+	return &ssov1.LoginResponse{}, f.err
+}
+
+func (f *fakeAuth) LoginVerify(context.Context, *ssov1.LoginVerifyRequest, ...grpc.CallOption) (*ssov1.LoginVerifyResponse, error) {
+	return &ssov1.LoginVerifyResponse{}, f.err
+}
+
+func (f *fakeAuth) EnrollTOTP(context.Context, *ssov1.EnrollTOTPRequest, ...grpc.CallOption) (*ssov1.EnrollTOTPResponse, error) {
+	return &ssov1.EnrollTOTPResponse{}, f.err
+}
+
+func (f *fakeAuth) ConfirmTOTP(context.Context, *ssov1.ConfirmTOTPRequest, ...grpc.CallOption) (*ssov1.ConfirmTOTPResponse, error) {
+	return &ssov1.ConfirmTOTPResponse{}, f.err
+}
+
+func (f *fakeAuth) DisableTOTP(context.Context, *ssov1.DisableTOTPRequest, ...grpc.CallOption) (*ssov1.DisableTOTPResponse, error) {
+	return &ssov1.DisableTOTPResponse{}, f.err
+}
+
+func (f *fakeAuth) Refresh(context.Context, *ssov1.RefreshRequest, ...grpc.CallOption) (*ssov1.RefreshResponse, error) {
+	return &ssov1.RefreshResponse{}, f.err
+}
+
+func (f *fakeAuth) Logout(context.Context, *ssov1.LogoutRequest, ...grpc.CallOption) (*ssov1.LogoutResponse, error) {
+	return &ssov1.LogoutResponse{}, f.err
+}
+
+func (f *fakeAuth) VerifyEmail(context.Context, *ssov1.VerifyEmailRequest, ...grpc.CallOption) (*ssov1.VerifyEmailResponse, error) {
+	return &ssov1.VerifyEmailResponse{}, f.err
+}
+
+func (f *fakeAuth) ResendVerification(context.Context, *ssov1.ResendVerificationRequest, ...grpc.CallOption) (*ssov1.ResendVerificationResponse, error) {
+	return &ssov1.ResendVerificationResponse{}, f.err
+}
+
+func (f *fakeAuth) RequestPasswordReset(context.Context, *ssov1.RequestPasswordResetRequest, ...grpc.CallOption) (*ssov1.RequestPasswordResetResponse, error) {
+	return &ssov1.RequestPasswordResetResponse{}, f.err
+}
+
+func (f *fakeAuth) ResetPassword(context.Context, *ssov1.ResetPasswordRequest, ...grpc.CallOption) (*ssov1.ResetPasswordResponse, error) {
+	return &ssov1.ResetPasswordResponse{}, f.err
+}
+
+func (f *fakeAuth) ValidateToken(context.Context, *ssov1.ValidateTokenRequest, ...grpc.CallOption) (*ssov1.ValidateTokenResponse, error) {
+	return &ssov1.ValidateTokenResponse{Valid: true}, f.err
+}
+
+// fakeCatalog records last request and metadata it was called with
+type fakeCatalog struct {
+	prodcatv1.CatalogClient
+	err     error
+	lastReq any
+	lastMD  metadata.MD
+}
+
+func (f *fakeCatalog) record(ctx context.Context, req any) {
+	f.lastReq = req
+	f.lastMD, _ = metadata.FromOutgoingContext(ctx)
+}
+
+func (f *fakeCatalog) ListListings(ctx context.Context, req *prodcatv1.ListListingsRequest, _ ...grpc.CallOption) (*prodcatv1.ListListingsResponse, error) {
+	f.record(ctx, req)
+	return &prodcatv1.ListListingsResponse{Listings: []*prodcatv1.Listing{{Id: 3, Price: 150}}}, f.err
+}
+
+func (f *fakeCatalog) SearchListings(ctx context.Context, req *prodcatv1.SearchListingsRequest, _ ...grpc.CallOption) (*prodcatv1.SearchListingsResponse, error) {
+	f.record(ctx, req)
+	return &prodcatv1.SearchListingsResponse{}, f.err
+}
+
+func (f *fakeCatalog) GetListing(ctx context.Context, req *prodcatv1.GetListingRequest, _ ...grpc.CallOption) (*prodcatv1.GetListingResponse, error) {
+	f.record(ctx, req)
+	return &prodcatv1.GetListingResponse{}, f.err
+}
+
+func (f *fakeCatalog) CreateListing(ctx context.Context, req *prodcatv1.CreateListingRequest, _ ...grpc.CallOption) (*prodcatv1.CreateListingResponse, error) {
+	f.record(ctx, req)
+	return &prodcatv1.CreateListingResponse{Id: 5}, f.err
+}
+
+func (f *fakeCatalog) UpdateListing(ctx context.Context, req *prodcatv1.UpdateListingRequest, _ ...grpc.CallOption) (*prodcatv1.UpdateListingResponse, error) {
+	f.record(ctx, req)
+	return &prodcatv1.UpdateListingResponse{}, f.err
+}
+
+func (f *fakeCatalog) DeleteListing(ctx context.Context, req *prodcatv1.DeleteListingRequest, _ ...grpc.CallOption) (*prodcatv1.DeleteListingResponse, error) {
+	f.record(ctx, req)
+	return &prodcatv1.DeleteListingResponse{}, f.err
+}
+
+func (f *fakeCatalog) ListCategories(ctx context.Context, req *prodcatv1.ListCategoriesRequest, _ ...grpc.CallOption) (*prodcatv1.ListCategoriesResponse, error) {
+	f.record(ctx, req)
+	return &prodcatv1.ListCategoriesResponse{}, f.err
+}
+
+func newRouter(auth *fakeAuth, catalog *fakeCatalog) http.Handler {
+	log := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return httpserver.NewRouter(log, auth, time.Second, catalog, time.Second)
+}
+
+func do(t *testing.T, h http.Handler, method, target, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestOpenAPI_EveryRouteIsServed(t *testing.T) {
+	h := newRouter(&fakeAuth{}, &fakeCatalog{})
+
+	rec := do(t, h, http.MethodGet, "/openapi.json", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	require.NotEmpty(t, doc.Paths)
+
+	for path, item := range doc.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+
+			target := strings.ReplaceAll(path, "{id}", "1") + "?q=phone"
+			rec := do(t, h, strings.ToUpper(method), target, "{}", "token")
+
+			assert.Less(t, rec.Code, 300, "%s %s: %s", method, path, rec.Body.String())
+		}
+	}
+}
+
+func TestListListings_Query(t *testing.T) {
+	catalog := &fakeCatalog{}
+	h := newRouter(&fakeAuth{}, catalog)
+
+	rec := do(t, h, http.MethodGet, "/listings?category_id=4&min_price=100&in_stock=true&sort=price_asc&page_size=5&page_token=abc", "", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	req := catalog.lastReq.(*prodcatv1.ListListingsRequest)
+	assert.Equal(t, int64(4), req.GetCategoryId())
+	assert.Equal(t, int64(100), req.GetMinPrice())
+	assert.Nil(t, req.MaxPrice)
+	assert.True(t, req.GetInStock())
+	assert.Equal(t, prodcatv1.ListingSort_LISTING_SORT_PRICE_ASC, req.GetSort())
+	assert.Equal(t, int32(5), req.GetPageSize())
+	assert.Equal(t, "abc", req.GetPageToken())
+
+	assert.JSONEq(t, `{"listings": [{"id": 3, "title": "", "description": "", "quantity": 0, "category": "",
+		"category_id": 0, "closed": false, "price": 150, "creator": 0, "created_at": 0}], "next_page_token": ""}`,
+		rec.Body.String())
+}
+
+func TestListListings_BadQuery(t *testing.T) {
+	for _, target := range []string{
+		"/listings?min_price=cheap",
+		"/listings?in_stock=maybe",
+		"/listings?sort=random",
+		"/listings?page_size=99999999999",
+	} {
+		catalog := &fakeCatalog{}
+		h := newRouter(&fakeAuth{}, catalog)
+
+		rec := do(t, h, http.MethodGet, target, "", "")
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+		assert.Nil(t, catalog.lastReq, target)
+	}
+}
+
+func TestCreateListing_ForwardsToken(t *testing.T) {
+	catalog := &fakeCatalog{}
+	h := newRouter(&fakeAuth{}, catalog)
+
+	rec := do(t, h, http.MethodPost, "/listings", `{"title": "Mug", "price": 1250, "category_id": 2}`, "secret")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, `{"id": 5}`, rec.Body.String())
+
+	assert.Equal(t, []string{"Bearer secret"}, catalog.lastMD.Get("authorization"))
+
+	req := catalog.lastReq.(*prodcatv1.CreateListingRequest)
+	assert.Equal(t, "Mug", req.GetTitle())
+	assert.Equal(t, int64(1250), req.GetPrice())
+	assert.Equal(t, int64(2), req.GetCategoryId())
+}
+
+func TestBadBody(t *testing.T) {
+	h := newRouter(&fakeAuth{}, &fakeCatalog{})
+
+	for _, body := range []string{"", "{", `{"title": 5}`, `{"token": "x"}`, `{} {}`} {
+		rec := do(t, h, http.MethodPost, "/listings", body, "secret")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	tests := []struct {
+		err     error
+		status  int
+		code    string
+		message string
+	}{
+		{status.Error(codes.NotFound, "listing not found"), http.StatusNotFound, "NOT_FOUND", "listing not found"},
+		{status.Error(codes.InvalidArgument, "bad"), http.StatusBadRequest, "INVALID_ARGUMENT", "bad"},
+		{status.Error(codes.Unauthenticated, "token is expired"), http.StatusUnauthorized, "UNAUTHENTICATED", "token is expired"},
+		{status.Error(codes.PermissionDenied, "nope"), http.StatusForbidden, "PERMISSION_DENIED", "nope"},
+		{status.Error(codes.FailedPrecondition, "closed"), http.StatusBadRequest, "FAILED_PRECONDITION", "closed"},
+		{status.Error(codes.ResourceExhausted, "too many"), http.StatusTooManyRequests, "RESOURCE_EXHAUSTED", "too many"},
+		{status.Error(codes.Aborted, "retry"), http.StatusConflict, "ABORTED", "retry"},
+		{status.Error(codes.Internal, "db is on fire"), http.StatusInternalServerError, "INTERNAL", "internal error"},
+		{status.Error(codes.Unavailable, "connection refused"), http.StatusServiceUnavailable, "UNAVAILABLE", "service is unavailable, retry later"},
+		{status.Error(codes.DeadlineExceeded, "slow"), http.StatusGatewayTimeout, "DEADLINE_EXCEEDED", "slow"},
+	}
+
+	for _, tt := range tests {
+		h := newRouter(&fakeAuth{}, &fakeCatalog{err: tt.err})
+
+		rec := do(t, h, http.MethodGet, "/listings/7", "", "")
+
+		assert.Equal(t, tt.status, rec.Code, tt.code)
+
+		var body struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, tt.code, body.Code)
+		assert.Equal(t, tt.message, body.Message)
+	}
+}
+
+func TestErrorViolations(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "password does not satisfy password policy").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "password", Reason: "TOO_SHORT", Description: "must be at least 8 characters long"},
+			{Field: "password", Reason: "BREACHED", Description: "is too common or was exposed in data breach"},
+		},
+	})
+	require.NoError(t, err)
+
+	h := newRouter(&fakeAuth{err: st.Err()}, &fakeCatalog{})
+
+	rec := do(t, h, http.MethodPost, "/auth/register", `{"email":"a@b.c","password":"pw"}`, "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"code": "INVALID_ARGUMENT",
+		"message": "password does not satisfy password policy",
+		"violations": [
+			{"field": "password", "reason": "TOO_SHORT", "description": "must be at least 8 characters long"},
+			{"field": "password", "reason": "BREACHED", "description": "is too common or was exposed in data breach"}
+		]
+	}`, rec.Body.String())
+}
+
+func TestTokenRequired(t *testing.T) {
+	h := newRouter(&fakeAuth{}, &fakeCatalog{})
+
+	for _, target := range []string{"/auth/logout", "/auth/me", "/auth/totp", "/auth/totp/confirm", "/auth/totp/disable"} {
+		method := http.MethodPost
+		if target == "/auth/me" {
+			method = http.MethodGet
+		}
+
+		rec := do(t, h, method, target, "", "")
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, target)
+	}
+}
+
+func TestLogin_TOTPRequired(t *testing.T) {
+	h := newRouter(&fakeAuth{login: &ssov1.LoginResponse{TotpRequired: true, ChallengeToken: "challenge"}}, &fakeCatalog{})
+
+	rec := do(t, h, http.MethodPost, "/auth/login", `{"email":"a@b.c","password":"pw","app_id":1}`, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"totp_required":true,"challenge_token":"challenge"}`, rec.Body.String())
+
+	h = newRouter(&fakeAuth{login: &ssov1.LoginResponse{Token: "access", RefreshToken: "refresh"}}, &fakeCatalog{})
+
+	rec = do(t, h, http.MethodPost, "/auth/login", `{"email":"a@b.c","password":"pw","app_id":1}`, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"token":"access","refresh_token":"refresh","totp_required":false}`, rec.Body.String())
+}
+
+func TestLogin_Throttled(t *testing.T) {
+	h := newRouter(&fakeAuth{
+		err:    status.Error(codes.Unavailable, "too many failed login attempts, try again later"),
+		header: metadata.Pairs("retry-after", "2"),
+	}, &fakeCatalog{})
+
+	rec := do(t, h, http.MethodPost, "/auth/login", `{"email":"a@b.c","password":"pw","app_id":1}`, "")
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), "too many failed login attempts")
+
+	h = newRouter(&fakeAuth{
+		err:    status.Error(codes.ResourceExhausted, "too many failed login attempts, account is temporarily locked"),
+		header: metadata.Pairs("retry-after", "900"),
+	}, &fakeCatalog{})
+
+	rec = do(t, h, http.MethodPost, "/auth/login", `{"email":"a@b.c","password":"pw","app_id":1}`, "")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "900", rec.Header().Get("Retry-After"))
+}
+
+func TestBadPathID(t *testing.T) {
+	h := newRouter(&fakeAuth{}, &fakeCatalog{})
+
+	for _, id := range []string{"abc", "0", "-1"} {
+		rec := do(t, h, http.MethodGet, "/listings/"+id, "", "")
+		assert.Equal(t, http.StatusBadRequest, rec.Code, id)
+	}
+}
@@ -1,32 +1,68 @@
 package app
 
 import (
+	"context"
 	"log/slog"
 
+	"github.com/Kry0z1/e-commerce/authtoken"
 	grpcapp "github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/app/grpc"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/config"
 	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/service"
 	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage/sqlite"
+	"github.com/Kry0z1/e-commerce/ssoclient"
+	sweeperapp "github.com/Kry0z1/e-commerce/sweeper"
 )
 
 type App struct {
 	GRPCServer *grpcapp.App
+	Sweeper    *sweeperapp.App
 }
 
 func New(
 	log *slog.Logger,
 	grpcPort int,
 	storagePath string,
+	ssoCfg config.SSOConfig,
+	reservationsCfg config.ReservationsConfig,
 ) *App {
 	storage, err := sqlite.New(storagePath)
 	if err != nil {
 		panic(err)
 	}
 
-	srvc := service.New(log, storage, storage)
+	ssoClient, err := ssoclient.New(log, ssoCfg.Address, ssoCfg.Timeout, ssoCfg.RetriesCount)
+	if err != nil {
+		panic(err)
+	}
+
+	verifier := authtoken.NewVerifier(
+		authtoken.NewCachedKeySet(ssoClient, ssoCfg.KeysCacheTTL),
+		authtoken.WithIssuer(ssoCfg.Issuer),
+		authtoken.WithAudience(ssoCfg.Audience...),
+		authtoken.WithLeeway(ssoCfg.Leeway),
+		authtoken.WithRevocationCheck(ssoclient.NewCachedRevocationChecker(ssoClient, ssoCfg.RevocationCacheTTL)),
+	)
+
+	admins := ssoclient.NewCachedAdminChecker(ssoClient, ssoCfg.AdminCacheTTL)
+
+	srvc := service.New(
+		log,
+		storage, storage, storage, storage, storage, storage, storage, storage, storage,
+		admins, storage,
+		reservationsCfg.TTL, reservationsCfg.MaxTTL,
+	)
+
+	if err := srvc.NormalizeCategorySlugs(context.Background()); err != nil {
+		panic(err)
+	}
+
+	grpcApp := grpcapp.New(srvc, verifier, log, grpcPort)
 
-	grpcApp := grpcapp.New(srvc, log, grpcPort)
+	sweeper := sweeperapp.New(log, srvc.SweepReservations, reservationsCfg.SweepInterval)
+	go sweeper.Run()
 
 	return &App{
 		GRPCServer: grpcApp,
+		Sweeper:    sweeper,
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"net"
 
+	"github.com/Kry0z1/e-commerce/authtoken"
 	grpcserver "github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/grpc"
 	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/service"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
@@ -21,10 +22,11 @@ type App struct {
 	port       int
 }
 
-func New(service *service.Service, log *slog.Logger, port int) *App {
+func New(service *service.Service, verifier *authtoken.Verifier, log *slog.Logger, port int) *App {
+	// requests of old clients carry tokens in deprecated field, so only calls are logged
 	loggingOpts := []logging.Option{
 		logging.WithLogOnEvents(
-			logging.PayloadReceived, logging.PayloadSent,
+			logging.StartCall, logging.FinishCall,
 		),
 	}
 
@@ -36,8 +38,11 @@ func New(service *service.Service, log *slog.Logger, port int) *App {
 	}
 
 	gRPCServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		grpcserver.LegacyTokenInterceptor(),
 		recovery.UnaryServerInterceptor(recoveryOpts...),
 		logging.UnaryServerInterceptor(InterceptorLogger(log), loggingOpts...),
+		authtoken.UnaryServerInterceptor(verifier),
+		authtoken.RequirePrincipal(grpcserver.AuthRequiredMethods...),
 	))
 
 	grpcserver.Register(gRPCServer, *service)
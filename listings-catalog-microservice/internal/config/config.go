@@ -13,6 +13,9 @@ type Config struct {
 	Env         string     `yaml:"env" env-default:"local"`
 	StoragePath string     `yaml:"storage_path" env-required:"true"`
 	GRPC        GRPCConfig `yaml:"grpc" env-required:"true"`
+	SSO         SSOConfig  `yaml:"sso" env-required:"true"`
+
+	Reservations ReservationsConfig `yaml:"reservations"`
 }
 
 type GRPCConfig struct {
@@ -20,6 +23,34 @@ type GRPCConfig struct {
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+type SSOConfig struct {
+	Address      string        `yaml:"address" env-required:"true"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	RetriesCount int           `yaml:"retries_count" env-default:"3"`
+	// How long signing keys fetched from sso are trusted without refetch
+	KeysCacheTTL time.Duration `yaml:"keys_cache_ttl" env-default:"5m"`
+	// Expected "iss" claim of tokens
+	Issuer string `yaml:"issuer" env-default:"sso"`
+	// Ids of apps whose tokens are accepted, empty means any app
+	Audience []string `yaml:"audience"`
+	// Allowed clock skew between sso and catalog
+	Leeway time.Duration `yaml:"leeway" env-default:"30s"`
+	// How long answer of sso about token revocation is trusted, revoked token
+	// is accepted for at most this long after revocation
+	RevocationCacheTTL time.Duration `yaml:"revocation_cache_ttl" env-default:"5s"`
+	// How long admin status of user fetched from sso is trusted
+	AdminCacheTTL time.Duration `yaml:"admin_cache_ttl" env-default:"30s"`
+}
+
+type ReservationsConfig struct {
+	// Lifetime of reservation if client didn't ask for specific one
+	TTL time.Duration `yaml:"ttl" env-default:"15m"`
+	// Longest lifetime client may ask for
+	MaxTTL time.Duration `yaml:"max_ttl" env-default:"1h"`
+	// How often expired reservations are swept
+	SweepInterval time.Duration `yaml:"sweep_interval" env-default:"1m"`
+}
+
 func MustLoad() *Config {
 	path := getConfigPath()
 	return MustLoadPath(path)
@@ -0,0 +1,76 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// AuthRequiredMethods are methods that can't be called without access token,
+// every other method is public
+var AuthRequiredMethods = []string{
+	prodcatv1.Catalog_CreateListing_FullMethodName,
+	prodcatv1.Catalog_UpdateListing_FullMethodName,
+	prodcatv1.Catalog_DeleteListing_FullMethodName,
+	prodcatv1.Catalog_CreateCategory_FullMethodName,
+	prodcatv1.Catalog_MoveCategory_FullMethodName,
+	prodcatv1.Catalog_RenameCategory_FullMethodName,
+	prodcatv1.Catalog_ArchiveCategory_FullMethodName,
+	prodcatv1.Catalog_CreateVariant_FullMethodName,
+	prodcatv1.Catalog_UpdateVariant_FullMethodName,
+	prodcatv1.Catalog_DeleteVariant_FullMethodName,
+	prodcatv1.Catalog_ReserveStock_FullMethodName,
+	prodcatv1.Catalog_CommitReservation_FullMethodName,
+	prodcatv1.Catalog_ReleaseReservation_FullMethodName,
+}
+
+type legacyTokenRequest interface {
+	GetToken() string
+}
+
+// LegacyTokenInterceptor moves token from deprecated request field
+// into "authorization" metadata for clients that don't send it yet.
+// Must be placed before authtoken.UnaryServerInterceptor.
+func LegacyTokenInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		r, ok := req.(legacyTokenRequest)
+		if !ok || r.GetToken() == "" || authtoken.BearerToken(ctx) != "" {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		md = md.Copy()
+		md.Set(authtoken.AuthorizationKey, "Bearer "+r.GetToken())
+
+		return handler(metadata.NewIncomingContext(ctx, md), req)
+	}
+}
+
+// caller returns id of user authenticated by interceptors
+func caller(ctx context.Context) (int64, error) {
+	principal, ok := authtoken.PrincipalFromContext(ctx)
+	if !ok {
+		return -1, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return principal.UserID, nil
+}
+
+// verifiedCaller is caller who has verified their email
+func verifiedCaller(ctx context.Context) (int64, error) {
+	principal, ok := authtoken.PrincipalFromContext(ctx)
+	if !ok {
+		return -1, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	if !principal.EmailVerified {
+		return -1, status.Error(codes.PermissionDenied, "email is not verified")
+	}
+
+	return principal.UserID, nil
+}
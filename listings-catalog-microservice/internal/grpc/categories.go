@@ -0,0 +1,170 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/slug"
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func validateSlug(value string) error {
+	if value == "" {
+		return status.Error(codes.InvalidArgument, "missing slug")
+	}
+	if !slug.Valid(value) {
+		return status.Error(codes.InvalidArgument, "slug must consist of lowercase letters, digits and single dashes")
+	}
+
+	return nil
+}
+
+func (s *serverAPI) ListCategories(ctx context.Context, req *prodcatv1.ListCategoriesRequest) (*prodcatv1.ListCategoriesResponse, error) {
+	categories, err := s.srvc.Categories(ctx, req.GetIncludeArchived())
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	resp := &prodcatv1.ListCategoriesResponse{
+		Categories: make([]*prodcatv1.Category, 0, len(categories)),
+	}
+	for _, category := range categories {
+		resp.Categories = append(resp.Categories, &prodcatv1.Category{
+			Id:       category.ID,
+			ParentId: category.ParentID,
+			Slug:     category.Slug,
+			Name:     category.Name,
+			Archived: category.Archived,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) CreateCategory(ctx context.Context, req *prodcatv1.CreateCategoryRequest) (*prodcatv1.CreateCategoryResponse, error) {
+	slug := req.GetSlug()
+	if err := validateSlug(slug); err != nil {
+		return nil, err
+	}
+
+	name := req.GetName()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing name")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.srvc.CreateCategory(ctx, req.GetParentId(), slug, name, callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.CreateCategoryResponse{Id: id}, nil
+}
+
+func (s *serverAPI) MoveCategory(ctx context.Context, req *prodcatv1.MoveCategoryRequest) (*prodcatv1.MoveCategoryResponse, error) {
+	id := req.GetId()
+	if id == req.GetParentId() {
+		return nil, status.Error(codes.InvalidArgument, "category can't be its own parent")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.MoveCategory(ctx, id, req.GetParentId(), callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.MoveCategoryResponse{}, nil
+}
+
+func (s *serverAPI) RenameCategory(ctx context.Context, req *prodcatv1.RenameCategoryRequest) (*prodcatv1.RenameCategoryResponse, error) {
+	if req.Slug == nil && req.Name == nil {
+		return nil, status.Error(codes.InvalidArgument, "nothing to rename")
+	}
+
+	if req.Slug != nil {
+		if err := validateSlug(req.GetSlug()); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.Name != nil && req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing name")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.RenameCategory(ctx, req.GetId(), req.Slug, req.Name, callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.RenameCategoryResponse{}, nil
+}
+
+func (s *serverAPI) ArchiveCategory(ctx context.Context, req *prodcatv1.ArchiveCategoryRequest) (*prodcatv1.ArchiveCategoryResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.ArchiveCategory(ctx, req.GetId(), callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.ArchiveCategoryResponse{}, nil
+}
+
+// categoryID resolves category of listing: id takes precedence over deprecated category.
+// Old clients send free-text category there, it is turned into slug the same way
+// backfilled categories got theirs.
+func (s *serverAPI) categoryID(ctx context.Context, id int64, category string) (int64, error) {
+	if id != 0 {
+		return id, nil
+	}
+
+	if category == "" {
+		return 0, status.Error(codes.InvalidArgument, "missing category")
+	}
+
+	found, err := s.srvc.CategoryBySlug(ctx, slug.Make(category))
+	if err != nil {
+		return 0, parseServiceError(err)
+	}
+
+	return found.ID, nil
+}
+
+// categoryFilter is categoryID for optional filters, nil -> filter is not applied
+func (s *serverAPI) categoryFilter(ctx context.Context, id *int64, slug *string) (*int64, error) {
+	if id == nil && slug == nil {
+		return nil, nil
+	}
+
+	var (
+		rawID   int64
+		rawSlug string
+	)
+	if id != nil {
+		rawID = *id
+	}
+	if slug != nil {
+		rawSlug = *slug
+	}
+
+	categoryID, err := s.categoryID(ctx, rawID, rawSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	return &categoryID, nil
+}
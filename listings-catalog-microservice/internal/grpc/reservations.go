@@ -0,0 +1,65 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (s *serverAPI) ReserveStock(ctx context.Context, req *prodcatv1.ReserveStockRequest) (*prodcatv1.ReserveStockResponse, error) {
+	quantity := req.GetQuantity()
+	if quantity <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+	}
+
+	ttl := req.GetTtlSeconds()
+	if ttl < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl cannot be negative")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.srvc.ReserveStock(
+		ctx, req.GetListingId(), req.GetVariantId(), quantity, time.Duration(ttl)*time.Second, callerID,
+	)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.ReserveStockResponse{
+		ReservationId: reservation.ID,
+		ExpiresAt:     reservation.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *serverAPI) CommitReservation(ctx context.Context, req *prodcatv1.CommitReservationRequest) (*prodcatv1.CommitReservationResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.CommitReservation(ctx, req.GetReservationId(), callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.CommitReservationResponse{}, nil
+}
+
+func (s *serverAPI) ReleaseReservation(ctx context.Context, req *prodcatv1.ReleaseReservationRequest) (*prodcatv1.ReleaseReservationResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.ReleaseReservation(ctx, req.GetReservationId(), callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.ReleaseReservationResponse{}, nil
+}
@@ -3,6 +3,7 @@ package grpcserver
 import (
 	"context"
 	"errors"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
 	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/service"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,15 +19,29 @@ type serverAPI struct {
 
 func parseServiceError(err error) error {
 	if err != nil {
-		if errors.Is(err, service.ErrListingNotFound) || errors.Is(err, service.ErrUserNotFound) {
+		if errors.Is(err, service.ErrListingNotFound) || errors.Is(err, service.ErrUserNotFound) ||
+			errors.Is(err, service.ErrCategoryNotFound) || errors.Is(err, service.ErrVariantNotFound) ||
+			errors.Is(err, service.ErrReservationNotFound) {
 			return status.Error(codes.NotFound, err.Error())
 		}
+		if errors.Is(err, service.ErrCategoryExists) || errors.Is(err, service.ErrSKUExists) ||
+			errors.Is(err, service.ErrOptionsExist) {
+			return status.Error(codes.AlreadyExists, err.Error())
+		}
+		if errors.Is(err, service.ErrCategoryArchived) || errors.Is(err, service.ErrCategoryCycle) ||
+			errors.Is(err, service.ErrOptionsMismatch) || errors.Is(err, service.ErrListingClosed) ||
+			errors.Is(err, service.ErrReservationNotActive) || errors.Is(err, service.ErrBelowReserved) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
 		if errors.Is(err, service.ErrNotEnoughPermissions) {
 			return status.Error(codes.PermissionDenied, err.Error())
 		}
-		if errors.Is(err, service.ErrInvalidToken) || errors.Is(err, service.ErrTokenExpired) {
+		if errors.Is(err, service.ErrInvalidPageToken) || errors.Is(err, service.ErrEmptyQuery) {
 			return status.Error(codes.InvalidArgument, err.Error())
 		}
+		if errors.Is(err, service.ErrInsufficientStock) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
 
 		return status.Error(codes.Internal, "internal error")
 	}
@@ -50,29 +65,36 @@ func (s *serverAPI) CreateListing(ctx context.Context, req *prodcatv1.CreateList
 		return nil, status.Error(codes.InvalidArgument, "quantity cannot be less than 0 dollars")
 	}
 
-	category := req.GetCategory()
-	if category == "" {
-		return nil, status.Error(codes.InvalidArgument, "missing category")
-	}
-
 	closed := req.GetClosed()
 	price := req.GetPrice()
 	if price < 0 {
 		return nil, status.Error(codes.InvalidArgument, "price cannot be less than 0 dollars")
 	}
 
-	token := req.GetToken()
+	callerID, err := verifiedCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID, err := s.categoryID(ctx, req.GetCategoryId(), req.GetCategory())
+	if err != nil {
+		return nil, err
+	}
 
-	id, err := s.srvc.CreateListing(ctx, title, description, quantity, category, closed, price, token)
+	id, err := s.srvc.CreateListing(ctx, title, description, quantity, categoryID, closed, price, callerID)
 
 	return &prodcatv1.CreateListingResponse{Id: id}, parseServiceError(err)
 }
 
 func (s *serverAPI) DeleteListing(ctx context.Context, req *prodcatv1.DeleteListingRequest) (*prodcatv1.DeleteListingResponse, error) {
 	id := req.GetId()
-	token := req.GetToken()
 
-	err := s.srvc.DeleteListing(ctx, id, token)
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.srvc.DeleteListing(ctx, id, callerID)
 	if err != nil {
 		return &prodcatv1.DeleteListingResponse{Succeeded: false}, parseServiceError(err)
 	}
@@ -86,13 +108,17 @@ func (s *serverAPI) GetListing(ctx context.Context, req *prodcatv1.GetListingReq
 	listing, err := s.srvc.GetListing(ctx, id)
 
 	return &prodcatv1.GetListingResponse{
-		Title:       listing.Title,
-		Description: listing.Description,
-		Quantity:    listing.Quantity,
-		Category:    listing.Category,
-		Closed:      listing.Closed,
-		Price:       listing.Price,
-		Creator:     listing.Creator,
+		Title:             listing.Title,
+		Description:       listing.Description,
+		Quantity:          listing.Quantity,
+		Category:          listing.Category,
+		Closed:            listing.Closed,
+		Price:             listing.Price,
+		Creator:           listing.Creator,
+		CategoryId:        listing.CategoryID,
+		Variants:          variantsToProto(listing.Variants),
+		Options:           matrixToProto(service.VariantMatrix(listing.Variants)),
+		AvailableQuantity: listing.Available,
 	}, parseServiceError(err)
 }
 
@@ -105,18 +131,12 @@ func (s *serverAPI) UpdateListing(ctx context.Context, req *prodcatv1.UpdateList
 	description := req.GetDescription()
 
 	quantity := req.GetQuantity()
-	category := req.GetCategory()
-	if category == "" {
-		return nil, status.Error(codes.InvalidArgument, "missing category")
-	}
-
 	closed := req.GetClosed()
 	price := req.GetPrice()
 	if price < 0 {
 		return nil, status.Error(codes.InvalidArgument, "price cannot be less than 0 dollars")
 	}
 
-	token := req.GetToken()
 	id := req.GetId()
 
 	var descriptionPtr *string
@@ -124,7 +144,17 @@ func (s *serverAPI) UpdateListing(ctx context.Context, req *prodcatv1.UpdateList
 		descriptionPtr = &description
 	}
 
-	err := s.srvc.UpdateListing(ctx, id, &title, descriptionPtr, &quantity, &category, &closed, &price, token)
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryID, err := s.categoryID(ctx, req.GetCategoryId(), req.GetCategory())
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.srvc.UpdateListing(ctx, id, &title, descriptionPtr, &quantity, &categoryID, &closed, &price, callerID)
 
 	if err != nil {
 		return &prodcatv1.UpdateListingResponse{Succeeded: false}, parseServiceError(err)
@@ -133,6 +163,122 @@ func (s *serverAPI) UpdateListing(ctx context.Context, req *prodcatv1.UpdateList
 	return &prodcatv1.UpdateListingResponse{Succeeded: true}, nil
 }
 
+var listingSorts = map[prodcatv1.ListingSort]models.ListingSort{
+	prodcatv1.ListingSort_LISTING_SORT_UNSPECIFIED: models.SortNewest,
+	prodcatv1.ListingSort_LISTING_SORT_NEWEST:      models.SortNewest,
+	prodcatv1.ListingSort_LISTING_SORT_PRICE_ASC:   models.SortPriceAsc,
+	prodcatv1.ListingSort_LISTING_SORT_PRICE_DESC:  models.SortPriceDesc,
+	prodcatv1.ListingSort_LISTING_SORT_TITLE:       models.SortTitle,
+}
+
+func (s *serverAPI) ListListings(ctx context.Context, req *prodcatv1.ListListingsRequest) (*prodcatv1.ListListingsResponse, error) {
+	sort, ok := listingSorts[req.GetSort()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown sort")
+	}
+
+	if req.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page size cannot be negative")
+	}
+
+	if req.GetMinPrice() < 0 || req.GetMaxPrice() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "price cannot be less than 0 dollars")
+	}
+
+	if req.MinPrice != nil && req.MaxPrice != nil && req.GetMinPrice() > req.GetMaxPrice() {
+		return nil, status.Error(codes.InvalidArgument, "min price cannot be greater than max price")
+	}
+
+	categoryID, err := s.categoryFilter(ctx, req.CategoryId, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := models.ListingFilter{
+		CategoryID: categoryID,
+		MinPrice:   req.MinPrice,
+		MaxPrice:   req.MaxPrice,
+		Creator:    req.Creator,
+		Closed:     req.Closed,
+		InStock:    req.GetInStock(),
+	}
+
+	listings, nextPageToken, err := s.srvc.ListListings(ctx, filter, sort, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	resp := &prodcatv1.ListListingsResponse{
+		Listings:      make([]*prodcatv1.Listing, 0, len(listings)),
+		NextPageToken: nextPageToken,
+	}
+	for _, listing := range listings {
+		resp.Listings = append(resp.Listings, listingToProto(listing))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) SearchListings(ctx context.Context, req *prodcatv1.SearchListingsRequest) (*prodcatv1.SearchListingsResponse, error) {
+	query := req.GetQuery()
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "missing query")
+	}
+
+	if req.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page size cannot be negative")
+	}
+
+	categoryID, err := s.categoryFilter(ctx, req.CategoryId, req.Category)
+	if err != nil {
+		return nil, err
+	}
+
+	result, nextPageToken, err := s.srvc.SearchListings(ctx, query, categoryID, req.GetIncludeClosed(), int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	resp := &prodcatv1.SearchListingsResponse{
+		Hits:          make([]*prodcatv1.SearchHit, 0, len(result.Hits)),
+		NextPageToken: nextPageToken,
+		Total:         result.Total,
+		Categories:    make([]*prodcatv1.CategoryFacet, 0, len(result.Categories)),
+	}
+	for _, hit := range result.Hits {
+		resp.Hits = append(resp.Hits, &prodcatv1.SearchHit{
+			Listing:            listingToProto(hit.Listing),
+			TitleHighlight:     hit.TitleHighlight,
+			DescriptionSnippet: hit.DescriptionSnippet,
+			Score:              hit.Score,
+		})
+	}
+	for _, facet := range result.Categories {
+		resp.Categories = append(resp.Categories, &prodcatv1.CategoryFacet{
+			Category:   facet.Category,
+			Count:      facet.Count,
+			CategoryId: facet.CategoryID,
+		})
+	}
+
+	return resp, nil
+}
+
+func listingToProto(listing models.Listing) *prodcatv1.Listing {
+	return &prodcatv1.Listing{
+		Id:          listing.ID,
+		Title:       listing.Title,
+		Description: listing.Description,
+		Quantity:    listing.Quantity,
+		Category:    listing.Category,
+		Closed:      listing.Closed,
+		Price:       listing.Price,
+		Creator:     listing.Creator,
+		CreatedAt:   listing.CreatedAt.Unix(),
+		CategoryId:  listing.CategoryID,
+	}
+}
+
 func Register(gRPCServer *grpc.Server, srvc service.Service) {
 	prodcatv1.RegisterCatalogServer(gRPCServer, &serverAPI{srvc: srvc})
 }
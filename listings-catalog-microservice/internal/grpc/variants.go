@@ -0,0 +1,161 @@
+package grpcserver
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	prodcatv1 "github.com/Kry0z1/e-commerce/protos/gen/go/listings-catalog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var skuRegexp = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+func validateSKU(sku string) error {
+	if sku == "" {
+		return status.Error(codes.InvalidArgument, "missing sku")
+	}
+	if !skuRegexp.MatchString(sku) {
+		return status.Error(codes.InvalidArgument, "sku must be at most 64 letters, digits, dashes or underscores")
+	}
+
+	return nil
+}
+
+// normalizeOptions lowercases and trims option names, trims values
+func normalizeOptions(options map[string]string) (map[string]string, error) {
+	if len(options) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "missing options")
+	}
+
+	normalized := make(map[string]string, len(options))
+	for name, value := range options {
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+
+		if name == "" || value == "" {
+			return nil, status.Error(codes.InvalidArgument, "option names and values cannot be empty")
+		}
+		if _, ok := normalized[name]; ok {
+			return nil, status.Error(codes.InvalidArgument, "duplicate option "+name)
+		}
+
+		normalized[name] = value
+	}
+
+	return normalized, nil
+}
+
+func (s *serverAPI) CreateVariant(ctx context.Context, req *prodcatv1.CreateVariantRequest) (*prodcatv1.CreateVariantResponse, error) {
+	sku := req.GetSku()
+	if err := validateSKU(sku); err != nil {
+		return nil, err
+	}
+
+	options, err := normalizeOptions(req.GetOptions())
+	if err != nil {
+		return nil, err
+	}
+
+	price := req.GetPrice()
+	if price < 0 {
+		return nil, status.Error(codes.InvalidArgument, "price cannot be less than 0 dollars")
+	}
+
+	quantity := req.GetQuantity()
+	if quantity < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity cannot be negative")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := s.srvc.CreateVariant(ctx, req.GetListingId(), sku, options, price, quantity, callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.CreateVariantResponse{Id: id}, nil
+}
+
+func (s *serverAPI) UpdateVariant(ctx context.Context, req *prodcatv1.UpdateVariantRequest) (*prodcatv1.UpdateVariantResponse, error) {
+	if req.Sku != nil {
+		if err := validateSKU(req.GetSku()); err != nil {
+			return nil, err
+		}
+	}
+
+	var options map[string]string
+	if len(req.GetOptions()) > 0 {
+		var err error
+		options, err = normalizeOptions(req.GetOptions())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.GetPrice() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "price cannot be less than 0 dollars")
+	}
+
+	if req.GetQuantity() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "quantity cannot be negative")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.srvc.UpdateVariant(ctx, req.GetId(), req.Sku, options, req.Price, req.Quantity, callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.UpdateVariantResponse{}, nil
+}
+
+func (s *serverAPI) DeleteVariant(ctx context.Context, req *prodcatv1.DeleteVariantRequest) (*prodcatv1.DeleteVariantResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.DeleteVariant(ctx, req.GetId(), callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &prodcatv1.DeleteVariantResponse{}, nil
+}
+
+func variantsToProto(variants []models.Variant) []*prodcatv1.Variant {
+	res := make([]*prodcatv1.Variant, 0, len(variants))
+	for _, variant := range variants {
+		res = append(res, &prodcatv1.Variant{
+			Id:                variant.ID,
+			Sku:               variant.SKU,
+			Options:           variant.Options,
+			Price:             variant.Price,
+			Quantity:          variant.Quantity,
+			AvailableQuantity: variant.Available,
+		})
+	}
+
+	return res
+}
+
+func matrixToProto(matrix []models.VariantOption) []*prodcatv1.VariantOption {
+	res := make([]*prodcatv1.VariantOption, 0, len(matrix))
+	for _, option := range matrix {
+		res = append(res, &prodcatv1.VariantOption{
+			Name:   option.Name,
+			Values: option.Values,
+		})
+	}
+
+	return res
+}
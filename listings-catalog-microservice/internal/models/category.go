@@ -0,0 +1,11 @@
+package models
+
+type Category struct {
+	ID int64
+	// 0 for root categories
+	ParentID int64
+	Slug     string
+	Name     string
+	// No new listings can be placed in archived category
+	Archived bool
+}
@@ -1,12 +1,54 @@
 package models
 
+import "time"
+
 type Listing struct {
 	ID          int64
 	Title       string
 	Description string
-	Quantity    int64
-	Category    string
-	Closed      bool
-	Price       int64
-	Creator     int64
+	// On-hand quantity, reserved stock included
+	Quantity   int64
+	CategoryID int64
+	// Slug of category, filled on reads
+	Category  string
+	Closed    bool
+	Price     int64
+	Creator   int64
+	CreatedAt time.Time
+	// Filled only when single listing is requested
+	Variants []Variant
+	// Quantity not held by reservations, filled only when single listing is requested
+	Available int64
+}
+
+type ListingSort string
+
+const (
+	SortNewest    ListingSort = "newest"
+	SortPriceAsc  ListingSort = "price_asc"
+	SortPriceDesc ListingSort = "price_desc"
+	SortTitle     ListingSort = "title"
+)
+
+// ListingFilter describes listings to browse.
+//
+// Nil pointer -> filter is not applied
+type ListingFilter struct {
+	// Listings of this category and all its subcategories
+	CategoryID *int64
+	MinPrice   *int64
+	MaxPrice   *int64
+	Creator    *int64
+	Closed     *bool
+	InStock    bool
+}
+
+// ListingCursor points at the last listing of previous page.
+// Only the field sort is made by is meaningful besides ID.
+type ListingCursor struct {
+	Sort      ListingSort `json:"s"`
+	ID        int64       `json:"id"`
+	Price     int64       `json:"p,omitempty"`
+	CreatedAt int64       `json:"c,omitempty"`
+	Title     string      `json:"t,omitempty"`
 }
@@ -0,0 +1,6 @@
+package models
+
+const (
+	ModerationUpdate = "update"
+	ModerationDelete = "delete"
+)
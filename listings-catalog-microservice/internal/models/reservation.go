@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	ReservationActive    = "active"
+	ReservationCommitted = "committed"
+	ReservationReleased  = "released"
+	ReservationExpired   = "expired"
+)
+
+type Reservation struct {
+	ID        int64
+	ListingID int64
+	// 0 if stock of listing itself is reserved
+	VariantID int64
+	Quantity  int64
+	HolderID  int64
+	Status    string
+	ExpiresAt time.Time
+}
@@ -0,0 +1,42 @@
+package models
+
+type SearchQuery struct {
+	// Words typed by user, every word is matched as prefix
+	Text string
+	// Nil pointer -> listings of all categories,
+	// otherwise listings of this category and its subcategories
+	CategoryID    *int64
+	IncludeClosed bool
+	Offset        int
+	Limit         int
+}
+
+type SearchHit struct {
+	Listing Listing
+	// Title with matched words wrapped in HighlightStart and HighlightEnd
+	TitleHighlight string
+	// Fragment of description around matched words, highlighted the same way
+	DescriptionSnippet string
+	// Bigger is more relevant
+	Score float64
+}
+
+type CategoryFacet struct {
+	CategoryID int64
+	// Slug of category
+	Category string
+	Count    int64
+}
+
+type SearchResult struct {
+	Hits []SearchHit
+	// Number of listings matching query
+	Total int64
+	// Counts of matching listings per category, category filter is not applied to them
+	Categories []CategoryFacet
+}
+
+const (
+	HighlightStart = "<mark>"
+	HighlightEnd   = "</mark>"
+)
@@ -0,0 +1,22 @@
+package models
+
+// Variant is purchasable version of listing, e.g. T-shirt of particular size and color
+type Variant struct {
+	ID        int64
+	ListingID int64
+	SKU       string
+	// Option name -> value, e.g. "size" -> "XL"
+	Options map[string]string
+	// Cost in cents
+	Price int64
+	// On-hand quantity, reserved stock included
+	Quantity int64
+	// Quantity not held by reservations
+	Available int64
+}
+
+// VariantOption is one axis of variant matrix with all its values
+type VariantOption struct {
+	Name   string
+	Values []string
+}
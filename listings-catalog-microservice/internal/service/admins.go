@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+type ModerationSaver interface {
+	SaveModerationAction(ctx context.Context, listingID int64, adminID int64, action string) error
+}
+
+// authorizeModification allows creator of listing and admins to modify it.
+// Returns true if caller acts as admin on someone else's listing.
+func (s *Service) authorizeModification(ctx context.Context, log *slog.Logger, listing models.Listing, callerID int64) (bool, error) {
+	if listing.Creator == callerID {
+		return false, nil
+	}
+
+	isAdmin, err := s.admins.IsAdmin(ctx, callerID)
+	if err != nil {
+		log.Error("failed to check admin status", ll.Err(err))
+		return false, fmt.Errorf("failed to check admin status: %w", err)
+	}
+
+	if !isAdmin {
+		log.Info("wrong user")
+		return false, ErrNotEnoughPermissions
+	}
+
+	return true, nil
+}
+
+// requireAdmin allows only admins to proceed
+func (s *Service) requireAdmin(ctx context.Context, log *slog.Logger, callerID int64) error {
+	isAdmin, err := s.admins.IsAdmin(ctx, callerID)
+	if err != nil {
+		log.Error("failed to check admin status", ll.Err(err))
+		return fmt.Errorf("failed to check admin status: %w", err)
+	}
+
+	if !isAdmin {
+		log.Info("caller is not admin")
+		return ErrNotEnoughPermissions
+	}
+
+	return nil
+}
+
+func (s *Service) recordModeration(ctx context.Context, log *slog.Logger, listingID int64, adminID int64, action string) {
+	log.Info("admin moderated listing",
+		slog.Int64("listing_id", listingID),
+		slog.Int64("admin_id", adminID),
+		slog.String("action", action),
+	)
+
+	if err := s.moderation.SaveModerationAction(ctx, listingID, adminID, action); err != nil {
+		log.Error("failed to record moderation action", ll.Err(err))
+	}
+}
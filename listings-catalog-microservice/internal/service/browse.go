@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// ListListings returns page of listings and token of the next page.
+// Empty next page token means there are no more listings.
+func (s *Service) ListListings(
+	ctx context.Context,
+	filter models.ListingFilter,
+	sort models.ListingSort,
+	pageSize int,
+	pageToken string,
+) ([]models.Listing, string, error) {
+	const op = "service.ListListings"
+
+	log := s.log.With(slog.String("op", op), slog.String("sort", string(sort)))
+
+	log.Info("started listings browsing")
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	var after *models.ListingCursor
+	if pageToken != "" {
+		var cursor models.ListingCursor
+		if err := decodePageToken(pageToken, &cursor); err != nil || cursor.Sort != sort {
+			log.Info("invalid page token")
+			return nil, "", ErrInvalidPageToken
+		}
+		after = &cursor
+	}
+
+	// one extra listing tells whether there is next page
+	listings, err := s.productProvider.Listings(ctx, filter, sort, after, pageSize+1)
+	if err != nil {
+		log.Error("internal error", ll.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var nextPageToken string
+	if len(listings) > pageSize {
+		listings = listings[:pageSize]
+		nextPageToken = encodePageToken(cursorAfter(listings[pageSize-1], sort))
+	}
+
+	log.Info("browsing succeeded", slog.Int("count", len(listings)))
+	return listings, nextPageToken, nil
+}
+
+func cursorAfter(listing models.Listing, sort models.ListingSort) models.ListingCursor {
+	cursor := models.ListingCursor{Sort: sort, ID: listing.ID}
+
+	switch sort {
+	case models.SortNewest:
+		cursor.CreatedAt = listing.CreatedAt.Unix()
+	case models.SortPriceAsc, models.SortPriceDesc:
+		cursor.Price = listing.Price
+	case models.SortTitle:
+		cursor.Title = listing.Title
+	}
+
+	return cursor
+}
+
+// encodePageToken makes opaque token out of cursor struct
+func encodePageToken(cursor any) string {
+	// cursors are plain structs, marshalling can't fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, cursor)
+}
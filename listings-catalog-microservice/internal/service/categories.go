@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+type CategorySaver interface {
+	// 0 parentID -> root category
+	SaveCategory(ctx context.Context, parentID int64, slug string, name string) (int64, error)
+	MoveCategory(ctx context.Context, id int64, parentID int64) error
+	// Nil pointer -> value is unchanged
+	RenameCategory(ctx context.Context, id int64, slug *string, name *string) error
+	// Archives category with all its descendants
+	ArchiveCategory(ctx context.Context, id int64) error
+	// Replaces slugs that are not valid, returns number of replaced slugs
+	NormalizeCategorySlugs(ctx context.Context) (int, error)
+}
+
+type CategoryProvider interface {
+	Category(ctx context.Context, id int64) (models.Category, error)
+	CategoryBySlug(ctx context.Context, slug string) (models.Category, error)
+	Categories(ctx context.Context, includeArchived bool) ([]models.Category, error)
+}
+
+// CreateCategory creates category under parentID, 0 parentID -> root category.
+// Caller must be admin.
+func (s *Service) CreateCategory(ctx context.Context, parentID int64, slug string, name string, callerID int64) (int64, error) {
+	const op = "service.CreateCategory"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.String("slug", slug))
+
+	log.Info("started category creation")
+
+	if err := s.requireAdmin(ctx, log, callerID); err != nil {
+		return -1, categoryError(op, err)
+	}
+
+	if parentID != 0 {
+		if err := s.usableCategory(ctx, log, parentID); err != nil {
+			return -1, categoryError(op, err)
+		}
+	}
+
+	id, err := s.categorySaver.SaveCategory(ctx, parentID, slug, name)
+	if err != nil {
+		if errors.Is(err, storage.ErrCategoryExists) {
+			log.Info("category already exists")
+			return -1, ErrCategoryExists
+		}
+		log.Error("failed to save category", ll.Err(err))
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("creation succeeded")
+	return id, nil
+}
+
+// MoveCategory makes parentID new parent of category, 0 parentID -> root category.
+// Caller must be admin.
+func (s *Service) MoveCategory(ctx context.Context, id int64, parentID int64, callerID int64) error {
+	const op = "service.MoveCategory"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("category_id", id))
+
+	log.Info("started category moving")
+
+	if err := s.requireAdmin(ctx, log, callerID); err != nil {
+		return categoryError(op, err)
+	}
+
+	if parentID != 0 {
+		if err := s.usableCategory(ctx, log, parentID); err != nil {
+			return categoryError(op, err)
+		}
+	}
+
+	if err := s.categorySaver.MoveCategory(ctx, id, parentID); err != nil {
+		if errors.Is(err, storage.ErrCategoryNotFound) {
+			log.Info("category not found")
+			return ErrCategoryNotFound
+		}
+		if errors.Is(err, storage.ErrCategoryCycle) {
+			log.Info("category moved into its subtree")
+			return ErrCategoryCycle
+		}
+		log.Error("failed to move category", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("moving succeeded")
+	return nil
+}
+
+// RenameCategory changes slug and display name of category. Caller must be admin.
+//
+// Nil pointer -> value is unchanged
+func (s *Service) RenameCategory(ctx context.Context, id int64, slug *string, name *string, callerID int64) error {
+	const op = "service.RenameCategory"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("category_id", id))
+
+	log.Info("started category renaming")
+
+	if err := s.requireAdmin(ctx, log, callerID); err != nil {
+		return categoryError(op, err)
+	}
+
+	if err := s.categorySaver.RenameCategory(ctx, id, slug, name); err != nil {
+		if errors.Is(err, storage.ErrCategoryNotFound) {
+			log.Info("category not found")
+			return ErrCategoryNotFound
+		}
+		if errors.Is(err, storage.ErrCategoryExists) {
+			log.Info("category already exists")
+			return ErrCategoryExists
+		}
+		log.Error("failed to rename category", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("renaming succeeded")
+	return nil
+}
+
+// NormalizeCategorySlugs fixes slugs of categories backfilled from free text
+// that can't be used through api
+func (s *Service) NormalizeCategorySlugs(ctx context.Context) error {
+	const op = "service.NormalizeCategorySlugs"
+
+	log := s.log.With(slog.String("op", op))
+
+	replaced, err := s.categorySaver.NormalizeCategorySlugs(ctx)
+	if err != nil {
+		log.Error("failed to normalize category slugs", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if replaced > 0 {
+		log.Info("normalized category slugs", slog.Int("count", replaced))
+	}
+
+	return nil
+}
+
+// ArchiveCategory archives category with all its subcategories. Caller must be admin.
+//
+// Listings of archived categories stay visible, but new listings can't be placed there.
+func (s *Service) ArchiveCategory(ctx context.Context, id int64, callerID int64) error {
+	const op = "service.ArchiveCategory"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("category_id", id))
+
+	log.Info("started category archiving")
+
+	if err := s.requireAdmin(ctx, log, callerID); err != nil {
+		return categoryError(op, err)
+	}
+
+	if err := s.categorySaver.ArchiveCategory(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrCategoryNotFound) {
+			log.Info("category not found")
+			return ErrCategoryNotFound
+		}
+		log.Error("failed to archive category", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("archiving succeeded")
+	return nil
+}
+
+// Categories returns all categories, parents go before their children
+func (s *Service) Categories(ctx context.Context, includeArchived bool) ([]models.Category, error) {
+	const op = "service.Categories"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("started categories getting")
+
+	categories, err := s.categoryProvider.Categories(ctx, includeArchived)
+	if err != nil {
+		log.Error("internal error", ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("getting succeeded")
+	return categories, nil
+}
+
+func (s *Service) CategoryBySlug(ctx context.Context, slug string) (models.Category, error) {
+	const op = "service.CategoryBySlug"
+
+	log := s.log.With(slog.String("op", op), slog.String("slug", slug))
+
+	category, err := s.categoryProvider.CategoryBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, storage.ErrCategoryNotFound) {
+			log.Info("category not found")
+			return category, ErrCategoryNotFound
+		}
+		log.Error("internal error", ll.Err(err))
+		return category, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return category, nil
+}
+
+// usableCategory checks that listings and subcategories can be placed into category
+func (s *Service) usableCategory(ctx context.Context, log *slog.Logger, id int64) error {
+	category, err := s.categoryProvider.Category(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrCategoryNotFound) {
+			log.Info("category not found", slog.Int64("category_id", id))
+			return ErrCategoryNotFound
+		}
+		log.Error("failed to get category", ll.Err(err))
+		return fmt.Errorf("failed to get category: %w", err)
+	}
+
+	if category.Archived {
+		log.Info("category is archived", slog.Int64("category_id", id))
+		return ErrCategoryArchived
+	}
+
+	return nil
+}
+
+// categoryError passes service errors through and wraps unexpected ones
+func categoryError(op string, err error) error {
+	for _, known := range []error{ErrNotEnoughPermissions, ErrCategoryNotFound, ErrCategoryArchived} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+type StockReserver interface {
+	ReserveStock(
+		ctx context.Context,
+		listingID int64,
+		variantID int64,
+		quantity int64,
+		holderID int64,
+		expiresAt time.Time,
+	) (int64, error)
+	CommitReservation(ctx context.Context, id int64) error
+	ReleaseReservation(ctx context.Context, id int64) error
+	ExpireReservations(ctx context.Context, now time.Time) (int64, error)
+}
+
+type ReservationProvider interface {
+	Reservation(ctx context.Context, id int64) (models.Reservation, error)
+	AvailableQuantity(ctx context.Context, listingID int64, variantID int64) (int64, error)
+}
+
+// ReserveStock holds quantity of listing (or its variant if variantID is not 0) for caller.
+// Reservation expires after ttl: 0 -> default ttl, ttl is capped by max ttl.
+func (s *Service) ReserveStock(
+	ctx context.Context,
+	listingID int64,
+	variantID int64,
+	quantity int64,
+	ttl time.Duration,
+	callerID int64,
+) (models.Reservation, error) {
+	const op = "service.ReserveStock"
+
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("caller_id", callerID),
+		slog.Int64("listing_id", listingID),
+		slog.Int64("variant_id", variantID),
+	)
+
+	log.Info("started stock reservation")
+
+	if ttl <= 0 {
+		ttl = s.reservationTTL
+	}
+	if ttl > s.maxReservationTTL {
+		ttl = s.maxReservationTTL
+	}
+
+	reservation := models.Reservation{
+		ListingID: listingID,
+		VariantID: variantID,
+		Quantity:  quantity,
+		HolderID:  callerID,
+		Status:    models.ReservationActive,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+
+	id, err := s.stock.ReserveStock(ctx, listingID, variantID, quantity, callerID, reservation.ExpiresAt)
+	if err != nil {
+		if mapped := storageReservationError(log, err); mapped != nil {
+			return reservation, mapped
+		}
+		log.Error("failed to reserve stock", ll.Err(err))
+		return reservation, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reservation.ID = id
+
+	log.Info("reservation succeeded", slog.Int64("reservation_id", id))
+	return reservation, nil
+}
+
+// CommitReservation takes reserved quantity from on-hand stock, caller must hold reservation
+func (s *Service) CommitReservation(ctx context.Context, id int64, callerID int64) error {
+	const op = "service.CommitReservation"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("reservation_id", id))
+
+	log.Info("started reservation commit")
+
+	if err := s.reservationHolder(ctx, log, id, callerID); err != nil {
+		return reservationError(op, err)
+	}
+
+	if err := s.stock.CommitReservation(ctx, id); err != nil {
+		if mapped := storageReservationError(log, err); mapped != nil {
+			return mapped
+		}
+		log.Error("failed to commit reservation", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("commit succeeded")
+	return nil
+}
+
+// ReleaseReservation returns reserved quantity to available stock, caller must hold reservation
+func (s *Service) ReleaseReservation(ctx context.Context, id int64, callerID int64) error {
+	const op = "service.ReleaseReservation"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("reservation_id", id))
+
+	log.Info("started reservation release")
+
+	if err := s.reservationHolder(ctx, log, id, callerID); err != nil {
+		return reservationError(op, err)
+	}
+
+	if err := s.stock.ReleaseReservation(ctx, id); err != nil {
+		if mapped := storageReservationError(log, err); mapped != nil {
+			return mapped
+		}
+		log.Error("failed to release reservation", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("release succeeded")
+	return nil
+}
+
+// SweepReservations marks expired reservations, so they no longer hold stock
+func (s *Service) SweepReservations(ctx context.Context) error {
+	const op = "service.SweepReservations"
+
+	log := s.log.With(slog.String("op", op))
+
+	count, err := s.stock.ExpireReservations(ctx, time.Now())
+	if err != nil {
+		log.Error("failed to expire reservations", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if count > 0 {
+		log.Info("expired reservations", slog.Int64("count", count))
+	}
+
+	return nil
+}
+
+func (s *Service) reservationHolder(ctx context.Context, log *slog.Logger, id int64, callerID int64) error {
+	reservation, err := s.reservations.Reservation(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrReservationNotFound) {
+			log.Info("reservation not found")
+			return ErrReservationNotFound
+		}
+		log.Error("failed to get reservation", ll.Err(err))
+		return fmt.Errorf("failed to get reservation: %w", err)
+	}
+
+	if reservation.HolderID != callerID {
+		log.Info("caller doesn't hold reservation")
+		return ErrNotEnoughPermissions
+	}
+
+	return nil
+}
+
+func storageReservationError(log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrListingNotFound):
+		log.Info("listing not found")
+		return ErrListingNotFound
+	case errors.Is(err, storage.ErrVariantNotFound):
+		log.Info("variant not found")
+		return ErrVariantNotFound
+	case errors.Is(err, storage.ErrListingClosed):
+		log.Info("listing is closed")
+		return ErrListingClosed
+	case errors.Is(err, storage.ErrInsufficientStock):
+		log.Info("not enough stock")
+		return ErrInsufficientStock
+	case errors.Is(err, storage.ErrReservationNotFound):
+		log.Info("reservation not found")
+		return ErrReservationNotFound
+	case errors.Is(err, storage.ErrReservationNotActive):
+		log.Info("reservation is not active")
+		return ErrReservationNotActive
+	}
+
+	return nil
+}
+
+// reservationError passes service errors through and wraps unexpected ones
+func reservationError(op string, err error) error {
+	for _, known := range []error{ErrNotEnoughPermissions, ErrReservationNotFound} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+// ListingSearcher is full-text search backend
+type ListingSearcher interface {
+	SearchListings(ctx context.Context, query models.SearchQuery) (models.SearchResult, error)
+}
+
+type searchCursor struct {
+	Text   string `json:"q"`
+	Offset int    `json:"o"`
+}
+
+// SearchListings returns page of listings matching text ordered by relevance
+// and token of the next page. Empty next page token means there are no more listings.
+func (s *Service) SearchListings(
+	ctx context.Context,
+	text string,
+	categoryID *int64,
+	includeClosed bool,
+	pageSize int,
+	pageToken string,
+) (models.SearchResult, string, error) {
+	const op = "service.SearchListings"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("started listings search")
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	var cursor searchCursor
+	if pageToken != "" {
+		if err := decodePageToken(pageToken, &cursor); err != nil || cursor.Text != text || cursor.Offset < 0 {
+			log.Info("invalid page token")
+			return models.SearchResult{}, "", ErrInvalidPageToken
+		}
+	}
+
+	result, err := s.searcher.SearchListings(ctx, models.SearchQuery{
+		Text:          text,
+		CategoryID:    categoryID,
+		IncludeClosed: includeClosed,
+		Offset:        cursor.Offset,
+		Limit:         pageSize,
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrEmptyQuery) {
+			log.Info("empty query")
+			return result, "", ErrEmptyQuery
+		}
+		log.Error("internal error", ll.Err(err))
+		return result, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var nextPageToken string
+	if next := cursor.Offset + len(result.Hits); len(result.Hits) > 0 && int64(next) < result.Total {
+		nextPageToken = encodePageToken(searchCursor{Text: text, Offset: next})
+	}
+
+	log.Info("search succeeded", slog.Int64("total", result.Total))
+	return result, nextPageToken, nil
+}
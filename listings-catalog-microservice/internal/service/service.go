@@ -5,18 +5,32 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"time"
 
-	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/jwt"
 	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
 	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/logger/ll"
 )
 
 var (
 	ErrUserNotFound         = errors.New("user not found")
 	ErrListingNotFound      = errors.New("listing not found")
 	ErrNotEnoughPermissions = errors.New("user is not authorized for this action")
-	ErrTokenExpired         = errors.New("token is expired")
-	ErrInvalidToken         = errors.New("token is invalid")
+	ErrInvalidPageToken     = errors.New("invalid page token")
+	ErrEmptyQuery           = errors.New("search query has no words")
+	ErrCategoryNotFound     = errors.New("category not found")
+	ErrCategoryExists       = errors.New("category with such slug already exists")
+	ErrCategoryArchived     = errors.New("category is archived")
+	ErrCategoryCycle        = errors.New("category can't be moved into its own subtree")
+	ErrVariantNotFound      = errors.New("variant not found")
+	ErrSKUExists            = errors.New("variant with such sku already exists")
+	ErrOptionsExist         = errors.New("variant with such options already exists")
+	ErrOptionsMismatch      = errors.New("variant options must have the same names as options of other variants")
+	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrReservationNotActive = errors.New("reservation is already committed, released or expired")
+	ErrInsufficientStock    = errors.New("not enough stock available")
+	ErrListingClosed        = errors.New("listing is closed")
+	ErrBelowReserved        = errors.New("quantity can't be less than quantity reserved by buyers")
 )
 
 type ListingSaver interface {
@@ -25,7 +39,7 @@ type ListingSaver interface {
 		title string,
 		description string,
 		quantity int64,
-		category string,
+		categoryID int64,
 		closed bool,
 		price int64,
 		creator int64,
@@ -38,7 +52,7 @@ type ListingSaver interface {
 		title *string,
 		description *string,
 		quantity *int64,
-		category *string,
+		categoryID *int64,
 		closed *bool,
 		price *int64,
 	) error
@@ -48,60 +62,100 @@ type ListingSaver interface {
 
 type ListingProvider interface {
 	Listing(ctx context.Context, id int64) (models.Listing, error)
+	Listings(
+		ctx context.Context,
+		filter models.ListingFilter,
+		sort models.ListingSort,
+		after *models.ListingCursor,
+		limit int,
+	) ([]models.Listing, error)
 }
 
 type Service struct {
-	log             *slog.Logger
-	productSaver    ListingSaver
-	productProvider ListingProvider
+	log              *slog.Logger
+	productSaver     ListingSaver
+	productProvider  ListingProvider
+	searcher         ListingSearcher
+	categorySaver    CategorySaver
+	categoryProvider CategoryProvider
+	variantSaver     VariantSaver
+	variantProvider  VariantProvider
+	stock            StockReserver
+	reservations     ReservationProvider
+	admins           AdminChecker
+	moderation       ModerationSaver
+
+	reservationTTL    time.Duration
+	maxReservationTTL time.Duration
 }
 
-func New(log *slog.Logger, productSaver ListingSaver, productProvider ListingProvider) *Service {
+func New(
+	log *slog.Logger,
+	productSaver ListingSaver,
+	productProvider ListingProvider,
+	searcher ListingSearcher,
+	categorySaver CategorySaver,
+	categoryProvider CategoryProvider,
+	variantSaver VariantSaver,
+	variantProvider VariantProvider,
+	stock StockReserver,
+	reservations ReservationProvider,
+	admins AdminChecker,
+	moderation ModerationSaver,
+	reservationTTL time.Duration,
+	maxReservationTTL time.Duration,
+) *Service {
 	return &Service{
-		log:             log,
-		productSaver:    productSaver,
-		productProvider: productProvider,
+		log:              log,
+		productSaver:     productSaver,
+		productProvider:  productProvider,
+		searcher:         searcher,
+		categorySaver:    categorySaver,
+		categoryProvider: categoryProvider,
+		variantSaver:     variantSaver,
+		variantProvider:  variantProvider,
+		stock:            stock,
+		reservations:     reservations,
+		admins:           admins,
+		moderation:       moderation,
+
+		reservationTTL:    reservationTTL,
+		maxReservationTTL: maxReservationTTL,
 	}
 }
 
+// CreateListing saves listing created by user with callerID
 func (s *Service) CreateListing(
 	ctx context.Context,
 	title string,
 	description string,
 	quantity int64,
-	category string,
+	categoryID int64,
 	closed bool,
 	price int64,
-	token string,
+	callerID int64,
 ) (int64, error) {
 	const op = "service.CreateListing"
 
-	log := s.log.With(slog.String("op", op))
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID))
 
 	log.Info("started listing creation")
 
-	tokenData, err := jwt.ParseToken(token)
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			log.Info("token expired")
-			return -1, ErrTokenExpired
+	if err := s.usableCategory(ctx, log, categoryID); err != nil {
+		if errors.Is(err, ErrCategoryNotFound) || errors.Is(err, ErrCategoryArchived) {
+			return -1, err
 		}
-		if errors.Is(err, jwt.ErrTokenInvalid) {
-			log.Info("token invalid: %s", err.Error())
-			return -1, ErrInvalidToken
-		}
-		log.Error("failed to parse token: %s", err.Error())
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
-	id, err := s.productSaver.SaveListing(ctx, title, description, quantity, category, closed, price, tokenData.ID)
+	id, err := s.productSaver.SaveListing(ctx, title, description, quantity, categoryID, closed, price, callerID)
 
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
 			log.Info("user not found")
 			return -1, ErrUserNotFound
 		}
-		log.Error("failed to find user: %s", err.Error())
+		log.Error("failed to save listing", ll.Err(err))
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -109,40 +163,30 @@ func (s *Service) CreateListing(
 	return id, nil
 }
 
-func (s *Service) DeleteListing(ctx context.Context, id int64, token string) error {
+// DeleteListing deletes listing together with its variants if caller is its creator or admin
+func (s *Service) DeleteListing(ctx context.Context, id int64, callerID int64) error {
 	const op = "service.DeleteListing"
 
-	log := s.log.With(slog.String("op", op))
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID))
 
 	log.Info("started listing deletion")
 
-	tokenData, err := jwt.ParseToken(token)
-	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			log.Info("token expired")
-			return ErrTokenExpired
-		}
-		if errors.Is(err, jwt.ErrTokenInvalid) {
-			log.Info("token invalid: %s", err.Error())
-			return ErrInvalidToken
-		}
-		log.Error("failed to parse token: %s", err.Error())
-		return fmt.Errorf("%s: %w", op, err)
-	}
-
 	listing, err := s.productProvider.Listing(ctx, id)
 	if err != nil {
 		if errors.Is(err, storage.ErrListingNotFound) {
 			log.Info("listing not found on get")
 			return ErrListingNotFound
 		}
-		log.Error("internal error: %s", err.Error())
+		log.Error("internal error", ll.Err(err))
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if listing.Creator != tokenData.ID {
-		log.Info("wrong user")
-		return ErrNotEnoughPermissions
+	moderated, err := s.authorizeModification(ctx, log, listing, callerID)
+	if err != nil {
+		if errors.Is(err, ErrNotEnoughPermissions) {
+			return err
+		}
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err := s.productSaver.DeleteListing(ctx, id); err != nil {
@@ -150,10 +194,14 @@ func (s *Service) DeleteListing(ctx context.Context, id int64, token string) err
 			log.Info("listing not found on delete")
 			return ErrListingNotFound
 		}
-		log.Error("internal error: %s", err.Error())
+		log.Error("internal error", ll.Err(err))
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if moderated {
+		s.recordModeration(ctx, log, id, callerID, models.ModerationDelete)
+	}
+
 	log.Info("deletion succeeded")
 	return nil
 }
@@ -171,7 +219,19 @@ func (s *Service) GetListing(ctx context.Context, id int64) (models.Listing, err
 			log.Info("listing not found on get")
 			return listing, ErrListingNotFound
 		}
-		log.Error("internal error: %s", err.Error())
+		log.Error("internal error", ll.Err(err))
+		return listing, fmt.Errorf("%s: %w", op, err)
+	}
+
+	listing.Variants, err = s.variantProvider.Variants(ctx, id)
+	if err != nil {
+		log.Error("failed to get variants", ll.Err(err))
+		return listing, fmt.Errorf("%s: %w", op, err)
+	}
+
+	listing.Available, err = s.reservations.AvailableQuantity(ctx, id, 0)
+	if err != nil {
+		log.Error("failed to get available quantity", ll.Err(err))
 		return listing, fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -179,6 +239,8 @@ func (s *Service) GetListing(ctx context.Context, id int64) (models.Listing, err
 	return listing, nil
 }
 
+// UpdateListing updates listing if caller is its creator or admin
+//
 // Nil pointer -> value is unchanged
 func (s *Service) UpdateListing(
 	ctx context.Context,
@@ -186,55 +248,62 @@ func (s *Service) UpdateListing(
 	title *string,
 	description *string,
 	quantity *int64,
-	category *string,
+	categoryID *int64,
 	closed *bool,
 	price *int64,
-	token string,
+	callerID int64,
 ) error {
 	const op = "service.UpdateListing"
 
-	log := s.log.With(slog.String("op", op))
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID))
 
 	log.Info("started listing updating")
 
-	tokenData, err := jwt.ParseToken(token)
+	listing, err := s.productProvider.Listing(ctx, id)
 	if err != nil {
-		if errors.Is(err, jwt.ErrTokenExpired) {
-			log.Info("token expired")
-			return ErrTokenExpired
-		}
-		if errors.Is(err, jwt.ErrTokenInvalid) {
-			log.Info("token invalid: %s", err.Error())
-			return ErrInvalidToken
+		if errors.Is(err, storage.ErrListingNotFound) {
+			log.Info("listing not found on get")
+			return ErrListingNotFound
 		}
-		log.Error("failed to parse token: %s", err.Error())
+		log.Error("internal error", ll.Err(err))
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	listing, err := s.productProvider.Listing(ctx, id)
+	moderated, err := s.authorizeModification(ctx, log, listing, callerID)
 	if err != nil {
-		if errors.Is(err, storage.ErrListingNotFound) {
-			log.Info("listing not found on get")
-			return ErrListingNotFound
+		if errors.Is(err, ErrNotEnoughPermissions) {
+			return err
 		}
-		log.Error("internal error: %s", err.Error())
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if listing.Creator != tokenData.ID {
-		log.Info("wrong user")
-		return ErrNotEnoughPermissions
+	// listing may stay in archived category, but can't be moved into one
+	if categoryID != nil && *categoryID != listing.CategoryID {
+		if err := s.usableCategory(ctx, log, *categoryID); err != nil {
+			if errors.Is(err, ErrCategoryNotFound) || errors.Is(err, ErrCategoryArchived) {
+				return err
+			}
+			return fmt.Errorf("%s: %w", op, err)
+		}
 	}
 
-	if err := s.productSaver.UpdateListing(ctx, id, title, description, quantity, category, closed, price); err != nil {
+	if err := s.productSaver.UpdateListing(ctx, id, title, description, quantity, categoryID, closed, price); err != nil {
 		if errors.Is(err, storage.ErrListingNotFound) {
-			log.Info("listing not found on delete")
+			log.Info("listing not found on update")
 			return ErrListingNotFound
 		}
-		log.Error("internal error: %s", err.Error())
+		if errors.Is(err, storage.ErrBelowReserved) {
+			log.Info("quantity is less than reserved")
+			return ErrBelowReserved
+		}
+		log.Error("internal error", ll.Err(err))
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
+	if moderated {
+		s.recordModeration(ctx, log, id, callerID, models.ModerationUpdate)
+	}
+
 	log.Info("update succeeded")
 	return nil
 }
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+type VariantSaver interface {
+	SaveVariant(
+		ctx context.Context,
+		listingID int64,
+		sku string,
+		options map[string]string,
+		price int64,
+		quantity int64,
+	) (int64, error)
+
+	// Nil pointer or nil options -> value is unchanged
+	UpdateVariant(
+		ctx context.Context,
+		id int64,
+		sku *string,
+		options map[string]string,
+		price *int64,
+		quantity *int64,
+	) error
+
+	DeleteVariant(ctx context.Context, id int64) error
+}
+
+type VariantProvider interface {
+	Variant(ctx context.Context, id int64) (models.Variant, error)
+	Variants(ctx context.Context, listingID int64) ([]models.Variant, error)
+}
+
+// CreateVariant adds variant to listing if caller is its creator or admin.
+//
+// All variants of listing must have the same option names
+// and no two variants may have the same option values.
+func (s *Service) CreateVariant(
+	ctx context.Context,
+	listingID int64,
+	sku string,
+	options map[string]string,
+	price int64,
+	quantity int64,
+	callerID int64,
+) (int64, error) {
+	const op = "service.CreateVariant"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("listing_id", listingID))
+
+	log.Info("started variant creation")
+
+	moderated, err := s.modifiableListing(ctx, log, listingID, callerID)
+	if err != nil {
+		return -1, variantError(op, err)
+	}
+
+	if err := s.sameOptionNames(ctx, log, listingID, 0, options); err != nil {
+		return -1, variantError(op, err)
+	}
+
+	id, err := s.variantSaver.SaveVariant(ctx, listingID, sku, options, price, quantity)
+	if err != nil {
+		if mapped := storageVariantError(log, err); mapped != nil {
+			return -1, mapped
+		}
+		log.Error("failed to save variant", ll.Err(err))
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if moderated {
+		s.recordModeration(ctx, log, listingID, callerID, models.ModerationUpdate)
+	}
+
+	log.Info("creation succeeded")
+	return id, nil
+}
+
+// UpdateVariant updates variant if caller is creator of its listing or admin
+//
+// Nil pointer or nil options -> value is unchanged
+func (s *Service) UpdateVariant(
+	ctx context.Context,
+	id int64,
+	sku *string,
+	options map[string]string,
+	price *int64,
+	quantity *int64,
+	callerID int64,
+) error {
+	const op = "service.UpdateVariant"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("variant_id", id))
+
+	log.Info("started variant updating")
+
+	variant, err := s.variant(ctx, log, id)
+	if err != nil {
+		return variantError(op, err)
+	}
+
+	moderated, err := s.modifiableListing(ctx, log, variant.ListingID, callerID)
+	if err != nil {
+		return variantError(op, err)
+	}
+
+	if options != nil {
+		if err := s.sameOptionNames(ctx, log, variant.ListingID, id, options); err != nil {
+			return variantError(op, err)
+		}
+	}
+
+	if err := s.variantSaver.UpdateVariant(ctx, id, sku, options, price, quantity); err != nil {
+		if mapped := storageVariantError(log, err); mapped != nil {
+			return mapped
+		}
+		log.Error("failed to update variant", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if moderated {
+		s.recordModeration(ctx, log, variant.ListingID, callerID, models.ModerationUpdate)
+	}
+
+	log.Info("update succeeded")
+	return nil
+}
+
+// DeleteVariant deletes variant if caller is creator of its listing or admin
+func (s *Service) DeleteVariant(ctx context.Context, id int64, callerID int64) error {
+	const op = "service.DeleteVariant"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("variant_id", id))
+
+	log.Info("started variant deletion")
+
+	variant, err := s.variant(ctx, log, id)
+	if err != nil {
+		return variantError(op, err)
+	}
+
+	moderated, err := s.modifiableListing(ctx, log, variant.ListingID, callerID)
+	if err != nil {
+		return variantError(op, err)
+	}
+
+	if err := s.variantSaver.DeleteVariant(ctx, id); err != nil {
+		if mapped := storageVariantError(log, err); mapped != nil {
+			return mapped
+		}
+		log.Error("failed to delete variant", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if moderated {
+		s.recordModeration(ctx, log, variant.ListingID, callerID, models.ModerationUpdate)
+	}
+
+	log.Info("deletion succeeded")
+	return nil
+}
+
+// VariantMatrix returns option names of variants with all their values, both sorted
+func VariantMatrix(variants []models.Variant) []models.VariantOption {
+	values := make(map[string]map[string]struct{})
+	for _, variant := range variants {
+		for name, value := range variant.Options {
+			if values[name] == nil {
+				values[name] = make(map[string]struct{})
+			}
+			values[name][value] = struct{}{}
+		}
+	}
+
+	matrix := make([]models.VariantOption, 0, len(values))
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		matrix = append(matrix, models.VariantOption{
+			Name:   name,
+			Values: slices.Sorted(maps.Keys(values[name])),
+		})
+	}
+
+	return matrix
+}
+
+func (s *Service) variant(ctx context.Context, log *slog.Logger, id int64) (models.Variant, error) {
+	variant, err := s.variantProvider.Variant(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrVariantNotFound) {
+			log.Info("variant not found")
+			return variant, ErrVariantNotFound
+		}
+		log.Error("failed to get variant", ll.Err(err))
+		return variant, fmt.Errorf("failed to get variant: %w", err)
+	}
+
+	return variant, nil
+}
+
+// modifiableListing checks that caller may modify listing.
+// Returns true if caller acts as admin on someone else's listing.
+func (s *Service) modifiableListing(ctx context.Context, log *slog.Logger, listingID int64, callerID int64) (bool, error) {
+	listing, err := s.productProvider.Listing(ctx, listingID)
+	if err != nil {
+		if errors.Is(err, storage.ErrListingNotFound) {
+			log.Info("listing not found on get")
+			return false, ErrListingNotFound
+		}
+		log.Error("failed to get listing", ll.Err(err))
+		return false, fmt.Errorf("failed to get listing: %w", err)
+	}
+
+	return s.authorizeModification(ctx, log, listing, callerID)
+}
+
+// sameOptionNames checks that options have the same names as options
+// of other variants of listing. Variant with skipID is not compared against.
+func (s *Service) sameOptionNames(ctx context.Context, log *slog.Logger, listingID int64, skipID int64, options map[string]string) error {
+	variants, err := s.variantProvider.Variants(ctx, listingID)
+	if err != nil {
+		log.Error("failed to get variants", ll.Err(err))
+		return fmt.Errorf("failed to get variants: %w", err)
+	}
+
+	names := slices.Sorted(maps.Keys(options))
+	for _, variant := range variants {
+		if variant.ID == skipID {
+			continue
+		}
+
+		if !slices.Equal(names, slices.Sorted(maps.Keys(variant.Options))) {
+			log.Info("option names differ from other variants")
+			return ErrOptionsMismatch
+		}
+	}
+
+	return nil
+}
+
+func storageVariantError(log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrVariantNotFound):
+		log.Info("variant not found")
+		return ErrVariantNotFound
+	case errors.Is(err, storage.ErrSKUExists):
+		log.Info("sku already exists")
+		return ErrSKUExists
+	case errors.Is(err, storage.ErrOptionsExist):
+		log.Info("options already exist")
+		return ErrOptionsExist
+	case errors.Is(err, storage.ErrBelowReserved):
+		log.Info("quantity is less than reserved")
+		return ErrBelowReserved
+	}
+
+	return nil
+}
+
+// variantError passes service errors through and wraps unexpected ones
+func variantError(op string, err error) error {
+	for _, known := range []error{ErrNotEnoughPermissions, ErrListingNotFound, ErrVariantNotFound, ErrOptionsMismatch} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,36 @@
+// Package slug validates and builds url-friendly category identifiers
+package slug
+
+import (
+	"regexp"
+	"strings"
+)
+
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Valid reports whether s consists of lowercase letters, digits and single dashes
+func Valid(s string) bool {
+	return slugRegexp.MatchString(s)
+}
+
+// Make turns free text into slug: letters are lowercased, runs of anything
+// but ascii letters and digits become single dash. Text without ascii letters
+// and digits gives empty string. Make of valid slug is the slug itself.
+func Make(text string) string {
+	var b strings.Builder
+
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package slug_test
+
+import (
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/slug"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		{text: "lamps", expected: "lamps"},
+		{text: "home-garden", expected: "home-garden"},
+		{text: "Home Garden", expected: "home-garden"},
+		{text: "  Home   Garden ", expected: "home-garden"},
+		{text: "Home & Garden!", expected: "home-garden"},
+		{text: "home_garden", expected: "home-garden"},
+		{text: "--home--garden--", expected: "home-garden"},
+		{text: "Café 24/7", expected: "caf-24-7"},
+		{text: "Книги", expected: ""},
+		{text: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			made := slug.Make(tt.text)
+			assert.Equal(t, tt.expected, made)
+
+			if made != "" {
+				assert.True(t, slug.Valid(made))
+				assert.Equal(t, made, slug.Make(made))
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, valid := range []string{"a", "lamps", "home-garden", "24-7"} {
+		assert.True(t, slug.Valid(valid), valid)
+	}
+
+	for _, invalid := range []string{"", "Lamps", "home--garden", "-home", "home-", "home_garden", "home garden", "café"} {
+		assert.False(t, slug.Valid(invalid), invalid)
+	}
+}
@@ -0,0 +1,116 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+)
+
+// Listings returns at most limit listings matching filter ordered by sort.
+// If after is not nil, listings are returned starting right after it.
+func (s *Storage) Listings(
+	ctx context.Context,
+	filter models.ListingFilter,
+	sort models.ListingSort,
+	after *models.ListingCursor,
+	limit int,
+) ([]models.Listing, error) {
+	const op = "storage.sqlite.Listings"
+
+	var (
+		conds []string
+		args  []any
+	)
+
+	if filter.CategoryID != nil {
+		conds = append(conds, "l.category_id IN ("+subtreeQuery+")")
+		args = append(args, *filter.CategoryID)
+	}
+	if filter.MinPrice != nil {
+		conds = append(conds, "l.price >= ?")
+		args = append(args, *filter.MinPrice)
+	}
+	if filter.MaxPrice != nil {
+		conds = append(conds, "l.price <= ?")
+		args = append(args, *filter.MaxPrice)
+	}
+	if filter.Creator != nil {
+		conds = append(conds, "l.creator = ?")
+		args = append(args, *filter.Creator)
+	}
+	if filter.Closed != nil {
+		conds = append(conds, "l.closed = ?")
+		args = append(args, *filter.Closed)
+	}
+	if filter.InStock {
+		// available the same way ReserveStock counts it: on hand minus active reservations
+		conds = append(conds, `l.quantity > (
+			SELECT COALESCE(SUM(r.quantity), 0)
+			FROM reservations r
+			WHERE r.listing_id = l.id AND r.variant_id = 0 AND r.status = 'active' AND r.expires_at > ?
+		)`)
+		args = append(args, time.Now().Unix())
+	}
+
+	var order string
+	switch sort {
+	case models.SortNewest:
+		order = "l.created_at DESC, l.id DESC"
+		if after != nil {
+			conds = append(conds, "(l.created_at, l.id) < (?, ?)")
+			args = append(args, after.CreatedAt, after.ID)
+		}
+	case models.SortPriceAsc:
+		order = "l.price ASC, l.id ASC"
+		if after != nil {
+			conds = append(conds, "(l.price, l.id) > (?, ?)")
+			args = append(args, after.Price, after.ID)
+		}
+	case models.SortPriceDesc:
+		order = "l.price DESC, l.id DESC"
+		if after != nil {
+			conds = append(conds, "(l.price, l.id) < (?, ?)")
+			args = append(args, after.Price, after.ID)
+		}
+	case models.SortTitle:
+		order = "l.title ASC, l.id ASC"
+		if after != nil {
+			conds = append(conds, "(l.title, l.id) > (?, ?)")
+			args = append(args, after.Title, after.ID)
+		}
+	default:
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrUnknownSort)
+	}
+
+	query := "SELECT " + listingColumns + " FROM " + listingTables
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY " + order + " LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	listings := make([]models.Listing, 0, limit)
+	for rows.Next() {
+		listing, err := scanListing(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		listings = append(listings, listing)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return listings, nil
+}
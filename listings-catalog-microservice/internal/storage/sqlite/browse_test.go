@@ -0,0 +1,164 @@
+//go:build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listingIDs(listings []models.Listing) []int64 {
+	ids := make([]int64, 0, len(listings))
+	for _, listing := range listings {
+		ids = append(ids, listing.ID)
+	}
+	return ids
+}
+
+// browseAll walks every page of listings and returns their ids in order
+func browseAll(t *testing.T, st *sqlite.Storage, filter models.ListingFilter, sort models.ListingSort, limit int) []int64 {
+	t.Helper()
+
+	var (
+		ids   []int64
+		after *models.ListingCursor
+	)
+
+	for {
+		page, err := st.Listings(context.Background(), filter, sort, after, limit)
+		require.NoError(t, err)
+
+		ids = append(ids, listingIDs(page)...)
+		if len(page) < limit {
+			return ids
+		}
+
+		last := page[len(page)-1]
+		after = &models.ListingCursor{
+			Sort:      sort,
+			ID:        last.ID,
+			Price:     last.Price,
+			CreatedAt: last.CreatedAt.Unix(),
+			Title:     last.Title,
+		}
+	}
+}
+
+func TestListings_SortsAndPages(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	save := func(title string, price int64) int64 {
+		id, err := st.SaveListing(ctx, title, title, 1, miscCategory, false, price, creator)
+		require.NoError(t, err)
+		return id
+	}
+
+	// equal prices and titles are ordered by id
+	b := save("b", 300)
+	a := save("a", 100)
+	c := save("c", 200)
+	a2 := save("a", 200)
+	d := save("d", 100)
+
+	tests := []struct {
+		sort models.ListingSort
+		ids  []int64
+	}{
+		// created in the same second, so newest falls back to id
+		{sort: models.SortNewest, ids: []int64{d, a2, c, a, b}},
+		{sort: models.SortPriceAsc, ids: []int64{a, d, c, a2, b}},
+		{sort: models.SortPriceDesc, ids: []int64{b, a2, c, d, a}},
+		{sort: models.SortTitle, ids: []int64{a, a2, b, c, d}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.sort), func(t *testing.T) {
+			for _, limit := range []int{1, 2, 5, 10} {
+				assert.Equal(t, tt.ids, browseAll(t, st, models.ListingFilter{}, tt.sort, limit), "limit %d", limit)
+			}
+		})
+	}
+
+	_, err := st.Listings(ctx, models.ListingFilter{}, "random", nil, 10)
+	require.ErrorIs(t, err, storage.ErrUnknownSort)
+}
+
+func TestListings_Filters(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	lighting, err := st.SaveCategory(ctx, 0, "lighting", "Lighting")
+	require.NoError(t, err)
+	lamps, err := st.SaveCategory(ctx, lighting, "lamps", "Lamps")
+	require.NoError(t, err)
+
+	lamp, err := st.SaveListing(ctx, "lamp", "lamp", 3, lamps, false, 150, creator)
+	require.NoError(t, err)
+	soldOut, err := st.SaveListing(ctx, "sold out lamp", "lamp", 0, lighting, false, 50, creator)
+	require.NoError(t, err)
+	closedID, err := st.SaveListing(ctx, "chair", "chair", 1, miscCategory, true, 300, creator)
+	require.NoError(t, err)
+	otherCreator, err := st.SaveListing(ctx, "table", "table", 1, miscCategory, false, 200, creator+1)
+	require.NoError(t, err)
+
+	price := func(p int64) *int64 { return &p }
+	open, closed := false, true
+	me := int64(creator)
+
+	tests := []struct {
+		name   string
+		filter models.ListingFilter
+		ids    []int64
+	}{
+		{name: "none", ids: []int64{lamp, soldOut, closedID, otherCreator}},
+		{name: "category with subcategories", filter: models.ListingFilter{CategoryID: &lighting}, ids: []int64{lamp, soldOut}},
+		{name: "leaf category", filter: models.ListingFilter{CategoryID: &lamps}, ids: []int64{lamp}},
+		{name: "min price", filter: models.ListingFilter{MinPrice: price(150)}, ids: []int64{lamp, closedID, otherCreator}},
+		{name: "max price", filter: models.ListingFilter{MaxPrice: price(150)}, ids: []int64{lamp, soldOut}},
+		{name: "price range", filter: models.ListingFilter{MinPrice: price(100), MaxPrice: price(200)}, ids: []int64{lamp, otherCreator}},
+		{name: "creator", filter: models.ListingFilter{Creator: &me}, ids: []int64{lamp, soldOut, closedID}},
+		{name: "open", filter: models.ListingFilter{Closed: &open}, ids: []int64{lamp, soldOut, otherCreator}},
+		{name: "closed", filter: models.ListingFilter{Closed: &closed}, ids: []int64{closedID}},
+		{name: "in stock", filter: models.ListingFilter{InStock: true, Closed: &open}, ids: []int64{lamp, otherCreator}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ElementsMatch(t, tt.ids, browseAll(t, st, tt.filter, models.SortPriceAsc, 2))
+		})
+	}
+}
+
+func TestListings_InStockCountsReservations(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	reserve := func(id int64, quantity int64, expiresAt time.Time) int64 {
+		reservationID, err := st.ReserveStock(ctx, id, 0, quantity, holder, expiresAt)
+		require.NoError(t, err)
+		return reservationID
+	}
+
+	free := saveListing(t, st, "free", 2)
+	partly := saveListing(t, st, "partly reserved", 2)
+	reserve(partly, 1, time.Now().Add(time.Minute))
+	fully := saveListing(t, st, "fully reserved", 2)
+	reserve(fully, 2, time.Now().Add(time.Minute))
+	expired := saveListing(t, st, "reservation expired", 1)
+	reserve(expired, 1, time.Now().Add(-time.Minute))
+	released := saveListing(t, st, "reservation released", 1)
+	require.NoError(t, st.ReleaseReservation(ctx, reserve(released, 1, time.Now().Add(time.Minute))))
+	committed := saveListing(t, st, "reservation committed", 1)
+	require.NoError(t, st.CommitReservation(ctx, reserve(committed, 1, time.Now().Add(time.Minute))))
+
+	filter := models.ListingFilter{InStock: true}
+	assert.ElementsMatch(t, []int64{free, partly, expired, released}, browseAll(t, st, filter, models.SortNewest, 2))
+}
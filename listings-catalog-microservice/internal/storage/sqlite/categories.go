@@ -0,0 +1,310 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/slug"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+)
+
+// subtreeQuery selects ids of category passed as parameter and all its descendants
+const subtreeQuery = `
+	WITH RECURSIVE subtree(id) AS (
+		SELECT ?
+		UNION
+		SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+	)
+	SELECT id FROM subtree`
+
+const categoryColumns = "id, COALESCE(parent_id, 0), slug, name, archived"
+
+func scanCategory(row scanner) (models.Category, error) {
+	var category models.Category
+
+	err := row.Scan(&category.ID, &category.ParentID, &category.Slug, &category.Name, &category.Archived)
+
+	return category, err
+}
+
+// nullableParent maps root parent id 0 to NULL
+func nullableParent(parentID int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: parentID, Valid: parentID != 0}
+}
+
+// SaveCategory saves category under parentID, 0 parentID -> root category
+func (s *Storage) SaveCategory(ctx context.Context, parentID int64, slug string, name string) (int64, error) {
+	const op = "storage.sqlite.SaveCategory"
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO categories(parent_id, slug, name)
+		VALUES (?, ?, ?)
+	`, nullableParent(parentID), slug, name)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return -1, storage.ErrCategoryExists
+		}
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) Category(ctx context.Context, id int64) (models.Category, error) {
+	const op = "storage.sqlite.Category"
+
+	category, err := scanCategory(s.db.QueryRowContext(ctx, `
+		SELECT `+categoryColumns+`
+		FROM categories
+		WHERE id = ?
+	`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return category, storage.ErrCategoryNotFound
+		}
+		return category, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return category, nil
+}
+
+func (s *Storage) CategoryBySlug(ctx context.Context, slug string) (models.Category, error) {
+	const op = "storage.sqlite.CategoryBySlug"
+
+	category, err := scanCategory(s.db.QueryRowContext(ctx, `
+		SELECT `+categoryColumns+`
+		FROM categories
+		WHERE slug = ?
+	`, slug))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return category, storage.ErrCategoryNotFound
+		}
+		return category, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return category, nil
+}
+
+// Categories returns all categories ordered so that parents go before children
+func (s *Storage) Categories(ctx context.Context, includeArchived bool) ([]models.Category, error) {
+	const op = "storage.sqlite.Categories"
+
+	rows, err := s.db.QueryContext(ctx, `
+		WITH RECURSIVE tree(id, depth) AS (
+			SELECT id, 0 FROM categories WHERE parent_id IS NULL
+			UNION ALL
+			SELECT c.id, t.depth + 1 FROM categories c JOIN tree t ON c.parent_id = t.id
+		)
+		SELECT `+categoryColumns+`
+		FROM categories JOIN tree USING (id)
+		WHERE ? OR archived = FALSE
+		ORDER BY depth, name, id
+	`, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		category, err := scanCategory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		categories = append(categories, category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return categories, nil
+}
+
+// MoveCategory makes parentID new parent of category, 0 parentID -> root category
+func (s *Storage) MoveCategory(ctx context.Context, id int64, parentID int64) error {
+	const op = "storage.sqlite.MoveCategory"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if parentID != 0 {
+		var inSubtree bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (`+subtreeQuery+` WHERE id = ?)
+		`, id, parentID).Scan(&inSubtree)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if inSubtree {
+			return storage.ErrCategoryCycle
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE categories
+		SET parent_id = ?
+		WHERE id = ?
+	`, nullableParent(parentID), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := categoryAffected(op, res); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Nil pointer -> value is unchanged
+func (s *Storage) RenameCategory(ctx context.Context, id int64, slug *string, name *string) error {
+	const op = "storage.sqlite.RenameCategory"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE categories
+		SET
+			slug = COALESCE(?, slug),
+			name = COALESCE(?, name)
+		WHERE id = ?
+	`, slug, name, id)
+	if err != nil {
+		var sqliteErr sqlite3.Error
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return storage.ErrCategoryExists
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return categoryAffected(op, res)
+}
+
+// NormalizeCategorySlugs replaces slugs that are not valid with slug.Make of them.
+// Categories backfilled from free text could get such slugs. Taken slug gets id
+// of category appended, text without ascii letters and digits gives "category-<id>".
+// Returns number of replaced slugs.
+func (s *Storage) NormalizeCategorySlugs(ctx context.Context) (int, error) {
+	const op = "storage.sqlite.NormalizeCategorySlugs"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id, slug FROM categories ORDER BY id`)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	invalid := make(map[int64]string)
+	var ids []int64
+	for rows.Next() {
+		var (
+			id      int64
+			current string
+		)
+
+		if err := rows.Scan(&id, &current); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !slug.Valid(current) {
+			invalid[id] = current
+			ids = append(ids, id)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, id := range ids {
+		candidates := []string{fmt.Sprintf("category-%d", id)}
+		if made := slug.Make(invalid[id]); made != "" {
+			candidates = []string{made, fmt.Sprintf("%s-%d", made, id), candidates[0]}
+		}
+
+		replaced := false
+		for _, candidate := range candidates {
+			res, err := tx.ExecContext(ctx, `
+				UPDATE categories
+				SET slug = ?
+				WHERE id = ? AND NOT EXISTS (SELECT 1 FROM categories WHERE slug = ?)
+			`, candidate, id, candidate)
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", op, err)
+			}
+
+			rowsAffected, err := res.RowsAffected()
+			if err != nil {
+				return 0, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if rowsAffected != 0 {
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			return 0, fmt.Errorf("%s: no free slug for category %d", op, id)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return len(ids), nil
+}
+
+// ArchiveCategory archives category together with all its descendants
+func (s *Storage) ArchiveCategory(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.ArchiveCategory"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE categories
+		SET archived = TRUE
+		WHERE id IN (`+subtreeQuery+`)
+	`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return categoryAffected(op, res)
+}
+
+func categoryAffected(op string, res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return storage.ErrCategoryNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,151 @@
+//go:build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategories_Tree(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	home, err := st.SaveCategory(ctx, 0, "home", "Home")
+	require.NoError(t, err)
+	lighting, err := st.SaveCategory(ctx, home, "lighting", "Lighting")
+	require.NoError(t, err)
+	lamps, err := st.SaveCategory(ctx, lighting, "lamps", "Lamps")
+	require.NoError(t, err)
+
+	_, err = st.SaveCategory(ctx, 0, "lamps", "Other lamps")
+	require.ErrorIs(t, err, storage.ErrCategoryExists)
+
+	category, err := st.CategoryBySlug(ctx, "lamps")
+	require.NoError(t, err)
+	assert.Equal(t, models.Category{ID: lamps, ParentID: lighting, Slug: "lamps", Name: "Lamps"}, category)
+
+	_, err = st.Category(ctx, lamps+1)
+	require.ErrorIs(t, err, storage.ErrCategoryNotFound)
+
+	// parents go before children
+	categories, err := st.Categories(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"home", "misc", "lighting", "lamps"}, slugs(categories))
+
+	// category can't become descendant of itself
+	require.ErrorIs(t, st.MoveCategory(ctx, home, lamps), storage.ErrCategoryCycle)
+	require.ErrorIs(t, st.MoveCategory(ctx, home, home), storage.ErrCategoryCycle)
+
+	require.NoError(t, st.MoveCategory(ctx, lamps, 0))
+	category, err = st.Category(ctx, lamps)
+	require.NoError(t, err)
+	assert.Zero(t, category.ParentID)
+
+	require.NoError(t, st.MoveCategory(ctx, lighting, lamps))
+	require.ErrorIs(t, st.MoveCategory(ctx, lamps+1, 0), storage.ErrCategoryNotFound)
+}
+
+func TestRenameCategory(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id, err := st.SaveCategory(ctx, 0, "lamps", "Lamps")
+	require.NoError(t, err)
+
+	name := "Lights"
+	require.NoError(t, st.RenameCategory(ctx, id, nil, &name))
+
+	category, err := st.Category(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "lamps", category.Slug)
+	assert.Equal(t, "Lights", category.Name)
+
+	taken := "misc"
+	require.ErrorIs(t, st.RenameCategory(ctx, id, &taken, nil), storage.ErrCategoryExists)
+	require.ErrorIs(t, st.RenameCategory(ctx, id+1, nil, &name), storage.ErrCategoryNotFound)
+}
+
+func TestArchiveCategory_ArchivesSubtree(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	home, err := st.SaveCategory(ctx, 0, "home", "Home")
+	require.NoError(t, err)
+	lighting, err := st.SaveCategory(ctx, home, "lighting", "Lighting")
+	require.NoError(t, err)
+	_, err = st.SaveCategory(ctx, lighting, "lamps", "Lamps")
+	require.NoError(t, err)
+	_, err = st.SaveCategory(ctx, home, "chairs", "Chairs")
+	require.NoError(t, err)
+
+	require.NoError(t, st.ArchiveCategory(ctx, lighting))
+
+	categories, err := st.Categories(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"home", "misc", "chairs"}, slugs(categories))
+
+	categories, err = st.Categories(ctx, true)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"home", "misc", "chairs", "lighting", "lamps"}, slugs(categories))
+
+	for _, category := range categories {
+		assert.Equal(t, category.Slug == "lighting" || category.Slug == "lamps", category.Archived, category.Slug)
+	}
+
+	require.ErrorIs(t, st.ArchiveCategory(ctx, 100), storage.ErrCategoryNotFound)
+}
+
+func slugs(categories []models.Category) []string {
+	slugs := make([]string, 0, len(categories))
+	for _, category := range categories {
+		slugs = append(slugs, category.Slug)
+	}
+	return slugs
+}
+
+func TestNormalizeCategorySlugs(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	// slugs migration 5 could backfill from free-text categories
+	save := func(slug string) int64 {
+		id, err := st.SaveCategory(ctx, 0, slug, slug)
+		require.NoError(t, err)
+		return id
+	}
+	lamps := save("lamps")
+	punctuation := save("home-&-garden!")
+	doubleSpace := save("home--garden")
+	underscore := save("kids_toys")
+	nonASCII := save("книги")
+
+	replaced, err := st.NormalizeCategorySlugs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 4, replaced)
+
+	expected := map[int64]string{
+		miscCategory: "misc",
+		lamps:        "lamps",
+		punctuation:  "home-garden",
+		// taken slug gets id appended
+		doubleSpace: fmt.Sprintf("home-garden-%d", doubleSpace),
+		underscore:  "kids-toys",
+		nonASCII:    fmt.Sprintf("category-%d", nonASCII),
+	}
+	for id, slug := range expected {
+		category, err := st.Category(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, slug, category.Slug)
+	}
+
+	replaced, err = st.NormalizeCategorySlugs(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, replaced)
+}
@@ -0,0 +1,291 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+)
+
+// reservedQuantity sums active unexpired reservations of stock.
+// Parameters: listing id, variant id, current unix time.
+const reservedQuantity = `
+	SELECT COALESCE(SUM(quantity), 0)
+	FROM reservations
+	WHERE listing_id = ? AND variant_id = ? AND status = 'active' AND expires_at > ?`
+
+// onHandQuantity selects on-hand quantity of open listing or variant of open listing.
+// Parameters: variant id, listing id, variant id.
+const onHandQuantity = `
+	SELECT CASE WHEN ? = 0 THEN l.quantity ELSE v.quantity END
+	FROM listings l
+	LEFT JOIN variants v ON v.listing_id = l.id AND v.id = ?
+	WHERE l.id = ? AND l.closed = FALSE AND (? = 0 OR v.id IS NOT NULL)`
+
+// ReserveStock holds quantity of listing stock (or its variant stock if variantID is not 0)
+// until expiresAt. Check of available stock and reservation happen in a single statement,
+// so concurrent reservations can't oversell.
+func (s *Storage) ReserveStock(
+	ctx context.Context,
+	listingID int64,
+	variantID int64,
+	quantity int64,
+	holderID int64,
+	expiresAt time.Time,
+) (int64, error) {
+	const op = "storage.sqlite.ReserveStock"
+
+	now := time.Now().Unix()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO reservations(listing_id, variant_id, quantity, holder_id, status, created_at, expires_at)
+		SELECT ?, ?, ?, ?, 'active', ?, ?
+		WHERE (`+onHandQuantity+`) - (`+reservedQuantity+`) >= ?
+	`,
+		listingID, variantID, quantity, holderID, now, expiresAt.Unix(),
+		variantID, variantID, listingID, variantID,
+		listingID, variantID, now,
+		quantity,
+	)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return -1, s.reserveFailure(ctx, op, listingID, variantID)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// reserveFailure finds out why stock wasn't reserved
+func (s *Storage) reserveFailure(ctx context.Context, op string, listingID int64, variantID int64) error {
+	var closed bool
+	err := s.db.QueryRowContext(ctx, `SELECT closed FROM listings WHERE id = ?`, listingID).Scan(&closed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrListingNotFound
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if closed {
+		return storage.ErrListingClosed
+	}
+
+	if variantID != 0 {
+		var exists bool
+		err := s.db.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM variants WHERE id = ? AND listing_id = ?)
+		`, variantID, listingID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !exists {
+			return storage.ErrVariantNotFound
+		}
+	}
+
+	return storage.ErrInsufficientStock
+}
+
+func (s *Storage) Reservation(ctx context.Context, id int64) (models.Reservation, error) {
+	const op = "storage.sqlite.Reservation"
+
+	var (
+		reservation models.Reservation
+		expiresAt   int64
+	)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, listing_id, variant_id, quantity, holder_id, status, expires_at
+		FROM reservations
+		WHERE id = ?
+	`, id).Scan(
+		&reservation.ID, &reservation.ListingID, &reservation.VariantID, &reservation.Quantity,
+		&reservation.HolderID, &reservation.Status, &expiresAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reservation, storage.ErrReservationNotFound
+		}
+		return reservation, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reservation.ExpiresAt = time.Unix(expiresAt, 0)
+
+	return reservation, nil
+}
+
+// CommitReservation turns active reservation into sale: reserved quantity is
+// taken from on-hand stock. Fails with storage.ErrInsufficientStock if stock has less than that.
+func (s *Storage) CommitReservation(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.CommitReservation"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var (
+		listingID int64
+		variantID int64
+		quantity  int64
+	)
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE reservations
+		SET status = 'committed'
+		WHERE id = ? AND status = 'active' AND expires_at > ?
+		RETURNING listing_id, variant_id, quantity
+	`, id, time.Now().Unix()).Scan(&listingID, &variantID, &quantity)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return s.reservationFailure(ctx, op, tx, id)
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// stock never goes negative, even if on-hand quantity was lowered below reserved somehow
+	var res sql.Result
+	if variantID == 0 {
+		res, err = tx.ExecContext(ctx, `
+			UPDATE listings SET quantity = quantity - ? WHERE id = ? AND quantity >= ?
+		`, quantity, listingID, quantity)
+	} else {
+		res, err = tx.ExecContext(ctx, `
+			UPDATE variants SET quantity = quantity - ? WHERE id = ? AND quantity >= ?
+		`, quantity, variantID, quantity)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return storage.ErrInsufficientStock
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseReservation returns reserved quantity back to available stock
+func (s *Storage) ReleaseReservation(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.ReleaseReservation"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE reservations
+		SET status = 'released'
+		WHERE id = ? AND status = 'active'
+	`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return s.reservationFailure(ctx, op, s.db, id)
+	}
+
+	return nil
+}
+
+// ExpireReservations marks active reservations expired before now and returns their count
+func (s *Storage) ExpireReservations(ctx context.Context, now time.Time) (int64, error) {
+	const op = "storage.sqlite.ExpireReservations"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE reservations
+		SET status = 'expired'
+		WHERE status = 'active' AND expires_at <= ?
+	`, now.Unix())
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return count, nil
+}
+
+// AvailableQuantity returns on-hand quantity of listing (or its variant if variantID is not 0)
+// minus quantity held by active reservations
+func (s *Storage) AvailableQuantity(ctx context.Context, listingID int64, variantID int64) (int64, error) {
+	const op = "storage.sqlite.AvailableQuantity"
+
+	var available int64
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT MAX(
+			CASE WHEN ? = 0 THEN l.quantity ELSE v.quantity END - (`+reservedQuantity+`),
+			0
+		)
+		FROM listings l
+		LEFT JOIN variants v ON v.listing_id = l.id AND v.id = ?
+		WHERE l.id = ? AND (? = 0 OR v.id IS NOT NULL)
+	`, variantID, listingID, variantID, time.Now().Unix(), variantID, listingID, variantID).Scan(&available)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if variantID != 0 {
+				return 0, storage.ErrVariantNotFound
+			}
+			return 0, storage.ErrListingNotFound
+		}
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return available, nil
+}
+
+// reservationFailure finds out why reservation couldn't change its status
+func (s *Storage) reservationFailure(ctx context.Context, op string, q querier, id int64) error {
+	var exists bool
+
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS (SELECT 1 FROM reservations WHERE id = ?)
+	`, id).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !exists {
+		return storage.ErrReservationNotFound
+	}
+
+	return storage.ErrReservationNotActive
+}
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
@@ -0,0 +1,256 @@
+//go:build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const holder = 2
+
+func TestCommitReservation(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "lamp", 5)
+
+	reservationID, err := st.ReserveStock(ctx, id, 0, 3, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	require.NoError(t, st.CommitReservation(ctx, reservationID))
+
+	listing, err := st.Listing(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), listing.Quantity)
+
+	available, err := st.AvailableQuantity(ctx, id, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), available)
+
+	require.ErrorIs(t, st.CommitReservation(ctx, reservationID), storage.ErrReservationNotActive)
+	require.ErrorIs(t, st.CommitReservation(ctx, reservationID+100), storage.ErrReservationNotFound)
+}
+
+func TestCommitReservation_StockNeverGoesNegative(t *testing.T) {
+	st, db := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "lamp", 5)
+
+	reservationID, err := st.ReserveStock(ctx, id, 0, 3, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	// stock lowered behind storage's back
+	_, err = db.Exec(`UPDATE listings SET quantity = 2 WHERE id = ?`, id)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, st.CommitReservation(ctx, reservationID), storage.ErrInsufficientStock)
+
+	listing, err := st.Listing(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), listing.Quantity)
+
+	// failed commit leaves reservation active
+	reservation, err := st.Reservation(ctx, reservationID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationActive, reservation.Status)
+}
+
+func TestUpdateListing_QuantityBelowReserved(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "lamp", 5)
+
+	reservationID, err := st.ReserveStock(ctx, id, 0, 3, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	// expired reservation holds nothing
+	_, err = st.ReserveStock(ctx, id, 0, 2, holder, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+
+	quantity := int64(2)
+	require.ErrorIs(t, st.UpdateListing(ctx, id, nil, nil, &quantity, nil, nil, nil), storage.ErrBelowReserved)
+
+	// fields other than quantity can still be changed
+	title := "desk lamp"
+	require.NoError(t, st.UpdateListing(ctx, id, &title, nil, nil, nil, nil, nil))
+
+	quantity = 3
+	require.NoError(t, st.UpdateListing(ctx, id, nil, nil, &quantity, nil, nil, nil))
+
+	require.NoError(t, st.ReleaseReservation(ctx, reservationID))
+
+	quantity = 0
+	require.NoError(t, st.UpdateListing(ctx, id, nil, nil, &quantity, nil, nil, nil))
+}
+
+func TestUpdateVariant_QuantityBelowReserved(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "shirt", 0)
+
+	variantID, err := st.SaveVariant(ctx, id, "shirt-m", map[string]string{"size": "M"}, 100, 5)
+	require.NoError(t, err)
+
+	// reservation of listing stock doesn't hold variant stock
+	_, err = st.ReserveStock(ctx, id, 0, 1, holder, time.Now().Add(time.Minute))
+	require.ErrorIs(t, err, storage.ErrInsufficientStock)
+
+	_, err = st.ReserveStock(ctx, id, variantID, 4, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	quantity := int64(3)
+	require.ErrorIs(t, st.UpdateVariant(ctx, variantID, nil, nil, nil, &quantity), storage.ErrBelowReserved)
+
+	quantity = 4
+	require.NoError(t, st.UpdateVariant(ctx, variantID, nil, nil, nil, &quantity))
+
+	require.ErrorIs(t, st.UpdateVariant(ctx, variantID+100, nil, nil, nil, &quantity), storage.ErrVariantNotFound)
+}
+
+func TestReserveStock_ConcurrentReservationsNeverOversell(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	const (
+		stock  = 5
+		buyers = 20
+	)
+
+	id := saveListing(t, st, "lamp", stock)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		reserved int
+	)
+
+	for range buyers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := st.ReserveStock(ctx, id, 0, 1, holder, time.Now().Add(time.Minute))
+			if err != nil {
+				assert.ErrorIs(t, err, storage.ErrInsufficientStock)
+				return
+			}
+
+			mu.Lock()
+			reserved++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, stock, reserved)
+
+	available, err := st.AvailableQuantity(ctx, id, 0)
+	require.NoError(t, err)
+	assert.Zero(t, available)
+}
+
+func TestReserveStock_Expiry(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "lamp", 5)
+
+	expiring, err := st.ReserveStock(ctx, id, 0, 4, holder, time.Now().Add(-time.Second))
+	require.NoError(t, err)
+
+	// reservation past its expiry holds nothing even before sweeper marks it
+	available, err := st.AvailableQuantity(ctx, id, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), available)
+
+	active, err := st.ReserveStock(ctx, id, 0, 5, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	expired, err := st.ExpireReservations(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+
+	reservation, err := st.Reservation(ctx, expiring)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationExpired, reservation.Status)
+
+	reservation, err = st.Reservation(ctx, active)
+	require.NoError(t, err)
+	assert.Equal(t, models.ReservationActive, reservation.Status)
+
+	require.ErrorIs(t, st.CommitReservation(ctx, expiring), storage.ErrReservationNotActive)
+	require.ErrorIs(t, st.ReleaseReservation(ctx, expiring), storage.ErrReservationNotActive)
+
+	// nothing left to expire
+	expired, err = st.ExpireReservations(ctx, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, expired)
+}
+
+func TestReleaseReservation(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "lamp", 5)
+
+	reservationID, err := st.ReserveStock(ctx, id, 0, 5, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+
+	_, err = st.ReserveStock(ctx, id, 0, 1, holder, time.Now().Add(time.Minute))
+	require.ErrorIs(t, err, storage.ErrInsufficientStock)
+
+	require.NoError(t, st.ReleaseReservation(ctx, reservationID))
+	require.ErrorIs(t, st.ReleaseReservation(ctx, reservationID), storage.ErrReservationNotActive)
+	require.ErrorIs(t, st.ReleaseReservation(ctx, reservationID+100), storage.ErrReservationNotFound)
+
+	available, err := st.AvailableQuantity(ctx, id, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), available)
+}
+
+func TestReserveStock_Fails(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "lamp", 5)
+
+	closedID := saveListing(t, st, "closed lamp", 5)
+	closed := true
+	require.NoError(t, st.UpdateListing(ctx, closedID, nil, nil, nil, nil, &closed, nil))
+
+	otherID := saveListing(t, st, "shirt", 0)
+	otherVariant, err := st.SaveVariant(ctx, otherID, "shirt-m", map[string]string{"size": "M"}, 100, 5)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		listingID int64
+		variantID int64
+		quantity  int64
+		err       error
+	}{
+		{name: "unknown listing", listingID: id + 100, quantity: 1, err: storage.ErrListingNotFound},
+		{name: "closed listing", listingID: closedID, quantity: 1, err: storage.ErrListingClosed},
+		{name: "unknown variant", listingID: id, variantID: otherVariant + 100, quantity: 1, err: storage.ErrVariantNotFound},
+		{name: "variant of other listing", listingID: id, variantID: otherVariant, quantity: 1, err: storage.ErrVariantNotFound},
+		{name: "more than stock", listingID: id, quantity: 6, err: storage.ErrInsufficientStock},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.ReserveStock(ctx, tt.listingID, tt.variantID, tt.quantity, holder, time.Now().Add(time.Minute))
+			require.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+)
+
+// Matches in title weigh more than matches in description
+const searchRank = "bm25(listings_fts, 10.0, 1.0)"
+
+// SearchListings finds listings by words in title and description using FTS5.
+//
+// Requires sqlite built with "sqlite_fts5" tag.
+func (s *Storage) SearchListings(ctx context.Context, query models.SearchQuery) (models.SearchResult, error) {
+	const op = "storage.sqlite.SearchListings"
+
+	var result models.SearchResult
+
+	match := ftsQuery(query.Text)
+	if match == "" {
+		return result, storage.ErrEmptyQuery
+	}
+
+	conds := []string{"listings_fts MATCH ?"}
+	args := []any{match}
+	if !query.IncludeClosed {
+		conds = append(conds, "l.closed = FALSE")
+	}
+
+	facetConds := strings.Join(conds, " AND ")
+	facetArgs := append([]any(nil), args...)
+
+	if query.CategoryID != nil {
+		conds = append(conds, "l.category_id IN ("+subtreeQuery+")")
+		args = append(args, *query.CategoryID)
+	}
+	where := strings.Join(conds, " AND ")
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+listingColumns+`,
+		       highlight(listings_fts, 0, ?, ?),
+		       snippet(listings_fts, 1, ?, ?, '…', 16),
+		       `+searchRank+`
+		FROM listings_fts
+		JOIN listings l ON l.id = listings_fts.rowid
+		JOIN categories c ON c.id = l.category_id
+		WHERE `+where+`
+		ORDER BY `+searchRank+`, l.id
+		LIMIT ? OFFSET ?
+	`, append(append([]any{
+		models.HighlightStart, models.HighlightEnd,
+		models.HighlightStart, models.HighlightEnd,
+	}, args...), query.Limit, query.Offset)...)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			hit       models.SearchHit
+			createdAt int64
+			rank      float64
+		)
+
+		err := rows.Scan(
+			&hit.Listing.ID, &hit.Listing.Title, &hit.Listing.Description, &hit.Listing.Quantity,
+			&hit.Listing.CategoryID, &hit.Listing.Category, &hit.Listing.Closed, &hit.Listing.Price, &hit.Listing.Creator, &createdAt,
+			&hit.TitleHighlight, &hit.DescriptionSnippet, &rank,
+		)
+		if err != nil {
+			return result, fmt.Errorf("%s: %w", op, err)
+		}
+
+		hit.Listing.CreatedAt = time.Unix(createdAt, 0)
+		// bm25 is negative, more relevant rows have smaller values
+		hit.Score = -rank
+
+		result.Hits = append(result.Hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*)
+		FROM listings_fts
+		JOIN listings l ON l.id = listings_fts.rowid
+		WHERE `+where, args...).Scan(&result.Total)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	facets, err := tx.QueryContext(ctx, `
+		SELECT c.id, c.slug, COUNT(*) AS cnt
+		FROM listings_fts
+		JOIN listings l ON l.id = listings_fts.rowid
+		JOIN categories c ON c.id = l.category_id
+		WHERE `+facetConds+`
+		GROUP BY c.id
+		ORDER BY cnt DESC, c.slug
+	`, facetArgs...)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+	defer facets.Close()
+
+	for facets.Next() {
+		var facet models.CategoryFacet
+		if err := facets.Scan(&facet.CategoryID, &facet.Category, &facet.Count); err != nil {
+			return result, fmt.Errorf("%s: %w", op, err)
+		}
+		result.Categories = append(result.Categories, facet)
+	}
+	if err := facets.Err(); err != nil {
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result, nil
+}
+
+// ftsQuery turns user input into FTS5 query matching every word as prefix.
+// Everything except letters and digits is dropped, so input can't inject FTS5 syntax.
+func ftsQuery(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		terms = append(terms, `"`+word+`"*`)
+	}
+
+	return strings.Join(terms, " ")
+}
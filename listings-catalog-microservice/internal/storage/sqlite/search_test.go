@@ -0,0 +1,151 @@
+//go:build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func search(t *testing.T, st *sqlite.Storage, query models.SearchQuery) models.SearchResult {
+	t.Helper()
+
+	if query.Limit == 0 {
+		query.Limit = 10
+	}
+
+	result, err := st.SearchListings(context.Background(), query)
+	require.NoError(t, err)
+
+	return result
+}
+
+func hitIDs(result models.SearchResult) []int64 {
+	ids := make([]int64, 0, len(result.Hits))
+	for _, hit := range result.Hits {
+		ids = append(ids, hit.Listing.ID)
+	}
+	return ids
+}
+
+func TestSearchListings_EscapesSyntax(t *testing.T) {
+	st, _ := newStorage(t)
+
+	lamp := saveListing(t, st, "desk lamp", 1)
+	saveListing(t, st, "office chair", 1)
+
+	// input is user text, FTS5 operators and quotes in it are not syntax
+	tests := []struct {
+		name  string
+		query string
+		ids   []int64
+	}{
+		{name: "plain", query: "lamp", ids: []int64{lamp}},
+		{name: "prefix", query: "la", ids: []int64{lamp}},
+		{name: "unbalanced quote", query: `"lamp`, ids: []int64{lamp}},
+		{name: "quotes inside word", query: `la"mp`, ids: []int64{}},
+		{name: "OR is a word", query: "lamp OR chair", ids: []int64{}},
+		{name: "NOT is a word", query: "desk NOT lamp", ids: []int64{}},
+		{name: "NEAR group", query: "NEAR(desk lamp)", ids: []int64{}},
+		{name: "column filter", query: "title:lamp", ids: []int64{}},
+		{name: "column filter of description", query: "description:lamp", ids: []int64{lamp}},
+		{name: "star", query: "lamp*", ids: []int64{lamp}},
+		{name: "caret", query: "^desk", ids: []int64{lamp}},
+		{name: "minus", query: "-chair lamp", ids: []int64{}},
+		{name: "parentheses", query: "(desk) (lamp", ids: []int64{lamp}},
+		{name: "plus and braces", query: "{desk} + lamp", ids: []int64{lamp}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := search(t, st, models.SearchQuery{Text: tt.query})
+			assert.Equal(t, tt.ids, hitIDs(result))
+			assert.Equal(t, int64(len(tt.ids)), result.Total)
+		})
+	}
+}
+
+func TestSearchListings_EmptyQuery(t *testing.T) {
+	st, _ := newStorage(t)
+
+	for _, query := range []string{"", "   ", `"*"`, "- ^ : ( )"} {
+		_, err := st.SearchListings(context.Background(), models.SearchQuery{Text: query, Limit: 10})
+		require.ErrorIs(t, err, storage.ErrEmptyQuery, query)
+	}
+}
+
+func TestSearchListings_RankingAndHighlight(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	inDescription, err := st.SaveListing(ctx, "table", "wooden table with a lamp stand", 1, miscCategory, false, 100, creator)
+	require.NoError(t, err)
+	inTitle, err := st.SaveListing(ctx, "lamp", "bright and small", 1, miscCategory, false, 100, creator)
+	require.NoError(t, err)
+
+	result := search(t, st, models.SearchQuery{Text: "lamp"})
+	require.Equal(t, []int64{inTitle, inDescription}, hitIDs(result))
+	assert.Greater(t, result.Hits[0].Score, result.Hits[1].Score)
+
+	assert.Equal(t, "<mark>lamp</mark>", result.Hits[0].TitleHighlight)
+	assert.Contains(t, result.Hits[1].DescriptionSnippet, "<mark>lamp</mark>")
+}
+
+func TestSearchListings_Filters(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	lighting, err := st.SaveCategory(ctx, 0, "lighting", "Lighting")
+	require.NoError(t, err)
+	lamps, err := st.SaveCategory(ctx, lighting, "lamps", "Lamps")
+	require.NoError(t, err)
+
+	inLamps, err := st.SaveListing(ctx, "red lamp", "lamp", 1, lamps, false, 100, creator)
+	require.NoError(t, err)
+	inMisc := saveListing(t, st, "blue lamp", 1)
+	closedID := saveListing(t, st, "old lamp", 1)
+	closed := true
+	require.NoError(t, st.UpdateListing(ctx, closedID, nil, nil, nil, nil, &closed, nil))
+
+	result := search(t, st, models.SearchQuery{Text: "lamp"})
+	assert.ElementsMatch(t, []int64{inLamps, inMisc}, hitIDs(result))
+
+	result = search(t, st, models.SearchQuery{Text: "lamp", IncludeClosed: true})
+	assert.ElementsMatch(t, []int64{inLamps, inMisc, closedID}, hitIDs(result))
+
+	// category filter includes subcategories, facets ignore it
+	result = search(t, st, models.SearchQuery{Text: "lamp", CategoryID: &lighting})
+	assert.Equal(t, []int64{inLamps}, hitIDs(result))
+	assert.Equal(t, []models.CategoryFacet{
+		{CategoryID: lamps, Category: "lamps", Count: 1},
+		{CategoryID: miscCategory, Category: "misc", Count: 1},
+	}, result.Categories)
+
+	// changed listing is found by its new text only
+	title, description := "green lantern", "lantern"
+	require.NoError(t, st.UpdateListing(ctx, inMisc, &title, &description, nil, nil, nil, nil))
+	assert.Equal(t, []int64{inMisc}, hitIDs(search(t, st, models.SearchQuery{Text: "lantern"})))
+	assert.Empty(t, hitIDs(search(t, st, models.SearchQuery{Text: "blue"})))
+}
+
+func TestSearchListings_Pages(t *testing.T) {
+	st, _ := newStorage(t)
+
+	for range 5 {
+		saveListing(t, st, "lamp", 1)
+	}
+
+	first := search(t, st, models.SearchQuery{Text: "lamp", Limit: 3})
+	second := search(t, st, models.SearchQuery{Text: "lamp", Limit: 3, Offset: 3})
+
+	assert.Len(t, first.Hits, 3)
+	assert.Len(t, second.Hits, 2)
+	assert.Equal(t, int64(5), first.Total)
+	assert.NotContains(t, hitIDs(first), second.Hits[0].Listing.ID)
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
+
 	"github.com/mattn/go-sqlite3"
 
 	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
@@ -37,7 +39,7 @@ func (s *Storage) SaveListing(
 	title string,
 	description string,
 	quantity int64,
-	category string,
+	categoryID int64,
 	closed bool,
 	price int64,
 	creator int64,
@@ -45,9 +47,9 @@ func (s *Storage) SaveListing(
 	const op = "storage.sqlite.SaveListing"
 
 	res, err := s.db.ExecContext(ctx, `
-		INSERT INTO listings(title, description, quantity, category, closed, price, creator)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`, title, description, quantity, category, closed, price, creator)
+		INSERT INTO listings(title, description, quantity, category_id, closed, price, creator, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, title, description, quantity, categoryID, closed, price, creator, time.Now().Unix())
 
 	if err != nil {
 		var sqliteErr sqlite3.Error
@@ -68,13 +70,11 @@ func (s *Storage) SaveListing(
 func (s *Storage) Listing(ctx context.Context, id int64) (models.Listing, error) {
 	const op = "storage.sqlite.Listing"
 
-	var prod models.Listing
-
-	err := s.db.QueryRowContext(ctx, `
-		SELECT title, description, quantity, category, closed, price, creator
-		FROM listings
-		WHERE id = ?
-	`, id).Scan(prod.Title, prod.Description, prod.Quantity, prod.Category, prod.Closed, prod.Price, prod.Creator)
+	prod, err := scanListing(s.db.QueryRowContext(ctx, `
+		SELECT `+listingColumns+`
+		FROM `+listingTables+`
+		WHERE l.id = ?
+	`, id))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -87,6 +87,27 @@ func (s *Storage) Listing(ctx context.Context, id int64) (models.Listing, error)
 	return prod, nil
 }
 
+const (
+	listingColumns = "l.id, l.title, l.description, l.quantity, l.category_id, c.slug, l.closed, l.price, l.creator, l.created_at"
+	listingTables  = "listings l JOIN categories c ON c.id = l.category_id"
+)
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanListing(row scanner) (models.Listing, error) {
+	var (
+		prod      models.Listing
+		createdAt int64
+	)
+
+	err := row.Scan(&prod.ID, &prod.Title, &prod.Description, &prod.Quantity, &prod.CategoryID, &prod.Category, &prod.Closed, &prod.Price, &prod.Creator, &createdAt)
+	prod.CreatedAt = time.Unix(createdAt, 0)
+
+	return prod, err
+}
+
 // Nil pointer -> value is unchanged
 func (s *Storage) UpdateListing(
 	ctx context.Context,
@@ -94,23 +115,25 @@ func (s *Storage) UpdateListing(
 	title *string,
 	description *string,
 	quantity *int64,
-	category *string,
+	categoryID *int64,
 	closed *bool,
 	price *int64,
 ) error {
 	const op = "storage.sqlite.UpdateListing"
 
+	// quantity can't go below what active reservations hold, or committing them oversells
 	res, err := s.db.ExecContext(ctx, `
         UPDATE listings
         SET 
             title = COALESCE(?, title),
             description = COALESCE(?, description),
             quantity = COALESCE(?, quantity),
-            category = COALESCE(?, category),
+            category_id = COALESCE(?, category_id),
             closed = COALESCE(?, closed),
             price = COALESCE(?, price)
-        WHERE id = ?
-    `, title, description, quantity, category, closed, price, id)
+        WHERE id = ? AND (? IS NULL OR ? >= (`+reservedQuantity+`))
+    `, title, description, quantity, categoryID, closed, price, id,
+		quantity, quantity, id, 0, time.Now().Unix())
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -121,7 +144,16 @@ func (s *Storage) UpdateListing(
 	}
 
 	if rowsAffected == 0 {
-		return storage.ErrListingNotFound
+		var exists bool
+		err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM listings WHERE id = ?)`, id).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !exists {
+			return storage.ErrListingNotFound
+		}
+		return storage.ErrBelowReserved
 	}
 
 	return nil
@@ -149,3 +181,17 @@ func (s *Storage) DeleteListing(ctx context.Context, id int64) error {
 
 	return nil
 }
+
+func (s *Storage) SaveModerationAction(ctx context.Context, listingID int64, adminID int64, action string) error {
+	const op = "storage.sqlite.SaveModerationAction"
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO moderation_actions(listing_id, admin_id, action, created_at)
+		VALUES (?, ?, ?, ?)
+	`, listingID, adminID, action, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
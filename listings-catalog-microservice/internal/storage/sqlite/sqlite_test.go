@@ -0,0 +1,103 @@
+//go:build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	creator = 1
+	// root category every fresh storage has
+	miscCategory = 1
+)
+
+// newStorage returns storage over fresh in-memory database with every migration applied
+// and connection to the same database for changes storage doesn't make
+func newStorage(t *testing.T) (*sqlite.Storage, *sql.DB) {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared&_busy_timeout=5000", strings.ReplaceAll(t.Name(), "/", "_"))
+
+	// in-memory database lives while at least one connection is open
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	for _, path := range migrations(t) {
+		migration, err := os.ReadFile(path)
+		require.NoError(t, err)
+
+		_, err = db.Exec(string(migration))
+		require.NoError(t, err, path)
+	}
+
+	st, err := sqlite.New(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Stop() })
+
+	id, err := st.SaveCategory(context.Background(), 0, "misc", "Misc")
+	require.NoError(t, err)
+	require.Equal(t, int64(miscCategory), id)
+
+	return st, db
+}
+
+// migrations returns up migrations sorted by their number
+func migrations(t *testing.T) []string {
+	t.Helper()
+
+	paths, err := filepath.Glob("../../../migrations/*.up.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	number := func(path string) int {
+		n, err := strconv.Atoi(strings.SplitN(filepath.Base(path), "_", 2)[0])
+		require.NoError(t, err, path)
+		return n
+	}
+	sort.Slice(paths, func(i, j int) bool { return number(paths[i]) < number(paths[j]) })
+
+	return paths
+}
+
+// saveListing saves open listing of creator in misc category with given quantity
+func saveListing(t *testing.T, st *sqlite.Storage, title string, quantity int64) int64 {
+	t.Helper()
+
+	id, err := st.SaveListing(context.Background(), title, title+" description", quantity, miscCategory, false, 100, creator)
+	require.NoError(t, err)
+
+	return id
+}
+
+func TestUpdateListing(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	id := saveListing(t, st, "lamp", 5)
+
+	title, quantity := "desk lamp", int64(7)
+	require.NoError(t, st.UpdateListing(ctx, id, &title, nil, &quantity, nil, nil, nil))
+
+	listing, err := st.Listing(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "desk lamp", listing.Title)
+	assert.Equal(t, "lamp description", listing.Description)
+	assert.Equal(t, int64(7), listing.Quantity)
+
+	require.ErrorIs(t, st.UpdateListing(ctx, id+100, &title, nil, nil, nil, nil, nil), storage.ErrListingNotFound)
+}
@@ -0,0 +1,232 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+)
+
+// variantColumns has one parameter: current unix time
+const variantColumns = `v.id, v.listing_id, v.sku, v.options, v.price, v.quantity,
+	MAX(v.quantity - (
+		SELECT COALESCE(SUM(r.quantity), 0)
+		FROM reservations r
+		WHERE r.listing_id = v.listing_id AND r.variant_id = v.id AND r.status = 'active' AND r.expires_at > ?
+	), 0)`
+
+func scanVariant(row scanner) (models.Variant, error) {
+	var (
+		variant models.Variant
+		options string
+	)
+
+	err := row.Scan(&variant.ID, &variant.ListingID, &variant.SKU, &options, &variant.Price, &variant.Quantity, &variant.Available)
+	if err != nil {
+		return variant, err
+	}
+
+	err = json.Unmarshal([]byte(options), &variant.Options)
+
+	return variant, err
+}
+
+// encodeOptions returns canonical text of options: json sorts map keys
+func encodeOptions(options map[string]string) (string, error) {
+	data, err := json.Marshal(options)
+	return string(data), err
+}
+
+// variantConflict maps unique constraint violations to storage errors
+func variantConflict(err error) error {
+	var sqliteErr sqlite3.Error
+	if !errors.As(err, &sqliteErr) || sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique {
+		return nil
+	}
+
+	if strings.Contains(sqliteErr.Error(), "variants.sku") {
+		return storage.ErrSKUExists
+	}
+
+	return storage.ErrOptionsExist
+}
+
+func (s *Storage) SaveVariant(
+	ctx context.Context,
+	listingID int64,
+	sku string,
+	options map[string]string,
+	price int64,
+	quantity int64,
+) (int64, error) {
+	const op = "storage.sqlite.SaveVariant"
+
+	encoded, err := encodeOptions(options)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO variants(listing_id, sku, options, price, quantity)
+		VALUES (?, ?, ?, ?, ?)
+	`, listingID, sku, encoded, price, quantity)
+	if err != nil {
+		if conflict := variantConflict(err); conflict != nil {
+			return -1, conflict
+		}
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) Variant(ctx context.Context, id int64) (models.Variant, error) {
+	const op = "storage.sqlite.Variant"
+
+	variant, err := scanVariant(s.db.QueryRowContext(ctx, `
+		SELECT `+variantColumns+`
+		FROM variants v
+		WHERE v.id = ?
+	`, time.Now().Unix(), id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return variant, storage.ErrVariantNotFound
+		}
+		return variant, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return variant, nil
+}
+
+// Variants returns all variants of listing ordered by id
+func (s *Storage) Variants(ctx context.Context, listingID int64) ([]models.Variant, error) {
+	const op = "storage.sqlite.Variants"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+variantColumns+`
+		FROM variants v
+		WHERE v.listing_id = ?
+		ORDER BY v.id
+	`, time.Now().Unix(), listingID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var variants []models.Variant
+	for rows.Next() {
+		variant, err := scanVariant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		variants = append(variants, variant)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return variants, nil
+}
+
+// Nil pointer or nil options -> value is unchanged
+func (s *Storage) UpdateVariant(
+	ctx context.Context,
+	id int64,
+	sku *string,
+	options map[string]string,
+	price *int64,
+	quantity *int64,
+) error {
+	const op = "storage.sqlite.UpdateVariant"
+
+	var encoded *string
+	if options != nil {
+		text, err := encodeOptions(options)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		encoded = &text
+	}
+
+	// quantity can't go below what active reservations hold, or committing them oversells
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE variants
+		SET
+			sku = COALESCE(?, sku),
+			options = COALESCE(?, options),
+			price = COALESCE(?, price),
+			quantity = COALESCE(?, quantity)
+		WHERE id = ? AND (? IS NULL OR ? >= (
+			SELECT COALESCE(SUM(r.quantity), 0)
+			FROM reservations r
+			WHERE r.listing_id = variants.listing_id AND r.variant_id = variants.id
+				AND r.status = 'active' AND r.expires_at > ?
+		))
+	`, sku, encoded, price, quantity, id, quantity, quantity, time.Now().Unix())
+	if err != nil {
+		if conflict := variantConflict(err); conflict != nil {
+			return conflict
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := variantAffected(op, res); err != nil {
+		if !errors.Is(err, storage.ErrVariantNotFound) {
+			return err
+		}
+
+		var exists bool
+		err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM variants WHERE id = ?)`, id).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !exists {
+			return storage.ErrVariantNotFound
+		}
+		return storage.ErrBelowReserved
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteVariant(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteVariant"
+
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM variants
+		WHERE id = ?
+	`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return variantAffected(op, res)
+}
+
+func variantAffected(op string, res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return storage.ErrVariantNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+//go:build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/listings-catalog-microservice/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSaveVariant_Conflicts(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	shirt := saveListing(t, st, "shirt", 0)
+	hoodie := saveListing(t, st, "hoodie", 0)
+
+	_, err := st.SaveVariant(ctx, shirt, "SHIRT-XL-RED", map[string]string{"size": "XL", "color": "red"}, 100, 1)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		listingID int64
+		sku       string
+		options   map[string]string
+		err       error
+	}{
+		{name: "same sku", listingID: shirt, sku: "SHIRT-XL-RED", options: map[string]string{"size": "L"}, err: storage.ErrSKUExists},
+		{name: "same sku of other listing", listingID: hoodie, sku: "SHIRT-XL-RED", options: map[string]string{"size": "L"}, err: storage.ErrSKUExists},
+		// options are compared regardless of order they were given in
+		{name: "same options", listingID: shirt, sku: "OTHER", options: map[string]string{"color": "red", "size": "XL"}, err: storage.ErrOptionsExist},
+		{name: "same options of other listing", listingID: hoodie, sku: "HOODIE-XL-RED", options: map[string]string{"size": "XL", "color": "red"}},
+		{name: "other options", listingID: shirt, sku: "SHIRT-L-RED", options: map[string]string{"size": "L", "color": "red"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.SaveVariant(ctx, tt.listingID, tt.sku, tt.options, 100, 1)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+
+	variants, err := st.Variants(ctx, shirt)
+	require.NoError(t, err)
+	require.Len(t, variants, 2)
+	assert.Equal(t, map[string]string{"size": "XL", "color": "red"}, variants[0].Options)
+}
+
+func TestUpdateVariant(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	shirt := saveListing(t, st, "shirt", 0)
+	xl, err := st.SaveVariant(ctx, shirt, "XL", map[string]string{"size": "XL"}, 100, 1)
+	require.NoError(t, err)
+	l, err := st.SaveVariant(ctx, shirt, "L", map[string]string{"size": "L"}, 100, 1)
+	require.NoError(t, err)
+
+	price := int64(150)
+	require.NoError(t, st.UpdateVariant(ctx, xl, nil, map[string]string{"size": "XXL"}, &price, nil))
+
+	variant, err := st.Variant(ctx, xl)
+	require.NoError(t, err)
+	assert.Equal(t, "XL", variant.SKU)
+	assert.Equal(t, map[string]string{"size": "XXL"}, variant.Options)
+	assert.Equal(t, int64(150), variant.Price)
+	assert.Equal(t, int64(1), variant.Quantity)
+
+	sku := "L"
+	require.ErrorIs(t, st.UpdateVariant(ctx, xl, &sku, nil, nil, nil), storage.ErrSKUExists)
+	require.ErrorIs(t, st.UpdateVariant(ctx, xl, nil, map[string]string{"size": "L"}, nil, nil), storage.ErrOptionsExist)
+	require.ErrorIs(t, st.UpdateVariant(ctx, l+1, nil, nil, &price, nil), storage.ErrVariantNotFound)
+
+	require.NoError(t, st.DeleteVariant(ctx, l))
+	_, err = st.Variant(ctx, l)
+	require.ErrorIs(t, err, storage.ErrVariantNotFound)
+	require.ErrorIs(t, st.DeleteVariant(ctx, l), storage.ErrVariantNotFound)
+}
+
+func TestVariant_Available(t *testing.T) {
+	st, _ := newStorage(t)
+	ctx := context.Background()
+
+	shirt := saveListing(t, st, "shirt", 0)
+	xl, err := st.SaveVariant(ctx, shirt, "XL", map[string]string{"size": "XL"}, 100, 5)
+	require.NoError(t, err)
+	l, err := st.SaveVariant(ctx, shirt, "L", map[string]string{"size": "L"}, 100, 5)
+	require.NoError(t, err)
+
+	_, err = st.ReserveStock(ctx, shirt, xl, 2, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	// expired reservation doesn't hold stock
+	_, err = st.ReserveStock(ctx, shirt, xl, 1, holder, time.Now().Add(-time.Minute))
+	require.NoError(t, err)
+	released, err := st.ReserveStock(ctx, shirt, xl, 1, holder, time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.NoError(t, st.ReleaseReservation(ctx, released))
+
+	variant, err := st.Variant(ctx, xl)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), variant.Quantity)
+	assert.Equal(t, int64(3), variant.Available)
+
+	// reservation of one variant doesn't hold stock of another
+	variant, err = st.Variant(ctx, l)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), variant.Available)
+
+	// listing is deleted with its variants
+	require.NoError(t, st.DeleteListing(ctx, shirt))
+	variants, err := st.Variants(ctx, shirt)
+	require.NoError(t, err)
+	assert.Empty(t, variants)
+}
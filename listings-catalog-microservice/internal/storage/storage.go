@@ -5,4 +5,20 @@ import "errors"
 var (
 	ErrListingNotFound = errors.New("listing with such id not found")
 	ErrUserNotFound    = errors.New("user with such id not found")
+	ErrUnknownSort     = errors.New("unknown listings sort")
+	ErrEmptyQuery      = errors.New("search query has no words")
+
+	ErrCategoryNotFound = errors.New("category with such id not found")
+	ErrCategoryExists   = errors.New("category with such slug already exists")
+	ErrCategoryCycle    = errors.New("category can't be moved into its own subtree")
+
+	ErrVariantNotFound = errors.New("variant with such id not found")
+	ErrSKUExists       = errors.New("variant with such sku already exists")
+	ErrOptionsExist    = errors.New("variant with such options already exists for listing")
+
+	ErrReservationNotFound  = errors.New("reservation with such id not found")
+	ErrReservationNotActive = errors.New("reservation is already committed, released or expired")
+	ErrInsufficientStock    = errors.New("not enough stock available")
+	ErrListingClosed        = errors.New("listing is closed")
+	ErrBelowReserved        = errors.New("quantity is less than quantity of active reservations")
 )
@@ -23,7 +23,13 @@ func main() {
 
 	logger := setupLogger(cfg.Env)
 
-	application := app.New(logger, cfg.GRPC.Port, cfg.StoragePath)
+	application := app.New(
+		logger,
+		cfg.GRPC.Port,
+		cfg.StoragePath,
+		cfg.SSO,
+		cfg.Reservations,
+	)
 
 	go func() {
 		application.GRPCServer.MustRun()
@@ -34,6 +40,8 @@ func main() {
 
 	<-stop
 
+	application.Sweeper.Stop()
+
 	logger.Info("Server gracefully died")
 }
 
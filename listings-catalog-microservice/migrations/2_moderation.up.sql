@@ -0,0 +1,11 @@
+-- Updates and deletions of listings made by admins on behalf of their creators
+CREATE TABLE IF NOT EXISTS moderation_actions
+(
+    id         INTEGER PRIMARY KEY,
+    listing_id INTEGER NOT NULL,
+    admin_id   INTEGER NOT NULL,
+    -- one of "update", "delete"
+    action     TEXT    NOT NULL,
+    created_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_moderation_actions_listing ON moderation_actions (listing_id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_listings_creator_created;
+DROP INDEX IF EXISTS idx_listings_category_price;
+DROP INDEX IF EXISTS idx_listings_category_created;
+DROP INDEX IF EXISTS idx_listings_title;
+DROP INDEX IF EXISTS idx_listings_price;
+DROP INDEX IF EXISTS idx_listings_created;
+
+ALTER TABLE listings DROP COLUMN created_at;
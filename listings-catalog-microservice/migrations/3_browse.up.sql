@@ -0,0 +1,10 @@
+-- Listings created before this migration are considered created at unix epoch
+ALTER TABLE listings ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+
+-- Every index ends with id: it is a tiebreaker for cursor pagination
+CREATE INDEX IF NOT EXISTS idx_listings_created ON listings (created_at, id);
+CREATE INDEX IF NOT EXISTS idx_listings_price ON listings (price, id);
+CREATE INDEX IF NOT EXISTS idx_listings_title ON listings (title, id);
+CREATE INDEX IF NOT EXISTS idx_listings_category_created ON listings (category, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_listings_category_price ON listings (category, price, id);
+CREATE INDEX IF NOT EXISTS idx_listings_creator_created ON listings (creator, created_at, id);
@@ -0,0 +1,28 @@
+-- External content table: text itself is stored only in listings
+CREATE VIRTUAL TABLE IF NOT EXISTS listings_fts USING fts5
+(
+    title,
+    description,
+    content = 'listings',
+    content_rowid = 'id',
+    tokenize = 'unicode61 remove_diacritics 2',
+    prefix = '2 3'
+);
+
+INSERT INTO listings_fts(listings_fts) VALUES ('rebuild');
+
+CREATE TRIGGER IF NOT EXISTS listings_fts_insert AFTER INSERT ON listings
+BEGIN
+    INSERT INTO listings_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS listings_fts_delete AFTER DELETE ON listings
+BEGIN
+    INSERT INTO listings_fts(listings_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+END;
+
+CREATE TRIGGER IF NOT EXISTS listings_fts_update AFTER UPDATE OF title, description ON listings
+BEGIN
+    INSERT INTO listings_fts(listings_fts, rowid, title, description) VALUES ('delete', old.id, old.title, old.description);
+    INSERT INTO listings_fts(rowid, title, description) VALUES (new.id, new.title, new.description);
+END;
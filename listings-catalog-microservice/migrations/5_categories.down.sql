@@ -0,0 +1,15 @@
+ALTER TABLE listings ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+UPDATE listings
+SET category = COALESCE((SELECT slug FROM categories WHERE id = listings.category_id), '');
+
+DROP INDEX IF EXISTS idx_listings_category_created;
+DROP INDEX IF EXISTS idx_listings_category_price;
+
+ALTER TABLE listings DROP COLUMN category_id;
+
+CREATE INDEX IF NOT EXISTS idx_listings_category_created ON listings (category, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_listings_category_price ON listings (category, price, id);
+
+DROP INDEX IF EXISTS idx_categories_parent;
+DROP TABLE IF EXISTS categories;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS categories
+(
+    id        INTEGER PRIMARY KEY,
+    -- NULL for root categories
+    parent_id INTEGER REFERENCES categories (id),
+    slug      TEXT    NOT NULL UNIQUE,
+    name      TEXT    NOT NULL,
+    archived  BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE INDEX IF NOT EXISTS idx_categories_parent ON categories (parent_id);
+
+-- Existing free-text categories become root categories,
+-- spellings differing only in case and surrounding spaces are merged.
+-- Slugs that are not valid (punctuation, repeated spaces, non-ascii)
+-- are replaced by catalog on start, see Storage.NormalizeCategorySlugs
+INSERT INTO categories (slug, name)
+SELECT lower(replace(trim(category), ' ', '-')), MIN(trim(category))
+FROM listings
+GROUP BY lower(replace(trim(category), ' ', '-'));
+
+-- No REFERENCES: sqlite can't drop such column on rollback.
+-- Existence of category is checked by service.
+ALTER TABLE listings ADD COLUMN category_id INTEGER NOT NULL DEFAULT 0;
+
+UPDATE listings
+SET category_id = (SELECT id FROM categories WHERE slug = lower(replace(trim(listings.category), ' ', '-')));
+
+DROP INDEX IF EXISTS idx_listings_category_created;
+DROP INDEX IF EXISTS idx_listings_category_price;
+
+ALTER TABLE listings DROP COLUMN category;
+
+CREATE INDEX IF NOT EXISTS idx_listings_category_created ON listings (category_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_listings_category_price ON listings (category_id, price, id);
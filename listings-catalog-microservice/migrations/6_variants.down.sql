@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS listings_delete_variants;
+DROP TABLE IF EXISTS variants;
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS variants
+(
+    id         INTEGER PRIMARY KEY,
+    listing_id INTEGER NOT NULL,
+    sku        TEXT    NOT NULL UNIQUE,
+    -- JSON object of option name -> value with sorted keys,
+    -- so equal combinations have equal text
+    options    TEXT    NOT NULL,
+    price      INTEGER NOT NULL,
+    quantity   INTEGER NOT NULL,
+    UNIQUE (listing_id, options)
+);
+
+CREATE TRIGGER IF NOT EXISTS listings_delete_variants AFTER DELETE ON listings
+BEGIN
+    DELETE FROM variants WHERE listing_id = old.id;
+END;
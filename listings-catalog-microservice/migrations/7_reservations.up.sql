@@ -0,0 +1,17 @@
+-- Holds on stock of listing or its variant.
+-- Available quantity = on-hand quantity - quantity of active unexpired reservations.
+CREATE TABLE IF NOT EXISTS reservations
+(
+    id         INTEGER PRIMARY KEY,
+    listing_id INTEGER NOT NULL,
+    -- 0 if stock of listing itself is reserved
+    variant_id INTEGER NOT NULL DEFAULT 0,
+    quantity   INTEGER NOT NULL,
+    holder_id  INTEGER NOT NULL,
+    -- one of "active", "committed", "released", "expired"
+    status     TEXT    NOT NULL,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_reservations_stock ON reservations (listing_id, variant_id, status);
+CREATE INDEX IF NOT EXISTS idx_reservations_expiry ON reservations (status, expires_at);
@@ -0,0 +1,88 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	dispatcherapp "github.com/Kry0z1/e-commerce/notification-microservice/internal/app/dispatcher"
+	grpcapp "github.com/Kry0z1/e-commerce/notification-microservice/internal/app/grpc"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/config"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender/file"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender/smtp"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/service"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/storage/sqlite"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/templates"
+	"github.com/Kry0z1/e-commerce/ssoclient"
+)
+
+type App struct {
+	GRPCServer *grpcapp.App
+	Dispatcher *dispatcherapp.App
+}
+
+func New(
+	log *slog.Logger,
+	grpcPort int,
+	storagePath string,
+	ssoCfg config.SSOConfig,
+	senderCfg config.SenderConfig,
+	deliveryCfg config.DeliveryConfig,
+) *App {
+	storage, err := sqlite.New(storagePath)
+	if err != nil {
+		panic(err)
+	}
+
+	ssoClient, err := ssoclient.New(log, ssoCfg.Address, ssoCfg.Timeout, ssoCfg.RetriesCount)
+	if err != nil {
+		panic(err)
+	}
+
+	verifier := authtoken.NewVerifier(
+		authtoken.NewCachedKeySet(ssoClient, ssoCfg.KeysCacheTTL),
+		authtoken.WithIssuer(ssoCfg.Issuer),
+		authtoken.WithAudience(ssoCfg.Audience...),
+		authtoken.WithLeeway(ssoCfg.Leeway),
+		authtoken.WithRevocationCheck(ssoclient.NewCachedRevocationChecker(ssoClient, ssoCfg.RevocationCacheTTL)),
+	)
+
+	admins := ssoclient.NewCachedAdminChecker(ssoClient, ssoCfg.AdminCacheTTL)
+
+	renderer, err := templates.New()
+	if err != nil {
+		panic(err)
+	}
+
+	srvc := service.New(
+		log,
+		storage, storage,
+		renderer, newSender(senderCfg), admins,
+		deliveryCfg.BatchSize, deliveryCfg.MaxAttempts, deliveryCfg.Backoff, deliveryCfg.MaxBackoff,
+	)
+
+	grpcApp := grpcapp.New(srvc, verifier, log, grpcPort)
+
+	dispatcher := dispatcherapp.New(log, srvc.DeliverDue, deliveryCfg.Interval)
+	go dispatcher.Run()
+
+	return &App{
+		GRPCServer: grpcApp,
+		Dispatcher: dispatcher,
+	}
+}
+
+func newSender(cfg config.SenderConfig) sender.Sender {
+	switch cfg.Kind {
+	case "file":
+		s, err := file.New(cfg.OutboxPath, cfg.From)
+		if err != nil {
+			panic(err)
+		}
+		return s
+	case "smtp":
+		return smtp.New(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.From)
+	default:
+		panic("unknown sender kind: " + cfg.Kind)
+	}
+}
@@ -0,0 +1,65 @@
+package dispatcherapp
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+// App periodically delivers due notifications until stopped
+type App struct {
+	log      *slog.Logger
+	deliver  func(ctx context.Context) error
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func New(log *slog.Logger, deliver func(ctx context.Context) error, interval time.Duration) *App {
+	return &App{
+		log:      log,
+		deliver:  deliver,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run blocks until Stop is called, errors of deliver are only logged
+func (a *App) Run() {
+	const op = "app.dispatcher.Run"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("dispatcher started", slog.Duration("interval", a.interval))
+
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), a.interval)
+			if err := a.deliver(ctx); err != nil {
+				log.Warn("delivery failed", ll.Err(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// Stop stops dispatcher and waits for current delivery to finish
+func (a *App) Stop() {
+	const op = "app.dispatcher.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping dispatcher")
+
+	close(a.stop)
+	<-a.done
+}
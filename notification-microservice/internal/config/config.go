@@ -0,0 +1,111 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type Config struct {
+	// one of "local", "prod"
+	Env         string         `yaml:"env" env-default:"local"`
+	StoragePath string         `yaml:"storage_path" env-required:"true"`
+	GRPC        GRPCConfig     `yaml:"grpc" env-required:"true"`
+	SSO         SSOConfig      `yaml:"sso" env-required:"true"`
+	Sender      SenderConfig   `yaml:"sender"`
+	Delivery    DeliveryConfig `yaml:"delivery"`
+}
+
+type GRPCConfig struct {
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type SSOConfig struct {
+	Address      string        `yaml:"address" env-required:"true"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	RetriesCount int           `yaml:"retries_count" env-default:"3"`
+	// How long signing keys fetched from sso are trusted without refetch
+	KeysCacheTTL time.Duration `yaml:"keys_cache_ttl" env-default:"5m"`
+	// Expected "iss" claim of tokens
+	Issuer string `yaml:"issuer" env-default:"sso"`
+	// Ids of apps whose tokens are accepted, empty means any app
+	Audience []string `yaml:"audience"`
+	// Allowed clock skew between sso and notifications
+	Leeway time.Duration `yaml:"leeway" env-default:"30s"`
+	// How long answer of sso about token revocation is trusted, revoked token
+	// is accepted for at most this long after revocation
+	RevocationCacheTTL time.Duration `yaml:"revocation_cache_ttl" env-default:"5s"`
+	// How long admin status of user fetched from sso is trusted
+	AdminCacheTTL time.Duration `yaml:"admin_cache_ttl" env-default:"30s"`
+}
+
+type SenderConfig struct {
+	// one of "file", "smtp"
+	Kind string `yaml:"kind" env-default:"file"`
+	// Value of "From" header
+	From string `yaml:"from" env-default:"no-reply@e-commerce.local"`
+	// Directory messages are written to by "file" sender
+	OutboxPath string     `yaml:"outbox_path" env-default:".data/outbox"`
+	SMTP       SMTPConfig `yaml:"smtp"`
+}
+
+type SMTPConfig struct {
+	Host string `yaml:"host" env-default:"localhost"`
+	Port int    `yaml:"port" env-default:"25"`
+	// Empty username -> no authentication
+	Username string `yaml:"username"`
+	Password string `yaml:"password" env:"SMTP_PASSWORD"`
+}
+
+type DeliveryConfig struct {
+	// How often due notifications are delivered
+	Interval time.Duration `yaml:"interval" env-default:"5s"`
+	// Most notifications delivered at once
+	BatchSize int `yaml:"batch_size" env-default:"50"`
+	// Failed attempts after which notification goes to dead letters
+	MaxAttempts int `yaml:"max_attempts" env-default:"5"`
+	// Delay after first failure, doubled after every next one
+	Backoff time.Duration `yaml:"backoff" env-default:"10s"`
+	// Longest delay between attempts
+	MaxBackoff time.Duration `yaml:"max_backoff" env-default:"1h"`
+}
+
+func MustLoad() *Config {
+	path := getConfigPath()
+	return MustLoadPath(path)
+}
+
+func MustLoadPath(path string) *Config {
+	if path == "" {
+		panic("empty config path")
+	}
+
+	var cfg Config
+
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		panic("couldn't read config: " + err.Error())
+	}
+
+	return &cfg
+}
+
+// Gets config path in this priority:
+// param > env > default
+//
+// Environment variable is CONFIG_PATH.
+// Default is empty string.
+func getConfigPath() string {
+	var res string
+
+	flag.StringVar(&res, "config", "", "path to config file")
+	flag.Parse()
+
+	if res == "" {
+		res = os.Getenv("CONFIG_PATH")
+	}
+
+	return res
+}
@@ -0,0 +1,27 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	notificationsv1 "github.com/Kry0z1/e-commerce/protos/gen/go/notifications"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthRequiredMethods are methods that can't be called without access token
+var AuthRequiredMethods = []string{
+	notificationsv1.Notifications_GetNotification_FullMethodName,
+	notificationsv1.Notifications_ListDeadLetters_FullMethodName,
+	notificationsv1.Notifications_RetryDeadLetter_FullMethodName,
+}
+
+// caller returns id of user authenticated by interceptors
+func caller(ctx context.Context) (int64, error) {
+	principal, ok := authtoken.PrincipalFromContext(ctx)
+	if !ok {
+		return -1, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return principal.UserID, nil
+}
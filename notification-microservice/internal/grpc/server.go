@@ -0,0 +1,205 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	notificationsv1 "github.com/Kry0z1/e-commerce/protos/gen/go/notifications"
+	"google.golang.org/grpc"
+)
+
+type serverAPI struct {
+	notificationsv1.UnimplementedNotificationsServer
+	srvc service.Service
+}
+
+func Register(gRPCServer *grpc.Server, srvc service.Service) {
+	notificationsv1.RegisterNotificationsServer(gRPCServer, &serverAPI{srvc: srvc})
+}
+
+func parseServiceError(err error) error {
+	if err != nil {
+		if errors.Is(err, service.ErrNotificationNotFound) || errors.Is(err, service.ErrDeadLetterNotFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrUnknownKind) || errors.Is(err, service.ErrInvalidPageToken) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, service.ErrNotEnoughPermissions) {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return nil
+}
+
+func (s *serverAPI) SendNotification(
+	ctx context.Context,
+	req *notificationsv1.SendNotificationRequest,
+) (*notificationsv1.SendNotificationResponse, error) {
+	recipient := req.GetRecipient()
+	if recipient == "" {
+		return nil, status.Error(codes.InvalidArgument, "recipient is required")
+	}
+
+	if addr, err := mail.ParseAddress(recipient); err != nil || addr.Address != recipient {
+		return nil, status.Error(codes.InvalidArgument, "recipient must be plain email address")
+	}
+
+	var (
+		kind models.Kind
+		data any
+	)
+
+	switch payload := req.GetPayload().(type) {
+	case *notificationsv1.SendNotificationRequest_Welcome:
+		kind, data = models.KindWelcome, models.WelcomeData{Email: recipient}
+	case *notificationsv1.SendNotificationRequest_OrderConfirmation:
+		kind, data = models.KindOrderConfirmation, orderConfirmationFromProto(payload.OrderConfirmation)
+	case *notificationsv1.SendNotificationRequest_PasswordReset:
+		if payload.PasswordReset.GetToken() == "" {
+			return nil, status.Error(codes.InvalidArgument, "password reset token is required")
+		}
+		kind, data = models.KindPasswordReset, models.PasswordResetData{
+			Token:     payload.PasswordReset.GetToken(),
+			ExpiresAt: time.Unix(payload.PasswordReset.GetExpiresAt(), 0),
+		}
+	case *notificationsv1.SendNotificationRequest_EmailVerification:
+		if payload.EmailVerification.GetToken() == "" {
+			return nil, status.Error(codes.InvalidArgument, "email verification token is required")
+		}
+		kind, data = models.KindEmailVerification, models.EmailVerificationData{
+			Token:     payload.EmailVerification.GetToken(),
+			ExpiresAt: time.Unix(payload.EmailVerification.GetExpiresAt(), 0),
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "payload is required")
+	}
+
+	id, err := s.srvc.Send(ctx, kind, recipient, data)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &notificationsv1.SendNotificationResponse{Id: id}, nil
+}
+
+func (s *serverAPI) GetNotification(
+	ctx context.Context,
+	req *notificationsv1.GetNotificationRequest,
+) (*notificationsv1.GetNotificationResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := s.srvc.Notification(ctx, req.GetId(), callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &notificationsv1.GetNotificationResponse{Notification: notificationToProto(n)}, nil
+}
+
+func (s *serverAPI) ListDeadLetters(
+	ctx context.Context,
+	req *notificationsv1.ListDeadLettersRequest,
+) (*notificationsv1.ListDeadLettersResponse, error) {
+	if req.GetPageSize() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size cannot be less than 0")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	letters, nextPageToken, err := s.srvc.ListDeadLetters(ctx, int(req.GetPageSize()), req.GetPageToken(), callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	resp := &notificationsv1.ListDeadLettersResponse{
+		DeadLetters:   make([]*notificationsv1.DeadLetter, 0, len(letters)),
+		NextPageToken: nextPageToken,
+	}
+	for _, letter := range letters {
+		resp.DeadLetters = append(resp.DeadLetters, &notificationsv1.DeadLetter{
+			Notification: notificationToProto(letter.Notification),
+			FailedAt:     letter.FailedAt.Unix(),
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RetryDeadLetter(
+	ctx context.Context,
+	req *notificationsv1.RetryDeadLetterRequest,
+) (*notificationsv1.RetryDeadLetterResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.RetryDeadLetter(ctx, req.GetId(), callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &notificationsv1.RetryDeadLetterResponse{}, nil
+}
+
+func orderConfirmationFromProto(order *notificationsv1.OrderConfirmation) models.OrderConfirmationData {
+	data := models.OrderConfirmationData{
+		OrderID: order.GetOrderId(),
+		Lines:   make([]models.OrderConfirmationLine, 0, len(order.GetLines())),
+		Total:   order.GetTotal(),
+	}
+
+	for _, line := range order.GetLines() {
+		data.Lines = append(data.Lines, models.OrderConfirmationLine{
+			Title:     line.GetTitle(),
+			Quantity:  line.GetQuantity(),
+			UnitPrice: line.GetUnitPrice(),
+			LineTotal: line.GetLineTotal(),
+		})
+	}
+
+	return data
+}
+
+var statuses = map[models.Status]notificationsv1.NotificationStatus{
+	models.StatusPending: notificationsv1.NotificationStatus_NOTIFICATION_STATUS_PENDING,
+	models.StatusSent:    notificationsv1.NotificationStatus_NOTIFICATION_STATUS_SENT,
+	models.StatusDead:    notificationsv1.NotificationStatus_NOTIFICATION_STATUS_DEAD,
+}
+
+func notificationToProto(n models.Notification) *notificationsv1.Notification {
+	var sentAt int64
+	if !n.SentAt.IsZero() {
+		sentAt = n.SentAt.Unix()
+	}
+
+	return &notificationsv1.Notification{
+		Id:        n.ID,
+		Kind:      string(n.Kind),
+		Recipient: n.Recipient,
+		Subject:   n.Subject,
+		TextBody:  n.Text,
+		HtmlBody:  n.HTML,
+		Status:    statuses[n.Status],
+		Attempts:  int32(n.Attempts),
+		LastError: n.LastError,
+		SentAt:    sentAt,
+		CreatedAt: n.CreatedAt.Unix(),
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Kind is name of template notification is rendered from
+type Kind string
+
+const (
+	KindWelcome           Kind = "welcome"
+	KindOrderConfirmation Kind = "order_confirmation"
+	KindPasswordReset     Kind = "password_reset"
+	KindEmailVerification Kind = "email_verification"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusSent    Status = "sent"
+	StatusDead    Status = "dead"
+)
+
+type Notification struct {
+	ID        int64
+	Kind      Kind
+	Recipient string
+	Subject   string
+	Text      string
+	HTML      string
+	Status    Status
+	// Failed delivery attempts
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	// Zero if not sent
+	SentAt time.Time
+}
+
+type DeadLetter struct {
+	Notification Notification
+	FailedAt     time.Time
+}
+
+type WelcomeData struct {
+	Email string
+}
+
+type OrderConfirmationData struct {
+	OrderID int64
+	Lines   []OrderConfirmationLine
+	// In cents
+	Total int64
+}
+
+type OrderConfirmationLine struct {
+	Title     string
+	Quantity  int64
+	UnitPrice int64
+	LineTotal int64
+}
+
+type PasswordResetData struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+type EmailVerificationData struct {
+	Token     string
+	ExpiresAt time.Time
+}
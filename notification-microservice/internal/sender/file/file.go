@@ -0,0 +1,59 @@
+// Package file implements sender writing messages as .eml files into outbox directory
+package file
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender"
+)
+
+type Sender struct {
+	dir  string
+	from string
+}
+
+func New(dir string, from string) (*Sender, error) {
+	const op = "sender.file.New"
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Sender{dir: dir, from: from}, nil
+}
+
+// Send writes message into <id>.eml, file of resent message is overwritten
+func (s *Sender) Send(_ context.Context, msg sender.Message) error {
+	const op = "sender.file.Send"
+
+	data, err := sender.Compose(s.from, msg, time.Now())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// rename is atomic, so readers of outbox never see half-written message
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, fmt.Sprintf("%d.eml", msg.ID))); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package smtp_test
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender/smtp"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender/smtp/smtptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) *smtptest.Server {
+	t.Helper()
+
+	srv, err := smtptest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { srv.Close() })
+
+	return srv
+}
+
+func TestSend(t *testing.T) {
+	srv := newServer(t)
+	host, port := srv.Host()
+
+	s := smtp.New(host, port, "", "", "shop@e-commerce.local")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := s.Send(ctx, sender.Message{
+		ID:      7,
+		To:      "buyer@b.c",
+		Subject: "Order #7 confirmed",
+		Text:    "Total: 10.00",
+		HTML:    "<p>Total: 10.00</p>",
+	})
+	require.NoError(t, err)
+
+	received := srv.Received()
+	require.Len(t, received, 1)
+	assert.Equal(t, "shop@e-commerce.local", received[0].From)
+	assert.Equal(t, []string{"buyer@b.c"}, received[0].To)
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(received[0].Data)))
+	require.NoError(t, err)
+	assert.Equal(t, "Order #7 confirmed", msg.Header.Get("Subject"))
+	assert.Equal(t, "<notification-7@e-commerce.local>", msg.Header.Get("Message-ID"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	var bodies []string
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		bodies = append(bodies, string(body))
+	}
+	assert.Equal(t, []string{"Total: 10.00", "<p>Total: 10.00</p>"}, bodies)
+}
+
+func TestSend_Rejected(t *testing.T) {
+	srv := newServer(t)
+	host, port := srv.Host()
+
+	srv.FailNext(1)
+
+	s := smtp.New(host, port, "", "", "shop@e-commerce.local")
+	msg := sender.Message{ID: 1, To: "a@b.c", Subject: "s", Text: "t", HTML: "h"}
+
+	require.Error(t, s.Send(context.Background(), msg))
+	assert.Empty(t, srv.Received())
+
+	require.NoError(t, s.Send(context.Background(), msg))
+	assert.Len(t, srv.Received(), 1)
+}
+
+func TestSend_Unreachable(t *testing.T) {
+	srv := newServer(t)
+	host, port := srv.Host()
+	require.NoError(t, srv.Close())
+
+	s := smtp.New(host, port, "", "", "shop@e-commerce.local")
+
+	require.Error(t, s.Send(context.Background(), sender.Message{ID: 1, To: "a@b.c"}))
+}
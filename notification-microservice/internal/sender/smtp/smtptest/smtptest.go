@@ -0,0 +1,155 @@
+// Package smtptest provides in-process SMTP server for tests of SMTP senders.
+//
+// Server accepts plain SMTP without authentication and keeps received messages in memory.
+package smtptest
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+type Received struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+type Server struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	received []Received
+	failures int
+}
+
+// NewServer starts server on random local port, stop it with Close
+func NewServer() (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{listener: l}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// Host returns host and port of server
+func (s *Server) Host() (string, int) {
+	addr := s.listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port
+}
+
+// Received returns messages accepted so far
+func (s *Server) Received() []Received {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Received(nil), s.received...)
+}
+
+// FailNext makes server reject next n messages with temporary error
+func (s *Server) FailNext(n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures = n
+}
+
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+			s.handle(textproto.NewConn(conn))
+		}()
+	}
+}
+
+func (s *Server) handle(c *textproto.Conn) {
+	var msg Received
+
+	_ = c.PrintfLine("220 smtptest ready")
+
+	for {
+		line, err := c.ReadLine()
+		if err != nil {
+			return
+		}
+
+		verb, arg, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = c.PrintfLine("250 smtptest")
+		case "MAIL":
+			msg = Received{From: address(arg)}
+			_ = c.PrintfLine("250 ok")
+		case "RCPT":
+			msg.To = append(msg.To, address(arg))
+			_ = c.PrintfLine("250 ok")
+		case "DATA":
+			_ = c.PrintfLine("354 go ahead")
+
+			data, err := c.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			msg.Data = data
+
+			if s.accept(msg) {
+				_ = c.PrintfLine("250 ok")
+			} else {
+				_ = c.PrintfLine("451 try again later")
+			}
+		case "RSET", "NOOP":
+			_ = c.PrintfLine("250 ok")
+		case "QUIT":
+			_ = c.PrintfLine("221 bye")
+			return
+		default:
+			_ = c.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+func (s *Server) accept(msg Received) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failures > 0 {
+		s.failures--
+		return false
+	}
+
+	s.received = append(s.received, msg)
+
+	return true
+}
+
+// address extracts address out of "FROM:<a@b.c>"
+func address(arg string) string {
+	_, addr, _ := strings.Cut(arg, ":")
+	return strings.Trim(strings.TrimSpace(addr), "<>")
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/storage"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type deadLetterCursor struct {
+	ID int64 `json:"id"`
+}
+
+// ListDeadLetters returns page of dead letters, newest first, and token of the next page.
+// Empty next page token means there are no more dead letters. Only for admins.
+func (s *Service) ListDeadLetters(
+	ctx context.Context,
+	pageSize int,
+	pageToken string,
+	callerID int64,
+) ([]models.DeadLetter, string, error) {
+	const op = "service.ListDeadLetters"
+
+	log := s.log.With(slog.String("op", op))
+
+	log.Info("started dead letters listing")
+
+	if err := s.requireAdmin(ctx, log, callerID); err != nil {
+		return nil, "", knownError(op, err)
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	var cursor deadLetterCursor
+	if pageToken != "" {
+		if err := decodePageToken(pageToken, &cursor); err != nil || cursor.ID <= 0 {
+			log.Info("invalid page token")
+			return nil, "", ErrInvalidPageToken
+		}
+	}
+
+	// one extra dead letter tells whether there is next page
+	letters, err := s.notificationProvider.DeadLetters(ctx, cursor.ID, pageSize+1)
+	if err != nil {
+		log.Error("internal error", ll.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var nextPageToken string
+	if len(letters) > pageSize {
+		letters = letters[:pageSize]
+		nextPageToken = encodePageToken(deadLetterCursor{ID: letters[pageSize-1].Notification.ID})
+	}
+
+	log.Info("listing succeeded", slog.Int("count", len(letters)))
+	return letters, nextPageToken, nil
+}
+
+// RetryDeadLetter queues dead notification for delivery again
+// with fresh attempts. Only for admins.
+func (s *Service) RetryDeadLetter(ctx context.Context, id int64, callerID int64) error {
+	const op = "service.RetryDeadLetter"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("id", id))
+
+	log.Info("started retrying dead letter")
+
+	if err := s.requireAdmin(ctx, log, callerID); err != nil {
+		return knownError(op, err)
+	}
+
+	if err := s.notificationSaver.RetryDeadLetter(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrDeadLetterNotFound) {
+			log.Info("dead letter not found")
+			return ErrDeadLetterNotFound
+		}
+		log.Error("internal error", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("dead letter queued again")
+	return nil
+}
+
+// encodePageToken makes opaque token out of cursor struct
+func encodePageToken(cursor any) string {
+	// cursors are plain structs, marshalling can't fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, cursor)
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/sender"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/templates"
+)
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrDeadLetterNotFound   = errors.New("dead letter not found")
+	ErrUnknownKind          = errors.New("unknown notification kind")
+	ErrNotEnoughPermissions = errors.New("user is not authorized for this action")
+	ErrInvalidPageToken     = errors.New("invalid page token")
+)
+
+type NotificationSaver interface {
+	SaveNotification(ctx context.Context, n models.Notification) (int64, error)
+	MarkSent(ctx context.Context, id int64) error
+	MarkFailed(ctx context.Context, id int64, attempts int, next time.Time, lastError string) error
+	MoveToDeadLetters(ctx context.Context, id int64, attempts int, lastError string) error
+	RetryDeadLetter(ctx context.Context, id int64) error
+}
+
+type NotificationProvider interface {
+	Notification(ctx context.Context, id int64) (models.Notification, error)
+	DueNotifications(ctx context.Context, now time.Time, limit int) ([]models.Notification, error)
+	DeadLetters(ctx context.Context, afterID int64, limit int) ([]models.DeadLetter, error)
+}
+
+type Renderer interface {
+	Render(kind models.Kind, data any) (templates.Message, error)
+}
+
+type Service struct {
+	log                  *slog.Logger
+	notificationSaver    NotificationSaver
+	notificationProvider NotificationProvider
+	renderer             Renderer
+	sender               sender.Sender
+	admins               AdminChecker
+
+	batchSize   int
+	maxAttempts int
+	backoff     time.Duration
+	maxBackoff  time.Duration
+}
+
+func New(
+	log *slog.Logger,
+	notificationSaver NotificationSaver,
+	notificationProvider NotificationProvider,
+	renderer Renderer,
+	sender sender.Sender,
+	admins AdminChecker,
+	batchSize int,
+	maxAttempts int,
+	backoff time.Duration,
+	maxBackoff time.Duration,
+) *Service {
+	return &Service{
+		log:                  log,
+		notificationSaver:    notificationSaver,
+		notificationProvider: notificationProvider,
+		renderer:             renderer,
+		sender:               sender,
+		admins:               admins,
+		batchSize:            batchSize,
+		maxAttempts:          maxAttempts,
+		backoff:              backoff,
+		maxBackoff:           maxBackoff,
+	}
+}
+
+// Send renders notification of given kind and queues it for delivery.
+// Returns id of notification, delivery itself happens in background.
+func (s *Service) Send(ctx context.Context, kind models.Kind, recipient string, data any) (int64, error) {
+	const op = "service.Send"
+
+	log := s.log.With(slog.String("op", op), slog.String("kind", string(kind)))
+
+	log.Info("started queueing notification")
+
+	msg, err := s.renderer.Render(kind, data)
+	if err != nil {
+		if errors.Is(err, templates.ErrUnknownKind) {
+			log.Info("unknown kind")
+			return -1, ErrUnknownKind
+		}
+		log.Error("failed to render notification", ll.Err(err))
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := s.notificationSaver.SaveNotification(ctx, models.Notification{
+		Kind:      kind,
+		Recipient: recipient,
+		Subject:   msg.Subject,
+		Text:      msg.Text,
+		HTML:      msg.HTML,
+	})
+	if err != nil {
+		log.Error("failed to save notification", ll.Err(err))
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("notification queued", slog.Int64("id", id))
+	return id, nil
+}
+
+// Notification returns notification by id, only for admins
+func (s *Service) Notification(ctx context.Context, id int64, callerID int64) (models.Notification, error) {
+	const op = "service.Notification"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("id", id))
+
+	log.Info("started getting notification")
+
+	if err := s.requireAdmin(ctx, log, callerID); err != nil {
+		return models.Notification{}, knownError(op, err)
+	}
+
+	n, err := s.notificationProvider.Notification(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotificationNotFound) {
+			log.Info("notification not found")
+			return n, ErrNotificationNotFound
+		}
+		log.Error("internal error", ll.Err(err))
+		return n, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("got notification")
+	return n, nil
+}
+
+// DeliverDue tries to send every notification whose attempt is due.
+//
+// Failed notification is retried with exponential backoff
+// and goes to dead letters after max attempts.
+func (s *Service) DeliverDue(ctx context.Context) error {
+	const op = "service.DeliverDue"
+
+	log := s.log.With(slog.String("op", op))
+
+	due, err := s.notificationProvider.DueNotifications(ctx, time.Now(), s.batchSize)
+	if err != nil {
+		log.Error("failed to get due notifications", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	var sent, failed int
+	for _, n := range due {
+		ok, err := s.deliver(ctx, log, n)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if ok {
+			sent++
+		} else {
+			failed++
+		}
+	}
+
+	if len(due) > 0 {
+		log.Info("delivered due notifications", slog.Int("sent", sent), slog.Int("failed", failed))
+	}
+
+	return nil
+}
+
+// deliver sends single notification, records outcome and tells whether it was sent.
+// Returns error only if outcome can't be recorded.
+func (s *Service) deliver(ctx context.Context, log *slog.Logger, n models.Notification) (bool, error) {
+	log = log.With(slog.Int64("id", n.ID), slog.String("kind", string(n.Kind)))
+
+	sendErr := s.sender.Send(ctx, sender.Message{
+		ID:      n.ID,
+		To:      n.Recipient,
+		Subject: n.Subject,
+		Text:    n.Text,
+		HTML:    n.HTML,
+	})
+	if sendErr == nil {
+		if err := s.notificationSaver.MarkSent(ctx, n.ID); err != nil {
+			log.Error("failed to mark notification sent", ll.Err(err))
+			return false, err
+		}
+		return true, nil
+	}
+
+	attempts := n.Attempts + 1
+
+	if attempts >= s.maxAttempts {
+		log.Warn("giving up on notification", slog.Int("attempts", attempts), ll.Err(sendErr))
+
+		if err := s.notificationSaver.MoveToDeadLetters(ctx, n.ID, attempts, sendErr.Error()); err != nil {
+			log.Error("failed to move notification to dead letters", ll.Err(err))
+			return false, err
+		}
+		return false, nil
+	}
+
+	next := time.Now().Add(s.retryDelay(attempts))
+
+	log.Info("delivery failed", slog.Int("attempts", attempts), slog.Time("next_attempt_at", next), ll.Err(sendErr))
+
+	if err := s.notificationSaver.MarkFailed(ctx, n.ID, attempts, next, sendErr.Error()); err != nil {
+		log.Error("failed to record failed delivery", ll.Err(err))
+		return false, err
+	}
+
+	return false, nil
+}
+
+// retryDelay is delay before next attempt after given number of failed ones
+func (s *Service) retryDelay(attempts int) time.Duration {
+	delay := s.backoff
+	for i := 1; i < attempts && delay < s.maxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, s.maxBackoff)
+}
+
+func knownError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, known := range []error{
+		ErrNotificationNotFound, ErrDeadLetterNotFound, ErrUnknownKind, ErrNotEnoughPermissions, ErrInvalidPageToken,
+	} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
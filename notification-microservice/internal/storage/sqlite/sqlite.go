@@ -0,0 +1,285 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+func New(storagePath string) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	db, err := sql.Open("sqlite3", storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+func (s *Storage) Stop() error {
+	return s.db.Close()
+}
+
+const notificationColumns = `id, kind, recipient, subject, text_body, html_body, status, attempts, next_attempt_at, last_error, created_at, sent_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanNotification(row scanner, extra ...any) (models.Notification, error) {
+	var (
+		n             models.Notification
+		kind          string
+		status        string
+		nextAttemptAt int64
+		createdAt     int64
+		sentAt        int64
+	)
+
+	dest := []any{
+		&n.ID, &kind, &n.Recipient, &n.Subject, &n.Text, &n.HTML, &status,
+		&n.Attempts, &nextAttemptAt, &n.LastError, &createdAt, &sentAt,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return n, err
+	}
+
+	n.Kind = models.Kind(kind)
+	n.Status = models.Status(status)
+	n.NextAttemptAt = time.Unix(nextAttemptAt, 0)
+	n.CreatedAt = time.Unix(createdAt, 0)
+	if sentAt != 0 {
+		n.SentAt = time.Unix(sentAt, 0)
+	}
+
+	return n, nil
+}
+
+// SaveNotification saves pending notification due right away and returns its id
+func (s *Storage) SaveNotification(ctx context.Context, n models.Notification) (int64, error) {
+	const op = "storage.sqlite.SaveNotification"
+
+	now := time.Now().Unix()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO notifications(kind, recipient, subject, text_body, html_body, status, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`, n.Kind, n.Recipient, n.Subject, n.Text, n.HTML, models.StatusPending, now, now)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) Notification(ctx context.Context, id int64) (models.Notification, error) {
+	const op = "storage.sqlite.Notification"
+
+	n, err := scanNotification(s.db.QueryRowContext(ctx, `
+		SELECT `+notificationColumns+` FROM notifications WHERE id = ?
+	`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return n, storage.ErrNotificationNotFound
+		}
+		return n, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return n, nil
+}
+
+// DueNotifications returns at most limit pending notifications
+// whose next attempt is not later than now, oldest first
+func (s *Storage) DueNotifications(ctx context.Context, now time.Time, limit int) ([]models.Notification, error) {
+	const op = "storage.sqlite.DueNotifications"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+notificationColumns+`
+		FROM notifications
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at, id
+		LIMIT ?
+	`, models.StatusPending, now.Unix(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var notifications []models.Notification
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		notifications = append(notifications, n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return notifications, nil
+}
+
+func (s *Storage) MarkSent(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.MarkSent"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE notifications SET status = ?, sent_at = ? WHERE id = ?
+	`, models.StatusSent, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MarkFailed records failed delivery attempt and postpones next one
+func (s *Storage) MarkFailed(ctx context.Context, id int64, attempts int, next time.Time, lastError string) error {
+	const op = "storage.sqlite.MarkFailed"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE notifications SET attempts = ?, next_attempt_at = ?, last_error = ? WHERE id = ?
+	`, attempts, next.Unix(), lastError, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// MoveToDeadLetters records last failed attempt and gives up on notification
+func (s *Storage) MoveToDeadLetters(ctx context.Context, id int64, attempts int, lastError string) (err error) {
+	const op = "storage.sqlite.MoveToDeadLetters"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE notifications SET status = ?, attempts = ?, last_error = ? WHERE id = ?
+	`, models.StatusDead, attempts, lastError, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO dead_letters(notification_id, failed_at) VALUES (?, ?)
+	`, id, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeadLetters returns at most limit dead letters, newest first.
+// afterID > 0 -> only dead letters of notifications older than one with that id.
+func (s *Storage) DeadLetters(ctx context.Context, afterID int64, limit int) ([]models.DeadLetter, error) {
+	const op = "storage.sqlite.DeadLetters"
+
+	query := `
+		SELECT ` + notificationColumns + `, d.failed_at
+		FROM dead_letters d JOIN notifications n ON n.id = d.notification_id`
+	var args []any
+	if afterID > 0 {
+		query += ` WHERE d.notification_id < ?`
+		args = append(args, afterID)
+	}
+	query += ` ORDER BY d.notification_id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var letters []models.DeadLetter
+	for rows.Next() {
+		var failedAt int64
+
+		n, err := scanNotification(rows, &failedAt)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		letters = append(letters, models.DeadLetter{Notification: n, FailedAt: time.Unix(failedAt, 0)})
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return letters, nil
+}
+
+// RetryDeadLetter takes notification out of dead letters
+// and makes it pending with fresh attempts, due right away
+func (s *Storage) RetryDeadLetter(ctx context.Context, id int64) (err error) {
+	const op = "storage.sqlite.RetryDeadLetter"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM dead_letters WHERE notification_id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return storage.ErrDeadLetterNotFound
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE notifications SET status = ?, attempts = 0, next_attempt_at = ? WHERE id = ?
+	`, models.StatusPending, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "errors"
+
+var (
+	ErrNotificationNotFound = errors.New("notification not found")
+	ErrDeadLetterNotFound   = errors.New("dead letter not found")
+)
@@ -0,0 +1,120 @@
+// Package templates renders notification messages.
+//
+// Every kind of notification has two templates: <kind>.txt.tmpl is text/template
+// defining "subject" and plain text body, <kind>.html.tmpl is html/template of html body.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/models"
+)
+
+var ErrUnknownKind = errors.New("unknown notification kind")
+
+//go:embed *.tmpl
+var files embed.FS
+
+var kinds = []models.Kind{
+	models.KindWelcome,
+	models.KindOrderConfirmation,
+	models.KindPasswordReset,
+	models.KindEmailVerification,
+}
+
+var funcs = map[string]any{
+	// money formats cents as 12.34
+	"money": func(cents int64) string {
+		sign := ""
+		if cents < 0 {
+			sign = "-"
+			cents = -cents
+		}
+		return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+	},
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04 MST")
+	},
+}
+
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+type set struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+type Renderer struct {
+	sets map[models.Kind]set
+}
+
+// New parses templates of all notification kinds
+func New() (*Renderer, error) {
+	const op = "templates.New"
+
+	r := &Renderer{sets: make(map[models.Kind]set, len(kinds))}
+
+	for _, kind := range kinds {
+		text, err := texttemplate.New("").Funcs(funcs).ParseFS(files, string(kind)+".txt.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s: %s.txt.tmpl defines no subject", op, kind)
+		}
+
+		html, err := htmltemplate.New("").Funcs(funcs).ParseFS(files, string(kind)+".html.tmpl")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		r.sets[kind] = set{text: text, html: html}
+	}
+
+	return r, nil
+}
+
+// Render renders message of kind with data
+func (r *Renderer) Render(kind models.Kind, data any) (Message, error) {
+	const op = "templates.Render"
+
+	var msg Message
+
+	s, ok := r.sets[kind]
+	if !ok {
+		return msg, ErrUnknownKind
+	}
+
+	var buf bytes.Buffer
+
+	if err := s.text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := s.text.ExecuteTemplate(&buf, string(kind)+".txt.tmpl", data); err != nil {
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+	msg.Text = buf.String()
+
+	buf.Reset()
+	if err := s.html.ExecuteTemplate(&buf, string(kind)+".html.tmpl", data); err != nil {
+		return msg, fmt.Errorf("%s: %w", op, err)
+	}
+	msg.HTML = buf.String()
+
+	return msg, nil
+}
@@ -0,0 +1,86 @@
+package templates_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/notification-microservice/internal/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Welcome(t *testing.T) {
+	r, err := templates.New()
+	require.NoError(t, err)
+
+	msg, err := r.Render(models.KindWelcome, models.WelcomeData{Email: "a@b.c"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Welcome to e-commerce", msg.Subject)
+	assert.Contains(t, msg.Text, "Hi a@b.c,")
+	assert.NotContains(t, msg.Text, "subject")
+	assert.Contains(t, msg.HTML, "<p>Hi a@b.c,</p>")
+}
+
+func TestRender_OrderConfirmation(t *testing.T) {
+	r, err := templates.New()
+	require.NoError(t, err)
+
+	msg, err := r.Render(models.KindOrderConfirmation, models.OrderConfirmationData{
+		OrderID: 42,
+		Lines: []models.OrderConfirmationLine{
+			{Title: "Mug <big>", Quantity: 2, UnitPrice: 505, LineTotal: 1010},
+			{Title: "Shirt", Quantity: 1, UnitPrice: 1200, LineTotal: 1200},
+		},
+		Total: 2210,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Order #42 confirmed", msg.Subject)
+	assert.Contains(t, msg.Text, "Mug <big>: 2 x 5.05 = 10.10\n")
+	assert.Contains(t, msg.Text, "Total: 22.10")
+	// html template escapes data
+	assert.Contains(t, msg.HTML, "Mug &lt;big&gt;")
+	assert.NotContains(t, msg.HTML, "<big>")
+}
+
+func TestRender_PasswordReset(t *testing.T) {
+	r, err := templates.New()
+	require.NoError(t, err)
+
+	msg, err := r.Render(models.KindPasswordReset, models.PasswordResetData{
+		Token:     "secret-token",
+		ExpiresAt: time.Date(2030, 1, 2, 3, 4, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Password reset", msg.Subject)
+	assert.Contains(t, msg.Text, "secret-token")
+	assert.Contains(t, msg.Text, "2030-01-02 03:04 UTC")
+	assert.Contains(t, msg.HTML, "<code>secret-token</code>")
+}
+
+func TestRender_EmailVerification(t *testing.T) {
+	r, err := templates.New()
+	require.NoError(t, err)
+
+	msg, err := r.Render(models.KindEmailVerification, models.EmailVerificationData{
+		Token:     "verify-token",
+		ExpiresAt: time.Date(2030, 1, 2, 3, 4, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "Confirm your email", msg.Subject)
+	assert.Contains(t, msg.Text, "verify-token")
+	assert.Contains(t, msg.Text, "2030-01-02 03:04 UTC")
+	assert.Contains(t, msg.HTML, "<code>verify-token</code>")
+}
+
+func TestRender_UnknownKind(t *testing.T) {
+	r, err := templates.New()
+	require.NoError(t, err)
+
+	_, err = r.Render("unknown", nil)
+	require.ErrorIs(t, err, templates.ErrUnknownKind)
+}
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS notifications
+(
+    id              INTEGER PRIMARY KEY,
+    kind            TEXT    NOT NULL,
+    recipient       TEXT    NOT NULL,
+    subject         TEXT    NOT NULL,
+    text_body       TEXT    NOT NULL,
+    html_body       TEXT    NOT NULL,
+    status          TEXT    NOT NULL CHECK (status IN ('pending', 'sent', 'dead')),
+    -- failed delivery attempts
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at INTEGER NOT NULL,
+    last_error      TEXT    NOT NULL DEFAULT '',
+    created_at      INTEGER NOT NULL,
+    -- 0 if not sent
+    sent_at         INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX IF NOT EXISTS idx_notifications_due ON notifications (status, next_attempt_at);
+
+-- notifications given up on after too many failed attempts
+CREATE TABLE IF NOT EXISTS dead_letters
+(
+    notification_id INTEGER PRIMARY KEY REFERENCES notifications (id),
+    failed_at       INTEGER NOT NULL
+);
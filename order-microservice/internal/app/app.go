@@ -0,0 +1,73 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	grpcapp "github.com/Kry0z1/e-commerce/order-microservice/internal/app/grpc"
+	cataloggrpc "github.com/Kry0z1/e-commerce/order-microservice/internal/clients/catalog/grpc"
+	notificationsgrpc "github.com/Kry0z1/e-commerce/order-microservice/internal/clients/notifications/grpc"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/config"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/service"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/storage/sqlite"
+	"github.com/Kry0z1/e-commerce/ssoclient"
+)
+
+type App struct {
+	GRPCServer *grpcapp.App
+}
+
+func New(
+	log *slog.Logger,
+	grpcPort int,
+	storagePath string,
+	ssoCfg config.SSOConfig,
+	catalogCfg config.CatalogConfig,
+	notificationsCfg config.NotificationsConfig,
+	ordersCfg config.OrdersConfig,
+) *App {
+	storage, err := sqlite.New(storagePath)
+	if err != nil {
+		panic(err)
+	}
+
+	ssoClient, err := ssoclient.New(log, ssoCfg.Address, ssoCfg.Timeout, ssoCfg.RetriesCount)
+	if err != nil {
+		panic(err)
+	}
+
+	catalogClient, err := cataloggrpc.New(log, catalogCfg.Address, catalogCfg.Timeout, catalogCfg.RetriesCount)
+	if err != nil {
+		panic(err)
+	}
+
+	verifier := authtoken.NewVerifier(
+		authtoken.NewCachedKeySet(ssoClient, ssoCfg.KeysCacheTTL),
+		authtoken.WithIssuer(ssoCfg.Issuer),
+		authtoken.WithAudience(ssoCfg.Audience...),
+		authtoken.WithLeeway(ssoCfg.Leeway),
+		authtoken.WithRevocationCheck(ssoclient.NewCachedRevocationChecker(ssoClient, ssoCfg.RevocationCacheTTL)),
+	)
+
+	admins := ssoclient.NewCachedAdminChecker(ssoClient, ssoCfg.AdminCacheTTL)
+
+	var notifier service.Notifier = service.NewLogNotifier(log)
+	if notificationsCfg.Address != "" {
+		notifier, err = notificationsgrpc.New(log, notificationsCfg.Address, notificationsCfg.Timeout)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	srvc := service.New(
+		log,
+		storage, storage, catalogClient, admins, notifier,
+		ordersCfg.MaxLines, ordersCfg.MaxQuantity,
+	)
+
+	grpcApp := grpcapp.New(srvc, verifier, log, grpcPort)
+
+	return &App{
+		GRPCServer: grpcApp,
+	}
+}
@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+	notificationsv1 "github.com/Kry0z1/e-commerce/protos/gen/go/notifications"
+	grpclog "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client queues emails in notification service
+type Client struct {
+	api     notificationsv1.NotificationsClient
+	timeout time.Duration
+}
+
+func New(log *slog.Logger, addr string, timeout time.Duration) (*Client, error) {
+	const op = "clients.notifications.grpc.New"
+
+	// requests carry email addresses of buyers, so only calls are logged
+	logOpts := []grpclog.Option{
+		grpclog.WithLogOnEvents(grpclog.StartCall, grpclog.FinishCall),
+	}
+
+	cc, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			grpclog.UnaryClientInterceptor(InterceptorLogger(log), logOpts...),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Client{
+		api:     notificationsv1.NewNotificationsClient(cc),
+		timeout: timeout,
+	}, nil
+}
+
+// OrderConfirmation queues email with lines and total of paid order to its buyer
+func (c *Client) OrderConfirmation(ctx context.Context, order models.Order) error {
+	const op = "clients.notifications.grpc.OrderConfirmation"
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	confirmation := &notificationsv1.OrderConfirmation{
+		OrderId: order.ID,
+		Lines:   make([]*notificationsv1.OrderConfirmationLine, 0, len(order.Lines)),
+		Total:   order.Total,
+	}
+	for _, line := range order.Lines {
+		confirmation.Lines = append(confirmation.Lines, &notificationsv1.OrderConfirmationLine{
+			Title:     line.Title,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			LineTotal: line.Total(),
+		})
+	}
+
+	_, err := c.api.SendNotification(ctx, &notificationsv1.SendNotificationRequest{
+		Recipient: order.BuyerEmail,
+		Payload:   &notificationsv1.SendNotificationRequest_OrderConfirmation{OrderConfirmation: confirmation},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// yoinked
+func InterceptorLogger(l *slog.Logger) grpclog.Logger {
+	return grpclog.LoggerFunc(func(ctx context.Context, lvl grpclog.Level, msg string, fields ...any) {
+		l.Log(ctx, slog.Level(lvl), msg, fields...)
+	})
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"time"
+
+	"github.com/ilyakaznacheev/cleanenv"
+)
+
+type Config struct {
+	// one of "local", "prod"
+	Env           string              `yaml:"env" env-default:"local"`
+	StoragePath   string              `yaml:"storage_path" env-required:"true"`
+	GRPC          GRPCConfig          `yaml:"grpc" env-required:"true"`
+	SSO           SSOConfig           `yaml:"sso" env-required:"true"`
+	Catalog       CatalogConfig       `yaml:"catalog" env-required:"true"`
+	Notifications NotificationsConfig `yaml:"notifications"`
+	Orders        OrdersConfig        `yaml:"orders"`
+}
+
+type GRPCConfig struct {
+	Port    int           `yaml:"port"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type SSOConfig struct {
+	Address      string        `yaml:"address" env-required:"true"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	RetriesCount int           `yaml:"retries_count" env-default:"3"`
+	// How long signing keys fetched from sso are trusted without refetch
+	KeysCacheTTL time.Duration `yaml:"keys_cache_ttl" env-default:"5m"`
+	// Expected "iss" claim of tokens
+	Issuer string `yaml:"issuer" env-default:"sso"`
+	// Ids of apps whose tokens are accepted, empty means any app
+	Audience []string `yaml:"audience"`
+	// Allowed clock skew between sso and orders
+	Leeway time.Duration `yaml:"leeway" env-default:"30s"`
+	// How long answer of sso about token revocation is trusted, revoked token
+	// is accepted for at most this long after revocation
+	RevocationCacheTTL time.Duration `yaml:"revocation_cache_ttl" env-default:"5s"`
+	// How long admin status of user fetched from sso is trusted
+	AdminCacheTTL time.Duration `yaml:"admin_cache_ttl" env-default:"30s"`
+}
+
+type CatalogConfig struct {
+	Address      string        `yaml:"address" env-required:"true"`
+	Timeout      time.Duration `yaml:"timeout" env-default:"5s"`
+	RetriesCount int           `yaml:"retries_count" env-default:"3"`
+}
+
+type NotificationsConfig struct {
+	// Empty address -> emails are not sent
+	Address string        `yaml:"address"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
+type OrdersConfig struct {
+	// Maximum number of lines in order
+	MaxLines int `yaml:"max_lines" env-default:"100"`
+	// Maximum quantity of single line
+	MaxQuantity int64 `yaml:"max_quantity" env-default:"99"`
+}
+
+func MustLoad() *Config {
+	path := getConfigPath()
+	return MustLoadPath(path)
+}
+
+func MustLoadPath(path string) *Config {
+	if path == "" {
+		panic("empty config path")
+	}
+
+	var cfg Config
+
+	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+		panic("couldn't read config: " + err.Error())
+	}
+
+	return &cfg
+}
+
+// Gets config path in this priority:
+// param > env > default
+//
+// Environment variable is CONFIG_PATH.
+// Default is empty string.
+func getConfigPath() string {
+	var res string
+
+	flag.StringVar(&res, "config", "", "path to config file")
+	flag.Parse()
+
+	if res == "" {
+		res = os.Getenv("CONFIG_PATH")
+	}
+
+	return res
+}
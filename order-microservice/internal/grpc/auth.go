@@ -0,0 +1,40 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	ordersv1 "github.com/Kry0z1/e-commerce/protos/gen/go/orders"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthRequiredMethods are methods that can't be called without access token
+var AuthRequiredMethods = []string{
+	ordersv1.Orders_PlaceOrder_FullMethodName,
+	ordersv1.Orders_GetOrder_FullMethodName,
+	ordersv1.Orders_ListMyOrders_FullMethodName,
+	ordersv1.Orders_ListSellerOrders_FullMethodName,
+	ordersv1.Orders_CancelOrder_FullMethodName,
+	ordersv1.Orders_UpdateOrderStatus_FullMethodName,
+}
+
+// caller returns id of user authenticated by interceptors
+func caller(ctx context.Context) (int64, error) {
+	principal, err := callerPrincipal(ctx)
+	if err != nil {
+		return -1, err
+	}
+
+	return principal.UserID, nil
+}
+
+// callerPrincipal returns user authenticated by interceptors
+func callerPrincipal(ctx context.Context) (authtoken.Principal, error) {
+	principal, ok := authtoken.PrincipalFromContext(ctx)
+	if !ok {
+		return principal, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return principal, nil
+}
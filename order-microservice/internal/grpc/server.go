@@ -0,0 +1,260 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/Kry0z1/e-commerce/protos/gen/go/orders"
+	"google.golang.org/grpc"
+)
+
+type serverAPI struct {
+	ordersv1.UnimplementedOrdersServer
+	srvc service.Service
+}
+
+func Register(gRPCServer *grpc.Server, srvc service.Service) {
+	ordersv1.RegisterOrdersServer(gRPCServer, &serverAPI{srvc: srvc})
+}
+
+func parseServiceError(err error) error {
+	if err != nil {
+		if errors.Is(err, service.ErrOrderNotFound) || errors.Is(err, service.ErrListingNotFound) ||
+			errors.Is(err, service.ErrVariantNotFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrListingClosed) || errors.Is(err, service.ErrVariantRequired) ||
+			errors.Is(err, service.ErrOwnListing) || errors.Is(err, service.ErrIllegalTransition) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, service.ErrNotEnoughPermissions) {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, service.ErrEmptyOrder) || errors.Is(err, service.ErrInvalidPageToken) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, service.ErrTooManyLines) || errors.Is(err, service.ErrQuantityTooLarge) {
+			return status.Error(codes.ResourceExhausted, err.Error())
+		}
+		if errors.Is(err, service.ErrOrderChanged) {
+			return status.Error(codes.Aborted, err.Error())
+		}
+
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return nil
+}
+
+func (s *serverAPI) PlaceOrder(ctx context.Context, req *ordersv1.PlaceOrderRequest) (*ordersv1.PlaceOrderResponse, error) {
+	items := make([]models.OrderItem, 0, len(req.GetItems()))
+	for _, item := range req.GetItems() {
+		if item.GetListingId() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "missing listing_id")
+		}
+		if item.GetVariantId() < 0 {
+			return nil, status.Error(codes.InvalidArgument, "variant_id cannot be less than 0")
+		}
+		if item.GetQuantity() <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "quantity must be positive")
+		}
+
+		items = append(items, models.OrderItem{
+			ListingID: item.GetListingId(),
+			VariantID: item.GetVariantId(),
+			Quantity:  item.GetQuantity(),
+		})
+	}
+
+	principal, err := callerPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := s.srvc.PlaceOrder(ctx, items, principal.UserID, principal.Email)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &ordersv1.PlaceOrderResponse{OrderIds: ids}, nil
+}
+
+func (s *serverAPI) GetOrder(ctx context.Context, req *ordersv1.GetOrderRequest) (*ordersv1.GetOrderResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, history, err := s.srvc.Order(ctx, req.GetId(), callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	transitions := make([]*ordersv1.Transition, 0, len(history))
+	for _, transition := range history {
+		transitions = append(transitions, &ordersv1.Transition{
+			From:      statusToProto(transition.From),
+			To:        statusToProto(transition.To),
+			Actor:     transition.Actor,
+			CreatedAt: transition.CreatedAt.Unix(),
+		})
+	}
+
+	return &ordersv1.GetOrderResponse{
+		Order:   orderToProto(order),
+		History: transitions,
+	}, nil
+}
+
+func (s *serverAPI) ListMyOrders(ctx context.Context, req *ordersv1.ListMyOrdersRequest) (*ordersv1.ListMyOrdersResponse, error) {
+	filter, err := statusFilter(req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, nextPageToken, err := s.srvc.ListBuyerOrders(ctx, callerID, filter, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &ordersv1.ListMyOrdersResponse{
+		Orders:        ordersToProto(orders),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (s *serverAPI) ListSellerOrders(ctx context.Context, req *ordersv1.ListSellerOrdersRequest) (*ordersv1.ListSellerOrdersResponse, error) {
+	filter, err := statusFilter(req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, nextPageToken, err := s.srvc.ListSellerOrders(ctx, callerID, filter, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &ordersv1.ListSellerOrdersResponse{
+		Orders:        ordersToProto(orders),
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (s *serverAPI) CancelOrder(ctx context.Context, req *ordersv1.CancelOrderRequest) (*ordersv1.CancelOrderResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.CancelOrder(ctx, req.GetId(), callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &ordersv1.CancelOrderResponse{}, nil
+}
+
+func (s *serverAPI) UpdateOrderStatus(ctx context.Context, req *ordersv1.UpdateOrderStatusRequest) (*ordersv1.UpdateOrderStatusResponse, error) {
+	to, ok := statusFromProto(req.GetStatus())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown status")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.srvc.UpdateOrderStatus(ctx, req.GetId(), to, callerID); err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &ordersv1.UpdateOrderStatusResponse{}, nil
+}
+
+var statuses = map[ordersv1.OrderStatus]models.OrderStatus{
+	ordersv1.OrderStatus_ORDER_STATUS_PENDING:   models.StatusPending,
+	ordersv1.OrderStatus_ORDER_STATUS_PAID:      models.StatusPaid,
+	ordersv1.OrderStatus_ORDER_STATUS_SHIPPED:   models.StatusShipped,
+	ordersv1.OrderStatus_ORDER_STATUS_DELIVERED: models.StatusDelivered,
+	ordersv1.OrderStatus_ORDER_STATUS_CANCELLED: models.StatusCancelled,
+	ordersv1.OrderStatus_ORDER_STATUS_REFUNDED:  models.StatusRefunded,
+}
+
+func statusFromProto(st ordersv1.OrderStatus) (models.OrderStatus, bool) {
+	res, ok := statuses[st]
+	return res, ok
+}
+
+func statusToProto(st models.OrderStatus) ordersv1.OrderStatus {
+	for proto, model := range statuses {
+		if model == st {
+			return proto
+		}
+	}
+
+	return ordersv1.OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+// statusFilter converts optional status of list requests, unset -> nil
+func statusFilter(st *ordersv1.OrderStatus) (*models.OrderStatus, error) {
+	if st == nil {
+		return nil, nil
+	}
+
+	res, ok := statusFromProto(*st)
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "unknown status")
+	}
+
+	return &res, nil
+}
+
+func orderToProto(order models.Order) *ordersv1.Order {
+	lines := make([]*ordersv1.OrderLine, 0, len(order.Lines))
+	for _, line := range order.Lines {
+		lines = append(lines, &ordersv1.OrderLine{
+			ListingId: line.ListingID,
+			VariantId: line.VariantID,
+			Title:     line.Title,
+			Sku:       line.SKU,
+			Quantity:  line.Quantity,
+			UnitPrice: line.UnitPrice,
+			LineTotal: line.Total(),
+		})
+	}
+
+	return &ordersv1.Order{
+		Id:        order.ID,
+		Buyer:     order.Buyer,
+		Seller:    order.Seller,
+		Status:    statusToProto(order.Status),
+		Total:     order.Total,
+		CreatedAt: order.CreatedAt.Unix(),
+		UpdatedAt: order.UpdatedAt.Unix(),
+		Lines:     lines,
+	}
+}
+
+func ordersToProto(orders []models.Order) []*ordersv1.Order {
+	res := make([]*ordersv1.Order, 0, len(orders))
+	for _, order := range orders {
+		res = append(res, orderToProto(order))
+	}
+
+	return res
+}
@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+type OrderStatus string
+
+const (
+	StatusPending   OrderStatus = "pending"
+	StatusPaid      OrderStatus = "paid"
+	StatusShipped   OrderStatus = "shipped"
+	StatusDelivered OrderStatus = "delivered"
+	StatusCancelled OrderStatus = "cancelled"
+	StatusRefunded  OrderStatus = "refunded"
+)
+
+// transitions lists statuses order can move to from given status
+var transitions = map[OrderStatus][]OrderStatus{
+	StatusPending:   {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusShipped, StatusRefunded},
+	StatusShipped:   {StatusDelivered},
+	StatusDelivered: {StatusRefunded},
+}
+
+// CanTransitionTo reports whether order in status s may move to status to
+func (s OrderStatus) CanTransitionTo(to OrderStatus) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+type Order struct {
+	ID    int64
+	Buyer int64
+	// Order confirmation is sent there, empty -> not sent
+	BuyerEmail string
+	Seller     int64
+	Status     OrderStatus
+	// Sum of line totals
+	Total     int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Lines     []OrderLine
+}
+
+// OrderLine is snapshot of listing (or its variant) at purchase time
+type OrderLine struct {
+	ListingID int64
+	// 0 if line is listing itself
+	VariantID int64
+	Title     string
+	// Empty if line is listing itself
+	SKU       string
+	Quantity  int64
+	UnitPrice int64
+}
+
+func (l OrderLine) Total() int64 {
+	return l.UnitPrice * l.Quantity
+}
+
+type Transition struct {
+	// Empty for placement of order
+	From      OrderStatus
+	To        OrderStatus
+	Actor     int64
+	CreatedAt time.Time
+}
+
+// OrderItem is item buyer asks to order
+type OrderItem struct {
+	ListingID int64
+	VariantID int64
+	Quantity  int64
+}
+
+// OrderFilter describes orders to list
+//
+// Zero Buyer or Seller -> filter is not applied, nil Status -> any status
+type OrderFilter struct {
+	Buyer  int64
+	Seller int64
+	Status *OrderStatus
+}
@@ -0,0 +1,37 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var statuses = []models.OrderStatus{
+	models.StatusPending,
+	models.StatusPaid,
+	models.StatusShipped,
+	models.StatusDelivered,
+	models.StatusCancelled,
+	models.StatusRefunded,
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	allowed := map[[2]models.OrderStatus]bool{
+		{models.StatusPending, models.StatusPaid}:       true,
+		{models.StatusPending, models.StatusCancelled}:  true,
+		{models.StatusPaid, models.StatusShipped}:       true,
+		{models.StatusPaid, models.StatusRefunded}:      true,
+		{models.StatusShipped, models.StatusDelivered}:  true,
+		{models.StatusDelivered, models.StatusRefunded}: true,
+	}
+
+	// every pair of statuses, so that new edge can't appear unnoticed
+	for _, from := range statuses {
+		for _, to := range statuses {
+			t.Run(string(from)+" to "+string(to), func(t *testing.T) {
+				assert.Equal(t, allowed[[2]models.OrderStatus{from, to}], from.CanTransitionTo(to))
+			})
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package models
+
+// Product is listing as seen by orders: only what's needed for order lines
+type Product struct {
+	ListingID int64
+	Title     string
+	Price     int64
+	Closed    bool
+	// id of listing creator
+	Seller int64
+	// Variant id -> variant
+	Variants map[int64]ProductVariant
+}
+
+type ProductVariant struct {
+	SKU   string
+	Price int64
+}
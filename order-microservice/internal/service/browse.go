@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// orderCursor points at the last order of previous page
+type orderCursor struct {
+	ID int64 `json:"id"`
+}
+
+// ListBuyerOrders returns page of orders placed by buyer and token of the next page.
+// Empty next page token means there are no more orders.
+func (s *Service) ListBuyerOrders(
+	ctx context.Context,
+	buyer int64,
+	status *models.OrderStatus,
+	pageSize int,
+	pageToken string,
+) ([]models.Order, string, error) {
+	const op = "service.ListBuyerOrders"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("buyer", buyer))
+
+	return s.listOrders(ctx, op, log, models.OrderFilter{Buyer: buyer, Status: status}, pageSize, pageToken)
+}
+
+// ListSellerOrders returns page of orders of listings created by seller and token of the next page.
+// Empty next page token means there are no more orders.
+func (s *Service) ListSellerOrders(
+	ctx context.Context,
+	seller int64,
+	status *models.OrderStatus,
+	pageSize int,
+	pageToken string,
+) ([]models.Order, string, error) {
+	const op = "service.ListSellerOrders"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("seller", seller))
+
+	return s.listOrders(ctx, op, log, models.OrderFilter{Seller: seller, Status: status}, pageSize, pageToken)
+}
+
+func (s *Service) listOrders(
+	ctx context.Context,
+	op string,
+	log *slog.Logger,
+	filter models.OrderFilter,
+	pageSize int,
+	pageToken string,
+) ([]models.Order, string, error) {
+	log.Info("started orders listing")
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	var cursor orderCursor
+	if pageToken != "" {
+		if err := decodePageToken(pageToken, &cursor); err != nil || cursor.ID <= 0 {
+			log.Info("invalid page token")
+			return nil, "", ErrInvalidPageToken
+		}
+	}
+
+	// one extra order tells whether there is next page
+	orders, err := s.orderProvider.Orders(ctx, filter, cursor.ID, pageSize+1)
+	if err != nil {
+		log.Error("internal error", ll.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var nextPageToken string
+	if len(orders) > pageSize {
+		orders = orders[:pageSize]
+		nextPageToken = encodePageToken(orderCursor{ID: orders[pageSize-1].ID})
+	}
+
+	log.Info("listing succeeded", slog.Int("count", len(orders)))
+	return orders, nextPageToken, nil
+}
+
+// encodePageToken makes opaque token out of cursor struct
+func encodePageToken(cursor any) string {
+	// cursors are plain structs, marshalling can't fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, cursor)
+}
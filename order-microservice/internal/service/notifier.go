@@ -0,0 +1,30 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+)
+
+// Notifier sends emails to buyers. Failures never fail calls that trigger emails.
+type Notifier interface {
+	// OrderConfirmation sends lines and total of order to order.BuyerEmail
+	OrderConfirmation(ctx context.Context, order models.Order) error
+}
+
+// LogNotifier is used when orders run without notification service:
+// emails are only logged, which is enough for local runs
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func NewLogNotifier(log *slog.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) OrderConfirmation(_ context.Context, order models.Order) error {
+	n.log.Info("order confirmation email",
+		slog.String("email", order.BuyerEmail), slog.Int64("order_id", order.ID), slog.Int64("total", order.Total))
+	return nil
+}
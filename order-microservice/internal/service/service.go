@@ -0,0 +1,382 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/clients/catalog"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/storage"
+)
+
+var (
+	ErrOrderNotFound        = errors.New("order not found")
+	ErrListingNotFound      = errors.New("listing not found")
+	ErrVariantNotFound      = errors.New("variant not found")
+	ErrVariantRequired      = errors.New("listing has variants, variant must be chosen")
+	ErrListingClosed        = errors.New("listing is closed")
+	ErrOwnListing           = errors.New("can't order own listing")
+	ErrEmptyOrder           = errors.New("order has no items")
+	ErrTooManyLines         = errors.New("too many lines in order")
+	ErrQuantityTooLarge     = errors.New("quantity of line is too large")
+	ErrNotEnoughPermissions = errors.New("user is not authorized for this action")
+	ErrIllegalTransition    = errors.New("order can't move to this status from its current status")
+	ErrOrderChanged         = errors.New("order was changed concurrently, retry")
+	ErrInvalidPageToken     = errors.New("invalid page token")
+)
+
+type OrderSaver interface {
+	SaveOrders(ctx context.Context, orders []models.Order) ([]int64, error)
+	TransitionOrder(
+		ctx context.Context,
+		id int64,
+		from models.OrderStatus,
+		to models.OrderStatus,
+		actor int64,
+	) error
+}
+
+type OrderProvider interface {
+	Order(ctx context.Context, id int64) (models.Order, error)
+	Orders(ctx context.Context, filter models.OrderFilter, afterID int64, limit int) ([]models.Order, error)
+	History(ctx context.Context, orderID int64) ([]models.Transition, error)
+}
+
+type ProductProvider interface {
+	Product(ctx context.Context, listingID int64) (models.Product, error)
+}
+
+type Service struct {
+	log           *slog.Logger
+	orderSaver    OrderSaver
+	orderProvider OrderProvider
+	products      ProductProvider
+	admins        AdminChecker
+	notifier      Notifier
+
+	maxLines    int
+	maxQuantity int64
+}
+
+func New(
+	log *slog.Logger,
+	orderSaver OrderSaver,
+	orderProvider OrderProvider,
+	products ProductProvider,
+	admins AdminChecker,
+	notifier Notifier,
+	maxLines int,
+	maxQuantity int64,
+) *Service {
+	return &Service{
+		log:           log,
+		orderSaver:    orderSaver,
+		orderProvider: orderProvider,
+		products:      products,
+		admins:        admins,
+		notifier:      notifier,
+		maxLines:      maxLines,
+		maxQuantity:   maxQuantity,
+	}
+}
+
+// PlaceOrder snapshots current catalog titles and prices of items into pending orders,
+// one order per seller. Returns ids of created orders.
+//
+// Order confirmation is sent to buyerEmail once order is paid, empty buyerEmail -> not sent.
+func (s *Service) PlaceOrder(ctx context.Context, items []models.OrderItem, buyer int64, buyerEmail string) ([]int64, error) {
+	const op = "service.PlaceOrder"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("buyer", buyer))
+
+	log.Info("started placing order")
+
+	items, err := mergeItems(items, s.maxQuantity)
+	if err != nil {
+		log.Info("quantity too large")
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		log.Info("empty order")
+		return nil, ErrEmptyOrder
+	}
+
+	if len(items) > s.maxLines {
+		log.Info("too many lines")
+		return nil, ErrTooManyLines
+	}
+
+	var (
+		orders   []models.Order
+		bySeller = make(map[int64]int)
+		products = make(map[int64]models.Product)
+	)
+
+	for _, item := range items {
+		product, ok := products[item.ListingID]
+		if !ok {
+			var err error
+			product, err = s.product(ctx, log, item.ListingID)
+			if err != nil {
+				return nil, knownError(op, err)
+			}
+			products[item.ListingID] = product
+		}
+
+		if product.Seller == buyer {
+			log.Info("buyer is seller", slog.Int64("listing_id", item.ListingID))
+			return nil, ErrOwnListing
+		}
+
+		line, err := snapshotLine(product, item)
+		if err != nil {
+			log.Info("can't order item", slog.Int64("listing_id", item.ListingID), ll.Err(err))
+			return nil, err
+		}
+
+		i, ok := bySeller[product.Seller]
+		if !ok {
+			i = len(orders)
+			bySeller[product.Seller] = i
+			orders = append(orders, models.Order{Buyer: buyer, BuyerEmail: buyerEmail, Seller: product.Seller})
+		}
+
+		orders[i].Lines = append(orders[i].Lines, line)
+		orders[i].Total += line.Total()
+	}
+
+	ids, err := s.orderSaver.SaveOrders(ctx, orders)
+	if err != nil {
+		log.Error("failed to save orders", ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("placing succeeded", slog.Any("order_ids", ids))
+	return ids, nil
+}
+
+// Order returns order with its history if caller is its buyer, seller or admin
+func (s *Service) Order(ctx context.Context, id int64, callerID int64) (models.Order, []models.Transition, error) {
+	const op = "service.Order"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("order_id", id))
+
+	log.Info("started getting order")
+
+	order, err := s.order(ctx, log, id)
+	if err != nil {
+		return order, nil, knownError(op, err)
+	}
+
+	if order.Buyer != callerID && order.Seller != callerID {
+		if err := s.requireAdmin(ctx, log, callerID); err != nil {
+			return models.Order{}, nil, knownError(op, err)
+		}
+	}
+
+	history, err := s.orderProvider.History(ctx, id)
+	if err != nil {
+		log.Error("failed to get history", ll.Err(err))
+		return order, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("getting succeeded")
+	return order, history, nil
+}
+
+// CancelOrder cancels pending order, caller must be its buyer, seller or admin
+func (s *Service) CancelOrder(ctx context.Context, id int64, callerID int64) error {
+	const op = "service.CancelOrder"
+
+	return s.transition(ctx, op, id, models.StatusCancelled, callerID)
+}
+
+// UpdateOrderStatus moves order to status to.
+//
+// Only admins can mark orders paid; sellers and admins can ship, deliver and refund;
+// buyers, sellers and admins can cancel.
+func (s *Service) UpdateOrderStatus(ctx context.Context, id int64, to models.OrderStatus, callerID int64) error {
+	const op = "service.UpdateOrderStatus"
+
+	return s.transition(ctx, op, id, to, callerID)
+}
+
+func (s *Service) transition(ctx context.Context, op string, id int64, to models.OrderStatus, callerID int64) error {
+	log := s.log.With(
+		slog.String("op", op),
+		slog.Int64("caller_id", callerID),
+		slog.Int64("order_id", id),
+		slog.String("to", string(to)),
+	)
+
+	log.Info("started order transition")
+
+	order, err := s.order(ctx, log, id)
+	if err != nil {
+		return knownError(op, err)
+	}
+
+	if !mayMove(order, to, callerID) {
+		if err := s.requireAdmin(ctx, log, callerID); err != nil {
+			return knownError(op, err)
+		}
+	}
+
+	if !order.Status.CanTransitionTo(to) {
+		log.Info("illegal transition", slog.String("from", string(order.Status)))
+		return ErrIllegalTransition
+	}
+
+	if err := s.orderSaver.TransitionOrder(ctx, id, order.Status, to, callerID); err != nil {
+		if errors.Is(err, storage.ErrOrderNotFound) {
+			log.Info("order not found on transition")
+			return ErrOrderNotFound
+		}
+		if errors.Is(err, storage.ErrStatusChanged) {
+			log.Info("order changed concurrently")
+			return ErrOrderChanged
+		}
+		log.Error("failed to transition order", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("transition succeeded", slog.String("from", string(order.Status)))
+
+	if to == models.StatusPaid {
+		s.confirm(ctx, log, order)
+	}
+
+	return nil
+}
+
+// confirm sends order confirmation to buyer of just paid order.
+// Order is paid already, so failed email is not worth failing the call.
+func (s *Service) confirm(ctx context.Context, log *slog.Logger, order models.Order) {
+	if order.BuyerEmail == "" {
+		log.Info("buyer email unknown, order confirmation not sent")
+		return
+	}
+
+	order.Status = models.StatusPaid
+	if err := s.notifier.OrderConfirmation(ctx, order); err != nil {
+		log.Warn("failed to send order confirmation", ll.Err(err))
+	}
+}
+
+// mayMove reports whether caller may move order to status to without being admin
+func mayMove(order models.Order, to models.OrderStatus, callerID int64) bool {
+	switch to {
+	case models.StatusCancelled:
+		return order.Buyer == callerID || order.Seller == callerID
+	case models.StatusShipped, models.StatusDelivered, models.StatusRefunded:
+		return order.Seller == callerID
+	default:
+		return false
+	}
+}
+
+func (s *Service) order(ctx context.Context, log *slog.Logger, id int64) (models.Order, error) {
+	order, err := s.orderProvider.Order(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrOrderNotFound) {
+			log.Info("order not found")
+			return order, ErrOrderNotFound
+		}
+		log.Error("failed to get order", ll.Err(err))
+		return order, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	return order, nil
+}
+
+func (s *Service) product(ctx context.Context, log *slog.Logger, listingID int64) (models.Product, error) {
+	product, err := s.products.Product(ctx, listingID)
+	if err != nil {
+		if errors.Is(err, catalog.ErrListingNotFound) {
+			log.Info("listing not found", slog.Int64("listing_id", listingID))
+			return product, ErrListingNotFound
+		}
+		log.Error("failed to get product", ll.Err(err))
+		return product, fmt.Errorf("failed to get product: %w", err)
+	}
+
+	return product, nil
+}
+
+// snapshotLine makes order line out of item priced by product
+func snapshotLine(product models.Product, item models.OrderItem) (models.OrderLine, error) {
+	line := models.OrderLine{
+		ListingID: item.ListingID,
+		VariantID: item.VariantID,
+		Title:     product.Title,
+		Quantity:  item.Quantity,
+		UnitPrice: product.Price,
+	}
+
+	if product.Closed {
+		return line, ErrListingClosed
+	}
+
+	if item.VariantID == 0 {
+		if len(product.Variants) > 0 {
+			return line, ErrVariantRequired
+		}
+		return line, nil
+	}
+
+	variant, ok := product.Variants[item.VariantID]
+	if !ok {
+		return line, ErrVariantNotFound
+	}
+
+	line.SKU = variant.SKU
+	line.UnitPrice = variant.Price
+
+	return line, nil
+}
+
+// mergeItems sums up quantities of the same listing and variant keeping first occurrence order.
+// Every line and every sum are checked against maxQuantity before they're made, so sums can't overflow.
+func mergeItems(items []models.OrderItem, maxQuantity int64) ([]models.OrderItem, error) {
+	type key struct{ listingID, variantID int64 }
+
+	merged := make([]models.OrderItem, 0, len(items))
+	index := make(map[key]int, len(items))
+
+	for _, item := range items {
+		if item.Quantity > maxQuantity {
+			return nil, ErrQuantityTooLarge
+		}
+
+		k := key{item.ListingID, item.VariantID}
+		if i, ok := index[k]; ok {
+			if merged[i].Quantity > maxQuantity-item.Quantity {
+				return nil, ErrQuantityTooLarge
+			}
+			merged[i].Quantity += item.Quantity
+			continue
+		}
+		index[k] = len(merged)
+		merged = append(merged, item)
+	}
+
+	return merged, nil
+}
+
+// knownError passes service errors through and wraps unexpected ones
+func knownError(op string, err error) error {
+	for _, known := range []error{
+		ErrOrderNotFound, ErrListingNotFound, ErrVariantNotFound, ErrVariantRequired,
+		ErrListingClosed, ErrNotEnoughPermissions,
+	} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
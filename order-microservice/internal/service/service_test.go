@@ -0,0 +1,363 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/logger/handlers/slogdiscard"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/clients/catalog"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/service"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	orderID    = 1
+	buyerEmail = "buyer@shop.test"
+	buyer      = 10
+	seller     = 20
+	admin      = 30
+	stranger   = 40
+)
+
+var statuses = []models.OrderStatus{
+	models.StatusPending,
+	models.StatusPaid,
+	models.StatusShipped,
+	models.StatusDelivered,
+	models.StatusCancelled,
+	models.StatusRefunded,
+}
+
+// oneOrder stores single order and remembers transitions made to it and orders saved
+type oneOrder struct {
+	order       models.Order
+	transitions []models.Transition
+	saved       []models.Order
+}
+
+func (o *oneOrder) SaveOrders(_ context.Context, orders []models.Order) ([]int64, error) {
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		o.saved = append(o.saved, order)
+		ids = append(ids, int64(len(o.saved)))
+	}
+
+	return ids, nil
+}
+
+func (o *oneOrder) TransitionOrder(_ context.Context, id int64, from models.OrderStatus, to models.OrderStatus, actor int64) error {
+	if id != o.order.ID {
+		return storage.ErrOrderNotFound
+	}
+	if from != o.order.Status {
+		return storage.ErrStatusChanged
+	}
+
+	o.order.Status = to
+	o.transitions = append(o.transitions, models.Transition{From: from, To: to, Actor: actor})
+
+	return nil
+}
+
+func (o *oneOrder) Order(_ context.Context, id int64) (models.Order, error) {
+	if id != o.order.ID {
+		return models.Order{}, storage.ErrOrderNotFound
+	}
+	return o.order, nil
+}
+
+func (o *oneOrder) Orders(context.Context, models.OrderFilter, int64, int) ([]models.Order, error) {
+	panic("not used")
+}
+
+func (o *oneOrder) History(context.Context, int64) ([]models.Transition, error) {
+	panic("not used")
+}
+
+// products is catalog of listing id -> product
+type products map[int64]models.Product
+
+func (p products) Product(_ context.Context, listingID int64) (models.Product, error) {
+	product, ok := p[listingID]
+	if !ok {
+		return product, catalog.ErrListingNotFound
+	}
+	return product, nil
+}
+
+// outbox remembers orders confirmations were sent for
+type outbox struct {
+	confirmed []models.Order
+	err       error
+}
+
+func (o *outbox) OrderConfirmation(_ context.Context, order models.Order) error {
+	if o.err != nil {
+		return o.err
+	}
+
+	o.confirmed = append(o.confirmed, order)
+	return nil
+}
+
+type onlyAdmin struct{}
+
+func (onlyAdmin) IsAdmin(_ context.Context, userID int64) (bool, error) {
+	return userID == admin, nil
+}
+
+const (
+	maxLines    = 3
+	maxQuantity = 10
+)
+
+func newService(order *oneOrder) *service.Service {
+	return newShop(order, nil, &outbox{})
+}
+
+func newShop(order *oneOrder, shop products, sent *outbox) *service.Service {
+	log := slog.New(slogdiscard.NewDiscardHandler())
+	return service.New(log, order, order, shop, onlyAdmin{}, sent, maxLines, maxQuantity)
+}
+
+func TestUpdateOrderStatus_Transitions(t *testing.T) {
+	callers := map[int64]string{buyer: "buyer", seller: "seller", admin: "admin", stranger: "stranger"}
+
+	// who may move order to status, whatever status it is in
+	mayMoveTo := map[models.OrderStatus][]int64{
+		models.StatusPending:   {admin},
+		models.StatusPaid:      {admin},
+		models.StatusShipped:   {seller, admin},
+		models.StatusDelivered: {seller, admin},
+		models.StatusCancelled: {buyer, seller, admin},
+		models.StatusRefunded:  {seller, admin},
+	}
+
+	allowed := map[[2]models.OrderStatus]bool{
+		{models.StatusPending, models.StatusPaid}:       true,
+		{models.StatusPending, models.StatusCancelled}:  true,
+		{models.StatusPaid, models.StatusShipped}:       true,
+		{models.StatusPaid, models.StatusRefunded}:      true,
+		{models.StatusShipped, models.StatusDelivered}:  true,
+		{models.StatusDelivered, models.StatusRefunded}: true,
+	}
+
+	for _, from := range statuses {
+		for _, to := range statuses {
+			for caller, name := range callers {
+				t.Run(string(from)+" to "+string(to)+" by "+name, func(t *testing.T) {
+					order := &oneOrder{order: models.Order{ID: orderID, Buyer: buyer, Seller: seller, Status: from}}
+
+					err := newService(order).UpdateOrderStatus(context.Background(), orderID, to, caller)
+
+					switch {
+					case !slices.Contains(mayMoveTo[to], caller):
+						require.ErrorIs(t, err, service.ErrNotEnoughPermissions)
+					case !allowed[[2]models.OrderStatus{from, to}]:
+						require.ErrorIs(t, err, service.ErrIllegalTransition)
+					default:
+						require.NoError(t, err)
+						assert.Equal(t, []models.Transition{{From: from, To: to, Actor: caller}}, order.transitions)
+						return
+					}
+
+					assert.Empty(t, order.transitions)
+				})
+			}
+		}
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		status models.OrderStatus
+		caller int64
+		err    error
+	}{
+		{name: "by buyer", status: models.StatusPending, caller: buyer},
+		{name: "by seller", status: models.StatusPending, caller: seller},
+		{name: "by admin", status: models.StatusPending, caller: admin},
+		{name: "by stranger", status: models.StatusPending, caller: stranger, err: service.ErrNotEnoughPermissions},
+		{name: "paid", status: models.StatusPaid, caller: buyer, err: service.ErrIllegalTransition},
+		{name: "cancelled", status: models.StatusCancelled, caller: buyer, err: service.ErrIllegalTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &oneOrder{order: models.Order{ID: orderID, Buyer: buyer, Seller: seller, Status: tt.status}}
+
+			err := newService(order).CancelOrder(context.Background(), orderID, tt.caller)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, models.StatusCancelled, order.order.Status)
+		})
+	}
+}
+
+func TestUpdateOrderStatus_NotFound(t *testing.T) {
+	order := &oneOrder{order: models.Order{ID: orderID, Buyer: buyer, Seller: seller, Status: models.StatusPending}}
+
+	err := newService(order).UpdateOrderStatus(context.Background(), orderID+1, models.StatusPaid, admin)
+	require.ErrorIs(t, err, service.ErrOrderNotFound)
+}
+
+func TestUpdateOrderStatus_SendsConfirmation(t *testing.T) {
+	lines := []models.OrderLine{{ListingID: 1, Title: "lamp", Quantity: 2, UnitPrice: 100}}
+	placed := models.Order{ID: orderID, Buyer: buyer, BuyerEmail: buyerEmail, Seller: seller, Lines: lines, Total: 200}
+
+	tests := []struct {
+		name      string
+		from      models.OrderStatus
+		to        models.OrderStatus
+		email     string
+		confirmed bool
+	}{
+		{name: "paid", from: models.StatusPending, to: models.StatusPaid, email: buyerEmail, confirmed: true},
+		{name: "cancelled", from: models.StatusPending, to: models.StatusCancelled, email: buyerEmail},
+		{name: "shipped", from: models.StatusPaid, to: models.StatusShipped, email: buyerEmail},
+		{name: "buyer email unknown", from: models.StatusPending, to: models.StatusPaid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := placed
+			stored.Status, stored.BuyerEmail = tt.from, tt.email
+			order := &oneOrder{order: stored}
+			sent := &outbox{}
+
+			require.NoError(t, newShop(order, nil, sent).UpdateOrderStatus(context.Background(), orderID, tt.to, admin))
+
+			if !tt.confirmed {
+				assert.Empty(t, sent.confirmed)
+				return
+			}
+
+			confirmed := placed
+			confirmed.Status = models.StatusPaid
+			assert.Equal(t, []models.Order{confirmed}, sent.confirmed)
+		})
+	}
+}
+
+func TestUpdateOrderStatus_FailedConfirmation(t *testing.T) {
+	order := &oneOrder{order: models.Order{ID: orderID, Buyer: buyer, BuyerEmail: buyerEmail, Seller: seller, Status: models.StatusPending}}
+	sent := &outbox{err: errors.New("notification service is down")}
+
+	// order is paid already, so email failure doesn't fail the call
+	require.NoError(t, newShop(order, nil, sent).UpdateOrderStatus(context.Background(), orderID, models.StatusPaid, admin))
+	assert.Equal(t, models.StatusPaid, order.order.Status)
+}
+
+func TestPlaceOrder(t *testing.T) {
+	shop := products{
+		1: {ListingID: 1, Title: "lamp", Price: 100, Seller: seller},
+		2: {ListingID: 2, Title: "chair", Price: 300, Seller: seller + 1},
+		3: {ListingID: 3, Title: "shirt", Price: 50, Seller: seller, Variants: map[int64]models.ProductVariant{
+			7: {SKU: "XL", Price: 70},
+		}},
+		4: {ListingID: 4, Title: "old lamp", Price: 100, Seller: seller, Closed: true},
+		5: {ListingID: 5, Title: "own lamp", Price: 100, Seller: buyer},
+	}
+
+	lamp := func(quantity int64) models.OrderLine {
+		return models.OrderLine{ListingID: 1, Title: "lamp", Quantity: quantity, UnitPrice: 100}
+	}
+
+	tests := []struct {
+		name   string
+		items  []models.OrderItem
+		orders []models.Order
+		err    error
+	}{
+		{
+			name:   "single line",
+			items:  []models.OrderItem{{ListingID: 1, Quantity: 2}},
+			orders: []models.Order{{Buyer: buyer, BuyerEmail: buyerEmail, Seller: seller, Lines: []models.OrderLine{lamp(2)}, Total: 200}},
+		},
+		{
+			name:   "duplicate lines are merged",
+			items:  []models.OrderItem{{ListingID: 1, Quantity: 2}, {ListingID: 1, Quantity: 3}},
+			orders: []models.Order{{Buyer: buyer, BuyerEmail: buyerEmail, Seller: seller, Lines: []models.OrderLine{lamp(5)}, Total: 500}},
+		},
+		{
+			name:  "one order per seller",
+			items: []models.OrderItem{{ListingID: 1, Quantity: 1}, {ListingID: 2, Quantity: 1}, {ListingID: 3, VariantID: 7, Quantity: 2}},
+			orders: []models.Order{
+				{Buyer: buyer, BuyerEmail: buyerEmail, Seller: seller, Lines: []models.OrderLine{
+					lamp(1),
+					{ListingID: 3, VariantID: 7, Title: "shirt", SKU: "XL", Quantity: 2, UnitPrice: 70},
+				}, Total: 240},
+				{Buyer: buyer, BuyerEmail: buyerEmail, Seller: seller + 1, Lines: []models.OrderLine{
+					{ListingID: 2, Title: "chair", Quantity: 1, UnitPrice: 300},
+				}, Total: 300},
+			},
+		},
+		{
+			name:   "merged quantity at max",
+			items:  []models.OrderItem{{ListingID: 1, Quantity: maxQuantity - 1}, {ListingID: 1, Quantity: 1}},
+			orders: []models.Order{{Buyer: buyer, BuyerEmail: buyerEmail, Seller: seller, Lines: []models.OrderLine{lamp(maxQuantity)}, Total: 100 * maxQuantity}},
+		},
+		{name: "empty", err: service.ErrEmptyOrder},
+		{
+			name:  "too many lines",
+			items: []models.OrderItem{{ListingID: 1, Quantity: 1}, {ListingID: 2, Quantity: 1}, {ListingID: 3, VariantID: 7, Quantity: 1}, {ListingID: 4, Quantity: 1}},
+			err:   service.ErrTooManyLines,
+		},
+		{name: "line above max", items: []models.OrderItem{{ListingID: 1, Quantity: maxQuantity + 1}}, err: service.ErrQuantityTooLarge},
+		{
+			name:  "merged quantity above max",
+			items: []models.OrderItem{{ListingID: 1, Quantity: maxQuantity}, {ListingID: 1, Quantity: 1}},
+			err:   service.ErrQuantityTooLarge,
+		},
+		{
+			// sum of these wraps to negative
+			name:  "merged quantity overflows",
+			items: []models.OrderItem{{ListingID: 1, Quantity: math.MaxInt64/2 + 1}, {ListingID: 1, Quantity: math.MaxInt64/2 + 1}},
+			err:   service.ErrQuantityTooLarge,
+		},
+		{
+			name:  "overflow of line merged into small one",
+			items: []models.OrderItem{{ListingID: 1, Quantity: 1}, {ListingID: 1, Quantity: math.MaxInt64}},
+			err:   service.ErrQuantityTooLarge,
+		},
+		{name: "unknown listing", items: []models.OrderItem{{ListingID: 9, Quantity: 1}}, err: service.ErrListingNotFound},
+		{name: "closed listing", items: []models.OrderItem{{ListingID: 4, Quantity: 1}}, err: service.ErrListingClosed},
+		{name: "own listing", items: []models.OrderItem{{ListingID: 5, Quantity: 1}}, err: service.ErrOwnListing},
+		{name: "variant required", items: []models.OrderItem{{ListingID: 3, Quantity: 1}}, err: service.ErrVariantRequired},
+		{name: "unknown variant", items: []models.OrderItem{{ListingID: 3, VariantID: 8, Quantity: 1}}, err: service.ErrVariantNotFound},
+		{
+			name:  "nothing is saved if any line fails",
+			items: []models.OrderItem{{ListingID: 1, Quantity: 1}, {ListingID: 4, Quantity: 1}},
+			err:   service.ErrListingClosed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &oneOrder{}
+
+			ids, err := newShop(order, shop, &outbox{}).PlaceOrder(context.Background(), tt.items, buyer, buyerEmail)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				assert.Empty(t, order.saved)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, ids, len(tt.orders))
+			assert.Equal(t, tt.orders, order.saved)
+		})
+	}
+}
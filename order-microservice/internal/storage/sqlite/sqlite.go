@@ -0,0 +1,345 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/order-microservice/internal/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+func New(storagePath string) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	db, err := sql.Open("sqlite3", storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+func (s *Storage) Stop() error {
+	return s.db.Close()
+}
+
+const orderColumns = `id, buyer_id, buyer_email, seller_id, status, total, created_at, updated_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanOrder(row scanner) (models.Order, error) {
+	var (
+		order     models.Order
+		status    string
+		createdAt int64
+		updatedAt int64
+	)
+
+	err := row.Scan(&order.ID, &order.Buyer, &order.BuyerEmail, &order.Seller, &status, &order.Total, &createdAt, &updatedAt)
+	if err != nil {
+		return order, err
+	}
+
+	order.Status = models.OrderStatus(status)
+	order.CreatedAt = time.Unix(createdAt, 0)
+	order.UpdatedAt = time.Unix(updatedAt, 0)
+
+	return order, nil
+}
+
+// SaveOrders saves pending orders with their lines in single transaction
+// and records their placement by buyer. Returns ids of orders in the same order.
+func (s *Storage) SaveOrders(ctx context.Context, orders []models.Order) ([]int64, error) {
+	const op = "storage.sqlite.SaveOrders"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+	ids := make([]int64, 0, len(orders))
+
+	for _, order := range orders {
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO orders(buyer_id, buyer_email, seller_id, status, total, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+		`, order.Buyer, order.BuyerEmail, order.Seller, models.StatusPending, order.Total, now, now)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		for _, line := range order.Lines {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO order_lines(order_id, listing_id, variant_id, title, sku, quantity, unit_price)
+				VALUES (?, ?, ?, ?, ?, ?, ?)
+			`, id, line.ListingID, line.VariantID, line.Title, line.SKU, line.Quantity, line.UnitPrice)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		if err := saveTransition(ctx, tx, id, "", models.StatusPending, order.Buyer, now); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return ids, nil
+}
+
+// Order returns order with its lines
+func (s *Storage) Order(ctx context.Context, id int64) (models.Order, error) {
+	const op = "storage.sqlite.Order"
+
+	order, err := scanOrder(s.db.QueryRowContext(ctx, `SELECT `+orderColumns+` FROM orders WHERE id = ?`, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return order, storage.ErrOrderNotFound
+		}
+		return order, fmt.Errorf("%s: %w", op, err)
+	}
+
+	orders := []models.Order{order}
+	if err := s.fillLines(ctx, orders); err != nil {
+		return order, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders[0], nil
+}
+
+// Orders returns at most limit orders matching filter with their lines, newest first.
+// afterID > 0 -> only orders older than order with that id.
+func (s *Storage) Orders(ctx context.Context, filter models.OrderFilter, afterID int64, limit int) ([]models.Order, error) {
+	const op = "storage.sqlite.Orders"
+
+	var (
+		conds []string
+		args  []any
+	)
+
+	if filter.Buyer != 0 {
+		conds = append(conds, "buyer_id = ?")
+		args = append(args, filter.Buyer)
+	}
+	if filter.Seller != 0 {
+		conds = append(conds, "seller_id = ?")
+		args = append(args, filter.Seller)
+	}
+	if filter.Status != nil {
+		conds = append(conds, "status = ?")
+		args = append(args, string(*filter.Status))
+	}
+	if afterID > 0 {
+		conds = append(conds, "id < ?")
+		args = append(args, afterID)
+	}
+
+	query := `SELECT ` + orderColumns + ` FROM orders`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var orders []models.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.fillLines(ctx, orders); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return orders, nil
+}
+
+// History returns transitions of order, oldest first
+func (s *Storage) History(ctx context.Context, orderID int64) ([]models.Transition, error) {
+	const op = "storage.sqlite.History"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT from_status, to_status, actor_id, created_at
+		FROM order_transitions
+		WHERE order_id = ?
+		ORDER BY id
+	`, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var history []models.Transition
+	for rows.Next() {
+		var (
+			transition models.Transition
+			from, to   string
+			createdAt  int64
+		)
+
+		if err := rows.Scan(&from, &to, &transition.Actor, &createdAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		transition.From = models.OrderStatus(from)
+		transition.To = models.OrderStatus(to)
+		transition.CreatedAt = time.Unix(createdAt, 0)
+		history = append(history, transition)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return history, nil
+}
+
+// TransitionOrder moves order from status from to status to and records transition.
+// Fails with storage.ErrStatusChanged if order is no longer in status from.
+func (s *Storage) TransitionOrder(
+	ctx context.Context,
+	id int64,
+	from models.OrderStatus,
+	to models.OrderStatus,
+	actor int64,
+) error {
+	const op = "storage.sqlite.TransitionOrder"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now().Unix()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE orders SET status = ?, updated_at = ? WHERE id = ? AND status = ?
+	`, to, now, id, from)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		var exists bool
+		err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM orders WHERE id = ?)`, id).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		if !exists {
+			return storage.ErrOrderNotFound
+		}
+		return storage.ErrStatusChanged
+	}
+
+	if err := saveTransition(ctx, tx, id, from, to, actor, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func saveTransition(
+	ctx context.Context,
+	tx *sql.Tx,
+	orderID int64,
+	from models.OrderStatus,
+	to models.OrderStatus,
+	actor int64,
+	at int64,
+) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO order_transitions(order_id, from_status, to_status, actor_id, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`, orderID, from, to, actor, at)
+
+	return err
+}
+
+// fillLines loads lines of all given orders with single query
+func (s *Storage) fillLines(ctx context.Context, orders []models.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	index := make(map[int64]int, len(orders))
+	args := make([]any, 0, len(orders))
+	for i, order := range orders {
+		index[order.ID] = i
+		args = append(args, order.ID)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT order_id, listing_id, variant_id, title, sku, quantity, unit_price
+		FROM order_lines
+		WHERE order_id IN (?`+strings.Repeat(", ?", len(args)-1)+`)
+		ORDER BY order_id, rowid
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			orderID int64
+			line    models.OrderLine
+		)
+
+		err := rows.Scan(&orderID, &line.ListingID, &line.VariantID, &line.Title, &line.SKU, &line.Quantity, &line.UnitPrice)
+		if err != nil {
+			return err
+		}
+
+		i := index[orderID]
+		orders[i].Lines = append(orders[i].Lines, line)
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,8 @@
+package storage
+
+import "errors"
+
+var (
+	ErrOrderNotFound = errors.New("order not found")
+	ErrStatusChanged = errors.New("order status was changed concurrently")
+)
@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS orders
+(
+    id         INTEGER PRIMARY KEY,
+    buyer_id   INTEGER NOT NULL,
+    seller_id  INTEGER NOT NULL,
+    status     TEXT    NOT NULL CHECK (status IN ('pending', 'paid', 'shipped', 'delivered', 'cancelled', 'refunded')),
+    total      INTEGER NOT NULL,
+    created_at INTEGER NOT NULL,
+    updated_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_orders_buyer ON orders (buyer_id, id);
+CREATE INDEX IF NOT EXISTS idx_orders_seller ON orders (seller_id, id);
+
+-- snapshot of listing at purchase time
+CREATE TABLE IF NOT EXISTS order_lines
+(
+    order_id   INTEGER NOT NULL REFERENCES orders (id),
+    listing_id INTEGER NOT NULL,
+    -- 0 if line is listing itself
+    variant_id INTEGER NOT NULL DEFAULT 0,
+    title      TEXT    NOT NULL,
+    sku        TEXT    NOT NULL DEFAULT '',
+    quantity   INTEGER NOT NULL,
+    unit_price INTEGER NOT NULL,
+    PRIMARY KEY (order_id, listing_id, variant_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_transitions
+(
+    id          INTEGER PRIMARY KEY,
+    order_id    INTEGER NOT NULL REFERENCES orders (id),
+    -- empty for placement of order
+    from_status TEXT    NOT NULL,
+    to_status   TEXT    NOT NULL,
+    actor_id    INTEGER NOT NULL,
+    created_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_order_transitions_order ON order_transitions (order_id, id);
@@ -0,0 +1,3 @@
+-- order confirmation is sent there once order is paid,
+-- empty for orders placed before
+ALTER TABLE orders ADD COLUMN buyer_email TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	grpcapp "github.com/Kry0z1/e-commerce/payment-microservice/internal/app/grpc"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/config"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider/fake"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/service"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/storage/sqlite"
+	"github.com/Kry0z1/e-commerce/ssoclient"
+)
+
+type App struct {
+	GRPCServer *grpcapp.App
+}
+
+func New(
+	log *slog.Logger,
+	grpcPort int,
+	storagePath string,
+	ssoCfg config.SSOConfig,
+	providerCfg config.ProviderConfig,
+	idempotencyCfg config.IdempotencyConfig,
+) *App {
+	storage, err := sqlite.New(storagePath)
+	if err != nil {
+		panic(err)
+	}
+
+	ssoClient, err := ssoclient.New(log, ssoCfg.Address, ssoCfg.Timeout, ssoCfg.RetriesCount)
+	if err != nil {
+		panic(err)
+	}
+
+	verifier := authtoken.NewVerifier(
+		authtoken.NewCachedKeySet(ssoClient, ssoCfg.KeysCacheTTL),
+		authtoken.WithIssuer(ssoCfg.Issuer),
+		authtoken.WithAudience(ssoCfg.Audience...),
+		authtoken.WithLeeway(ssoCfg.Leeway),
+		authtoken.WithRevocationCheck(ssoclient.NewCachedRevocationChecker(ssoClient, ssoCfg.RevocationCacheTTL)),
+	)
+
+	admins := ssoclient.NewCachedAdminChecker(ssoClient, ssoCfg.AdminCacheTTL)
+
+	srvc := service.New(
+		log,
+		storage, storage, storage,
+		newProvider(providerCfg.Name), admins,
+		providerCfg.Retries, providerCfg.Backoff, idempotencyCfg.KeyLease,
+	)
+
+	grpcApp := grpcapp.New(srvc, verifier, log, grpcPort)
+
+	return &App{
+		GRPCServer: grpcApp,
+	}
+}
+
+func newProvider(name string) provider.Provider {
+	switch name {
+	case "fake":
+		return fake.New()
+	default:
+		panic("unknown payment provider: " + name)
+	}
+}
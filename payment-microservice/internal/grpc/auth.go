@@ -0,0 +1,28 @@
+package grpcserver
+
+import (
+	"context"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	paymentsv1 "github.com/Kry0z1/e-commerce/protos/gen/go/payments"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AuthRequiredMethods are methods that can't be called without access token
+var AuthRequiredMethods = []string{
+	paymentsv1.Payments_CreatePaymentIntent_FullMethodName,
+	paymentsv1.Payments_CapturePayment_FullMethodName,
+	paymentsv1.Payments_RefundPayment_FullMethodName,
+	paymentsv1.Payments_GetPayment_FullMethodName,
+}
+
+// caller returns id of user authenticated by interceptors
+func caller(ctx context.Context) (int64, error) {
+	principal, ok := authtoken.PrincipalFromContext(ctx)
+	if !ok {
+		return -1, status.Error(codes.Unauthenticated, "authorization token is required")
+	}
+
+	return principal.UserID, nil
+}
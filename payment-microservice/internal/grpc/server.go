@@ -0,0 +1,199 @@
+package grpcserver
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/service"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentsv1 "github.com/Kry0z1/e-commerce/protos/gen/go/payments"
+	"google.golang.org/grpc"
+)
+
+const maxIdempotencyKeyLength = 255
+
+type serverAPI struct {
+	paymentsv1.UnimplementedPaymentsServer
+	srvc service.Service
+}
+
+func Register(gRPCServer *grpc.Server, srvc service.Service) {
+	paymentsv1.RegisterPaymentsServer(gRPCServer, &serverAPI{srvc: srvc})
+}
+
+func parseServiceError(err error) error {
+	if err != nil {
+		if errors.Is(err, service.ErrPaymentNotFound) {
+			return status.Error(codes.NotFound, err.Error())
+		}
+		if errors.Is(err, service.ErrNotCapturable) || errors.Is(err, service.ErrNotRefundable) ||
+			errors.Is(err, service.ErrRefundTooLarge) || errors.Is(err, service.ErrKeyReused) {
+			return status.Error(codes.FailedPrecondition, err.Error())
+		}
+		if errors.Is(err, service.ErrNotEnoughPermissions) {
+			return status.Error(codes.PermissionDenied, err.Error())
+		}
+		if errors.Is(err, service.ErrInvalidCard) {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+		if errors.Is(err, service.ErrPaymentChanged) || errors.Is(err, service.ErrRequestInProgress) {
+			return status.Error(codes.Aborted, err.Error())
+		}
+		if errors.Is(err, service.ErrProviderUnavailable) {
+			return status.Error(codes.Unavailable, err.Error())
+		}
+
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	return nil
+}
+
+func (s *serverAPI) CreatePaymentIntent(
+	ctx context.Context,
+	req *paymentsv1.CreatePaymentIntentRequest,
+) (*paymentsv1.CreatePaymentIntentResponse, error) {
+	if err := validateIdempotencyKey(req.GetIdempotencyKey()); err != nil {
+		return nil, err
+	}
+
+	if req.GetAmount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be positive")
+	}
+
+	if req.GetOrderId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "order_id cannot be less than 0")
+	}
+
+	if err := validateCardNumber(req.GetCardNumber()); err != nil {
+		return nil, err
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.srvc.CreatePaymentIntent(
+		ctx,
+		req.GetIdempotencyKey(),
+		req.GetAmount(),
+		req.GetOrderId(),
+		req.GetCardNumber(),
+		req.GetDescription(),
+		callerID,
+	)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &paymentsv1.CreatePaymentIntentResponse{Payment: paymentToProto(payment)}, nil
+}
+
+func (s *serverAPI) CapturePayment(ctx context.Context, req *paymentsv1.CapturePaymentRequest) (*paymentsv1.CapturePaymentResponse, error) {
+	if err := validateIdempotencyKey(req.GetIdempotencyKey()); err != nil {
+		return nil, err
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.srvc.CapturePayment(ctx, req.GetIdempotencyKey(), req.GetPaymentId(), callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &paymentsv1.CapturePaymentResponse{Payment: paymentToProto(payment)}, nil
+}
+
+func (s *serverAPI) RefundPayment(ctx context.Context, req *paymentsv1.RefundPaymentRequest) (*paymentsv1.RefundPaymentResponse, error) {
+	if err := validateIdempotencyKey(req.GetIdempotencyKey()); err != nil {
+		return nil, err
+	}
+
+	if req.GetAmount() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount cannot be less than 0")
+	}
+
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.srvc.RefundPayment(ctx, req.GetIdempotencyKey(), req.GetPaymentId(), req.GetAmount(), callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &paymentsv1.RefundPaymentResponse{Payment: paymentToProto(payment)}, nil
+}
+
+func (s *serverAPI) GetPayment(ctx context.Context, req *paymentsv1.GetPaymentRequest) (*paymentsv1.GetPaymentResponse, error) {
+	callerID, err := caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	payment, err := s.srvc.Payment(ctx, req.GetId(), callerID)
+	if err != nil {
+		return nil, parseServiceError(err)
+	}
+
+	return &paymentsv1.GetPaymentResponse{Payment: paymentToProto(payment)}, nil
+}
+
+func validateIdempotencyKey(key string) error {
+	if key == "" {
+		return status.Error(codes.InvalidArgument, "missing idempotency_key")
+	}
+
+	if len(key) > maxIdempotencyKeyLength {
+		return status.Error(codes.InvalidArgument, "idempotency_key is too long")
+	}
+
+	return nil
+}
+
+// validateCardNumber checks format only, provider decides whether card is valid
+func validateCardNumber(number string) error {
+	if len(number) < 12 || len(number) > 19 {
+		return status.Error(codes.InvalidArgument, "card_number must have 12 to 19 digits")
+	}
+
+	for _, c := range number {
+		if c < '0' || c > '9' {
+			return status.Error(codes.InvalidArgument, "card_number must have only digits")
+		}
+	}
+
+	return nil
+}
+
+var statuses = map[models.PaymentStatus]paymentsv1.PaymentStatus{
+	models.StatusAuthorized:        paymentsv1.PaymentStatus_PAYMENT_STATUS_AUTHORIZED,
+	models.StatusDeclined:          paymentsv1.PaymentStatus_PAYMENT_STATUS_DECLINED,
+	models.StatusCaptured:          paymentsv1.PaymentStatus_PAYMENT_STATUS_CAPTURED,
+	models.StatusPartiallyRefunded: paymentsv1.PaymentStatus_PAYMENT_STATUS_PARTIALLY_REFUNDED,
+	models.StatusRefunded:          paymentsv1.PaymentStatus_PAYMENT_STATUS_REFUNDED,
+}
+
+func paymentToProto(payment models.Payment) *paymentsv1.Payment {
+	return &paymentsv1.Payment{
+		Id:             payment.ID,
+		Payer:          payment.Payer,
+		OrderId:        payment.OrderID,
+		Amount:         payment.Amount,
+		RefundedAmount: payment.Refunded,
+		Status:         statuses[payment.Status],
+		CardLast4:      payment.CardLast4,
+		DeclineReason:  payment.DeclineReason,
+		Description:    payment.Description,
+		CreatedAt:      payment.CreatedAt.Unix(),
+		UpdatedAt:      payment.UpdatedAt.Unix(),
+	}
+}
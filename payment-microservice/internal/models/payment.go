@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+type PaymentStatus string
+
+const (
+	StatusAuthorized        PaymentStatus = "authorized"
+	StatusDeclined          PaymentStatus = "declined"
+	StatusCaptured          PaymentStatus = "captured"
+	StatusPartiallyRefunded PaymentStatus = "partially_refunded"
+	StatusRefunded          PaymentStatus = "refunded"
+)
+
+type Payment struct {
+	ID    int64
+	Payer int64
+	// 0 if payment is not for order
+	OrderID int64
+	// In cents
+	Amount   int64
+	Refunded int64
+	Status   PaymentStatus
+	// Last 4 digits of card
+	CardLast4 string
+	// Set for declined payments
+	DeclineReason string
+	Description   string
+	// Reference of authorization in provider, empty for declined payments
+	ProviderRef string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IdempotencyKey is key client passed to mutating call
+type IdempotencyKey struct {
+	UserID int64
+	Key    string
+	// Method the key was used for
+	Method string
+	// Hash of call arguments
+	RequestHash string
+	// 0 while call is in progress
+	PaymentID int64
+}
@@ -0,0 +1,153 @@
+// Package fake implements in-process payment provider with deterministic behaviour
+// chosen by card number. It makes no network calls and is meant for local runs and tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider"
+)
+
+// Card numbers with special behaviour, any other number passing Luhn check succeeds
+const (
+	CardSuccess           = "4242424242424242"
+	CardDeclined          = "4000000000000002"
+	CardInsufficientFunds = "4000000000009995"
+	// Fails with provider.ErrUnavailable FlakyFailures times per idempotency key, then succeeds
+	CardFlaky = "4000000000000119"
+)
+
+const FlakyFailures = 2
+
+type authorization struct {
+	amount   int64
+	captured int64
+	refunded int64
+}
+
+type Provider struct {
+	mu sync.Mutex
+
+	nextRef        int64
+	authorizations map[string]*authorization
+	// idempotency key -> reference of authorization made with it
+	authorized map[string]string
+	// idempotency keys of captures and refunds already made
+	done map[string]bool
+	// idempotency key -> failed attempts of flaky card
+	attempts map[string]int
+}
+
+func New() *Provider {
+	return &Provider{
+		authorizations: make(map[string]*authorization),
+		authorized:     make(map[string]string),
+		done:           make(map[string]bool),
+		attempts:       make(map[string]int),
+	}
+}
+
+func (p *Provider) Authorize(_ context.Context, amount int64, cardNumber string, idempotencyKey string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ref, ok := p.authorized[idempotencyKey]; ok {
+		return ref, nil
+	}
+
+	switch {
+	case !luhnValid(cardNumber):
+		return "", provider.ErrInvalidCard
+	case cardNumber == CardDeclined:
+		return "", provider.ErrCardDeclined
+	case cardNumber == CardInsufficientFunds:
+		return "", provider.ErrInsufficientFunds
+	case cardNumber == CardFlaky && p.attempts[idempotencyKey] < FlakyFailures:
+		p.attempts[idempotencyKey]++
+		return "", provider.ErrUnavailable
+	}
+
+	p.nextRef++
+	ref := fmt.Sprintf("fake_auth_%d", p.nextRef)
+
+	p.authorizations[ref] = &authorization{amount: amount}
+	p.authorized[idempotencyKey] = ref
+
+	return ref, nil
+}
+
+func (p *Provider) Capture(_ context.Context, reference string, amount int64, idempotencyKey string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done[idempotencyKey] {
+		return nil
+	}
+
+	auth, ok := p.authorizations[reference]
+	if !ok {
+		return provider.ErrAuthNotFound
+	}
+
+	if auth.captured+amount > auth.amount {
+		return provider.ErrInvalidAmount
+	}
+
+	auth.captured += amount
+	p.done[idempotencyKey] = true
+
+	return nil
+}
+
+func (p *Provider) Refund(_ context.Context, reference string, amount int64, idempotencyKey string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.done[idempotencyKey] {
+		return nil
+	}
+
+	auth, ok := p.authorizations[reference]
+	if !ok {
+		return provider.ErrAuthNotFound
+	}
+
+	if auth.refunded+amount > auth.captured {
+		return provider.ErrInvalidAmount
+	}
+
+	auth.refunded += amount
+	p.done[idempotencyKey] = true
+
+	return nil
+}
+
+// luhnValid checks card number checksum
+func luhnValid(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+
+	return sum%10 == 0
+}
@@ -0,0 +1,90 @@
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider/fake"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorize_Cards(t *testing.T) {
+	tests := []struct {
+		name string
+		card string
+		err  error
+	}{
+		{name: "success", card: fake.CardSuccess},
+		{name: "other valid card", card: "5555555555554444"},
+		{name: "declined", card: fake.CardDeclined, err: provider.ErrCardDeclined},
+		{name: "insufficient funds", card: fake.CardInsufficientFunds, err: provider.ErrInsufficientFunds},
+		{name: "bad checksum", card: "4242424242424241", err: provider.ErrInvalidCard},
+		{name: "not digits", card: "4242-4242-4242-4242", err: provider.ErrInvalidCard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := fake.New().Authorize(context.Background(), 100, tt.card, "key")
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotEmpty(t, ref)
+		})
+	}
+}
+
+func TestAuthorize_FlakyCardSucceedsAfterRetries(t *testing.T) {
+	p := fake.New()
+	ctx := context.Background()
+
+	for i := 0; i < fake.FlakyFailures; i++ {
+		_, err := p.Authorize(ctx, 100, fake.CardFlaky, "key")
+		require.ErrorIs(t, err, provider.ErrUnavailable)
+	}
+
+	ref, err := p.Authorize(ctx, 100, fake.CardFlaky, "key")
+	require.NoError(t, err)
+	assert.NotEmpty(t, ref)
+
+	// other key starts failing anew
+	_, err = p.Authorize(ctx, 100, fake.CardFlaky, "other key")
+	require.ErrorIs(t, err, provider.ErrUnavailable)
+}
+
+func TestIdempotency(t *testing.T) {
+	p := fake.New()
+	ctx := context.Background()
+
+	ref, err := p.Authorize(ctx, 100, fake.CardSuccess, "auth")
+	require.NoError(t, err)
+
+	again, err := p.Authorize(ctx, 100, fake.CardSuccess, "auth")
+	require.NoError(t, err)
+	assert.Equal(t, ref, again)
+
+	require.NoError(t, p.Capture(ctx, ref, 100, "capture"))
+	// repeated capture is not applied twice
+	require.NoError(t, p.Capture(ctx, ref, 100, "capture"))
+
+	require.NoError(t, p.Refund(ctx, ref, 60, "refund 1"))
+	require.NoError(t, p.Refund(ctx, ref, 60, "refund 1"))
+	require.NoError(t, p.Refund(ctx, ref, 40, "refund 2"))
+	require.ErrorIs(t, p.Refund(ctx, ref, 1, "refund 3"), provider.ErrInvalidAmount)
+}
+
+func TestCapture_Limits(t *testing.T) {
+	p := fake.New()
+	ctx := context.Background()
+
+	require.ErrorIs(t, p.Capture(ctx, "unknown", 1, "capture"), provider.ErrAuthNotFound)
+
+	ref, err := p.Authorize(ctx, 100, fake.CardSuccess, "auth")
+	require.NoError(t, err)
+
+	require.ErrorIs(t, p.Capture(ctx, ref, 101, "too much"), provider.ErrInvalidAmount)
+	require.ErrorIs(t, p.Refund(ctx, ref, 1, "nothing captured"), provider.ErrInvalidAmount)
+}
@@ -0,0 +1,30 @@
+// Package provider describes payment providers: services that actually move money
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrCardDeclined      = errors.New("card declined")
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrInvalidCard       = errors.New("invalid card number")
+	ErrAuthNotFound      = errors.New("authorization not found")
+	ErrInvalidAmount     = errors.New("amount exceeds what can be captured or refunded")
+	// ErrUnavailable is temporary failure: the same call may succeed later
+	ErrUnavailable = errors.New("provider temporarily unavailable")
+)
+
+// Provider charges cards.
+//
+// Every call takes idempotency key: provider must not repeat effect
+// of call already made with the same key.
+type Provider interface {
+	// Authorize holds amount on card and returns reference of authorization
+	Authorize(ctx context.Context, amount int64, cardNumber string, idempotencyKey string) (string, error)
+	// Capture takes authorized amount from card
+	Capture(ctx context.Context, reference string, amount int64, idempotencyKey string) error
+	// Refund returns part of captured amount to card
+	Refund(ctx context.Context, reference string, amount int64, idempotencyKey string) error
+}
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// requireAdmin allows only admins to proceed
+func (s *Service) requireAdmin(ctx context.Context, log *slog.Logger, callerID int64) error {
+	isAdmin, err := s.admins.IsAdmin(ctx, callerID)
+	if err != nil {
+		log.Error("failed to check admin status", ll.Err(err))
+		return fmt.Errorf("failed to check admin status: %w", err)
+	}
+
+	if !isAdmin {
+		log.Info("caller is not admin")
+		return ErrNotEnoughPermissions
+	}
+
+	return nil
+}
@@ -0,0 +1,453 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/storage"
+)
+
+var (
+	ErrPaymentNotFound      = errors.New("payment not found")
+	ErrNotEnoughPermissions = errors.New("user is not authorized for this action")
+	ErrInvalidCard          = errors.New("invalid card number")
+	ErrNotCapturable        = errors.New("only authorized payment can be captured")
+	ErrNotRefundable        = errors.New("only captured payment can be refunded")
+	ErrRefundTooLarge       = errors.New("refund exceeds amount not refunded yet")
+	ErrPaymentChanged       = errors.New("payment was changed concurrently, retry")
+	ErrKeyReused            = errors.New("idempotency key was already used with other arguments")
+	ErrRequestInProgress    = errors.New("call with this idempotency key is in progress")
+	ErrProviderUnavailable  = errors.New("payment provider is unavailable, retry later")
+)
+
+const (
+	methodCreate  = "create"
+	methodCapture = "capture"
+	methodRefund  = "refund"
+)
+
+// cleanupTimeout bounds release and completion of idempotency key,
+// which run even after client cancelled the call
+const cleanupTimeout = 5 * time.Second
+
+type PaymentSaver interface {
+	SavePayment(ctx context.Context, payment models.Payment) (int64, error)
+	CapturePayment(ctx context.Context, id int64) error
+	RefundPayment(ctx context.Context, id int64, amount int64) error
+}
+
+type PaymentProvider interface {
+	Payment(ctx context.Context, id int64) (models.Payment, error)
+}
+
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey reclaims key left in progress for longer than lease
+	ReserveIdempotencyKey(
+		ctx context.Context,
+		userID int64,
+		key string,
+		method string,
+		requestHash string,
+		lease time.Duration,
+	) (models.IdempotencyKey, bool, error)
+	CompleteIdempotencyKey(ctx context.Context, userID int64, key string, paymentID int64) error
+	ReleaseIdempotencyKey(ctx context.Context, userID int64, key string) error
+}
+
+type Service struct {
+	log             *slog.Logger
+	paymentSaver    PaymentSaver
+	paymentProvider PaymentProvider
+	keys            IdempotencyStore
+	provider        provider.Provider
+	admins          AdminChecker
+
+	retries  int
+	backoff  time.Duration
+	keyLease time.Duration
+}
+
+func New(
+	log *slog.Logger,
+	paymentSaver PaymentSaver,
+	paymentProvider PaymentProvider,
+	keys IdempotencyStore,
+	provider provider.Provider,
+	admins AdminChecker,
+	retries int,
+	backoff time.Duration,
+	keyLease time.Duration,
+) *Service {
+	return &Service{
+		log:             log,
+		paymentSaver:    paymentSaver,
+		paymentProvider: paymentProvider,
+		keys:            keys,
+		provider:        provider,
+		admins:          admins,
+		retries:         retries,
+		backoff:         backoff,
+		keyLease:        keyLease,
+	}
+}
+
+// CreatePaymentIntent authorizes amount on card of payer.
+// Declined authorization is saved as declined payment, not returned as error.
+func (s *Service) CreatePaymentIntent(
+	ctx context.Context,
+	idempotencyKey string,
+	amount int64,
+	orderID int64,
+	cardNumber string,
+	description string,
+	payer int64,
+) (models.Payment, error) {
+	const op = "service.CreatePaymentIntent"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("payer", payer), slog.Int64("order_id", orderID))
+
+	log.Info("started creating payment intent")
+
+	// card number must not be kept even hashed, its last digits are enough to tell calls apart
+	hash := requestHash(amount, orderID, cardLast4(cardNumber), description)
+
+	return s.idempotent(ctx, op, log, payer, idempotencyKey, methodCreate, hash, func() (int64, error) {
+		payment := models.Payment{
+			Payer:       payer,
+			OrderID:     orderID,
+			Amount:      amount,
+			Status:      models.StatusAuthorized,
+			CardLast4:   cardLast4(cardNumber),
+			Description: description,
+		}
+
+		err := s.withRetries(ctx, log, func() error {
+			ref, err := s.provider.Authorize(ctx, amount, cardNumber, providerKey(payer, idempotencyKey))
+			payment.ProviderRef = ref
+			return err
+		})
+		if err != nil {
+			reason := declineReason(err)
+			if reason == "" {
+				return -1, err
+			}
+			log.Info("payment declined", slog.String("reason", reason))
+			payment.Status = models.StatusDeclined
+			payment.DeclineReason = reason
+		}
+
+		id, err := s.paymentSaver.SavePayment(ctx, payment)
+		if err != nil {
+			log.Error("failed to save payment", ll.Err(err))
+			return -1, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return id, nil
+	})
+}
+
+// CapturePayment captures authorized payment, caller must be payer or admin
+func (s *Service) CapturePayment(ctx context.Context, idempotencyKey string, id int64, callerID int64) (models.Payment, error) {
+	const op = "service.CapturePayment"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("payment_id", id))
+
+	log.Info("started capturing payment")
+
+	hash := requestHash(id)
+
+	return s.idempotent(ctx, op, log, callerID, idempotencyKey, methodCapture, hash, func() (int64, error) {
+		payment, err := s.payment(ctx, log, id)
+		if err != nil {
+			return -1, err
+		}
+
+		if payment.Payer != callerID {
+			if err := s.requireAdmin(ctx, log, callerID); err != nil {
+				return -1, err
+			}
+		}
+
+		if payment.Status != models.StatusAuthorized {
+			log.Info("payment is not authorized", slog.String("status", string(payment.Status)))
+			return -1, ErrNotCapturable
+		}
+
+		err = s.withRetries(ctx, log, func() error {
+			return s.provider.Capture(ctx, payment.ProviderRef, payment.Amount, providerKey(callerID, idempotencyKey))
+		})
+		if err != nil {
+			return -1, err
+		}
+
+		if err := s.paymentSaver.CapturePayment(ctx, id); err != nil {
+			return -1, s.updateError(log, err)
+		}
+
+		return id, nil
+	})
+}
+
+// RefundPayment refunds amount of captured payment, 0 amount refunds everything left.
+// Caller must be admin.
+func (s *Service) RefundPayment(
+	ctx context.Context,
+	idempotencyKey string,
+	id int64,
+	amount int64,
+	callerID int64,
+) (models.Payment, error) {
+	const op = "service.RefundPayment"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("payment_id", id))
+
+	log.Info("started refunding payment")
+
+	hash := requestHash(id, amount)
+
+	return s.idempotent(ctx, op, log, callerID, idempotencyKey, methodRefund, hash, func() (int64, error) {
+		if err := s.requireAdmin(ctx, log, callerID); err != nil {
+			return -1, err
+		}
+
+		payment, err := s.payment(ctx, log, id)
+		if err != nil {
+			return -1, err
+		}
+
+		if payment.Status != models.StatusCaptured && payment.Status != models.StatusPartiallyRefunded {
+			log.Info("payment is not captured", slog.String("status", string(payment.Status)))
+			return -1, ErrNotRefundable
+		}
+
+		left := payment.Amount - payment.Refunded
+		if amount == 0 {
+			amount = left
+		}
+		if amount > left {
+			log.Info("refund too large", slog.Int64("amount", amount), slog.Int64("left", left))
+			return -1, ErrRefundTooLarge
+		}
+
+		err = s.withRetries(ctx, log, func() error {
+			return s.provider.Refund(ctx, payment.ProviderRef, amount, providerKey(callerID, idempotencyKey))
+		})
+		if err != nil {
+			return -1, err
+		}
+
+		if err := s.paymentSaver.RefundPayment(ctx, id, amount); err != nil {
+			return -1, s.updateError(log, err)
+		}
+
+		return id, nil
+	})
+}
+
+// Payment returns payment if caller is its payer or admin
+func (s *Service) Payment(ctx context.Context, id int64, callerID int64) (models.Payment, error) {
+	const op = "service.Payment"
+
+	log := s.log.With(slog.String("op", op), slog.Int64("caller_id", callerID), slog.Int64("payment_id", id))
+
+	log.Info("started getting payment")
+
+	payment, err := s.payment(ctx, log, id)
+	if err != nil {
+		return payment, knownError(op, err)
+	}
+
+	if payment.Payer != callerID {
+		if err := s.requireAdmin(ctx, log, callerID); err != nil {
+			return models.Payment{}, knownError(op, err)
+		}
+	}
+
+	log.Info("getting succeeded")
+	return payment, nil
+}
+
+// idempotent runs call at most once per idempotency key of user.
+// Repeated call with the same key gets payment the first call made or changed.
+// Key left in progress by call that never finished is reclaimed after key lease.
+func (s *Service) idempotent(
+	ctx context.Context,
+	op string,
+	log *slog.Logger,
+	userID int64,
+	key string,
+	method string,
+	hash string,
+	call func() (int64, error),
+) (models.Payment, error) {
+	stored, reserved, err := s.keys.ReserveIdempotencyKey(ctx, userID, key, method, hash, s.keyLease)
+	if err != nil {
+		log.Error("failed to reserve idempotency key", ll.Err(err))
+		return models.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !reserved {
+		if stored.Method != method || stored.RequestHash != hash {
+			log.Info("idempotency key reused with other arguments")
+			return models.Payment{}, ErrKeyReused
+		}
+		if stored.PaymentID == 0 {
+			log.Info("call with the same idempotency key is in progress")
+			return models.Payment{}, ErrRequestInProgress
+		}
+
+		log.Info("replaying call", slog.Int64("payment_id", stored.PaymentID))
+		payment, err := s.payment(ctx, log, stored.PaymentID)
+		return payment, knownError(op, err)
+	}
+
+	id, err := call()
+
+	// key must not stay in progress because client went away
+	cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+	defer cancel()
+
+	if err != nil {
+		// failed call made no payment, so client may retry with the same key
+		if err := s.keys.ReleaseIdempotencyKey(cleanupCtx, userID, key); err != nil {
+			log.Error("failed to release idempotency key", ll.Err(err))
+		}
+		return models.Payment{}, knownError(op, err)
+	}
+
+	if err := s.keys.CompleteIdempotencyKey(cleanupCtx, userID, key, id); err != nil {
+		log.Error("failed to complete idempotency key", ll.Err(err))
+		return models.Payment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	payment, err := s.payment(ctx, log, id)
+	if err != nil {
+		return payment, knownError(op, err)
+	}
+
+	log.Info("call succeeded", slog.Int64("payment_id", id), slog.String("status", string(payment.Status)))
+	return payment, nil
+}
+
+// withRetries calls provider until it stops failing temporarily or retries run out
+func (s *Service) withRetries(ctx context.Context, log *slog.Logger, call func() error) error {
+	delay := s.backoff
+
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if !errors.Is(err, provider.ErrUnavailable) {
+			return providerError(log, err)
+		}
+
+		if attempt == s.retries {
+			log.Warn("provider is unavailable, retries exhausted", ll.Err(err))
+			return ErrProviderUnavailable
+		}
+
+		log.Info("provider is unavailable, retrying", slog.Int("attempt", attempt+1), slog.Duration("delay", delay))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+	}
+}
+
+func (s *Service) payment(ctx context.Context, log *slog.Logger, id int64) (models.Payment, error) {
+	payment, err := s.paymentProvider.Payment(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrPaymentNotFound) {
+			log.Info("payment not found")
+			return payment, ErrPaymentNotFound
+		}
+		log.Error("failed to get payment", ll.Err(err))
+		return payment, fmt.Errorf("failed to get payment: %w", err)
+	}
+
+	return payment, nil
+}
+
+func (s *Service) updateError(log *slog.Logger, err error) error {
+	if errors.Is(err, storage.ErrPaymentNotFound) {
+		log.Info("payment not found on update")
+		return ErrPaymentNotFound
+	}
+	if errors.Is(err, storage.ErrStatusChanged) {
+		log.Info("payment changed concurrently")
+		return ErrPaymentChanged
+	}
+
+	log.Error("failed to update payment", ll.Err(err))
+	return fmt.Errorf("failed to update payment: %w", err)
+}
+
+// declineReason returns reason of declined authorization, empty if err is not decline
+func declineReason(err error) string {
+	switch {
+	case errors.Is(err, provider.ErrCardDeclined):
+		return "card_declined"
+	case errors.Is(err, provider.ErrInsufficientFunds):
+		return "insufficient_funds"
+	default:
+		return ""
+	}
+}
+
+// providerError converts errors of provider that are not declines into service errors
+func providerError(log *slog.Logger, err error) error {
+	if err == nil || declineReason(err) != "" {
+		return err
+	}
+
+	if errors.Is(err, provider.ErrInvalidCard) {
+		log.Info("invalid card")
+		return ErrInvalidCard
+	}
+
+	log.Error("provider failed", ll.Err(err))
+	return fmt.Errorf("provider failed: %w", err)
+}
+
+// requestHash identifies arguments of call made with idempotency key
+func requestHash(args ...any) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%q", args)))
+	return hex.EncodeToString(sum[:])
+}
+
+func cardLast4(cardNumber string) string {
+	return cardNumber[len(cardNumber)-4:]
+}
+
+// providerKey scopes idempotency key of user for provider, which knows nothing of users
+func providerKey(userID int64, key string) string {
+	return fmt.Sprintf("%d:%s", userID, key)
+}
+
+// knownError passes service errors through and wraps unexpected ones
+func knownError(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	for _, known := range []error{
+		ErrPaymentNotFound, ErrNotEnoughPermissions, ErrInvalidCard, ErrNotCapturable, ErrNotRefundable,
+		ErrRefundTooLarge, ErrPaymentChanged, ErrProviderUnavailable,
+	} {
+		if errors.Is(err, known) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
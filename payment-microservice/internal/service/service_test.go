@@ -0,0 +1,233 @@
+package service_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/handlers/slogdiscard"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/provider/fake"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/service"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const payer = 1
+
+// memStorage keeps payments and idempotency keys in memory
+// and fails calls made with cancelled context, as database does
+type memStorage struct {
+	mu       sync.Mutex
+	payments map[int64]models.Payment
+	keys     map[string]models.IdempotencyKey
+
+	// called after payment is saved
+	afterSave func()
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{
+		payments: make(map[int64]models.Payment),
+		keys:     make(map[string]models.IdempotencyKey),
+	}
+}
+
+func (m *memStorage) SavePayment(ctx context.Context, payment models.Payment) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return -1, err
+	}
+
+	m.mu.Lock()
+	payment.ID = int64(len(m.payments) + 1)
+	m.payments[payment.ID] = payment
+	m.mu.Unlock()
+
+	if m.afterSave != nil {
+		m.afterSave()
+	}
+
+	return payment.ID, nil
+}
+
+func (m *memStorage) CapturePayment(ctx context.Context, id int64) error {
+	return ctx.Err()
+}
+
+func (m *memStorage) RefundPayment(ctx context.Context, id int64, amount int64) error {
+	return ctx.Err()
+}
+
+func (m *memStorage) Payment(ctx context.Context, id int64) (models.Payment, error) {
+	if err := ctx.Err(); err != nil {
+		return models.Payment{}, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	payment, ok := m.payments[id]
+	if !ok {
+		return payment, storage.ErrPaymentNotFound
+	}
+
+	return payment, nil
+}
+
+func (m *memStorage) ReserveIdempotencyKey(
+	ctx context.Context,
+	userID int64,
+	key string,
+	method string,
+	requestHash string,
+	_ time.Duration,
+) (models.IdempotencyKey, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return models.IdempotencyKey{}, false, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if stored, ok := m.keys[key]; ok {
+		return stored, false, nil
+	}
+
+	stored := models.IdempotencyKey{UserID: userID, Key: key, Method: method, RequestHash: requestHash}
+	m.keys[key] = stored
+
+	return stored, true, nil
+}
+
+func (m *memStorage) CompleteIdempotencyKey(ctx context.Context, _ int64, key string, paymentID int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored := m.keys[key]
+	stored.PaymentID = paymentID
+	m.keys[key] = stored
+
+	return nil
+}
+
+func (m *memStorage) ReleaseIdempotencyKey(ctx context.Context, _ int64, key string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.keys[key].PaymentID == 0 {
+		delete(m.keys, key)
+	}
+
+	return nil
+}
+
+func (m *memStorage) key(key string) (models.IdempotencyKey, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, ok := m.keys[key]
+	return stored, ok
+}
+
+// hookProvider runs hook before every authorization
+type hookProvider struct {
+	*fake.Provider
+	beforeAuthorize func() error
+}
+
+func (p *hookProvider) Authorize(ctx context.Context, amount int64, cardNumber string, idempotencyKey string) (string, error) {
+	if p.beforeAuthorize != nil {
+		if err := p.beforeAuthorize(); err != nil {
+			return "", err
+		}
+	}
+
+	return p.Provider.Authorize(ctx, amount, cardNumber, idempotencyKey)
+}
+
+type noAdmins struct{}
+
+func (noAdmins) IsAdmin(context.Context, int64) (bool, error) {
+	return false, nil
+}
+
+func newService(st *memStorage, p provider.Provider) *service.Service {
+	log := slog.New(slogdiscard.NewDiscardHandler())
+	return service.New(log, st, st, st, p, noAdmins{}, 3, time.Millisecond, time.Minute)
+}
+
+func TestIdempotent_FailedCallReleasesKeyAfterClientLeft(t *testing.T) {
+	st := newMemStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// client goes away while provider is unavailable
+	p := &hookProvider{Provider: fake.New(), beforeAuthorize: func() error {
+		cancel()
+		return provider.ErrUnavailable
+	}}
+	srvc := newService(st, p)
+
+	_, err := srvc.CreatePaymentIntent(ctx, "key", 100, 0, fake.CardSuccess, "", payer)
+	require.ErrorIs(t, err, context.Canceled)
+
+	_, ok := st.key("key")
+	assert.False(t, ok, "key of failed call must be released")
+
+	// retry with the same key is made, not reported as in progress
+	p.beforeAuthorize = nil
+	payment, err := srvc.CreatePaymentIntent(context.Background(), "key", 100, 0, fake.CardSuccess, "", payer)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusAuthorized, payment.Status)
+}
+
+func TestIdempotent_SucceededCallCompletesKeyAfterClientLeft(t *testing.T) {
+	st := newMemStorage()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// client goes away right after payment is saved
+	st.afterSave = cancel
+	srvc := newService(st, fake.New())
+
+	_, err := srvc.CreatePaymentIntent(ctx, "key", 100, 0, fake.CardSuccess, "", payer)
+	require.ErrorIs(t, err, context.Canceled)
+
+	stored, ok := st.key("key")
+	require.True(t, ok)
+	require.NotZero(t, stored.PaymentID, "key of succeeded call must be bound to its payment")
+
+	// retry gets the same payment instead of charging again
+	st.afterSave = nil
+	payment, err := srvc.CreatePaymentIntent(context.Background(), "key", 100, 0, fake.CardSuccess, "", payer)
+	require.NoError(t, err)
+	assert.Equal(t, stored.PaymentID, payment.ID)
+	assert.Len(t, st.payments, 1)
+}
+
+func TestIdempotent_KeyReusedWithOtherCard(t *testing.T) {
+	srvc := newService(newMemStorage(), fake.New())
+	ctx := context.Background()
+
+	first, err := srvc.CreatePaymentIntent(ctx, "key", 100, 0, fake.CardSuccess, "", payer)
+	require.NoError(t, err)
+
+	replayed, err := srvc.CreatePaymentIntent(ctx, "key", 100, 0, fake.CardSuccess, "", payer)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, replayed.ID)
+
+	_, err = srvc.CreatePaymentIntent(ctx, "key", 100, 0, fake.CardDeclined, "", payer)
+	require.ErrorIs(t, err, service.ErrKeyReused)
+}
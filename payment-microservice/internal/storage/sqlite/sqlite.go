@@ -0,0 +1,231 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/storage"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+type Storage struct {
+	db *sql.DB
+}
+
+func New(storagePath string) (*Storage, error) {
+	const op = "storage.sqlite.New"
+
+	db, err := sql.Open("sqlite3", storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Storage{db: db}, nil
+}
+
+func (s *Storage) Stop() error {
+	return s.db.Close()
+}
+
+// SavePayment saves payment and returns its id
+func (s *Storage) SavePayment(ctx context.Context, payment models.Payment) (int64, error) {
+	const op = "storage.sqlite.SavePayment"
+
+	now := time.Now().Unix()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO payments(payer_id, order_id, amount, status, card_last4, decline_reason, description, provider_ref, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, payment.Payer, payment.OrderID, payment.Amount, payment.Status, payment.CardLast4,
+		payment.DeclineReason, payment.Description, payment.ProviderRef, now, now)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (s *Storage) Payment(ctx context.Context, id int64) (models.Payment, error) {
+	const op = "storage.sqlite.Payment"
+
+	var (
+		payment   models.Payment
+		status    string
+		createdAt int64
+		updatedAt int64
+	)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, payer_id, order_id, amount, refunded, status, card_last4, decline_reason, description, provider_ref, created_at, updated_at
+		FROM payments
+		WHERE id = ?
+	`, id).Scan(
+		&payment.ID, &payment.Payer, &payment.OrderID, &payment.Amount, &payment.Refunded, &status,
+		&payment.CardLast4, &payment.DeclineReason, &payment.Description, &payment.ProviderRef, &createdAt, &updatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return payment, storage.ErrPaymentNotFound
+		}
+		return payment, fmt.Errorf("%s: %w", op, err)
+	}
+
+	payment.Status = models.PaymentStatus(status)
+	payment.CreatedAt = time.Unix(createdAt, 0)
+	payment.UpdatedAt = time.Unix(updatedAt, 0)
+
+	return payment, nil
+}
+
+// CapturePayment marks authorized payment captured.
+// Fails with storage.ErrStatusChanged if payment is no longer authorized.
+func (s *Storage) CapturePayment(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.CapturePayment"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE payments SET status = ?, updated_at = ? WHERE id = ? AND status = ?
+	`, models.StatusCaptured, time.Now().Unix(), id, models.StatusAuthorized)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkUpdated(ctx, res, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RefundPayment adds amount to refunded part of captured payment
+// and marks it refunded once whole amount is refunded.
+// Fails with storage.ErrStatusChanged if payment was refunded concurrently.
+func (s *Storage) RefundPayment(ctx context.Context, id int64, amount int64) error {
+	const op = "storage.sqlite.RefundPayment"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE payments
+		SET refunded   = refunded + ?1,
+		    status     = CASE WHEN refunded + ?1 = amount THEN ?2 ELSE ?3 END,
+		    updated_at = ?4
+		WHERE id = ?5 AND status IN (?6, ?3) AND refunded + ?1 <= amount
+	`, amount, models.StatusRefunded, models.StatusPartiallyRefunded, time.Now().Unix(), id, models.StatusCaptured)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := s.checkUpdated(ctx, res, id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// checkUpdated tells why conditional update of payment changed nothing
+func (s *Storage) checkUpdated(ctx context.Context, res sql.Result, id int64) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := s.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM payments WHERE id = ?)`, id).Scan(&exists); err != nil {
+		return err
+	}
+
+	if !exists {
+		return storage.ErrPaymentNotFound
+	}
+
+	return storage.ErrStatusChanged
+}
+
+// ReserveIdempotencyKey saves key as in progress unless user already used it.
+// Key left in progress for longer than lease belongs to call that never finished,
+// it is reclaimed for this call.
+// Returns stored key and whether it was saved by this call.
+func (s *Storage) ReserveIdempotencyKey(
+	ctx context.Context,
+	userID int64,
+	key string,
+	method string,
+	requestHash string,
+	lease time.Duration,
+) (models.IdempotencyKey, bool, error) {
+	const op = "storage.sqlite.ReserveIdempotencyKey"
+
+	now := time.Now()
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO idempotency_keys(user_id, key, method, request_hash, created_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET method       = excluded.method,
+		    request_hash = excluded.request_hash,
+		    created_at   = excluded.created_at
+		WHERE payment_id IS NULL AND created_at <= ?
+	`, userID, key, method, requestHash, now.Unix(), now.Add(-lease).Unix())
+	if err != nil {
+		return models.IdempotencyKey{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return models.IdempotencyKey{}, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stored := models.IdempotencyKey{UserID: userID, Key: key}
+
+	var paymentID sql.NullInt64
+	err = s.db.QueryRowContext(ctx, `
+		SELECT method, request_hash, payment_id FROM idempotency_keys WHERE user_id = ? AND key = ?
+	`, userID, key).Scan(&stored.Method, &stored.RequestHash, &paymentID)
+	if err != nil {
+		return stored, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	stored.PaymentID = paymentID.Int64
+
+	return stored, rowsAffected > 0, nil
+}
+
+// CompleteIdempotencyKey binds key to payment made by call
+func (s *Storage) CompleteIdempotencyKey(ctx context.Context, userID int64, key string, paymentID int64) error {
+	const op = "storage.sqlite.CompleteIdempotencyKey"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE idempotency_keys SET payment_id = ? WHERE user_id = ? AND key = ?
+	`, paymentID, userID, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ReleaseIdempotencyKey forgets key of failed call so that it can be retried
+func (s *Storage) ReleaseIdempotencyKey(ctx context.Context, userID int64, key string) error {
+	const op = "storage.sqlite.ReleaseIdempotencyKey"
+
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM idempotency_keys WHERE user_id = ? AND key = ? AND payment_id IS NULL
+	`, userID, key)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/models"
+	"github.com/Kry0z1/e-commerce/payment-microservice/internal/storage/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStorage returns storage over fresh in-memory database with migrations applied
+func newStorage(t *testing.T) *sqlite.Storage {
+	t.Helper()
+
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", t.Name())
+
+	// in-memory database lives while at least one connection is open
+	db, err := sql.Open("sqlite3", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migration, err := os.ReadFile("../../../migrations/1_init.up.sql")
+	require.NoError(t, err)
+	_, err = db.Exec(string(migration))
+	require.NoError(t, err)
+
+	st, err := sqlite.New(dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Stop() })
+
+	return st
+}
+
+func TestReserveIdempotencyKey_InProgress(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+
+	_, reserved, err := st.ReserveIdempotencyKey(ctx, 1, "key", "create", "hash", time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	stored, reserved, err := st.ReserveIdempotencyKey(ctx, 1, "key", "create", "other hash", time.Hour)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, "hash", stored.RequestHash)
+	assert.Zero(t, stored.PaymentID)
+
+	// keys are per user
+	_, reserved, err = st.ReserveIdempotencyKey(ctx, 2, "key", "create", "hash", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func TestReserveIdempotencyKey_ReclaimsExpiredLease(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+
+	_, reserved, err := st.ReserveIdempotencyKey(ctx, 1, "key", "create", "hash", time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	// call holding the key never finished and its lease is over
+	stored, reserved, err := st.ReserveIdempotencyKey(ctx, 1, "key", "capture", "other hash", 0)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, "capture", stored.Method)
+	assert.Equal(t, "other hash", stored.RequestHash)
+}
+
+func TestReserveIdempotencyKey_CompletedKeyIsNotReclaimed(t *testing.T) {
+	st := newStorage(t)
+	ctx := context.Background()
+
+	id, err := st.SavePayment(ctx, models.Payment{Payer: 1, Amount: 100, Status: models.StatusAuthorized, CardLast4: "4242"})
+	require.NoError(t, err)
+
+	_, _, err = st.ReserveIdempotencyKey(ctx, 1, "key", "create", "hash", time.Hour)
+	require.NoError(t, err)
+	require.NoError(t, st.CompleteIdempotencyKey(ctx, 1, "key", id))
+
+	stored, reserved, err := st.ReserveIdempotencyKey(ctx, 1, "key", "create", "hash", 0)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, id, stored.PaymentID)
+
+	// completed key is not released either
+	require.NoError(t, st.ReleaseIdempotencyKey(ctx, 1, "key"))
+	stored, reserved, err = st.ReserveIdempotencyKey(ctx, 1, "key", "create", "hash", 0)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+	assert.Equal(t, id, stored.PaymentID)
+}
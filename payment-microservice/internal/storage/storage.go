@@ -0,0 +1,8 @@
+package storage
+
+import "errors"
+
+var (
+	ErrPaymentNotFound = errors.New("payment not found")
+	ErrStatusChanged   = errors.New("payment was changed concurrently")
+)
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS payments
+(
+    id             INTEGER PRIMARY KEY,
+    payer_id       INTEGER NOT NULL,
+    -- 0 if payment is not for order
+    order_id       INTEGER NOT NULL DEFAULT 0,
+    -- cents
+    amount         INTEGER NOT NULL CHECK (amount > 0),
+    refunded       INTEGER NOT NULL DEFAULT 0 CHECK (refunded BETWEEN 0 AND amount),
+    status         TEXT    NOT NULL CHECK (status IN ('authorized', 'declined', 'captured', 'partially_refunded', 'refunded')),
+    card_last4     TEXT    NOT NULL,
+    decline_reason TEXT    NOT NULL DEFAULT '',
+    description    TEXT    NOT NULL DEFAULT '',
+    provider_ref   TEXT    NOT NULL DEFAULT '',
+    created_at     INTEGER NOT NULL,
+    updated_at     INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_payments_payer ON payments (payer_id);
+CREATE INDEX IF NOT EXISTS idx_payments_order ON payments (order_id);
+
+-- keys of mutating calls, unique per user
+CREATE TABLE IF NOT EXISTS idempotency_keys
+(
+    user_id      INTEGER NOT NULL,
+    key          TEXT    NOT NULL,
+    method       TEXT    NOT NULL,
+    request_hash TEXT    NOT NULL,
+    -- NULL while call is in progress
+    payment_id   INTEGER REFERENCES payments (id),
+    created_at   INTEGER NOT NULL,
+    PRIMARY KEY (user_id, key)
+);
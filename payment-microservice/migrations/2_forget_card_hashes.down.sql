@@ -0,0 +1 @@
+-- forgotten hashes can't be restored
@@ -0,0 +1,3 @@
+-- hashes of create calls were made of whole card number, which is not kept even hashed.
+-- Empty hash matches no call, so such keys are reported as reused instead of replayed.
+UPDATE idempotency_keys SET request_hash = '' WHERE method = 'create';
@@ -21,17 +21,79 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ListingSort int32
+
+const (
+	// Same as LISTING_SORT_NEWEST
+	ListingSort_LISTING_SORT_UNSPECIFIED ListingSort = 0
+	ListingSort_LISTING_SORT_NEWEST      ListingSort = 1
+	ListingSort_LISTING_SORT_PRICE_ASC   ListingSort = 2
+	ListingSort_LISTING_SORT_PRICE_DESC  ListingSort = 3
+	ListingSort_LISTING_SORT_TITLE       ListingSort = 4
+)
+
+// Enum value maps for ListingSort.
+var (
+	ListingSort_name = map[int32]string{
+		0: "LISTING_SORT_UNSPECIFIED",
+		1: "LISTING_SORT_NEWEST",
+		2: "LISTING_SORT_PRICE_ASC",
+		3: "LISTING_SORT_PRICE_DESC",
+		4: "LISTING_SORT_TITLE",
+	}
+	ListingSort_value = map[string]int32{
+		"LISTING_SORT_UNSPECIFIED": 0,
+		"LISTING_SORT_NEWEST":      1,
+		"LISTING_SORT_PRICE_ASC":   2,
+		"LISTING_SORT_PRICE_DESC":  3,
+		"LISTING_SORT_TITLE":       4,
+	}
+)
+
+func (x ListingSort) Enum() *ListingSort {
+	p := new(ListingSort)
+	*p = x
+	return p
+}
+
+func (x ListingSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ListingSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_listings_catalog_listings_catalog_proto_enumTypes[0].Descriptor()
+}
+
+func (ListingSort) Type() protoreflect.EnumType {
+	return &file_listings_catalog_listings_catalog_proto_enumTypes[0]
+}
+
+func (x ListingSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ListingSort.Descriptor instead.
+func (ListingSort) EnumDescriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{0}
+}
+
 type CreateListingRequest struct {
 	state       protoimpl.MessageState `protogen:"open.v1"`
 	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	Quantity    int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Category    string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Closed      bool                   `protobuf:"varint,5,opt,name=closed,proto3" json:"closed,omitempty"`
+	// Deprecated: use category_id instead. Slug of category
+	//
+	// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
+	Category string `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Closed   bool   `protobuf:"varint,5,opt,name=closed,proto3" json:"closed,omitempty"`
 	// Cost in cents
 	Price int64 `protobuf:"varint,6,opt,name=price,proto3" json:"price,omitempty"`
-	// JWT token of user issuing update
+	// Deprecated: pass token as "authorization" metadata instead
+	//
+	// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 	Token         string `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`
+	CategoryId    int64  `protobuf:"varint,8,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -87,6 +149,7 @@ func (x *CreateListingRequest) GetQuantity() int64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 func (x *CreateListingRequest) GetCategory() string {
 	if x != nil {
 		return x.Category
@@ -108,6 +171,7 @@ func (x *CreateListingRequest) GetPrice() int64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 func (x *CreateListingRequest) GetToken() string {
 	if x != nil {
 		return x.Token
@@ -115,6 +179,13 @@ func (x *CreateListingRequest) GetToken() string {
 	return ""
 }
 
+func (x *CreateListingRequest) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
 type CreateListingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -207,15 +278,23 @@ type GetListingResponse struct {
 	state       protoimpl.MessageState `protogen:"open.v1"`
 	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
-	Quantity    int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Category    string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Closed      bool                   `protobuf:"varint,5,opt,name=closed,proto3" json:"closed,omitempty"`
+	// On-hand quantity, reserved stock included
+	Quantity int64 `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Slug of category
+	Category string `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Closed   bool   `protobuf:"varint,5,opt,name=closed,proto3" json:"closed,omitempty"`
 	// Cost in cents
 	Price int64 `protobuf:"varint,6,opt,name=price,proto3" json:"price,omitempty"`
 	// id of task creator
-	Creator       int64 `protobuf:"varint,7,opt,name=creator,proto3" json:"creator,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Creator    int64      `protobuf:"varint,7,opt,name=creator,proto3" json:"creator,omitempty"`
+	CategoryId int64      `protobuf:"varint,8,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Variants   []*Variant `protobuf:"bytes,9,rep,name=variants,proto3" json:"variants,omitempty"`
+	// Option names of variants with all their values
+	Options []*VariantOption `protobuf:"bytes,10,rep,name=options,proto3" json:"options,omitempty"`
+	// Quantity not held by reservations
+	AvailableQuantity int64 `protobuf:"varint,11,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
 func (x *GetListingResponse) Reset() {
@@ -297,18 +376,52 @@ func (x *GetListingResponse) GetCreator() int64 {
 	return 0
 }
 
+func (x *GetListingResponse) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+func (x *GetListingResponse) GetVariants() []*Variant {
+	if x != nil {
+		return x.Variants
+	}
+	return nil
+}
+
+func (x *GetListingResponse) GetOptions() []*VariantOption {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *GetListingResponse) GetAvailableQuantity() int64 {
+	if x != nil {
+		return x.AvailableQuantity
+	}
+	return 0
+}
+
 type UpdateListingRequest struct {
 	state       protoimpl.MessageState `protogen:"open.v1"`
 	Title       string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
 	Quantity    int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Category    string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Closed      bool                   `protobuf:"varint,5,opt,name=closed,proto3" json:"closed,omitempty"`
+	// Deprecated: use category_id instead. Slug of category
+	//
+	// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
+	Category string `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Closed   bool   `protobuf:"varint,5,opt,name=closed,proto3" json:"closed,omitempty"`
 	// Cost in cents
 	Price int64 `protobuf:"varint,6,opt,name=price,proto3" json:"price,omitempty"`
-	// JWT token of user issuing update
+	// Deprecated: pass token as "authorization" metadata instead
+	//
+	// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 	Token         string `protobuf:"bytes,7,opt,name=token,proto3" json:"token,omitempty"`
 	Id            int64  `protobuf:"varint,8,opt,name=id,proto3" json:"id,omitempty"`
+	CategoryId    int64  `protobuf:"varint,9,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -364,6 +477,7 @@ func (x *UpdateListingRequest) GetQuantity() int64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 func (x *UpdateListingRequest) GetCategory() string {
 	if x != nil {
 		return x.Category
@@ -385,6 +499,7 @@ func (x *UpdateListingRequest) GetPrice() int64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 func (x *UpdateListingRequest) GetToken() string {
 	if x != nil {
 		return x.Token
@@ -399,6 +514,13 @@ func (x *UpdateListingRequest) GetId() int64 {
 	return 0
 }
 
+func (x *UpdateListingRequest) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
 type UpdateListingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Succeeded     bool                   `protobuf:"varint,1,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
@@ -445,7 +567,9 @@ func (x *UpdateListingResponse) GetSucceeded() bool {
 
 type DeleteListingRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// JWT token of user issuing update
+	// Deprecated: pass token as "authorization" metadata instead
+	//
+	// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 	Token         string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
 	Id            int64  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -482,6 +606,7 @@ func (*DeleteListingRequest) Descriptor() ([]byte, []int) {
 	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{6}
 }
 
+// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
 func (x *DeleteListingRequest) GetToken() string {
 	if x != nil {
 		return x.Token
@@ -540,53 +665,2114 @@ func (x *DeleteListingResponse) GetSucceeded() bool {
 	return false
 }
 
-var File_listings_catalog_listings_catalog_proto protoreflect.FileDescriptor
+type Listing struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title       string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Quantity    int64                  `protobuf:"varint,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Slug of category
+	Category string `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`
+	Closed   bool   `protobuf:"varint,6,opt,name=closed,proto3" json:"closed,omitempty"`
+	// Cost in cents
+	Price int64 `protobuf:"varint,7,opt,name=price,proto3" json:"price,omitempty"`
+	// id of listing creator
+	Creator int64 `protobuf:"varint,8,opt,name=creator,proto3" json:"creator,omitempty"`
+	// Unix time of creation
+	CreatedAt     int64 `protobuf:"varint,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CategoryId    int64 `protobuf:"varint,10,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_listings_catalog_listings_catalog_proto_rawDesc = "" +
-	"\n" +
-	"'listings-catalog/listings-catalog.proto\"\xca\x01\n" +
-	"\x14CreateListingRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x16\n" +
-	"\x06closed\x18\x05 \x01(\bR\x06closed\x12\x14\n" +
-	"\x05price\x18\x06 \x01(\x03R\x05price\x12\x14\n" +
-	"\x05token\x18\a \x01(\tR\x05token\"'\n" +
-	"\x15CreateListingResponse\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"#\n" +
-	"\x11GetListingRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"\xcc\x01\n" +
-	"\x12GetListingResponse\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x16\n" +
-	"\x06closed\x18\x05 \x01(\bR\x06closed\x12\x14\n" +
-	"\x05price\x18\x06 \x01(\x03R\x05price\x12\x18\n" +
-	"\acreator\x18\a \x01(\x03R\acreator\"\xda\x01\n" +
-	"\x14UpdateListingRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
-	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1a\n" +
-	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x16\n" +
-	"\x06closed\x18\x05 \x01(\bR\x06closed\x12\x14\n" +
-	"\x05price\x18\x06 \x01(\x03R\x05price\x12\x14\n" +
-	"\x05token\x18\a \x01(\tR\x05token\x12\x0e\n" +
-	"\x02id\x18\b \x01(\x03R\x02id\"5\n" +
-	"\x15UpdateListingResponse\x12\x1c\n" +
-	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded\"<\n" +
-	"\x14DeleteListingRequest\x12\x14\n" +
-	"\x05token\x18\x01 \x01(\tR\x05token\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\x03R\x02id\"5\n" +
-	"\x15DeleteListingResponse\x12\x1c\n" +
-	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded2\x88\x02\n" +
+func (x *Listing) Reset() {
+	*x = Listing{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Listing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Listing) ProtoMessage() {}
+
+func (x *Listing) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Listing.ProtoReflect.Descriptor instead.
+func (*Listing) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Listing) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Listing) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Listing) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Listing) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Listing) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *Listing) GetClosed() bool {
+	if x != nil {
+		return x.Closed
+	}
+	return false
+}
+
+func (x *Listing) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Listing) GetCreator() int64 {
+	if x != nil {
+		return x.Creator
+	}
+	return 0
+}
+
+func (x *Listing) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *Listing) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+type ListListingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: use category_id instead. Slug of category
+	//
+	// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
+	Category *string `protobuf:"bytes,1,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	// Price range in cents, inclusive
+	MinPrice *int64 `protobuf:"varint,2,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice *int64 `protobuf:"varint,3,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	Creator  *int64 `protobuf:"varint,4,opt,name=creator,proto3,oneof" json:"creator,omitempty"`
+	Closed   *bool  `protobuf:"varint,5,opt,name=closed,proto3,oneof" json:"closed,omitempty"`
+	// Only listings with positive quantity
+	InStock bool        `protobuf:"varint,6,opt,name=in_stock,json=inStock,proto3" json:"in_stock,omitempty"`
+	Sort    ListingSort `protobuf:"varint,7,opt,name=sort,proto3,enum=ListingSort" json:"sort,omitempty"`
+	// Default 20, at most 100
+	PageSize int32 `protobuf:"varint,8,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Empty for first page
+	PageToken string `protobuf:"bytes,9,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Listings of this category and all its subcategories
+	CategoryId    *int64 `protobuf:"varint,10,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListListingsRequest) Reset() {
+	*x = ListListingsRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListListingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListListingsRequest) ProtoMessage() {}
+
+func (x *ListListingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListListingsRequest.ProtoReflect.Descriptor instead.
+func (*ListListingsRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{9}
+}
+
+// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
+func (x *ListListingsRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *ListListingsRequest) GetMinPrice() int64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *ListListingsRequest) GetMaxPrice() int64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ListListingsRequest) GetCreator() int64 {
+	if x != nil && x.Creator != nil {
+		return *x.Creator
+	}
+	return 0
+}
+
+func (x *ListListingsRequest) GetClosed() bool {
+	if x != nil && x.Closed != nil {
+		return *x.Closed
+	}
+	return false
+}
+
+func (x *ListListingsRequest) GetInStock() bool {
+	if x != nil {
+		return x.InStock
+	}
+	return false
+}
+
+func (x *ListListingsRequest) GetSort() ListingSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ListingSort_LISTING_SORT_UNSPECIFIED
+}
+
+func (x *ListListingsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListListingsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListListingsRequest) GetCategoryId() int64 {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return 0
+}
+
+type ListListingsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Listings []*Listing             `protobuf:"bytes,1,rep,name=listings,proto3" json:"listings,omitempty"`
+	// Empty if there are no more pages
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListListingsResponse) Reset() {
+	*x = ListListingsResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListListingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListListingsResponse) ProtoMessage() {}
+
+func (x *ListListingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListListingsResponse.ProtoReflect.Descriptor instead.
+func (*ListListingsResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListListingsResponse) GetListings() []*Listing {
+	if x != nil {
+		return x.Listings
+	}
+	return nil
+}
+
+func (x *ListListingsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type SearchListingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Deprecated: use category_id instead. Slug of category
+	//
+	// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
+	Category *string `protobuf:"bytes,2,opt,name=category,proto3,oneof" json:"category,omitempty"`
+	// Closed listings are not shown by default
+	IncludeClosed bool `protobuf:"varint,3,opt,name=include_closed,json=includeClosed,proto3" json:"include_closed,omitempty"`
+	// Default 20, at most 100
+	PageSize int32 `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Empty for first page
+	PageToken string `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Unset -> listings of all categories,
+	// otherwise listings of this category and all its subcategories
+	CategoryId    *int64 `protobuf:"varint,6,opt,name=category_id,json=categoryId,proto3,oneof" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchListingsRequest) Reset() {
+	*x = SearchListingsRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchListingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchListingsRequest) ProtoMessage() {}
+
+func (x *SearchListingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchListingsRequest.ProtoReflect.Descriptor instead.
+func (*SearchListingsRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchListingsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+// Deprecated: Marked as deprecated in listings-catalog/listings-catalog.proto.
+func (x *SearchListingsRequest) GetCategory() string {
+	if x != nil && x.Category != nil {
+		return *x.Category
+	}
+	return ""
+}
+
+func (x *SearchListingsRequest) GetIncludeClosed() bool {
+	if x != nil {
+		return x.IncludeClosed
+	}
+	return false
+}
+
+func (x *SearchListingsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchListingsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *SearchListingsRequest) GetCategoryId() int64 {
+	if x != nil && x.CategoryId != nil {
+		return *x.CategoryId
+	}
+	return 0
+}
+
+type SearchHit struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Listing *Listing               `protobuf:"bytes,1,opt,name=listing,proto3" json:"listing,omitempty"`
+	// Title with matched words highlighted
+	TitleHighlight string `protobuf:"bytes,2,opt,name=title_highlight,json=titleHighlight,proto3" json:"title_highlight,omitempty"`
+	// Fragment of description around matched words, highlighted
+	DescriptionSnippet string `protobuf:"bytes,3,opt,name=description_snippet,json=descriptionSnippet,proto3" json:"description_snippet,omitempty"`
+	// Relevance, bigger is better
+	Score         float64 `protobuf:"fixed64,4,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchHit) Reset() {
+	*x = SearchHit{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchHit) ProtoMessage() {}
+
+func (x *SearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchHit.ProtoReflect.Descriptor instead.
+func (*SearchHit) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SearchHit) GetListing() *Listing {
+	if x != nil {
+		return x.Listing
+	}
+	return nil
+}
+
+func (x *SearchHit) GetTitleHighlight() string {
+	if x != nil {
+		return x.TitleHighlight
+	}
+	return ""
+}
+
+func (x *SearchHit) GetDescriptionSnippet() string {
+	if x != nil {
+		return x.DescriptionSnippet
+	}
+	return ""
+}
+
+func (x *SearchHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type CategoryFacet struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Slug of category
+	Category      string `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	Count         int64  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	CategoryId    int64  `protobuf:"varint,3,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryFacet) Reset() {
+	*x = CategoryFacet{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryFacet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryFacet) ProtoMessage() {}
+
+func (x *CategoryFacet) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryFacet.ProtoReflect.Descriptor instead.
+func (*CategoryFacet) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CategoryFacet) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *CategoryFacet) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CategoryFacet) GetCategoryId() int64 {
+	if x != nil {
+		return x.CategoryId
+	}
+	return 0
+}
+
+type SearchListingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Hits  []*SearchHit           `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	// Empty if there are no more pages
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Number of listings matching query
+	Total int64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	// Number of matching listings per category, ignoring category filter
+	Categories    []*CategoryFacet `protobuf:"bytes,4,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchListingsResponse) Reset() {
+	*x = SearchListingsResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchListingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchListingsResponse) ProtoMessage() {}
+
+func (x *SearchListingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchListingsResponse.ProtoReflect.Descriptor instead.
+func (*SearchListingsResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SearchListingsResponse) GetHits() []*SearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchListingsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *SearchListingsResponse) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SearchListingsResponse) GetCategories() []*CategoryFacet {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type Category struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 0 for root categories
+	ParentId int64 `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// Unique lowercase identifier: letters, digits and dashes
+	Slug string `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	// Display name
+	Name          string `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Archived      bool   `protobuf:"varint,5,opt,name=archived,proto3" json:"archived,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Category) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Category) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *Category) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+type ListCategoriesRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	IncludeArchived bool                   `protobuf:"varint,1,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRequest) Reset() {
+	*x = ListCategoriesRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRequest) ProtoMessage() {}
+
+func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListCategoriesRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ListCategoriesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCategoriesResponse) Reset() {
+	*x = ListCategoriesResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesResponse) ProtoMessage() {}
+
+func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListCategoriesResponse) GetCategories() []*Category {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+type CreateCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 0 -> root category
+	ParentId      int64  `protobuf:"varint,1,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	Slug          string `protobuf:"bytes,2,opt,name=slug,proto3" json:"slug,omitempty"`
+	Name          string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCategoryRequest) Reset() {
+	*x = CreateCategoryRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCategoryRequest) ProtoMessage() {}
+
+func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateCategoryRequest) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+func (x *CreateCategoryRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *CreateCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type CreateCategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCategoryResponse) Reset() {
+	*x = CreateCategoryResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCategoryResponse) ProtoMessage() {}
+
+func (x *CreateCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCategoryResponse.ProtoReflect.Descriptor instead.
+func (*CreateCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CreateCategoryResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type MoveCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// 0 -> make category root
+	ParentId      int64 `protobuf:"varint,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveCategoryRequest) Reset() {
+	*x = MoveCategoryRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCategoryRequest) ProtoMessage() {}
+
+func (x *MoveCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCategoryRequest.ProtoReflect.Descriptor instead.
+func (*MoveCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *MoveCategoryRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *MoveCategoryRequest) GetParentId() int64 {
+	if x != nil {
+		return x.ParentId
+	}
+	return 0
+}
+
+type MoveCategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoveCategoryResponse) Reset() {
+	*x = MoveCategoryResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoveCategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoveCategoryResponse) ProtoMessage() {}
+
+func (x *MoveCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoveCategoryResponse.ProtoReflect.Descriptor instead.
+func (*MoveCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{21}
+}
+
+type RenameCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unset fields are unchanged
+	Slug          *string `protobuf:"bytes,2,opt,name=slug,proto3,oneof" json:"slug,omitempty"`
+	Name          *string `protobuf:"bytes,3,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameCategoryRequest) Reset() {
+	*x = RenameCategoryRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameCategoryRequest) ProtoMessage() {}
+
+func (x *RenameCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameCategoryRequest.ProtoReflect.Descriptor instead.
+func (*RenameCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *RenameCategoryRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RenameCategoryRequest) GetSlug() string {
+	if x != nil && x.Slug != nil {
+		return *x.Slug
+	}
+	return ""
+}
+
+func (x *RenameCategoryRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+type RenameCategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameCategoryResponse) Reset() {
+	*x = RenameCategoryResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameCategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameCategoryResponse) ProtoMessage() {}
+
+func (x *RenameCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameCategoryResponse.ProtoReflect.Descriptor instead.
+func (*RenameCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{23}
+}
+
+type ArchiveCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveCategoryRequest) Reset() {
+	*x = ArchiveCategoryRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveCategoryRequest) ProtoMessage() {}
+
+func (x *ArchiveCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveCategoryRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ArchiveCategoryRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ArchiveCategoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveCategoryResponse) Reset() {
+	*x = ArchiveCategoryResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveCategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveCategoryResponse) ProtoMessage() {}
+
+func (x *ArchiveCategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveCategoryResponse.ProtoReflect.Descriptor instead.
+func (*ArchiveCategoryResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{25}
+}
+
+type Variant struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Stock keeping unit: unique code of variant
+	Sku string `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	// Option name -> value, e.g. "size" -> "XL"
+	Options map[string]string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Cost in cents
+	Price int64 `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	// On-hand quantity, reserved stock included
+	Quantity int64 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Quantity not held by reservations
+	AvailableQuantity int64 `protobuf:"varint,6,opt,name=available_quantity,json=availableQuantity,proto3" json:"available_quantity,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Variant) Reset() {
+	*x = Variant{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Variant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Variant) ProtoMessage() {}
+
+func (x *Variant) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Variant.ProtoReflect.Descriptor instead.
+func (*Variant) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Variant) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Variant) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *Variant) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *Variant) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Variant) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Variant) GetAvailableQuantity() int64 {
+	if x != nil {
+		return x.AvailableQuantity
+	}
+	return 0
+}
+
+type VariantOption struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Values        []string               `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VariantOption) Reset() {
+	*x = VariantOption{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VariantOption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VariantOption) ProtoMessage() {}
+
+func (x *VariantOption) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VariantOption.ProtoReflect.Descriptor instead.
+func (*VariantOption) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *VariantOption) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *VariantOption) GetValues() []string {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+type CreateVariantRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ListingId int64                  `protobuf:"varint,1,opt,name=listing_id,json=listingId,proto3" json:"listing_id,omitempty"`
+	Sku       string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	// At least one option is required.
+	// Names are lowercased, names and values are trimmed.
+	Options map[string]string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Cost in cents
+	Price         int64 `protobuf:"varint,4,opt,name=price,proto3" json:"price,omitempty"`
+	Quantity      int64 `protobuf:"varint,5,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVariantRequest) Reset() {
+	*x = CreateVariantRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVariantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVariantRequest) ProtoMessage() {}
+
+func (x *CreateVariantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVariantRequest.ProtoReflect.Descriptor instead.
+func (*CreateVariantRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CreateVariantRequest) GetListingId() int64 {
+	if x != nil {
+		return x.ListingId
+	}
+	return 0
+}
+
+func (x *CreateVariantRequest) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *CreateVariantRequest) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *CreateVariantRequest) GetPrice() int64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CreateVariantRequest) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CreateVariantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateVariantResponse) Reset() {
+	*x = CreateVariantResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateVariantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateVariantResponse) ProtoMessage() {}
+
+func (x *CreateVariantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateVariantResponse.ProtoReflect.Descriptor instead.
+func (*CreateVariantResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CreateVariantResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type UpdateVariantRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Unset fields are unchanged, empty options -> options unchanged
+	Sku           *string           `protobuf:"bytes,2,opt,name=sku,proto3,oneof" json:"sku,omitempty"`
+	Options       map[string]string `protobuf:"bytes,3,rep,name=options,proto3" json:"options,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Price         *int64            `protobuf:"varint,4,opt,name=price,proto3,oneof" json:"price,omitempty"`
+	Quantity      *int64            `protobuf:"varint,5,opt,name=quantity,proto3,oneof" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateVariantRequest) Reset() {
+	*x = UpdateVariantRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVariantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVariantRequest) ProtoMessage() {}
+
+func (x *UpdateVariantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVariantRequest.ProtoReflect.Descriptor instead.
+func (*UpdateVariantRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *UpdateVariantRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateVariantRequest) GetSku() string {
+	if x != nil && x.Sku != nil {
+		return *x.Sku
+	}
+	return ""
+}
+
+func (x *UpdateVariantRequest) GetOptions() map[string]string {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *UpdateVariantRequest) GetPrice() int64 {
+	if x != nil && x.Price != nil {
+		return *x.Price
+	}
+	return 0
+}
+
+func (x *UpdateVariantRequest) GetQuantity() int64 {
+	if x != nil && x.Quantity != nil {
+		return *x.Quantity
+	}
+	return 0
+}
+
+type UpdateVariantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateVariantResponse) Reset() {
+	*x = UpdateVariantResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateVariantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateVariantResponse) ProtoMessage() {}
+
+func (x *UpdateVariantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateVariantResponse.ProtoReflect.Descriptor instead.
+func (*UpdateVariantResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{31}
+}
+
+type DeleteVariantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVariantRequest) Reset() {
+	*x = DeleteVariantRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVariantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVariantRequest) ProtoMessage() {}
+
+func (x *DeleteVariantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVariantRequest.ProtoReflect.Descriptor instead.
+func (*DeleteVariantRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DeleteVariantRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type DeleteVariantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteVariantResponse) Reset() {
+	*x = DeleteVariantResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteVariantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVariantResponse) ProtoMessage() {}
+
+func (x *DeleteVariantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVariantResponse.ProtoReflect.Descriptor instead.
+func (*DeleteVariantResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{33}
+}
+
+type ReserveStockRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	ListingId int64                  `protobuf:"varint,1,opt,name=listing_id,json=listingId,proto3" json:"listing_id,omitempty"`
+	// 0 -> stock of listing itself is reserved
+	VariantId int64 `protobuf:"varint,2,opt,name=variant_id,json=variantId,proto3" json:"variant_id,omitempty"`
+	Quantity  int64 `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// 0 -> default lifetime, too long lifetime is capped
+	TtlSeconds    int64 `protobuf:"varint,4,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockRequest) ProtoMessage() {}
+
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ReserveStockRequest) GetListingId() int64 {
+	if x != nil {
+		return x.ListingId
+	}
+	return 0
+}
+
+func (x *ReserveStockRequest) GetVariantId() int64 {
+	if x != nil {
+		return x.VariantId
+	}
+	return 0
+}
+
+func (x *ReserveStockRequest) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ReserveStockRequest) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type ReserveStockResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int64                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	// Unix time
+	ExpiresAt     int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReserveStockResponse) Reset() {
+	*x = ReserveStockResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReserveStockResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReserveStockResponse) ProtoMessage() {}
+
+func (x *ReserveStockResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReserveStockResponse.ProtoReflect.Descriptor instead.
+func (*ReserveStockResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ReserveStockResponse) GetReservationId() int64 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+func (x *ReserveStockResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type CommitReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int64                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationRequest) ProtoMessage() {}
+
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CommitReservationRequest) GetReservationId() int64 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+type CommitReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommitReservationResponse) Reset() {
+	*x = CommitReservationResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommitReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommitReservationResponse) ProtoMessage() {}
+
+func (x *CommitReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommitReservationResponse.ProtoReflect.Descriptor instead.
+func (*CommitReservationResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{37}
+}
+
+type ReleaseReservationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReservationId int64                  `protobuf:"varint,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationRequest) ProtoMessage() {}
+
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ReleaseReservationRequest) GetReservationId() int64 {
+	if x != nil {
+		return x.ReservationId
+	}
+	return 0
+}
+
+type ReleaseReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReleaseReservationResponse) Reset() {
+	*x = ReleaseReservationResponse{}
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReleaseReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReleaseReservationResponse) ProtoMessage() {}
+
+func (x *ReleaseReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_listings_catalog_listings_catalog_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReleaseReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationResponse) Descriptor() ([]byte, []int) {
+	return file_listings_catalog_listings_catalog_proto_rawDescGZIP(), []int{39}
+}
+
+var File_listings_catalog_listings_catalog_proto protoreflect.FileDescriptor
+
+const file_listings_catalog_listings_catalog_proto_rawDesc = "" +
+	"\n" +
+	"'listings-catalog/listings-catalog.proto\"\xf3\x01\n" +
+	"\x14CreateListingRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1e\n" +
+	"\bcategory\x18\x04 \x01(\tB\x02\x18\x01R\bcategory\x12\x16\n" +
+	"\x06closed\x18\x05 \x01(\bR\x06closed\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x03R\x05price\x12\x18\n" +
+	"\x05token\x18\a \x01(\tB\x02\x18\x01R\x05token\x12\x1f\n" +
+	"\vcategory_id\x18\b \x01(\x03R\n" +
+	"categoryId\"'\n" +
+	"\x15CreateListingResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"#\n" +
+	"\x11GetListingRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xec\x02\n" +
+	"\x12GetListingResponse\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1a\n" +
+	"\bcategory\x18\x04 \x01(\tR\bcategory\x12\x16\n" +
+	"\x06closed\x18\x05 \x01(\bR\x06closed\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x03R\x05price\x12\x18\n" +
+	"\acreator\x18\a \x01(\x03R\acreator\x12\x1f\n" +
+	"\vcategory_id\x18\b \x01(\x03R\n" +
+	"categoryId\x12$\n" +
+	"\bvariants\x18\t \x03(\v2\b.VariantR\bvariants\x12(\n" +
+	"\aoptions\x18\n" +
+	" \x03(\v2\x0e.VariantOptionR\aoptions\x12-\n" +
+	"\x12available_quantity\x18\v \x01(\x03R\x11availableQuantity\"\x83\x02\n" +
+	"\x14UpdateListingRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1e\n" +
+	"\bcategory\x18\x04 \x01(\tB\x02\x18\x01R\bcategory\x12\x16\n" +
+	"\x06closed\x18\x05 \x01(\bR\x06closed\x12\x14\n" +
+	"\x05price\x18\x06 \x01(\x03R\x05price\x12\x18\n" +
+	"\x05token\x18\a \x01(\tB\x02\x18\x01R\x05token\x12\x0e\n" +
+	"\x02id\x18\b \x01(\x03R\x02id\x12\x1f\n" +
+	"\vcategory_id\x18\t \x01(\x03R\n" +
+	"categoryId\"5\n" +
+	"\x15UpdateListingResponse\x12\x1c\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded\"@\n" +
+	"\x14DeleteListingRequest\x12\x18\n" +
+	"\x05token\x18\x01 \x01(\tB\x02\x18\x01R\x05token\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x03R\x02id\"5\n" +
+	"\x15DeleteListingResponse\x12\x1c\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded\"\x91\x02\n" +
+	"\aListing\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x03R\bquantity\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x12\x16\n" +
+	"\x06closed\x18\x06 \x01(\bR\x06closed\x12\x14\n" +
+	"\x05price\x18\a \x01(\x03R\x05price\x12\x18\n" +
+	"\acreator\x18\b \x01(\x03R\acreator\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\x03R\tcreatedAt\x12\x1f\n" +
+	"\vcategory_id\x18\n" +
+	" \x01(\x03R\n" +
+	"categoryId\"\xa9\x03\n" +
+	"\x13ListListingsRequest\x12#\n" +
+	"\bcategory\x18\x01 \x01(\tB\x02\x18\x01H\x00R\bcategory\x88\x01\x01\x12 \n" +
+	"\tmin_price\x18\x02 \x01(\x03H\x01R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\x03 \x01(\x03H\x02R\bmaxPrice\x88\x01\x01\x12\x1d\n" +
+	"\acreator\x18\x04 \x01(\x03H\x03R\acreator\x88\x01\x01\x12\x1b\n" +
+	"\x06closed\x18\x05 \x01(\bH\x04R\x06closed\x88\x01\x01\x12\x19\n" +
+	"\bin_stock\x18\x06 \x01(\bR\ainStock\x12 \n" +
+	"\x04sort\x18\a \x01(\x0e2\f.ListingSortR\x04sort\x12\x1b\n" +
+	"\tpage_size\x18\b \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\t \x01(\tR\tpageToken\x12$\n" +
+	"\vcategory_id\x18\n" +
+	" \x01(\x03H\x05R\n" +
+	"categoryId\x88\x01\x01B\v\n" +
+	"\t_categoryB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_priceB\n" +
+	"\n" +
+	"\b_creatorB\t\n" +
+	"\a_closedB\x0e\n" +
+	"\f_category_id\"d\n" +
+	"\x14ListListingsResponse\x12$\n" +
+	"\blistings\x18\x01 \x03(\v2\b.ListingR\blistings\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf8\x01\n" +
+	"\x15SearchListingsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12#\n" +
+	"\bcategory\x18\x02 \x01(\tB\x02\x18\x01H\x00R\bcategory\x88\x01\x01\x12%\n" +
+	"\x0einclude_closed\x18\x03 \x01(\bR\rincludeClosed\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\x12$\n" +
+	"\vcategory_id\x18\x06 \x01(\x03H\x01R\n" +
+	"categoryId\x88\x01\x01B\v\n" +
+	"\t_categoryB\x0e\n" +
+	"\f_category_id\"\x9f\x01\n" +
+	"\tSearchHit\x12\"\n" +
+	"\alisting\x18\x01 \x01(\v2\b.ListingR\alisting\x12'\n" +
+	"\x0ftitle_highlight\x18\x02 \x01(\tR\x0etitleHighlight\x12/\n" +
+	"\x13description_snippet\x18\x03 \x01(\tR\x12descriptionSnippet\x12\x14\n" +
+	"\x05score\x18\x04 \x01(\x01R\x05score\"b\n" +
+	"\rCategoryFacet\x12\x1a\n" +
+	"\bcategory\x18\x01 \x01(\tR\bcategory\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x1f\n" +
+	"\vcategory_id\x18\x03 \x01(\x03R\n" +
+	"categoryId\"\xa6\x01\n" +
+	"\x16SearchListingsResponse\x12\x1e\n" +
+	"\x04hits\x18\x01 \x03(\v2\n" +
+	".SearchHitR\x04hits\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12.\n" +
+	"\n" +
+	"categories\x18\x04 \x03(\v2\x0e.CategoryFacetR\n" +
+	"categories\"{\n" +
+	"\bCategory\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x03R\bparentId\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1a\n" +
+	"\barchived\x18\x05 \x01(\bR\barchived\"B\n" +
+	"\x15ListCategoriesRequest\x12)\n" +
+	"\x10include_archived\x18\x01 \x01(\bR\x0fincludeArchived\"C\n" +
+	"\x16ListCategoriesResponse\x12)\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\t.CategoryR\n" +
+	"categories\"\\\n" +
+	"\x15CreateCategoryRequest\x12\x1b\n" +
+	"\tparent_id\x18\x01 \x01(\x03R\bparentId\x12\x12\n" +
+	"\x04slug\x18\x02 \x01(\tR\x04slug\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"(\n" +
+	"\x16CreateCategoryResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"B\n" +
+	"\x13MoveCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\x03R\bparentId\"\x16\n" +
+	"\x14MoveCategoryResponse\"k\n" +
+	"\x15RenameCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\x04slug\x18\x02 \x01(\tH\x00R\x04slug\x88\x01\x01\x12\x17\n" +
+	"\x04name\x18\x03 \x01(\tH\x01R\x04name\x88\x01\x01B\a\n" +
+	"\x05_slugB\a\n" +
+	"\x05_name\"\x18\n" +
+	"\x16RenameCategoryResponse\"(\n" +
+	"\x16ArchiveCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x19\n" +
+	"\x17ArchiveCategoryResponse\"\xf9\x01\n" +
+	"\aVariant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12/\n" +
+	"\aoptions\x18\x03 \x03(\v2\x15.Variant.OptionsEntryR\aoptions\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x03R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\x12-\n" +
+	"\x12available_quantity\x18\x06 \x01(\x03R\x11availableQuantity\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\";\n" +
+	"\rVariantOption\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06values\x18\x02 \x03(\tR\x06values\"\xf3\x01\n" +
+	"\x14CreateVariantRequest\x12\x1d\n" +
+	"\n" +
+	"listing_id\x18\x01 \x01(\x03R\tlistingId\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12<\n" +
+	"\aoptions\x18\x03 \x03(\v2\".CreateVariantRequest.OptionsEntryR\aoptions\x12\x14\n" +
+	"\x05price\x18\x04 \x01(\x03R\x05price\x12\x1a\n" +
+	"\bquantity\x18\x05 \x01(\x03R\bquantity\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"'\n" +
+	"\x15CreateVariantResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x92\x02\n" +
+	"\x14UpdateVariantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x15\n" +
+	"\x03sku\x18\x02 \x01(\tH\x00R\x03sku\x88\x01\x01\x12<\n" +
+	"\aoptions\x18\x03 \x03(\v2\".UpdateVariantRequest.OptionsEntryR\aoptions\x12\x19\n" +
+	"\x05price\x18\x04 \x01(\x03H\x01R\x05price\x88\x01\x01\x12\x1f\n" +
+	"\bquantity\x18\x05 \x01(\x03H\x02R\bquantity\x88\x01\x01\x1a:\n" +
+	"\fOptionsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x06\n" +
+	"\x04_skuB\b\n" +
+	"\x06_priceB\v\n" +
+	"\t_quantity\"\x17\n" +
+	"\x15UpdateVariantResponse\"&\n" +
+	"\x14DeleteVariantRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x17\n" +
+	"\x15DeleteVariantResponse\"\x90\x01\n" +
+	"\x13ReserveStockRequest\x12\x1d\n" +
+	"\n" +
+	"listing_id\x18\x01 \x01(\x03R\tlistingId\x12\x1d\n" +
+	"\n" +
+	"variant_id\x18\x02 \x01(\x03R\tvariantId\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1f\n" +
+	"\vttl_seconds\x18\x04 \x01(\x03R\n" +
+	"ttlSeconds\"\\\n" +
+	"\x14ReserveStockResponse\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x03R\rreservationId\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"A\n" +
+	"\x18CommitReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x03R\rreservationId\"\x1b\n" +
+	"\x19CommitReservationResponse\"B\n" +
+	"\x19ReleaseReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\x03R\rreservationId\"\x1c\n" +
+	"\x1aReleaseReservationResponse*\x95\x01\n" +
+	"\vListingSort\x12\x1c\n" +
+	"\x18LISTING_SORT_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13LISTING_SORT_NEWEST\x10\x01\x12\x1a\n" +
+	"\x16LISTING_SORT_PRICE_ASC\x10\x02\x12\x1b\n" +
+	"\x17LISTING_SORT_PRICE_DESC\x10\x03\x12\x16\n" +
+	"\x12LISTING_SORT_TITLE\x10\x042\x86\t\n" +
 	"\aCatalog\x12@\n" +
 	"\rCreateListing\x12\x15.CreateListingRequest\x1a\x16.CreateListingResponse\"\x00\x127\n" +
 	"\n" +
-	"GetListing\x12\x12.GetListingRequest\x1a\x13.GetListingResponse\"\x00\x12@\n" +
+	"GetListing\x12\x12.GetListingRequest\x1a\x13.GetListingResponse\"\x00\x12=\n" +
+	"\fListListings\x12\x14.ListListingsRequest\x1a\x15.ListListingsResponse\"\x00\x12C\n" +
+	"\x0eSearchListings\x12\x16.SearchListingsRequest\x1a\x17.SearchListingsResponse\"\x00\x12@\n" +
 	"\rUpdateListing\x12\x15.UpdateListingRequest\x1a\x16.UpdateListingResponse\"\x00\x12@\n" +
-	"\rDeleteListing\x12\x15.DeleteListingRequest\x1a\x16.DeleteListingResponse\"\x00B\x1dZ\x1bKry0z1.prodcat.v1;prodcatv1b\x06proto3"
+	"\rDeleteListing\x12\x15.DeleteListingRequest\x1a\x16.DeleteListingResponse\"\x00\x12@\n" +
+	"\rCreateVariant\x12\x15.CreateVariantRequest\x1a\x16.CreateVariantResponse\"\x00\x12@\n" +
+	"\rUpdateVariant\x12\x15.UpdateVariantRequest\x1a\x16.UpdateVariantResponse\"\x00\x12@\n" +
+	"\rDeleteVariant\x12\x15.DeleteVariantRequest\x1a\x16.DeleteVariantResponse\"\x00\x12=\n" +
+	"\fReserveStock\x12\x14.ReserveStockRequest\x1a\x15.ReserveStockResponse\"\x00\x12L\n" +
+	"\x11CommitReservation\x12\x19.CommitReservationRequest\x1a\x1a.CommitReservationResponse\"\x00\x12O\n" +
+	"\x12ReleaseReservation\x12\x1a.ReleaseReservationRequest\x1a\x1b.ReleaseReservationResponse\"\x00\x12C\n" +
+	"\x0eListCategories\x12\x16.ListCategoriesRequest\x1a\x17.ListCategoriesResponse\"\x00\x12C\n" +
+	"\x0eCreateCategory\x12\x16.CreateCategoryRequest\x1a\x17.CreateCategoryResponse\"\x00\x12=\n" +
+	"\fMoveCategory\x12\x14.MoveCategoryRequest\x1a\x15.MoveCategoryResponse\"\x00\x12C\n" +
+	"\x0eRenameCategory\x12\x16.RenameCategoryRequest\x1a\x17.RenameCategoryResponse\"\x00\x12F\n" +
+	"\x0fArchiveCategory\x12\x17.ArchiveCategoryRequest\x1a\x18.ArchiveCategoryResponse\"\x00B\x1dZ\x1bKry0z1.prodcat.v1;prodcatv1b\x06proto3"
 
 var (
 	file_listings_catalog_listings_catalog_proto_rawDescOnce sync.Once
@@ -600,31 +2786,105 @@ func file_listings_catalog_listings_catalog_proto_rawDescGZIP() []byte {
 	return file_listings_catalog_listings_catalog_proto_rawDescData
 }
 
-var file_listings_catalog_listings_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_listings_catalog_listings_catalog_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_listings_catalog_listings_catalog_proto_msgTypes = make([]protoimpl.MessageInfo, 43)
 var file_listings_catalog_listings_catalog_proto_goTypes = []any{
-	(*CreateListingRequest)(nil),  // 0: CreateListingRequest
-	(*CreateListingResponse)(nil), // 1: CreateListingResponse
-	(*GetListingRequest)(nil),     // 2: GetListingRequest
-	(*GetListingResponse)(nil),    // 3: GetListingResponse
-	(*UpdateListingRequest)(nil),  // 4: UpdateListingRequest
-	(*UpdateListingResponse)(nil), // 5: UpdateListingResponse
-	(*DeleteListingRequest)(nil),  // 6: DeleteListingRequest
-	(*DeleteListingResponse)(nil), // 7: DeleteListingResponse
+	(ListingSort)(0),                   // 0: ListingSort
+	(*CreateListingRequest)(nil),       // 1: CreateListingRequest
+	(*CreateListingResponse)(nil),      // 2: CreateListingResponse
+	(*GetListingRequest)(nil),          // 3: GetListingRequest
+	(*GetListingResponse)(nil),         // 4: GetListingResponse
+	(*UpdateListingRequest)(nil),       // 5: UpdateListingRequest
+	(*UpdateListingResponse)(nil),      // 6: UpdateListingResponse
+	(*DeleteListingRequest)(nil),       // 7: DeleteListingRequest
+	(*DeleteListingResponse)(nil),      // 8: DeleteListingResponse
+	(*Listing)(nil),                    // 9: Listing
+	(*ListListingsRequest)(nil),        // 10: ListListingsRequest
+	(*ListListingsResponse)(nil),       // 11: ListListingsResponse
+	(*SearchListingsRequest)(nil),      // 12: SearchListingsRequest
+	(*SearchHit)(nil),                  // 13: SearchHit
+	(*CategoryFacet)(nil),              // 14: CategoryFacet
+	(*SearchListingsResponse)(nil),     // 15: SearchListingsResponse
+	(*Category)(nil),                   // 16: Category
+	(*ListCategoriesRequest)(nil),      // 17: ListCategoriesRequest
+	(*ListCategoriesResponse)(nil),     // 18: ListCategoriesResponse
+	(*CreateCategoryRequest)(nil),      // 19: CreateCategoryRequest
+	(*CreateCategoryResponse)(nil),     // 20: CreateCategoryResponse
+	(*MoveCategoryRequest)(nil),        // 21: MoveCategoryRequest
+	(*MoveCategoryResponse)(nil),       // 22: MoveCategoryResponse
+	(*RenameCategoryRequest)(nil),      // 23: RenameCategoryRequest
+	(*RenameCategoryResponse)(nil),     // 24: RenameCategoryResponse
+	(*ArchiveCategoryRequest)(nil),     // 25: ArchiveCategoryRequest
+	(*ArchiveCategoryResponse)(nil),    // 26: ArchiveCategoryResponse
+	(*Variant)(nil),                    // 27: Variant
+	(*VariantOption)(nil),              // 28: VariantOption
+	(*CreateVariantRequest)(nil),       // 29: CreateVariantRequest
+	(*CreateVariantResponse)(nil),      // 30: CreateVariantResponse
+	(*UpdateVariantRequest)(nil),       // 31: UpdateVariantRequest
+	(*UpdateVariantResponse)(nil),      // 32: UpdateVariantResponse
+	(*DeleteVariantRequest)(nil),       // 33: DeleteVariantRequest
+	(*DeleteVariantResponse)(nil),      // 34: DeleteVariantResponse
+	(*ReserveStockRequest)(nil),        // 35: ReserveStockRequest
+	(*ReserveStockResponse)(nil),       // 36: ReserveStockResponse
+	(*CommitReservationRequest)(nil),   // 37: CommitReservationRequest
+	(*CommitReservationResponse)(nil),  // 38: CommitReservationResponse
+	(*ReleaseReservationRequest)(nil),  // 39: ReleaseReservationRequest
+	(*ReleaseReservationResponse)(nil), // 40: ReleaseReservationResponse
+	nil,                                // 41: Variant.OptionsEntry
+	nil,                                // 42: CreateVariantRequest.OptionsEntry
+	nil,                                // 43: UpdateVariantRequest.OptionsEntry
 }
 var file_listings_catalog_listings_catalog_proto_depIdxs = []int32{
-	0, // 0: Catalog.CreateListing:input_type -> CreateListingRequest
-	2, // 1: Catalog.GetListing:input_type -> GetListingRequest
-	4, // 2: Catalog.UpdateListing:input_type -> UpdateListingRequest
-	6, // 3: Catalog.DeleteListing:input_type -> DeleteListingRequest
-	1, // 4: Catalog.CreateListing:output_type -> CreateListingResponse
-	3, // 5: Catalog.GetListing:output_type -> GetListingResponse
-	5, // 6: Catalog.UpdateListing:output_type -> UpdateListingResponse
-	7, // 7: Catalog.DeleteListing:output_type -> DeleteListingResponse
-	4, // [4:8] is the sub-list for method output_type
-	0, // [0:4] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	27, // 0: GetListingResponse.variants:type_name -> Variant
+	28, // 1: GetListingResponse.options:type_name -> VariantOption
+	0,  // 2: ListListingsRequest.sort:type_name -> ListingSort
+	9,  // 3: ListListingsResponse.listings:type_name -> Listing
+	9,  // 4: SearchHit.listing:type_name -> Listing
+	13, // 5: SearchListingsResponse.hits:type_name -> SearchHit
+	14, // 6: SearchListingsResponse.categories:type_name -> CategoryFacet
+	16, // 7: ListCategoriesResponse.categories:type_name -> Category
+	41, // 8: Variant.options:type_name -> Variant.OptionsEntry
+	42, // 9: CreateVariantRequest.options:type_name -> CreateVariantRequest.OptionsEntry
+	43, // 10: UpdateVariantRequest.options:type_name -> UpdateVariantRequest.OptionsEntry
+	1,  // 11: Catalog.CreateListing:input_type -> CreateListingRequest
+	3,  // 12: Catalog.GetListing:input_type -> GetListingRequest
+	10, // 13: Catalog.ListListings:input_type -> ListListingsRequest
+	12, // 14: Catalog.SearchListings:input_type -> SearchListingsRequest
+	5,  // 15: Catalog.UpdateListing:input_type -> UpdateListingRequest
+	7,  // 16: Catalog.DeleteListing:input_type -> DeleteListingRequest
+	29, // 17: Catalog.CreateVariant:input_type -> CreateVariantRequest
+	31, // 18: Catalog.UpdateVariant:input_type -> UpdateVariantRequest
+	33, // 19: Catalog.DeleteVariant:input_type -> DeleteVariantRequest
+	35, // 20: Catalog.ReserveStock:input_type -> ReserveStockRequest
+	37, // 21: Catalog.CommitReservation:input_type -> CommitReservationRequest
+	39, // 22: Catalog.ReleaseReservation:input_type -> ReleaseReservationRequest
+	17, // 23: Catalog.ListCategories:input_type -> ListCategoriesRequest
+	19, // 24: Catalog.CreateCategory:input_type -> CreateCategoryRequest
+	21, // 25: Catalog.MoveCategory:input_type -> MoveCategoryRequest
+	23, // 26: Catalog.RenameCategory:input_type -> RenameCategoryRequest
+	25, // 27: Catalog.ArchiveCategory:input_type -> ArchiveCategoryRequest
+	2,  // 28: Catalog.CreateListing:output_type -> CreateListingResponse
+	4,  // 29: Catalog.GetListing:output_type -> GetListingResponse
+	11, // 30: Catalog.ListListings:output_type -> ListListingsResponse
+	15, // 31: Catalog.SearchListings:output_type -> SearchListingsResponse
+	6,  // 32: Catalog.UpdateListing:output_type -> UpdateListingResponse
+	8,  // 33: Catalog.DeleteListing:output_type -> DeleteListingResponse
+	30, // 34: Catalog.CreateVariant:output_type -> CreateVariantResponse
+	32, // 35: Catalog.UpdateVariant:output_type -> UpdateVariantResponse
+	34, // 36: Catalog.DeleteVariant:output_type -> DeleteVariantResponse
+	36, // 37: Catalog.ReserveStock:output_type -> ReserveStockResponse
+	38, // 38: Catalog.CommitReservation:output_type -> CommitReservationResponse
+	40, // 39: Catalog.ReleaseReservation:output_type -> ReleaseReservationResponse
+	18, // 40: Catalog.ListCategories:output_type -> ListCategoriesResponse
+	20, // 41: Catalog.CreateCategory:output_type -> CreateCategoryResponse
+	22, // 42: Catalog.MoveCategory:output_type -> MoveCategoryResponse
+	24, // 43: Catalog.RenameCategory:output_type -> RenameCategoryResponse
+	26, // 44: Catalog.ArchiveCategory:output_type -> ArchiveCategoryResponse
+	28, // [28:45] is the sub-list for method output_type
+	11, // [11:28] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_listings_catalog_listings_catalog_proto_init() }
@@ -632,18 +2892,23 @@ func file_listings_catalog_listings_catalog_proto_init() {
 	if File_listings_catalog_listings_catalog_proto != nil {
 		return
 	}
+	file_listings_catalog_listings_catalog_proto_msgTypes[9].OneofWrappers = []any{}
+	file_listings_catalog_listings_catalog_proto_msgTypes[11].OneofWrappers = []any{}
+	file_listings_catalog_listings_catalog_proto_msgTypes[22].OneofWrappers = []any{}
+	file_listings_catalog_listings_catalog_proto_msgTypes[30].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_listings_catalog_listings_catalog_proto_rawDesc), len(file_listings_catalog_listings_catalog_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   8,
+			NumEnums:      1,
+			NumMessages:   43,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_listings_catalog_listings_catalog_proto_goTypes,
 		DependencyIndexes: file_listings_catalog_listings_catalog_proto_depIdxs,
+		EnumInfos:         file_listings_catalog_listings_catalog_proto_enumTypes,
 		MessageInfos:      file_listings_catalog_listings_catalog_proto_msgTypes,
 	}.Build()
 	File_listings_catalog_listings_catalog_proto = out.File
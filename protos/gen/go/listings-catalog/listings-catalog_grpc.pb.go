@@ -19,27 +19,85 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Catalog_CreateListing_FullMethodName = "/Catalog/CreateListing"
-	Catalog_GetListing_FullMethodName    = "/Catalog/GetListing"
-	Catalog_UpdateListing_FullMethodName = "/Catalog/UpdateListing"
-	Catalog_DeleteListing_FullMethodName = "/Catalog/DeleteListing"
+	Catalog_CreateListing_FullMethodName      = "/Catalog/CreateListing"
+	Catalog_GetListing_FullMethodName         = "/Catalog/GetListing"
+	Catalog_ListListings_FullMethodName       = "/Catalog/ListListings"
+	Catalog_SearchListings_FullMethodName     = "/Catalog/SearchListings"
+	Catalog_UpdateListing_FullMethodName      = "/Catalog/UpdateListing"
+	Catalog_DeleteListing_FullMethodName      = "/Catalog/DeleteListing"
+	Catalog_CreateVariant_FullMethodName      = "/Catalog/CreateVariant"
+	Catalog_UpdateVariant_FullMethodName      = "/Catalog/UpdateVariant"
+	Catalog_DeleteVariant_FullMethodName      = "/Catalog/DeleteVariant"
+	Catalog_ReserveStock_FullMethodName       = "/Catalog/ReserveStock"
+	Catalog_CommitReservation_FullMethodName  = "/Catalog/CommitReservation"
+	Catalog_ReleaseReservation_FullMethodName = "/Catalog/ReleaseReservation"
+	Catalog_ListCategories_FullMethodName     = "/Catalog/ListCategories"
+	Catalog_CreateCategory_FullMethodName     = "/Catalog/CreateCategory"
+	Catalog_MoveCategory_FullMethodName       = "/Catalog/MoveCategory"
+	Catalog_RenameCategory_FullMethodName     = "/Catalog/RenameCategory"
+	Catalog_ArchiveCategory_FullMethodName    = "/Catalog/ArchiveCategory"
 )
 
 // CatalogClient is the client API for Catalog service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Methods creating, updating or deleting listings require caller's access token
+// passed as "authorization: Bearer <token>" metadata.
 type CatalogClient interface {
-	// Creates product listing and returns its id
+	// Creates product listing and returns its id.
+	// Caller must have verified their email.
 	CreateListing(ctx context.Context, in *CreateListingRequest, opts ...grpc.CallOption) (*CreateListingResponse, error)
-	// Returns listing by its id
+	// Returns listing by its id together with its variants
 	GetListing(ctx context.Context, in *GetListingRequest, opts ...grpc.CallOption) (*GetListingResponse, error)
-	// Updates listing: user needs to be creator of that listing or admin
+	// Returns page of listings matching filters.
+	//
+	// Pass next_page_token from response as page_token to get next page,
+	// filters and sort must stay the same between pages.
+	ListListings(ctx context.Context, in *ListListingsRequest, opts ...grpc.CallOption) (*ListListingsResponse, error)
+	// Finds listings by words in title and description, most relevant first.
+	//
+	// Every word of query is matched as prefix: "pho" finds "phone".
+	// Matched words in highlights and snippets are wrapped in <mark></mark>.
+	SearchListings(ctx context.Context, in *SearchListingsRequest, opts ...grpc.CallOption) (*SearchListingsResponse, error)
+	// Updates listing: user needs to be creator of that listing or admin,
+	// updates made by admins are recorded
 	//
 	// Must pass all the fields, even unchanged.
 	// Except for description: empty description -> description unchanged
 	UpdateListing(ctx context.Context, in *UpdateListingRequest, opts ...grpc.CallOption) (*UpdateListingResponse, error)
-	// Deletes listing: user needs to be creator of that listing or admin
+	// Deletes listing: user needs to be creator of that listing or admin,
+	// deletions made by admins are recorded
 	DeleteListing(ctx context.Context, in *DeleteListingRequest, opts ...grpc.CallOption) (*DeleteListingResponse, error)
+	// Adds variant to listing: user needs to be creator of that listing or admin.
+	//
+	// All variants of listing must have the same option names,
+	// option values combination must be unique within listing.
+	CreateVariant(ctx context.Context, in *CreateVariantRequest, opts ...grpc.CallOption) (*CreateVariantResponse, error)
+	// Updates variant: user needs to be creator of its listing or admin
+	UpdateVariant(ctx context.Context, in *UpdateVariantRequest, opts ...grpc.CallOption) (*UpdateVariantResponse, error)
+	// Deletes variant: user needs to be creator of its listing or admin
+	DeleteVariant(ctx context.Context, in *DeleteVariantRequest, opts ...grpc.CallOption) (*DeleteVariantResponse, error)
+	// Holds stock of listing or its variant for caller until reservation expires.
+	// Held stock is not available for other reservations.
+	ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error)
+	// Takes reserved quantity from on-hand stock: caller must hold reservation
+	// and reservation must be active
+	CommitReservation(ctx context.Context, in *CommitReservationRequest, opts ...grpc.CallOption) (*CommitReservationResponse, error)
+	// Returns reserved quantity to available stock: caller must hold reservation
+	// and reservation must be active
+	ReleaseReservation(ctx context.Context, in *ReleaseReservationRequest, opts ...grpc.CallOption) (*ReleaseReservationResponse, error)
+	// Returns category tree flattened: parents go before their children
+	ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error)
+	// Creates category, caller must be admin
+	CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error)
+	// Changes parent of category, caller must be admin
+	MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*MoveCategoryResponse, error)
+	// Changes slug and/or name of category, caller must be admin
+	RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*RenameCategoryResponse, error)
+	// Archives category with all its subcategories, caller must be admin.
+	// Listings stay in archived categories, but no new listings can be placed there.
+	ArchiveCategory(ctx context.Context, in *ArchiveCategoryRequest, opts ...grpc.CallOption) (*ArchiveCategoryResponse, error)
 }
 
 type catalogClient struct {
@@ -70,6 +128,26 @@ func (c *catalogClient) GetListing(ctx context.Context, in *GetListingRequest, o
 	return out, nil
 }
 
+func (c *catalogClient) ListListings(ctx context.Context, in *ListListingsRequest, opts ...grpc.CallOption) (*ListListingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListListingsResponse)
+	err := c.cc.Invoke(ctx, Catalog_ListListings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) SearchListings(ctx context.Context, in *SearchListingsRequest, opts ...grpc.CallOption) (*SearchListingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchListingsResponse)
+	err := c.cc.Invoke(ctx, Catalog_SearchListings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *catalogClient) UpdateListing(ctx context.Context, in *UpdateListingRequest, opts ...grpc.CallOption) (*UpdateListingResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(UpdateListingResponse)
@@ -90,21 +168,176 @@ func (c *catalogClient) DeleteListing(ctx context.Context, in *DeleteListingRequ
 	return out, nil
 }
 
+func (c *catalogClient) CreateVariant(ctx context.Context, in *CreateVariantRequest, opts ...grpc.CallOption) (*CreateVariantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateVariantResponse)
+	err := c.cc.Invoke(ctx, Catalog_CreateVariant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) UpdateVariant(ctx context.Context, in *UpdateVariantRequest, opts ...grpc.CallOption) (*UpdateVariantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateVariantResponse)
+	err := c.cc.Invoke(ctx, Catalog_UpdateVariant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) DeleteVariant(ctx context.Context, in *DeleteVariantRequest, opts ...grpc.CallOption) (*DeleteVariantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteVariantResponse)
+	err := c.cc.Invoke(ctx, Catalog_DeleteVariant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ReserveStock(ctx context.Context, in *ReserveStockRequest, opts ...grpc.CallOption) (*ReserveStockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReserveStockResponse)
+	err := c.cc.Invoke(ctx, Catalog_ReserveStock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) CommitReservation(ctx context.Context, in *CommitReservationRequest, opts ...grpc.CallOption) (*CommitReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommitReservationResponse)
+	err := c.cc.Invoke(ctx, Catalog_CommitReservation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ReleaseReservation(ctx context.Context, in *ReleaseReservationRequest, opts ...grpc.CallOption) (*ReleaseReservationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseReservationResponse)
+	err := c.cc.Invoke(ctx, Catalog_ReleaseReservation_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ListCategories(ctx context.Context, in *ListCategoriesRequest, opts ...grpc.CallOption) (*ListCategoriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCategoriesResponse)
+	err := c.cc.Invoke(ctx, Catalog_ListCategories_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) CreateCategory(ctx context.Context, in *CreateCategoryRequest, opts ...grpc.CallOption) (*CreateCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateCategoryResponse)
+	err := c.cc.Invoke(ctx, Catalog_CreateCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*MoveCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MoveCategoryResponse)
+	err := c.cc.Invoke(ctx, Catalog_MoveCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) RenameCategory(ctx context.Context, in *RenameCategoryRequest, opts ...grpc.CallOption) (*RenameCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenameCategoryResponse)
+	err := c.cc.Invoke(ctx, Catalog_RenameCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catalogClient) ArchiveCategory(ctx context.Context, in *ArchiveCategoryRequest, opts ...grpc.CallOption) (*ArchiveCategoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArchiveCategoryResponse)
+	err := c.cc.Invoke(ctx, Catalog_ArchiveCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CatalogServer is the server API for Catalog service.
 // All implementations must embed UnimplementedCatalogServer
 // for forward compatibility.
+//
+// Methods creating, updating or deleting listings require caller's access token
+// passed as "authorization: Bearer <token>" metadata.
 type CatalogServer interface {
-	// Creates product listing and returns its id
+	// Creates product listing and returns its id.
+	// Caller must have verified their email.
 	CreateListing(context.Context, *CreateListingRequest) (*CreateListingResponse, error)
-	// Returns listing by its id
+	// Returns listing by its id together with its variants
 	GetListing(context.Context, *GetListingRequest) (*GetListingResponse, error)
-	// Updates listing: user needs to be creator of that listing or admin
+	// Returns page of listings matching filters.
+	//
+	// Pass next_page_token from response as page_token to get next page,
+	// filters and sort must stay the same between pages.
+	ListListings(context.Context, *ListListingsRequest) (*ListListingsResponse, error)
+	// Finds listings by words in title and description, most relevant first.
+	//
+	// Every word of query is matched as prefix: "pho" finds "phone".
+	// Matched words in highlights and snippets are wrapped in <mark></mark>.
+	SearchListings(context.Context, *SearchListingsRequest) (*SearchListingsResponse, error)
+	// Updates listing: user needs to be creator of that listing or admin,
+	// updates made by admins are recorded
 	//
 	// Must pass all the fields, even unchanged.
 	// Except for description: empty description -> description unchanged
 	UpdateListing(context.Context, *UpdateListingRequest) (*UpdateListingResponse, error)
-	// Deletes listing: user needs to be creator of that listing or admin
+	// Deletes listing: user needs to be creator of that listing or admin,
+	// deletions made by admins are recorded
 	DeleteListing(context.Context, *DeleteListingRequest) (*DeleteListingResponse, error)
+	// Adds variant to listing: user needs to be creator of that listing or admin.
+	//
+	// All variants of listing must have the same option names,
+	// option values combination must be unique within listing.
+	CreateVariant(context.Context, *CreateVariantRequest) (*CreateVariantResponse, error)
+	// Updates variant: user needs to be creator of its listing or admin
+	UpdateVariant(context.Context, *UpdateVariantRequest) (*UpdateVariantResponse, error)
+	// Deletes variant: user needs to be creator of its listing or admin
+	DeleteVariant(context.Context, *DeleteVariantRequest) (*DeleteVariantResponse, error)
+	// Holds stock of listing or its variant for caller until reservation expires.
+	// Held stock is not available for other reservations.
+	ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error)
+	// Takes reserved quantity from on-hand stock: caller must hold reservation
+	// and reservation must be active
+	CommitReservation(context.Context, *CommitReservationRequest) (*CommitReservationResponse, error)
+	// Returns reserved quantity to available stock: caller must hold reservation
+	// and reservation must be active
+	ReleaseReservation(context.Context, *ReleaseReservationRequest) (*ReleaseReservationResponse, error)
+	// Returns category tree flattened: parents go before their children
+	ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error)
+	// Creates category, caller must be admin
+	CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error)
+	// Changes parent of category, caller must be admin
+	MoveCategory(context.Context, *MoveCategoryRequest) (*MoveCategoryResponse, error)
+	// Changes slug and/or name of category, caller must be admin
+	RenameCategory(context.Context, *RenameCategoryRequest) (*RenameCategoryResponse, error)
+	// Archives category with all its subcategories, caller must be admin.
+	// Listings stay in archived categories, but no new listings can be placed there.
+	ArchiveCategory(context.Context, *ArchiveCategoryRequest) (*ArchiveCategoryResponse, error)
 	mustEmbedUnimplementedCatalogServer()
 }
 
@@ -121,12 +354,51 @@ func (UnimplementedCatalogServer) CreateListing(context.Context, *CreateListingR
 func (UnimplementedCatalogServer) GetListing(context.Context, *GetListingRequest) (*GetListingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetListing not implemented")
 }
+func (UnimplementedCatalogServer) ListListings(context.Context, *ListListingsRequest) (*ListListingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListListings not implemented")
+}
+func (UnimplementedCatalogServer) SearchListings(context.Context, *SearchListingsRequest) (*SearchListingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchListings not implemented")
+}
 func (UnimplementedCatalogServer) UpdateListing(context.Context, *UpdateListingRequest) (*UpdateListingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateListing not implemented")
 }
 func (UnimplementedCatalogServer) DeleteListing(context.Context, *DeleteListingRequest) (*DeleteListingResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteListing not implemented")
 }
+func (UnimplementedCatalogServer) CreateVariant(context.Context, *CreateVariantRequest) (*CreateVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateVariant not implemented")
+}
+func (UnimplementedCatalogServer) UpdateVariant(context.Context, *UpdateVariantRequest) (*UpdateVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateVariant not implemented")
+}
+func (UnimplementedCatalogServer) DeleteVariant(context.Context, *DeleteVariantRequest) (*DeleteVariantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVariant not implemented")
+}
+func (UnimplementedCatalogServer) ReserveStock(context.Context, *ReserveStockRequest) (*ReserveStockResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReserveStock not implemented")
+}
+func (UnimplementedCatalogServer) CommitReservation(context.Context, *CommitReservationRequest) (*CommitReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CommitReservation not implemented")
+}
+func (UnimplementedCatalogServer) ReleaseReservation(context.Context, *ReleaseReservationRequest) (*ReleaseReservationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReleaseReservation not implemented")
+}
+func (UnimplementedCatalogServer) ListCategories(context.Context, *ListCategoriesRequest) (*ListCategoriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCategories not implemented")
+}
+func (UnimplementedCatalogServer) CreateCategory(context.Context, *CreateCategoryRequest) (*CreateCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCategory not implemented")
+}
+func (UnimplementedCatalogServer) MoveCategory(context.Context, *MoveCategoryRequest) (*MoveCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveCategory not implemented")
+}
+func (UnimplementedCatalogServer) RenameCategory(context.Context, *RenameCategoryRequest) (*RenameCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenameCategory not implemented")
+}
+func (UnimplementedCatalogServer) ArchiveCategory(context.Context, *ArchiveCategoryRequest) (*ArchiveCategoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveCategory not implemented")
+}
 func (UnimplementedCatalogServer) mustEmbedUnimplementedCatalogServer() {}
 func (UnimplementedCatalogServer) testEmbeddedByValue()                 {}
 
@@ -184,6 +456,42 @@ func _Catalog_GetListing_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Catalog_ListListings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListListingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ListListings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_ListListings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ListListings(ctx, req.(*ListListingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_SearchListings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchListingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).SearchListings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_SearchListings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).SearchListings(ctx, req.(*SearchListingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Catalog_UpdateListing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(UpdateListingRequest)
 	if err := dec(in); err != nil {
@@ -220,6 +528,204 @@ func _Catalog_DeleteListing_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Catalog_CreateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_CreateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateVariant(ctx, req.(*CreateVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_UpdateVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).UpdateVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_UpdateVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).UpdateVariant(ctx, req.(*UpdateVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_DeleteVariant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVariantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).DeleteVariant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_DeleteVariant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).DeleteVariant(ctx, req.(*DeleteVariantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ReserveStock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReserveStockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ReserveStock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_ReserveStock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ReserveStock(ctx, req.(*ReserveStockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_CommitReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CommitReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CommitReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_CommitReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CommitReservation(ctx, req.(*CommitReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ReleaseReservation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseReservationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ReleaseReservation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_ReleaseReservation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ReleaseReservation(ctx, req.(*ReleaseReservationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ListCategories_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCategoriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ListCategories(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_ListCategories_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ListCategories(ctx, req.(*ListCategoriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_CreateCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).CreateCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_CreateCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).CreateCategory(ctx, req.(*CreateCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_MoveCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).MoveCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_MoveCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).MoveCategory(ctx, req.(*MoveCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_RenameCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenameCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).RenameCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_RenameCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).RenameCategory(ctx, req.(*RenameCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Catalog_ArchiveCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatalogServer).ArchiveCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Catalog_ArchiveCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatalogServer).ArchiveCategory(ctx, req.(*ArchiveCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Catalog_ServiceDesc is the grpc.ServiceDesc for Catalog service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -235,6 +741,14 @@ var Catalog_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetListing",
 			Handler:    _Catalog_GetListing_Handler,
 		},
+		{
+			MethodName: "ListListings",
+			Handler:    _Catalog_ListListings_Handler,
+		},
+		{
+			MethodName: "SearchListings",
+			Handler:    _Catalog_SearchListings_Handler,
+		},
 		{
 			MethodName: "UpdateListing",
 			Handler:    _Catalog_UpdateListing_Handler,
@@ -243,6 +757,50 @@ var Catalog_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteListing",
 			Handler:    _Catalog_DeleteListing_Handler,
 		},
+		{
+			MethodName: "CreateVariant",
+			Handler:    _Catalog_CreateVariant_Handler,
+		},
+		{
+			MethodName: "UpdateVariant",
+			Handler:    _Catalog_UpdateVariant_Handler,
+		},
+		{
+			MethodName: "DeleteVariant",
+			Handler:    _Catalog_DeleteVariant_Handler,
+		},
+		{
+			MethodName: "ReserveStock",
+			Handler:    _Catalog_ReserveStock_Handler,
+		},
+		{
+			MethodName: "CommitReservation",
+			Handler:    _Catalog_CommitReservation_Handler,
+		},
+		{
+			MethodName: "ReleaseReservation",
+			Handler:    _Catalog_ReleaseReservation_Handler,
+		},
+		{
+			MethodName: "ListCategories",
+			Handler:    _Catalog_ListCategories_Handler,
+		},
+		{
+			MethodName: "CreateCategory",
+			Handler:    _Catalog_CreateCategory_Handler,
+		},
+		{
+			MethodName: "MoveCategory",
+			Handler:    _Catalog_MoveCategory_Handler,
+		},
+		{
+			MethodName: "RenameCategory",
+			Handler:    _Catalog_RenameCategory_Handler,
+		},
+		{
+			MethodName: "ArchiveCategory",
+			Handler:    _Catalog_ArchiveCategory_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "listings-catalog/listings-catalog.proto",
@@ -0,0 +1,1137 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v6.30.1
+// source: notifications/notifications.proto
+
+package notificationsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type NotificationStatus int32
+
+const (
+	NotificationStatus_NOTIFICATION_STATUS_UNSPECIFIED NotificationStatus = 0
+	NotificationStatus_NOTIFICATION_STATUS_PENDING     NotificationStatus = 1
+	NotificationStatus_NOTIFICATION_STATUS_SENT        NotificationStatus = 2
+	NotificationStatus_NOTIFICATION_STATUS_DEAD        NotificationStatus = 3
+)
+
+// Enum value maps for NotificationStatus.
+var (
+	NotificationStatus_name = map[int32]string{
+		0: "NOTIFICATION_STATUS_UNSPECIFIED",
+		1: "NOTIFICATION_STATUS_PENDING",
+		2: "NOTIFICATION_STATUS_SENT",
+		3: "NOTIFICATION_STATUS_DEAD",
+	}
+	NotificationStatus_value = map[string]int32{
+		"NOTIFICATION_STATUS_UNSPECIFIED": 0,
+		"NOTIFICATION_STATUS_PENDING":     1,
+		"NOTIFICATION_STATUS_SENT":        2,
+		"NOTIFICATION_STATUS_DEAD":        3,
+	}
+)
+
+func (x NotificationStatus) Enum() *NotificationStatus {
+	p := new(NotificationStatus)
+	*p = x
+	return p
+}
+
+func (x NotificationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NotificationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_notifications_notifications_proto_enumTypes[0].Descriptor()
+}
+
+func (NotificationStatus) Type() protoreflect.EnumType {
+	return &file_notifications_notifications_proto_enumTypes[0]
+}
+
+func (x NotificationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NotificationStatus.Descriptor instead.
+func (NotificationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{0}
+}
+
+// Sent to user right after registration
+type Welcome struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Welcome) Reset() {
+	*x = Welcome{}
+	mi := &file_notifications_notifications_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Welcome) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Welcome) ProtoMessage() {}
+
+func (x *Welcome) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Welcome.ProtoReflect.Descriptor instead.
+func (*Welcome) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{0}
+}
+
+type OrderConfirmationLine struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Title    string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Quantity int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Cost in cents
+	UnitPrice     int64 `protobuf:"varint,3,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	LineTotal     int64 `protobuf:"varint,4,opt,name=line_total,json=lineTotal,proto3" json:"line_total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderConfirmationLine) Reset() {
+	*x = OrderConfirmationLine{}
+	mi := &file_notifications_notifications_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderConfirmationLine) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderConfirmationLine) ProtoMessage() {}
+
+func (x *OrderConfirmationLine) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderConfirmationLine.ProtoReflect.Descriptor instead.
+func (*OrderConfirmationLine) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *OrderConfirmationLine) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *OrderConfirmationLine) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderConfirmationLine) GetUnitPrice() int64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *OrderConfirmationLine) GetLineTotal() int64 {
+	if x != nil {
+		return x.LineTotal
+	}
+	return 0
+}
+
+type OrderConfirmation struct {
+	state   protoimpl.MessageState   `protogen:"open.v1"`
+	OrderId int64                    `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Lines   []*OrderConfirmationLine `protobuf:"bytes,2,rep,name=lines,proto3" json:"lines,omitempty"`
+	// Cost in cents
+	Total         int64 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderConfirmation) Reset() {
+	*x = OrderConfirmation{}
+	mi := &file_notifications_notifications_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderConfirmation) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderConfirmation) ProtoMessage() {}
+
+func (x *OrderConfirmation) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderConfirmation.ProtoReflect.Descriptor instead.
+func (*OrderConfirmation) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OrderConfirmation) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderConfirmation) GetLines() []*OrderConfirmationLine {
+	if x != nil {
+		return x.Lines
+	}
+	return nil
+}
+
+func (x *OrderConfirmation) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type PasswordReset struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Unix time token expires at
+	ExpiresAt     int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasswordReset) Reset() {
+	*x = PasswordReset{}
+	mi := &file_notifications_notifications_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasswordReset) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasswordReset) ProtoMessage() {}
+
+func (x *PasswordReset) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasswordReset.ProtoReflect.Descriptor instead.
+func (*PasswordReset) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PasswordReset) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PasswordReset) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// Sent to user to confirm their email
+type EmailVerification struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Token string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// Unix time token expires at
+	ExpiresAt     int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EmailVerification) Reset() {
+	*x = EmailVerification{}
+	mi := &file_notifications_notifications_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EmailVerification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EmailVerification) ProtoMessage() {}
+
+func (x *EmailVerification) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EmailVerification.ProtoReflect.Descriptor instead.
+func (*EmailVerification) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *EmailVerification) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *EmailVerification) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type SendNotificationRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Email address
+	Recipient string `protobuf:"bytes,1,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*SendNotificationRequest_Welcome
+	//	*SendNotificationRequest_OrderConfirmation
+	//	*SendNotificationRequest_PasswordReset
+	//	*SendNotificationRequest_EmailVerification
+	Payload       isSendNotificationRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendNotificationRequest) Reset() {
+	*x = SendNotificationRequest{}
+	mi := &file_notifications_notifications_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendNotificationRequest) ProtoMessage() {}
+
+func (x *SendNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendNotificationRequest.ProtoReflect.Descriptor instead.
+func (*SendNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SendNotificationRequest) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *SendNotificationRequest) GetPayload() isSendNotificationRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *SendNotificationRequest) GetWelcome() *Welcome {
+	if x != nil {
+		if x, ok := x.Payload.(*SendNotificationRequest_Welcome); ok {
+			return x.Welcome
+		}
+	}
+	return nil
+}
+
+func (x *SendNotificationRequest) GetOrderConfirmation() *OrderConfirmation {
+	if x != nil {
+		if x, ok := x.Payload.(*SendNotificationRequest_OrderConfirmation); ok {
+			return x.OrderConfirmation
+		}
+	}
+	return nil
+}
+
+func (x *SendNotificationRequest) GetPasswordReset() *PasswordReset {
+	if x != nil {
+		if x, ok := x.Payload.(*SendNotificationRequest_PasswordReset); ok {
+			return x.PasswordReset
+		}
+	}
+	return nil
+}
+
+func (x *SendNotificationRequest) GetEmailVerification() *EmailVerification {
+	if x != nil {
+		if x, ok := x.Payload.(*SendNotificationRequest_EmailVerification); ok {
+			return x.EmailVerification
+		}
+	}
+	return nil
+}
+
+type isSendNotificationRequest_Payload interface {
+	isSendNotificationRequest_Payload()
+}
+
+type SendNotificationRequest_Welcome struct {
+	Welcome *Welcome `protobuf:"bytes,2,opt,name=welcome,proto3,oneof"`
+}
+
+type SendNotificationRequest_OrderConfirmation struct {
+	OrderConfirmation *OrderConfirmation `protobuf:"bytes,3,opt,name=order_confirmation,json=orderConfirmation,proto3,oneof"`
+}
+
+type SendNotificationRequest_PasswordReset struct {
+	PasswordReset *PasswordReset `protobuf:"bytes,4,opt,name=password_reset,json=passwordReset,proto3,oneof"`
+}
+
+type SendNotificationRequest_EmailVerification struct {
+	EmailVerification *EmailVerification `protobuf:"bytes,5,opt,name=email_verification,json=emailVerification,proto3,oneof"`
+}
+
+func (*SendNotificationRequest_Welcome) isSendNotificationRequest_Payload() {}
+
+func (*SendNotificationRequest_OrderConfirmation) isSendNotificationRequest_Payload() {}
+
+func (*SendNotificationRequest_PasswordReset) isSendNotificationRequest_Payload() {}
+
+func (*SendNotificationRequest_EmailVerification) isSendNotificationRequest_Payload() {}
+
+type SendNotificationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendNotificationResponse) Reset() {
+	*x = SendNotificationResponse{}
+	mi := &file_notifications_notifications_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendNotificationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendNotificationResponse) ProtoMessage() {}
+
+func (x *SendNotificationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendNotificationResponse.ProtoReflect.Descriptor instead.
+func (*SendNotificationResponse) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *SendNotificationResponse) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type Notification struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Template name: "welcome", "order_confirmation", "password_reset" or "email_verification"
+	Kind      string             `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Recipient string             `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Subject   string             `protobuf:"bytes,4,opt,name=subject,proto3" json:"subject,omitempty"`
+	TextBody  string             `protobuf:"bytes,5,opt,name=text_body,json=textBody,proto3" json:"text_body,omitempty"`
+	HtmlBody  string             `protobuf:"bytes,6,opt,name=html_body,json=htmlBody,proto3" json:"html_body,omitempty"`
+	Status    NotificationStatus `protobuf:"varint,7,opt,name=status,proto3,enum=NotificationStatus" json:"status,omitempty"`
+	// Failed delivery attempts
+	Attempts  int32  `protobuf:"varint,8,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError string `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// Unix time, 0 if not sent
+	SentAt        int64 `protobuf:"varint,10,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	CreatedAt     int64 `protobuf:"varint,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Notification) Reset() {
+	*x = Notification{}
+	mi := &file_notifications_notifications_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Notification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Notification) ProtoMessage() {}
+
+func (x *Notification) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Notification.ProtoReflect.Descriptor instead.
+func (*Notification) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Notification) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Notification) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Notification) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *Notification) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *Notification) GetTextBody() string {
+	if x != nil {
+		return x.TextBody
+	}
+	return ""
+}
+
+func (x *Notification) GetHtmlBody() string {
+	if x != nil {
+		return x.HtmlBody
+	}
+	return ""
+}
+
+func (x *Notification) GetStatus() NotificationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return NotificationStatus_NOTIFICATION_STATUS_UNSPECIFIED
+}
+
+func (x *Notification) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *Notification) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *Notification) GetSentAt() int64 {
+	if x != nil {
+		return x.SentAt
+	}
+	return 0
+}
+
+func (x *Notification) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type GetNotificationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationRequest) Reset() {
+	*x = GetNotificationRequest{}
+	mi := &file_notifications_notifications_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationRequest) ProtoMessage() {}
+
+func (x *GetNotificationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationRequest) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetNotificationRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type GetNotificationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Notification  *Notification          `protobuf:"bytes,1,opt,name=notification,proto3" json:"notification,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetNotificationResponse) Reset() {
+	*x = GetNotificationResponse{}
+	mi := &file_notifications_notifications_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetNotificationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetNotificationResponse) ProtoMessage() {}
+
+func (x *GetNotificationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetNotificationResponse.ProtoReflect.Descriptor instead.
+func (*GetNotificationResponse) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetNotificationResponse) GetNotification() *Notification {
+	if x != nil {
+		return x.Notification
+	}
+	return nil
+}
+
+type DeadLetter struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Notification *Notification          `protobuf:"bytes,1,opt,name=notification,proto3" json:"notification,omitempty"`
+	// Unix time delivery was given up
+	FailedAt      int64 `protobuf:"varint,2,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_notifications_notifications_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeadLetter) GetNotification() *Notification {
+	if x != nil {
+		return x.Notification
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetFailedAt() int64 {
+	if x != nil {
+		return x.FailedAt
+	}
+	return 0
+}
+
+type ListDeadLettersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Default 20, at most 100
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Empty for first page
+	PageToken     string `protobuf:"bytes,2,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersRequest) Reset() {
+	*x = ListDeadLettersRequest{}
+	mi := &file_notifications_notifications_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersRequest) ProtoMessage() {}
+
+func (x *ListDeadLettersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersRequest) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListDeadLettersRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeadLettersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListDeadLettersResponse struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DeadLetters []*DeadLetter          `protobuf:"bytes,1,rep,name=dead_letters,json=deadLetters,proto3" json:"dead_letters,omitempty"`
+	// Empty if there are no more pages
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadLettersResponse) Reset() {
+	*x = ListDeadLettersResponse{}
+	mi := &file_notifications_notifications_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadLettersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadLettersResponse) ProtoMessage() {}
+
+func (x *ListDeadLettersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadLettersResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadLettersResponse) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListDeadLettersResponse) GetDeadLetters() []*DeadLetter {
+	if x != nil {
+		return x.DeadLetters
+	}
+	return nil
+}
+
+func (x *ListDeadLettersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type RetryDeadLetterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id of notification
+	Id            int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryDeadLetterRequest) Reset() {
+	*x = RetryDeadLetterRequest{}
+	mi := &file_notifications_notifications_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryDeadLetterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryDeadLetterRequest) ProtoMessage() {}
+
+func (x *RetryDeadLetterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryDeadLetterRequest.ProtoReflect.Descriptor instead.
+func (*RetryDeadLetterRequest) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RetryDeadLetterRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type RetryDeadLetterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryDeadLetterResponse) Reset() {
+	*x = RetryDeadLetterResponse{}
+	mi := &file_notifications_notifications_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryDeadLetterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryDeadLetterResponse) ProtoMessage() {}
+
+func (x *RetryDeadLetterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_notifications_notifications_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryDeadLetterResponse.ProtoReflect.Descriptor instead.
+func (*RetryDeadLetterResponse) Descriptor() ([]byte, []int) {
+	return file_notifications_notifications_proto_rawDescGZIP(), []int{14}
+}
+
+var File_notifications_notifications_proto protoreflect.FileDescriptor
+
+const file_notifications_notifications_proto_rawDesc = "" +
+	"\n" +
+	"!notifications/notifications.proto\"\t\n" +
+	"\aWelcome\"\x87\x01\n" +
+	"\x15OrderConfirmationLine\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x03 \x01(\x03R\tunitPrice\x12\x1d\n" +
+	"\n" +
+	"line_total\x18\x04 \x01(\x03R\tlineTotal\"r\n" +
+	"\x11OrderConfirmation\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12,\n" +
+	"\x05lines\x18\x02 \x03(\v2\x16.OrderConfirmationLineR\x05lines\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\"D\n" +
+	"\rPasswordReset\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"H\n" +
+	"\x11EmailVerification\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"\xab\x02\n" +
+	"\x17SendNotificationRequest\x12\x1c\n" +
+	"\trecipient\x18\x01 \x01(\tR\trecipient\x12$\n" +
+	"\awelcome\x18\x02 \x01(\v2\b.WelcomeH\x00R\awelcome\x12C\n" +
+	"\x12order_confirmation\x18\x03 \x01(\v2\x12.OrderConfirmationH\x00R\x11orderConfirmation\x127\n" +
+	"\x0epassword_reset\x18\x04 \x01(\v2\x0e.PasswordResetH\x00R\rpasswordReset\x12C\n" +
+	"\x12email_verification\x18\x05 \x01(\v2\x12.EmailVerificationH\x00R\x11emailVerificationB\t\n" +
+	"\apayload\"*\n" +
+	"\x18SendNotificationResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xc4\x02\n" +
+	"\fNotification\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x1c\n" +
+	"\trecipient\x18\x03 \x01(\tR\trecipient\x12\x18\n" +
+	"\asubject\x18\x04 \x01(\tR\asubject\x12\x1b\n" +
+	"\ttext_body\x18\x05 \x01(\tR\btextBody\x12\x1b\n" +
+	"\thtml_body\x18\x06 \x01(\tR\bhtmlBody\x12+\n" +
+	"\x06status\x18\a \x01(\x0e2\x13.NotificationStatusR\x06status\x12\x1a\n" +
+	"\battempts\x18\b \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\t \x01(\tR\tlastError\x12\x17\n" +
+	"\asent_at\x18\n" +
+	" \x01(\x03R\x06sentAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\x03R\tcreatedAt\"(\n" +
+	"\x16GetNotificationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"L\n" +
+	"\x17GetNotificationResponse\x121\n" +
+	"\fnotification\x18\x01 \x01(\v2\r.NotificationR\fnotification\"\\\n" +
+	"\n" +
+	"DeadLetter\x121\n" +
+	"\fnotification\x18\x01 \x01(\v2\r.NotificationR\fnotification\x12\x1b\n" +
+	"\tfailed_at\x18\x02 \x01(\x03R\bfailedAt\"T\n" +
+	"\x16ListDeadLettersRequest\x12\x1b\n" +
+	"\tpage_size\x18\x01 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x02 \x01(\tR\tpageToken\"q\n" +
+	"\x17ListDeadLettersResponse\x12.\n" +
+	"\fdead_letters\x18\x01 \x03(\v2\v.DeadLetterR\vdeadLetters\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"(\n" +
+	"\x16RetryDeadLetterRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\x19\n" +
+	"\x17RetryDeadLetterResponse*\x96\x01\n" +
+	"\x12NotificationStatus\x12#\n" +
+	"\x1fNOTIFICATION_STATUS_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bNOTIFICATION_STATUS_PENDING\x10\x01\x12\x1c\n" +
+	"\x18NOTIFICATION_STATUS_SENT\x10\x02\x12\x1c\n" +
+	"\x18NOTIFICATION_STATUS_DEAD\x10\x032\xb2\x02\n" +
+	"\rNotifications\x12I\n" +
+	"\x10SendNotification\x12\x18.SendNotificationRequest\x1a\x19.SendNotificationResponse\"\x00\x12F\n" +
+	"\x0fGetNotification\x12\x17.GetNotificationRequest\x1a\x18.GetNotificationResponse\"\x00\x12F\n" +
+	"\x0fListDeadLetters\x12\x17.ListDeadLettersRequest\x1a\x18.ListDeadLettersResponse\"\x00\x12F\n" +
+	"\x0fRetryDeadLetter\x12\x17.RetryDeadLetterRequest\x1a\x18.RetryDeadLetterResponse\"\x00B)Z'Kry0z1.notifications.v1;notificationsv1b\x06proto3"
+
+var (
+	file_notifications_notifications_proto_rawDescOnce sync.Once
+	file_notifications_notifications_proto_rawDescData []byte
+)
+
+func file_notifications_notifications_proto_rawDescGZIP() []byte {
+	file_notifications_notifications_proto_rawDescOnce.Do(func() {
+		file_notifications_notifications_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_notifications_notifications_proto_rawDesc), len(file_notifications_notifications_proto_rawDesc)))
+	})
+	return file_notifications_notifications_proto_rawDescData
+}
+
+var file_notifications_notifications_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_notifications_notifications_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_notifications_notifications_proto_goTypes = []any{
+	(NotificationStatus)(0),          // 0: NotificationStatus
+	(*Welcome)(nil),                  // 1: Welcome
+	(*OrderConfirmationLine)(nil),    // 2: OrderConfirmationLine
+	(*OrderConfirmation)(nil),        // 3: OrderConfirmation
+	(*PasswordReset)(nil),            // 4: PasswordReset
+	(*EmailVerification)(nil),        // 5: EmailVerification
+	(*SendNotificationRequest)(nil),  // 6: SendNotificationRequest
+	(*SendNotificationResponse)(nil), // 7: SendNotificationResponse
+	(*Notification)(nil),             // 8: Notification
+	(*GetNotificationRequest)(nil),   // 9: GetNotificationRequest
+	(*GetNotificationResponse)(nil),  // 10: GetNotificationResponse
+	(*DeadLetter)(nil),               // 11: DeadLetter
+	(*ListDeadLettersRequest)(nil),   // 12: ListDeadLettersRequest
+	(*ListDeadLettersResponse)(nil),  // 13: ListDeadLettersResponse
+	(*RetryDeadLetterRequest)(nil),   // 14: RetryDeadLetterRequest
+	(*RetryDeadLetterResponse)(nil),  // 15: RetryDeadLetterResponse
+}
+var file_notifications_notifications_proto_depIdxs = []int32{
+	2,  // 0: OrderConfirmation.lines:type_name -> OrderConfirmationLine
+	1,  // 1: SendNotificationRequest.welcome:type_name -> Welcome
+	3,  // 2: SendNotificationRequest.order_confirmation:type_name -> OrderConfirmation
+	4,  // 3: SendNotificationRequest.password_reset:type_name -> PasswordReset
+	5,  // 4: SendNotificationRequest.email_verification:type_name -> EmailVerification
+	0,  // 5: Notification.status:type_name -> NotificationStatus
+	8,  // 6: GetNotificationResponse.notification:type_name -> Notification
+	8,  // 7: DeadLetter.notification:type_name -> Notification
+	11, // 8: ListDeadLettersResponse.dead_letters:type_name -> DeadLetter
+	6,  // 9: Notifications.SendNotification:input_type -> SendNotificationRequest
+	9,  // 10: Notifications.GetNotification:input_type -> GetNotificationRequest
+	12, // 11: Notifications.ListDeadLetters:input_type -> ListDeadLettersRequest
+	14, // 12: Notifications.RetryDeadLetter:input_type -> RetryDeadLetterRequest
+	7,  // 13: Notifications.SendNotification:output_type -> SendNotificationResponse
+	10, // 14: Notifications.GetNotification:output_type -> GetNotificationResponse
+	13, // 15: Notifications.ListDeadLetters:output_type -> ListDeadLettersResponse
+	15, // 16: Notifications.RetryDeadLetter:output_type -> RetryDeadLetterResponse
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_notifications_notifications_proto_init() }
+func file_notifications_notifications_proto_init() {
+	if File_notifications_notifications_proto != nil {
+		return
+	}
+	file_notifications_notifications_proto_msgTypes[5].OneofWrappers = []any{
+		(*SendNotificationRequest_Welcome)(nil),
+		(*SendNotificationRequest_OrderConfirmation)(nil),
+		(*SendNotificationRequest_PasswordReset)(nil),
+		(*SendNotificationRequest_EmailVerification)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_notifications_notifications_proto_rawDesc), len(file_notifications_notifications_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_notifications_notifications_proto_goTypes,
+		DependencyIndexes: file_notifications_notifications_proto_depIdxs,
+		EnumInfos:         file_notifications_notifications_proto_enumTypes,
+		MessageInfos:      file_notifications_notifications_proto_msgTypes,
+	}.Build()
+	File_notifications_notifications_proto = out.File
+	file_notifications_notifications_proto_goTypes = nil
+	file_notifications_notifications_proto_depIdxs = nil
+}
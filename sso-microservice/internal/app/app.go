@@ -1,11 +1,18 @@
 package app
 
 import (
+	"context"
 	"log/slog"
+	"os"
 	"time"
 
 	grpcapp "github.com/Kry0z1/e-commerce/sso-microservice/internal/app/grpc"
+	notificationsgrpc "github.com/Kry0z1/e-commerce/sso-microservice/internal/clients/notifications/grpc"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/config"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/password"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/services/auth"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/services/keys"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/services/lockout"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage/sqlite"
 )
 
@@ -18,15 +25,120 @@ func New(
 	grpcPort int,
 	storagePath string,
 	tokenTTL time.Duration,
+	refreshTokenTTL time.Duration,
+	emailVerificationTTL time.Duration,
+	passwordResetTTL time.Duration,
+	issuer string,
+	signingAlgorithm string,
+	keyRotation time.Duration,
+	notificationsCfg config.NotificationsConfig,
+	totpCfg config.TOTPConfig,
+	lockoutCfg config.LockoutConfig,
+	passwordCfg config.PasswordConfig,
+	passwordHashCfg config.PasswordHashConfig,
 ) *App {
 	storage, err := sqlite.New(storagePath)
 	if err != nil {
 		panic(err)
 	}
 
-	authService := auth.New(log, storage, storage, storage, tokenTTL)
+	keyManager := keys.New(log, storage, signingAlgorithm, keyRotation, tokenTTL)
 
-	grpcApp := grpcapp.New(authService, log, grpcPort)
+	loginGuard := lockout.New(log, storage,
+		lockout.Policy{
+			FreeAttempts: lockoutCfg.FreeAttempts,
+			MaxFailures:  lockoutCfg.AccountMaxFailures,
+			BaseDelay:    lockoutCfg.BaseDelay,
+			MaxDelay:     lockoutCfg.MaxDelay,
+			Lockout:      lockoutCfg.Duration,
+			Window:       lockoutCfg.Window,
+		},
+		// many users may share address, so it is only locked out, never delayed
+		lockout.Policy{
+			FreeAttempts: lockoutCfg.PeerMaxFailures,
+			MaxFailures:  lockoutCfg.PeerMaxFailures,
+			Lockout:      lockoutCfg.Duration,
+			Window:       lockoutCfg.Window,
+		},
+	)
+
+	breached := password.Common()
+	if passwordCfg.BreachedListPath != "" {
+		f, err := os.Open(passwordCfg.BreachedListPath)
+		if err != nil {
+			panic(err)
+		}
+
+		err = breached.Load(f)
+		f.Close()
+		if err != nil {
+			panic("couldn't load breached passwords: " + err.Error())
+		}
+	}
+
+	passwordPolicy := password.Policy{
+		MinLength:     passwordCfg.MinLength,
+		MaxLength:     passwordCfg.MaxLength,
+		RequireLower:  passwordCfg.RequireLower,
+		RequireUpper:  passwordCfg.RequireUpper,
+		RequireDigit:  passwordCfg.RequireDigit,
+		RequireSymbol: passwordCfg.RequireSymbol,
+		DisallowEmail: passwordCfg.DisallowEmail,
+		Breached:      breached,
+	}
+
+	hasher := password.Hasher{Params: password.Argon2Params{
+		Memory:      passwordHashCfg.Memory,
+		Iterations:  passwordHashCfg.Iterations,
+		Parallelism: passwordHashCfg.Parallelism,
+		SaltLength:  passwordHashCfg.SaltLength,
+		KeyLength:   passwordHashCfg.KeyLength,
+	}}
+
+	var notifier auth.Notifier = auth.NewLogNotifier(log)
+	if notificationsCfg.Address != "" {
+		notifier, err = notificationsgrpc.New(log, notificationsCfg.Address, notificationsCfg.Timeout)
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	authService := auth.New(
+		log,
+		auth.Deps{
+			UserSaver:    storage,
+			UserProvider: storage,
+			AppSaver:     storage,
+			AppProvider:  storage,
+			RoleSaver:    storage,
+			RoleProvider: storage,
+			RefreshSaver: storage,
+			TokenRevoker: storage,
+			VerifyStore:  storage,
+			ResetStore:   storage,
+			TOTPStore:    storage,
+			LoginGuard:   loginGuard,
+			KeyProvider:  keyManager,
+			Notifier:     notifier,
+		},
+		auth.Config{
+			Passwords:    passwordPolicy,
+			Hasher:       hasher,
+			Issuer:       issuer,
+			TOTPIssuer:   totpCfg.Issuer,
+			TokenTTL:     tokenTTL,
+			RefreshTTL:   refreshTokenTTL,
+			VerifyTTL:    emailVerificationTTL,
+			ResetTTL:     passwordResetTTL,
+			ChallengeTTL: totpCfg.ChallengeTTL,
+		},
+	)
+
+	if err := authService.HashLegacyAppSecrets(context.Background()); err != nil {
+		panic(err)
+	}
+
+	grpcApp := grpcapp.New(authService, log, grpcPort, lockoutCfg.TrustedProxies)
 
 	return &App{
 		GRPCServer: grpcApp,
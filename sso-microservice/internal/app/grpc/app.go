@@ -20,10 +20,11 @@ type App struct {
 	port       int
 }
 
-func New(authService auth.Auth, log *slog.Logger, port int) *App {
+func New(authService auth.Auth, log *slog.Logger, port int, trustedProxies []string) *App {
+	// payloads carry passwords, tokens and secrets, so only calls are logged
 	loggingOpts := []logging.Option{
 		logging.WithLogOnEvents(
-			logging.PayloadReceived, logging.PayloadSent,
+			logging.StartCall, logging.FinishCall,
 		),
 	}
 
@@ -39,7 +40,7 @@ func New(authService auth.Auth, log *slog.Logger, port int) *App {
 		logging.UnaryServerInterceptor(InterceptorLogger(log), loggingOpts...),
 	))
 
-	auth.Register(gRPCServer, authService)
+	auth.Register(gRPCServer, authService, trustedProxies)
 
 	return &App{
 		log:        log,
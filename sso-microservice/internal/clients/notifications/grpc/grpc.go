@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	notificationsv1 "github.com/Kry0z1/e-commerce/protos/gen/go/notifications"
+	grpclog "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client queues emails in notification service
+type Client struct {
+	api     notificationsv1.NotificationsClient
+	timeout time.Duration
+}
+
+func New(log *slog.Logger, addr string, timeout time.Duration) (*Client, error) {
+	const op = "clients.notifications.grpc.New"
+
+	// requests carry reset and verification tokens, so only calls are logged
+	logOpts := []grpclog.Option{
+		grpclog.WithLogOnEvents(grpclog.StartCall, grpclog.FinishCall),
+	}
+
+	cc, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			grpclog.UnaryClientInterceptor(InterceptorLogger(log), logOpts...),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Client{
+		api:     notificationsv1.NewNotificationsClient(cc),
+		timeout: timeout,
+	}, nil
+}
+
+// Welcome queues welcome email to just registered user
+func (c *Client) Welcome(ctx context.Context, email string) error {
+	const op = "clients.notifications.grpc.Welcome"
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.api.SendNotification(ctx, &notificationsv1.SendNotificationRequest{
+		Recipient: email,
+		Payload:   &notificationsv1.SendNotificationRequest_Welcome{Welcome: &notificationsv1.Welcome{}},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// EmailVerification queues email with email verification token
+func (c *Client) EmailVerification(ctx context.Context, email string, token string, expiresAt time.Time) error {
+	const op = "clients.notifications.grpc.EmailVerification"
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.api.SendNotification(ctx, &notificationsv1.SendNotificationRequest{
+		Recipient: email,
+		Payload: &notificationsv1.SendNotificationRequest_EmailVerification{EmailVerification: &notificationsv1.EmailVerification{
+			Token:     token,
+			ExpiresAt: expiresAt.Unix(),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PasswordReset queues email with password reset token
+func (c *Client) PasswordReset(ctx context.Context, email string, token string, expiresAt time.Time) error {
+	const op = "clients.notifications.grpc.PasswordReset"
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	_, err := c.api.SendNotification(ctx, &notificationsv1.SendNotificationRequest{
+		Recipient: email,
+		Payload: &notificationsv1.SendNotificationRequest_PasswordReset{PasswordReset: &notificationsv1.PasswordReset{
+			Token:     token,
+			ExpiresAt: expiresAt.Unix(),
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// yoinked
+func InterceptorLogger(l *slog.Logger) grpclog.Logger {
+	return grpclog.LoggerFunc(func(ctx context.Context, lvl grpclog.Level, msg string, fields ...any) {
+		l.Log(ctx, slog.Level(lvl), msg, fields...)
+	})
+}
@@ -10,10 +10,21 @@ import (
 
 type Config struct {
 	// one of "local", "prod"
-	Env         string        `yaml:"env" env-default:"local"`
-	StoragePath string        `yaml:"storage_path" env-required:"true"`
-	GRPC        GRPCConfig    `yaml:"grpc" env-required:"true"`
-	TokenTTL    time.Duration `yaml:"token_ttl" env-required:"true"`
+	Env             string        `yaml:"env" env-default:"local"`
+	StoragePath     string        `yaml:"storage_path" env-required:"true"`
+	GRPC            GRPCConfig    `yaml:"grpc" env-required:"true"`
+	TokenTTL        time.Duration `yaml:"token_ttl" env-required:"true"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" env-default:"720h"`
+	// How long email verification token can be used
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl" env-default:"24h"`
+	// How long password reset token can be used
+	PasswordResetTTL time.Duration       `yaml:"password_reset_ttl" env-default:"1h"`
+	Signing          SigningConfig       `yaml:"signing"`
+	Notifications    NotificationsConfig `yaml:"notifications"`
+	TOTP             TOTPConfig          `yaml:"totp"`
+	Lockout          LockoutConfig       `yaml:"lockout"`
+	Password         PasswordConfig      `yaml:"password"`
+	PasswordHash     PasswordHashConfig  `yaml:"password_hash"`
 }
 
 type GRPCConfig struct {
@@ -21,6 +32,76 @@ type GRPCConfig struct {
 	Timeout time.Duration `yaml:"timeout"`
 }
 
+type SigningConfig struct {
+	// Value of "iss" claim
+	Issuer string `yaml:"issuer" env-default:"sso"`
+	// one of "EdDSA", "RS256"
+	Algorithm      string        `yaml:"algorithm" env-default:"EdDSA"`
+	RotationPeriod time.Duration `yaml:"rotation_period" env-default:"720h"`
+}
+
+type TOTPConfig struct {
+	// Shown by authenticator apps next to codes
+	Issuer string `yaml:"issuer" env-default:"e-commerce"`
+	// How long users with 2FA have to enter code after password
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" env-default:"5m"`
+}
+
+// LockoutConfig limits failed logins.
+//
+// After FreeAttempts failures with the same email each next attempt is delayed
+// twice as long as previous, starting at BaseDelay up to MaxDelay.
+// Emails with AccountMaxFailures failures and peer addresses with PeerMaxFailures
+// failures are locked out for Duration. Failures older than Window are forgotten.
+//
+// Calls from TrustedProxies, like gateway, are counted for address
+// they pass as "x-forwarded-for" metadata instead of their own.
+type LockoutConfig struct {
+	FreeAttempts       int           `yaml:"free_attempts" env-default:"3"`
+	BaseDelay          time.Duration `yaml:"base_delay" env-default:"1s"`
+	MaxDelay           time.Duration `yaml:"max_delay" env-default:"1m"`
+	AccountMaxFailures int           `yaml:"account_max_failures" env-default:"10"`
+	PeerMaxFailures    int           `yaml:"peer_max_failures" env-default:"100"`
+	Duration           time.Duration `yaml:"duration" env-default:"15m"`
+	Window             time.Duration `yaml:"window" env-default:"1h"`
+	TrustedProxies     []string      `yaml:"trusted_proxies"`
+}
+
+// PasswordConfig is policy new passwords of users must satisfy
+type PasswordConfig struct {
+	// In characters
+	MinLength int `yaml:"min_length" env-default:"8"`
+	// In bytes, at most 72: bcrypt ignores the rest
+	MaxLength     int  `yaml:"max_length" env-default:"72"`
+	RequireLower  bool `yaml:"require_lower" env-default:"true"`
+	RequireUpper  bool `yaml:"require_upper" env-default:"true"`
+	RequireDigit  bool `yaml:"require_digit" env-default:"true"`
+	RequireSymbol bool `yaml:"require_symbol" env-default:"false"`
+	// Rejects passwords containing email of user
+	DisallowEmail bool `yaml:"disallow_email" env-default:"true"`
+	// File with SHA-1 hashes of breached passwords, in format of Pwned Passwords downloads.
+	// Passwords are always checked against bundled list of common passwords.
+	BreachedListPath string `yaml:"breached_list_path"`
+}
+
+// PasswordHashConfig holds argon2id parameters of new password hashes.
+// Hashes made with other parameters are replaced on next login of their user.
+type PasswordHashConfig struct {
+	// In KiB
+	Memory      uint32 `yaml:"memory" env-default:"19456"`
+	Iterations  uint32 `yaml:"iterations" env-default:"2"`
+	Parallelism uint8  `yaml:"parallelism" env-default:"1"`
+	// In bytes
+	SaltLength uint32 `yaml:"salt_length" env-default:"16"`
+	KeyLength  uint32 `yaml:"key_length" env-default:"32"`
+}
+
+type NotificationsConfig struct {
+	// Empty address -> emails are not sent
+	Address string        `yaml:"address"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
 func MustLoad() *Config {
 	path := getConfigPath()
 	return MustLoadPath(path)
@@ -1,7 +1,21 @@
 package models
 
+import "time"
+
 type App struct {
-	ID        int
-	Name      string
-	SecretKey string
+	ID   int
+	Name string
+	// Users with unverified email can't log in
+	RequireVerifiedEmail bool
+	RedirectURIs         []string
+	Scopes               []string
+	// Lifetime of access tokens issued for app, 0 means global one
+	TokenTTL  time.Duration
+	CreatedAt time.Time
+	// Zero if app is enabled, users can't log in to disabled apps
+	DisabledAt time.Time
+}
+
+func (a App) Disabled() bool {
+	return !a.DisabledAt.IsZero()
 }
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+type SigningKey struct {
+	// Key id put into "kid" header of tokens
+	ID        string
+	Algorithm string
+	// PKCS #8 DER, empty when key is used only for verification
+	PrivateKey []byte
+	// PKIX DER
+	PublicKey []byte
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// LoginFailures counts consecutive failed logins with the same email or from the same address
+type LoginFailures struct {
+	Key           string
+	Failures      int
+	LastFailureAt time.Time
+}
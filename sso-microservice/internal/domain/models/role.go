@@ -0,0 +1,25 @@
+package models
+
+const (
+	RoleBuyer   = "buyer"
+	RoleSeller  = "seller"
+	RoleSupport = "support"
+	RoleAdmin   = "admin"
+)
+
+const (
+	PermissionPlaceOrders      = "orders:place"
+	PermissionCreateListings   = "listings:create"
+	PermissionFulfilOrders     = "orders:fulfil"
+	PermissionModerateListings = "listings:moderate"
+	PermissionReadUsers        = "users:read"
+	PermissionRevokeSessions   = "sessions:revoke"
+	PermissionManageUsers      = "users:manage"
+	PermissionManageRoles      = "roles:manage"
+	PermissionManageApps       = "apps:manage"
+)
+
+type Role struct {
+	Name        string
+	Permissions []string
+}
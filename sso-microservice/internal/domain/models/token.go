@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+type RefreshToken struct {
+	ID        int64
+	TokenHash []byte
+	UserID    int64
+	AppID     int64
+	FamilyID  string
+	ExpiresAt time.Time
+}
+
+type EmailVerification struct {
+	TokenHash []byte
+	UserID    int64
+	ExpiresAt time.Time
+}
+
+type PasswordReset struct {
+	TokenHash []byte
+	UserID    int64
+	ExpiresAt time.Time
+}
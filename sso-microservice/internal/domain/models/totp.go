@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type TOTP struct {
+	UserID int64
+	Secret []byte
+	// False until user enters first code from their app
+	Confirmed bool
+	// Time step of last accepted code
+	LastUsedStep int64
+}
+
+// LoginChallenge is second step of login of user with 2FA
+type LoginChallenge struct {
+	ID        int64
+	TokenHash []byte
+	UserID    int64
+	AppID     int64
+	ExpiresAt time.Time
+	// Wrong codes entered so far
+	Attempts int
+}
+
+// LoginResult has either tokens, or challenge token when user has 2FA
+type LoginResult struct {
+	Tokens         TokenPair
+	ChallengeToken string
+}
@@ -1,7 +1,38 @@
 package models
 
+import "time"
+
 type User struct {
 	ID             int64
 	Email          string
 	HashedPassword []byte
+	EmailVerified  bool
+	// Zero for users registered before it was recorded
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// Zero if user is enabled, disabled users can't log in
+	DisabledAt time.Time
+}
+
+func (u User) Disabled() bool {
+	return !u.DisabledAt.IsZero()
+}
+
+// UserProfile is user as shown to support staff
+type UserProfile struct {
+	User
+	// Sorted by name
+	Roles []string
+}
+
+// UserFilter narrows down listing of users, zero fields don't filter
+type UserFilter struct {
+	// Case-insensitive
+	EmailPrefix string
+	// Users having role with this name
+	Role string
+	// Users created at or after
+	CreatedFrom time.Time
+	// Users created before
+	CreatedTo time.Time
 }
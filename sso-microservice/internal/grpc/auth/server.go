@@ -3,28 +3,73 @@ package grpcauth
 import (
 	"context"
 	"errors"
+	"math"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
+	"github.com/Kry0z1/e-commerce/authtoken"
 	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/password"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/services/auth"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/services/lockout"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 )
 
 type Auth interface {
-	Login(ctx context.Context, email, password string, appID int64) (string, error)
+	Login(ctx context.Context, email, password string, appID int64, peer string) (models.LoginResult, error)
+	LoginVerify(ctx context.Context, challengeToken string, code string) (models.TokenPair, error)
+	EnrollTOTP(ctx context.Context, token string) (string, string, error)
+	ConfirmTOTP(ctx context.Context, token string, code string) ([]string, error)
+	DisableTOTP(ctx context.Context, token string, code string) error
+	Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error)
 	Register(ctx context.Context, email, password string) (int64, error)
 	IsAdmin(ctx context.Context, id int64) (bool, error)
+	AssignRole(ctx context.Context, token string, userID int64, role string) error
+	RevokeRole(ctx context.Context, token string, userID int64, role string) error
+	ListUserRoles(ctx context.Context, userID int64) ([]models.Role, error)
+	CheckPermission(ctx context.Context, userID int64, permission string) (bool, error)
+	Logout(ctx context.Context, token string, refreshToken string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	RequestPasswordReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token string, newPassword string) error
+	RevokeAllSessions(ctx context.Context, token string, userID int64) error
+	UnlockAccount(ctx context.Context, token string, userID int64) error
+	ListUsers(ctx context.Context, token string, filter models.UserFilter, pageSize int, pageToken string) ([]models.UserProfile, string, error)
+	GetUser(ctx context.Context, token string, userID int64) (models.UserProfile, error)
+	DisableUser(ctx context.Context, token string, userID int64) error
+	EnableUser(ctx context.Context, token string, userID int64) error
+	SetAdmin(ctx context.Context, token string, userID int64, isAdmin bool) error
+	DeleteUser(ctx context.Context, token string, userID int64) error
+	CreateApp(ctx context.Context, token string, app models.App) (models.App, string, error)
+	ListApps(ctx context.Context, token string) ([]models.App, error)
+	RotateAppSecret(ctx context.Context, token string, appID int64) (string, error)
+	DisableApp(ctx context.Context, token string, appID int64) error
+	ValidateToken(ctx context.Context, token string) (*authtoken.Claims, error)
+	SigningKeys(ctx context.Context) ([]models.SigningKey, error)
 }
 
 type serverAPI struct {
 	ssov1.UnimplementedAuthServer
 	auth Auth
+	// hosts whose "x-forwarded-for" metadata is trusted
+	trustedProxies []string
 }
 
-func Register(gRPCServer *grpc.Server, auth Auth) {
-	ssov1.RegisterAuthServer(gRPCServer, &serverAPI{auth: auth})
+func Register(gRPCServer *grpc.Server, auth Auth, trustedProxies []string) {
+	ssov1.RegisterAuthServer(gRPCServer, &serverAPI{auth: auth, trustedProxies: trustedProxies})
 }
 
 func (s *serverAPI) RegisterUser(ctx context.Context, req *ssov1.RegisterUserRequest) (*ssov1.RegisterResponse, error) {
@@ -41,6 +86,9 @@ func (s *serverAPI) RegisterUser(ctx context.Context, req *ssov1.RegisterUserReq
 
 	id, err := s.auth.Register(ctx, email, password)
 	if err != nil {
+		if weak := weakPasswordError(err, "password"); weak != nil {
+			return nil, weak
+		}
 		if errors.Is(err, auth.ErrUserExists) {
 			return nil, status.Error(codes.InvalidArgument, "user with such email already exists")
 		}
@@ -63,16 +111,463 @@ func (s *serverAPI) Login(ctx context.Context, req *ssov1.LoginRequest) (*ssov1.
 		return nil, status.Error(codes.InvalidArgument, "app_id is required")
 	}
 
-	token, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), req.GetAppId())
+	result, err := s.auth.Login(ctx, req.GetEmail(), req.GetPassword(), req.GetAppId(), s.peerAddress(ctx))
 	if err != nil {
+		var blocked *lockout.BlockedError
+		if errors.As(err, &blocked) {
+			// ceil, so client retrying right after waiting is not rejected again
+			seconds := int64(math.Ceil(blocked.RetryAfter.Seconds()))
+			_ = grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.FormatInt(seconds, 10)))
+
+			if errors.Is(err, lockout.ErrLocked) {
+				return nil, status.Error(codes.ResourceExhausted, "too many failed login attempts, account is temporarily locked")
+			}
+			return nil, status.Error(codes.Unavailable, "too many failed login attempts, try again later")
+		}
 		if errors.Is(err, auth.ErrInvalidCredentials) {
 			return nil, status.Error(codes.InvalidArgument, "invalid email or password")
 		}
+		if errors.Is(err, auth.ErrEmailNotVerified) {
+			return nil, status.Error(codes.FailedPrecondition, "email is not verified")
+		}
+		if errors.Is(err, auth.ErrAppDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "app is disabled")
+		}
+		if errors.Is(err, auth.ErrUserDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "user is disabled")
+		}
 
 		return nil, status.Error(codes.Internal, "failed to login")
 	}
 
-	return &ssov1.LoginResponse{Token: token}, nil
+	if result.ChallengeToken != "" {
+		return &ssov1.LoginResponse{TotpRequired: true, ChallengeToken: result.ChallengeToken}, nil
+	}
+
+	return &ssov1.LoginResponse{Token: result.Tokens.AccessToken, RefreshToken: result.Tokens.RefreshToken}, nil
+}
+
+func (s *serverAPI) LoginVerify(ctx context.Context, req *ssov1.LoginVerifyRequest) (*ssov1.LoginVerifyResponse, error) {
+	if req.GetChallengeToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge_token is required")
+	}
+
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	pair, err := s.auth.LoginVerify(ctx, req.GetChallengeToken(), req.GetCode())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidChallenge) {
+			return nil, status.Error(codes.Unauthenticated, "invalid login challenge")
+		}
+
+		return nil, parseAuthError(err, "failed to verify login")
+	}
+
+	return &ssov1.LoginVerifyResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
+
+func (s *serverAPI) EnrollTOTP(ctx context.Context, req *ssov1.EnrollTOTPRequest) (*ssov1.EnrollTOTPResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	secret, uri, err := s.auth.EnrollTOTP(ctx, req.GetToken())
+	if err != nil {
+		return nil, parseAuthError(err, "failed to enroll totp")
+	}
+
+	return &ssov1.EnrollTOTPResponse{Secret: secret, Uri: uri}, nil
+}
+
+func (s *serverAPI) ConfirmTOTP(ctx context.Context, req *ssov1.ConfirmTOTPRequest) (*ssov1.ConfirmTOTPResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	recoveryCodes, err := s.auth.ConfirmTOTP(ctx, req.GetToken(), req.GetCode())
+	if err != nil {
+		return nil, parseAuthError(err, "failed to confirm totp")
+	}
+
+	return &ssov1.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}, nil
+}
+
+func (s *serverAPI) DisableTOTP(ctx context.Context, req *ssov1.DisableTOTPRequest) (*ssov1.DisableTOTPResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	if err := s.auth.DisableTOTP(ctx, req.GetToken(), req.GetCode()); err != nil {
+		return nil, parseAuthError(err, "failed to disable totp")
+	}
+
+	return &ssov1.DisableTOTPResponse{}, nil
+}
+
+func (s *serverAPI) Refresh(ctx context.Context, req *ssov1.RefreshRequest) (*ssov1.RefreshResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	pair, err := s.auth.Refresh(ctx, req.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefresh) {
+			return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+		if errors.Is(err, auth.ErrAppDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "app is disabled")
+		}
+		if errors.Is(err, auth.ErrUserDisabled) {
+			return nil, status.Error(codes.FailedPrecondition, "user is disabled")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to refresh")
+	}
+
+	return &ssov1.RefreshResponse{Token: pair.AccessToken, RefreshToken: pair.RefreshToken}, nil
+}
+
+func (s *serverAPI) Logout(ctx context.Context, req *ssov1.LogoutRequest) (*ssov1.LogoutResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.auth.Logout(ctx, req.GetToken(), req.GetRefreshToken()); err != nil {
+		return &ssov1.LogoutResponse{Succeeded: false}, parseAuthError(err, "failed to logout")
+	}
+
+	return &ssov1.LogoutResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) VerifyEmail(ctx context.Context, req *ssov1.VerifyEmailRequest) (*ssov1.VerifyEmailResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.auth.VerifyEmail(ctx, req.GetToken()); err != nil {
+		if errors.Is(err, auth.ErrInvalidVerifyToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid email verification token")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to verify email")
+	}
+
+	return &ssov1.VerifyEmailResponse{}, nil
+}
+
+func (s *serverAPI) ResendVerification(
+	ctx context.Context,
+	req *ssov1.ResendVerificationRequest,
+) (*ssov1.ResendVerificationResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.auth.ResendVerification(ctx, req.GetEmail()); err != nil {
+		return nil, status.Error(codes.Internal, "failed to resend verification")
+	}
+
+	return &ssov1.ResendVerificationResponse{}, nil
+}
+
+func (s *serverAPI) RequestPasswordReset(
+	ctx context.Context,
+	req *ssov1.RequestPasswordResetRequest,
+) (*ssov1.RequestPasswordResetResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := s.auth.RequestPasswordReset(ctx, req.GetEmail()); err != nil {
+		return nil, status.Error(codes.Internal, "failed to request password reset")
+	}
+
+	return &ssov1.RequestPasswordResetResponse{}, nil
+}
+
+func (s *serverAPI) ResetPassword(ctx context.Context, req *ssov1.ResetPasswordRequest) (*ssov1.ResetPasswordResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if req.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	if err := s.auth.ResetPassword(ctx, req.GetToken(), req.GetNewPassword()); err != nil {
+		if errors.Is(err, auth.ErrInvalidResetToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid password reset token")
+		}
+		if weak := weakPasswordError(err, "new_password"); weak != nil {
+			return nil, weak
+		}
+
+		return nil, status.Error(codes.Internal, "failed to reset password")
+	}
+
+	return &ssov1.ResetPasswordResponse{}, nil
+}
+
+func (s *serverAPI) RevokeAllSessions(ctx context.Context, req *ssov1.RevokeAllSessionsRequest) (*ssov1.RevokeAllSessionsResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.auth.RevokeAllSessions(ctx, req.GetToken(), req.GetUserId()); err != nil {
+		return &ssov1.RevokeAllSessionsResponse{Succeeded: false}, parseAuthError(err, "failed to revoke sessions")
+	}
+
+	return &ssov1.RevokeAllSessionsResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) UnlockAccount(ctx context.Context, req *ssov1.UnlockAccountRequest) (*ssov1.UnlockAccountResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.auth.UnlockAccount(ctx, req.GetToken(), req.GetUserId()); err != nil {
+		return &ssov1.UnlockAccountResponse{Succeeded: false}, parseAuthError(err, "failed to unlock account")
+	}
+
+	return &ssov1.UnlockAccountResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) ListUsers(ctx context.Context, req *ssov1.ListUsersRequest) (*ssov1.ListUsersResponse, error) {
+	if req.GetCreatedFrom() < 0 || req.GetCreatedTo() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "created_from and created_to must not be negative")
+	}
+
+	filter := models.UserFilter{
+		EmailPrefix: req.GetEmailPrefix(),
+		Role:        req.GetRole(),
+	}
+	if req.GetCreatedFrom() > 0 {
+		filter.CreatedFrom = time.Unix(req.GetCreatedFrom(), 0)
+	}
+	if req.GetCreatedTo() > 0 {
+		filter.CreatedTo = time.Unix(req.GetCreatedTo(), 0)
+	}
+
+	users, nextPageToken, err := s.auth.ListUsers(ctx, req.GetToken(), filter, int(req.GetPageSize()), req.GetPageToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+
+		return nil, parseAuthError(err, "failed to list users")
+	}
+
+	resp := &ssov1.ListUsersResponse{
+		Users:         make([]*ssov1.User, 0, len(users)),
+		NextPageToken: nextPageToken,
+	}
+	for _, user := range users {
+		resp.Users = append(resp.Users, userToProto(user))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) GetUser(ctx context.Context, req *ssov1.GetUserRequest) (*ssov1.GetUserResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	user, err := s.auth.GetUser(ctx, req.GetToken(), req.GetUserId())
+	if err != nil {
+		return nil, parseAuthError(err, "failed to get user")
+	}
+
+	return &ssov1.GetUserResponse{User: userToProto(user)}, nil
+}
+
+func (s *serverAPI) DisableUser(ctx context.Context, req *ssov1.DisableUserRequest) (*ssov1.DisableUserResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.auth.DisableUser(ctx, req.GetToken(), req.GetUserId()); err != nil {
+		return &ssov1.DisableUserResponse{Succeeded: false}, parseAuthError(err, "failed to disable user")
+	}
+
+	return &ssov1.DisableUserResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) EnableUser(ctx context.Context, req *ssov1.EnableUserRequest) (*ssov1.EnableUserResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.auth.EnableUser(ctx, req.GetToken(), req.GetUserId()); err != nil {
+		return &ssov1.EnableUserResponse{Succeeded: false}, parseAuthError(err, "failed to enable user")
+	}
+
+	return &ssov1.EnableUserResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) SetAdmin(ctx context.Context, req *ssov1.SetAdminRequest) (*ssov1.SetAdminResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.auth.SetAdmin(ctx, req.GetToken(), req.GetUserId(), req.GetIsAdmin()); err != nil {
+		return &ssov1.SetAdminResponse{Succeeded: false}, parseAuthError(err, "failed to set admin")
+	}
+
+	return &ssov1.SetAdminResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) DeleteUser(ctx context.Context, req *ssov1.DeleteUserRequest) (*ssov1.DeleteUserResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if err := s.auth.DeleteUser(ctx, req.GetToken(), req.GetUserId()); err != nil {
+		return &ssov1.DeleteUserResponse{Succeeded: false}, parseAuthError(err, "failed to delete user")
+	}
+
+	return &ssov1.DeleteUserResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) CreateApp(ctx context.Context, req *ssov1.CreateAppRequest) (*ssov1.CreateAppResponse, error) {
+	if req.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name is required")
+	}
+
+	if req.GetTokenTtl() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "token_ttl must not be negative")
+	}
+
+	for _, uri := range req.GetRedirectUris() {
+		parsed, err := url.Parse(uri)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" || parsed.Fragment != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "redirect uri %q must be absolute and have no fragment", uri)
+		}
+	}
+
+	for _, scope := range req.GetScopes() {
+		if scope == "" || strings.ContainsFunc(scope, unicode.IsSpace) {
+			return nil, status.Errorf(codes.InvalidArgument, "scope %q must be non-empty and have no spaces", scope)
+		}
+	}
+
+	app, secret, err := s.auth.CreateApp(ctx, req.GetToken(), models.App{
+		Name:                 req.GetName(),
+		RequireVerifiedEmail: req.GetRequireVerifiedEmail(),
+		RedirectURIs:         req.GetRedirectUris(),
+		Scopes:               req.GetScopes(),
+		TokenTTL:             time.Duration(req.GetTokenTtl()) * time.Second,
+	})
+	if err != nil {
+		if errors.Is(err, auth.ErrAppExists) {
+			return nil, status.Error(codes.AlreadyExists, "app with such name already exists")
+		}
+		if errors.Is(err, auth.ErrInvalidTokenTTL) {
+			return nil, status.Error(codes.InvalidArgument, "token_ttl must not be longer than default token lifetime")
+		}
+
+		return nil, parseAuthError(err, "failed to create app")
+	}
+
+	return &ssov1.CreateAppResponse{App: appToProto(app), Secret: secret}, nil
+}
+
+func (s *serverAPI) ListApps(ctx context.Context, req *ssov1.ListAppsRequest) (*ssov1.ListAppsResponse, error) {
+	apps, err := s.auth.ListApps(ctx, req.GetToken())
+	if err != nil {
+		return nil, parseAuthError(err, "failed to list apps")
+	}
+
+	resp := &ssov1.ListAppsResponse{Apps: make([]*ssov1.App, 0, len(apps))}
+	for _, app := range apps {
+		resp.Apps = append(resp.Apps, appToProto(app))
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) RotateAppSecret(ctx context.Context, req *ssov1.RotateAppSecretRequest) (*ssov1.RotateAppSecretResponse, error) {
+	if req.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	secret, err := s.auth.RotateAppSecret(ctx, req.GetToken(), req.GetAppId())
+	if err != nil {
+		return nil, parseAuthError(err, "failed to rotate app secret")
+	}
+
+	return &ssov1.RotateAppSecretResponse{Secret: secret}, nil
+}
+
+func (s *serverAPI) DisableApp(ctx context.Context, req *ssov1.DisableAppRequest) (*ssov1.DisableAppResponse, error) {
+	if req.GetAppId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "app_id is required")
+	}
+
+	if err := s.auth.DisableApp(ctx, req.GetToken(), req.GetAppId()); err != nil {
+		return &ssov1.DisableAppResponse{Succeeded: false}, parseAuthError(err, "failed to disable app")
+	}
+
+	return &ssov1.DisableAppResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) ValidateToken(ctx context.Context, req *ssov1.ValidateTokenRequest) (*ssov1.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	claims, err := s.auth.ValidateToken(ctx, req.GetToken())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidToken):
+			return &ssov1.ValidateTokenResponse{Valid: false, Reason: "invalid"}, nil
+		case errors.Is(err, auth.ErrTokenExpired):
+			return &ssov1.ValidateTokenResponse{Valid: false, Reason: "expired"}, nil
+		case errors.Is(err, auth.ErrTokenRevoked):
+			return &ssov1.ValidateTokenResponse{Valid: false, Reason: "revoked"}, nil
+		}
+
+		return nil, status.Error(codes.Internal, "failed to validate token")
+	}
+
+	return &ssov1.ValidateTokenResponse{
+		Valid:         true,
+		UserId:        claims.UserID,
+		AppId:         claims.AppID,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified,
+		Roles:         claims.Roles,
+		ExpiresAt:     claims.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *serverAPI) GetSigningKeys(ctx context.Context, req *ssov1.GetSigningKeysRequest) (*ssov1.GetSigningKeysResponse, error) {
+	keys, err := s.auth.SigningKeys(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get signing keys")
+	}
+
+	resp := &ssov1.GetSigningKeysResponse{Keys: make([]*ssov1.SigningKey, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, &ssov1.SigningKey{
+			Kid:       key.ID,
+			Algorithm: key.Algorithm,
+			PublicKey: key.PublicKey,
+			ExpiresAt: key.ExpiresAt.Unix(),
+		})
+	}
+
+	return resp, nil
 }
 
 func (s *serverAPI) IsAdmin(ctx context.Context, req *ssov1.IsAdminRequest) (*ssov1.IsAdminResponse, error) {
@@ -92,6 +587,204 @@ func (s *serverAPI) IsAdmin(ctx context.Context, req *ssov1.IsAdminRequest) (*ss
 	return &ssov1.IsAdminResponse{IsAdmin: isAdmin}, nil
 }
 
-func New(auth Auth) ssov1.AuthServer {
-	return &serverAPI{auth: auth}
+func (s *serverAPI) AssignRole(ctx context.Context, req *ssov1.AssignRoleRequest) (*ssov1.AssignRoleResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.GetRole() == "" {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	err := s.auth.AssignRole(ctx, req.GetToken(), req.GetUserId(), req.GetRole())
+	if err != nil {
+		return &ssov1.AssignRoleResponse{Succeeded: false}, parseAuthError(err, "failed to assign role")
+	}
+
+	return &ssov1.AssignRoleResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) RevokeRole(ctx context.Context, req *ssov1.RevokeRoleRequest) (*ssov1.RevokeRoleResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.GetRole() == "" {
+		return nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	err := s.auth.RevokeRole(ctx, req.GetToken(), req.GetUserId(), req.GetRole())
+	if err != nil {
+		return &ssov1.RevokeRoleResponse{Succeeded: false}, parseAuthError(err, "failed to revoke role")
+	}
+
+	return &ssov1.RevokeRoleResponse{Succeeded: true}, nil
+}
+
+func (s *serverAPI) ListUserRoles(ctx context.Context, req *ssov1.ListUserRolesRequest) (*ssov1.ListUserRolesResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	roles, err := s.auth.ListUserRoles(ctx, req.GetUserId())
+	if err != nil {
+		return nil, parseAuthError(err, "failed to list roles")
+	}
+
+	resp := &ssov1.ListUserRolesResponse{Roles: make([]*ssov1.Role, 0, len(roles))}
+	for _, role := range roles {
+		resp.Roles = append(resp.Roles, &ssov1.Role{
+			Name:        role.Name,
+			Permissions: role.Permissions,
+		})
+	}
+
+	return resp, nil
+}
+
+func (s *serverAPI) CheckPermission(ctx context.Context, req *ssov1.CheckPermissionRequest) (*ssov1.CheckPermissionResponse, error) {
+	if req.GetUserId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if req.GetPermission() == "" {
+		return nil, status.Error(codes.InvalidArgument, "permission is required")
+	}
+
+	allowed, err := s.auth.CheckPermission(ctx, req.GetUserId(), req.GetPermission())
+	if err != nil {
+		return nil, parseAuthError(err, "failed to check permission")
+	}
+
+	return &ssov1.CheckPermissionResponse{Allowed: allowed}, nil
+}
+
+func userToProto(user models.UserProfile) *ssov1.User {
+	resp := &ssov1.User{
+		Id:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Roles:         user.Roles,
+	}
+
+	if !user.CreatedAt.IsZero() {
+		resp.CreatedAt = user.CreatedAt.Unix()
+	}
+	if !user.UpdatedAt.IsZero() {
+		resp.UpdatedAt = user.UpdatedAt.Unix()
+	}
+	if user.Disabled() {
+		resp.DisabledAt = user.DisabledAt.Unix()
+	}
+
+	return resp
+}
+
+func appToProto(app models.App) *ssov1.App {
+	resp := &ssov1.App{
+		Id:                   int64(app.ID),
+		Name:                 app.Name,
+		RedirectUris:         app.RedirectURIs,
+		Scopes:               app.Scopes,
+		TokenTtl:             int64(app.TokenTTL / time.Second),
+		RequireVerifiedEmail: app.RequireVerifiedEmail,
+	}
+
+	if !app.CreatedAt.IsZero() {
+		resp.CreatedAt = app.CreatedAt.Unix()
+	}
+	if app.Disabled() {
+		resp.DisabledAt = app.DisabledAt.Unix()
+	}
+
+	return resp
+}
+
+// weakPasswordError turns policy violations of password into InvalidArgument
+// with BadRequest details listing every violated rule, nil for other errors
+func weakPasswordError(err error, field string) error {
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return nil
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range policyErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Reason:      v.Reason,
+			Description: v.Description,
+		})
+	}
+
+	st, detailsErr := status.New(codes.InvalidArgument, policyErr.Error()).WithDetails(badRequest)
+	if detailsErr != nil {
+		return status.Error(codes.InvalidArgument, policyErr.Error())
+	}
+
+	return st.Err()
+}
+
+// peerAddress returns host of client calling, empty if unknown.
+// Trusted proxies call on behalf of client they pass as "x-forwarded-for" metadata.
+func (s *serverAPI) peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		host = p.Addr.String()
+	}
+
+	if !slices.Contains(s.trustedProxies, host) {
+		return host
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 && forwarded[0] != "" {
+		// first address is the original client, others are proxies in between
+		client, _, _ := strings.Cut(forwarded[0], ",")
+		return strings.TrimSpace(client)
+	}
+
+	return host
+}
+
+func parseAuthError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, storage.ErrRoleNotFound):
+		return status.Error(codes.NotFound, "role not found")
+	case errors.Is(err, storage.ErrAppNotFound):
+		return status.Error(codes.NotFound, "app not found")
+	case errors.Is(err, auth.ErrAppDisabled):
+		return status.Error(codes.FailedPrecondition, "app is disabled")
+	case errors.Is(err, auth.ErrUserDisabled):
+		return status.Error(codes.FailedPrecondition, "user is disabled")
+	case errors.Is(err, auth.ErrSelfManagement):
+		return status.Error(codes.FailedPrecondition, "users can't disable, delete or demote themselves")
+	case errors.Is(err, auth.ErrInvalidToken):
+		return status.Error(codes.Unauthenticated, "token is invalid")
+	case errors.Is(err, auth.ErrTokenExpired):
+		return status.Error(codes.Unauthenticated, "token is expired")
+	case errors.Is(err, auth.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "token is revoked")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	case errors.Is(err, auth.ErrTOTPEnabled):
+		return status.Error(codes.FailedPrecondition, "two-factor authentication is already enabled")
+	case errors.Is(err, auth.ErrTOTPNotEnrolled):
+		return status.Error(codes.FailedPrecondition, "two-factor authentication is not enabled")
+	case errors.Is(err, auth.ErrInvalidTOTPCode):
+		return status.Error(codes.InvalidArgument, "invalid code")
+	}
+
+	return status.Error(codes.Internal, internalMsg)
+}
+
+func New(auth Auth, trustedProxies []string) ssov1.AuthServer {
+	return &serverAPI{auth: auth, trustedProxies: trustedProxies}
 }
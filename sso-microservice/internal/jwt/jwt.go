@@ -1,25 +1,123 @@
 package jwt
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
 	"time"
 
+	"github.com/Kry0z1/e-commerce/authtoken"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func NewToken(user models.User, app models.App, duration time.Duration) (string, error) {
-	token := jwt.New(jwt.SigningMethodHS256)
+var ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
 
-	claims := token.Claims.(jwt.MapClaims)
-	claims["uid"] = user.ID
-	claims["email"] = user.Email
-	claims["exp"] = time.Now().Add(duration).Unix()
-	claims["app_id"] = app.ID
+const rsaKeyBits = 2048
 
-	tokenString, err := token.SignedString([]byte(app.SecretKey))
+// NewToken fills "jti", "iat", "iat_ms", "nbf" and "exp" claims and signs token
+// with private part of key, putting key id into "kid" header
+func NewToken(claims authtoken.Claims, duration time.Duration, key models.SigningKey) (string, error) {
+	method, err := signingMethod(key.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return "", err
+	}
+
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	claims.ID = jti
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	// lets revocation of every session of user tell tokens issued right before it
+	// from tokens issued right after
+	claims.IssuedAtMilli = now.UnixMilli()
+	claims.NotBefore = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(duration))
+
+	if claims.Roles == nil {
+		claims.Roles = []string{}
+	}
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = key.ID
+
+	tokenString, err := token.SignedString(privateKey)
 	if err != nil {
 		return "", err
 	}
 
 	return tokenString, nil
 }
+
+// GenerateKey creates key pair for algorithm.
+// Returns private key as PKCS #8 DER and public key as PKIX DER.
+func GenerateKey(algorithm string) ([]byte, []byte, error) {
+	var (
+		private crypto.PrivateKey
+		public  crypto.PublicKey
+	)
+
+	switch algorithm {
+	case authtoken.AlgorithmEdDSA:
+		pub, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		private, public = priv, pub
+	case authtoken.AlgorithmRS256:
+		priv, err := rsa.GenerateKey(rand.Reader, rsaKeyBits)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		private, public = priv, &priv.PublicKey
+	default:
+		return nil, nil, ErrUnsupportedAlgorithm
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return privateDER, publicDER, nil
+}
+
+func signingMethod(algorithm string) (jwt.SigningMethod, error) {
+	switch algorithm {
+	case authtoken.AlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	case authtoken.AlgorithmRS256:
+		return jwt.SigningMethodRS256, nil
+	}
+
+	return nil, ErrUnsupportedAlgorithm
+}
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
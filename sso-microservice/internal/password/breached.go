@@ -0,0 +1,91 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+const (
+	hashLength = sha1.Size * 2
+	// length of hash prefix Pwned Passwords range API groups hashes by
+	prefixLength = 5
+)
+
+//go:embed common.txt
+var common []byte
+
+// Breached is set of SHA-1 hashes of common or breached passwords.
+//
+// Hashes are grouped by 5 character prefix the way Pwned Passwords
+// range API does, so lists of hashes it returns can be loaded as is.
+type Breached struct {
+	// prefix -> sorted suffixes
+	ranges map[string][]string
+}
+
+// Common returns bundled list of most common passwords
+func Common() *Breached {
+	b := &Breached{ranges: make(map[string][]string)}
+
+	if err := b.Load(bytes.NewReader(common)); err != nil {
+		panic("bundled password list is broken: " + err.Error())
+	}
+
+	return b
+}
+
+// Load adds hashes from r to list.
+//
+// Every line is uppercase or lowercase hex SHA-1 of password optionally followed
+// by ":count", like in Pwned Passwords downloads. Empty lines and lines
+// starting with "#" are skipped.
+func (b *Breached) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+		if len(hash) != hashLength {
+			return fmt.Errorf("line %d: hash must be %d hex characters", line, hashLength)
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		hash = strings.ToUpper(hash)
+		prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+		b.ranges[prefix] = append(b.ranges[prefix], suffix)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	for prefix, suffixes := range b.ranges {
+		slices.Sort(suffixes)
+		b.ranges[prefix] = slices.Compact(suffixes)
+	}
+
+	return nil
+}
+
+// Contains reports if password is in list
+func (b *Breached) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, found := slices.BinarySearch(b.ranges[hash[:prefixLength]], hash[prefixLength:])
+
+	return found
+}
@@ -0,0 +1,85 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// small parameters keep tests fast
+var hasher = password.Hasher{Params: password.Argon2Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}}
+
+func TestHasher_Argon2(t *testing.T) {
+	hash, err := hasher.Hash("Correct-Horse-7")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(hash), "$argon2id$v=19$m=1024,t=1,p=1$"))
+
+	ok, rehash, err := hasher.Verify("Correct-Horse-7", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.False(t, rehash)
+
+	ok, _, err = hasher.Verify("Wrong-Horse-7", hash)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// same password gets different salt
+	other, err := hasher.Hash("Correct-Horse-7")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, other)
+}
+
+func TestHasher_OutdatedParams(t *testing.T) {
+	hash, err := hasher.Hash("Correct-Horse-7")
+	require.NoError(t, err)
+
+	stronger := hasher
+	stronger.Params.Iterations = 2
+
+	ok, rehash, err := stronger.Verify("Correct-Horse-7", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	ok, _, err = stronger.Verify("Wrong-Horse-7", hash)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_LegacyBcrypt(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("Correct-Horse-7"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	ok, rehash, err := hasher.Verify("Correct-Horse-7", hash)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, rehash)
+
+	ok, _, err = hasher.Verify("Wrong-Horse-7", hash)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_UnknownHash(t *testing.T) {
+	for _, hash := range []string{
+		"",
+		"plain-text",
+		"$argon2i$v=19$m=1024,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=16$m=1024,t=1,p=1$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=1024$c2FsdA$aGFzaA",
+		"$argon2id$v=19$m=1024,t=1,p=1$not base64!$aGFzaA",
+	} {
+		_, _, err := hasher.Verify("Correct-Horse-7", []byte(hash))
+		assert.ErrorIs(t, err, password.ErrUnknownHash, hash)
+	}
+}
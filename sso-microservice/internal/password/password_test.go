@@ -0,0 +1,128 @@
+package password_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var policy = password.Policy{
+	MinLength:     8,
+	MaxLength:     password.MaxBytes,
+	RequireLower:  true,
+	RequireUpper:  true,
+	RequireDigit:  true,
+	RequireSymbol: true,
+	DisallowEmail: true,
+	Breached:      password.Common(),
+}
+
+func reasons(t *testing.T, err error) []string {
+	t.Helper()
+
+	var policyErr *password.PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.True(t, errors.Is(err, password.ErrWeak))
+
+	reasons := make([]string, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		assert.NotEmpty(t, v.Description)
+		reasons = append(reasons, v.Reason)
+	}
+
+	return reasons
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		email    string
+		expected []string
+	}{
+		{
+			name:     "strong",
+			password: "Correct-Horse-7",
+			email:    "user@example.com",
+		},
+		{
+			name:     "digits only",
+			password: "123456",
+			email:    "user@example.com",
+			expected: []string{
+				password.ReasonTooShort, password.ReasonNoLower, password.ReasonNoUpper,
+				password.ReasonNoSymbol, password.ReasonBreached,
+			},
+		},
+		{
+			name:     "too long for bcrypt",
+			password: "Aa1!" + strings.Repeat("a", password.MaxBytes),
+			email:    "user@example.com",
+			expected: []string{password.ReasonTooLong},
+		},
+		{
+			name:     "multibyte characters counted once",
+			password: "Пароль-7Ж",
+			email:    "user@example.com",
+		},
+		{
+			name:     "contains email",
+			password: "My-USER@example.com-1",
+			email:    "user@example.com",
+			expected: []string{password.ReasonContainsMail},
+		},
+		{
+			name:     "contains local part of email",
+			password: "Johnny.B-good1",
+			email:    "johnny.b@example.com",
+			expected: []string{password.ReasonContainsMail},
+		},
+		{
+			name:     "common",
+			password: "P@ssw0rd",
+			email:    "user@example.com",
+			expected: []string{password.ReasonBreached},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password, tt.email)
+			if tt.expected == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			assert.Equal(t, tt.expected, reasons(t, err))
+		})
+	}
+}
+
+func TestCheck_MaxLengthCapped(t *testing.T) {
+	p := password.Policy{MaxLength: 1000}
+
+	err := p.Check(strings.Repeat("a", password.MaxBytes+1), "")
+	assert.Equal(t, []string{password.ReasonTooLong}, reasons(t, err))
+
+	require.NoError(t, p.Check(strings.Repeat("a", password.MaxBytes), ""))
+}
+
+func TestBreached_Load(t *testing.T) {
+	b := password.Common()
+	assert.True(t, b.Contains("qwerty"))
+	assert.False(t, b.Contains("not-a-common-password"))
+
+	// sha1("not-a-common-password") in lowercase, with count like Pwned Passwords
+	err := b.Load(strings.NewReader("# comment\n\n" + "098a97fc2a704aa37061f460c0bff84c6fce4665:42\n"))
+	require.NoError(t, err)
+	assert.True(t, b.Contains("not-a-common-password"))
+	assert.True(t, b.Contains("qwerty"))
+
+	err = b.Load(strings.NewReader("not a hash\n"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "line 1")
+}
@@ -0,0 +1,149 @@
+// Package password checks new passwords of users against password policy
+// and hashes them
+package password
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxBytes is the most bytes of password bcrypt uses
+const MaxBytes = 72
+
+// Reasons of violations
+const (
+	ReasonTooShort     = "TOO_SHORT"
+	ReasonTooLong      = "TOO_LONG"
+	ReasonNoLower      = "NO_LOWER"
+	ReasonNoUpper      = "NO_UPPER"
+	ReasonNoDigit      = "NO_DIGIT"
+	ReasonNoSymbol     = "NO_SYMBOL"
+	ReasonContainsMail = "CONTAINS_EMAIL"
+	ReasonBreached     = "BREACHED"
+)
+
+// ErrWeak is wrapped by *PolicyError
+var ErrWeak = errors.New("password does not satisfy password policy")
+
+// Violation is rule of policy password breaks
+type Violation struct {
+	Reason      string
+	Description string
+}
+
+// PolicyError lists every rule password breaks
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	descriptions := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		descriptions = append(descriptions, v.Description)
+	}
+
+	return fmt.Sprintf("%s: %s", ErrWeak, strings.Join(descriptions, "; "))
+}
+
+func (e *PolicyError) Unwrap() error {
+	return ErrWeak
+}
+
+type Policy struct {
+	// In characters
+	MinLength int
+	// In bytes, values above MaxBytes and 0 mean MaxBytes
+	MaxLength int
+
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	// Rejects passwords containing email of user or its part before "@"
+	DisallowEmail bool
+
+	// nil -> not checked
+	Breached *Breached
+}
+
+// Check returns *PolicyError if password of user with email breaks any rule
+func (p Policy) Check(password string, email string) error {
+	var violations []Violation
+	add := func(reason, description string) {
+		violations = append(violations, Violation{Reason: reason, Description: description})
+	}
+
+	maxLength := p.MaxLength
+	if maxLength <= 0 || maxLength > MaxBytes {
+		maxLength = MaxBytes
+	}
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		add(ReasonTooShort, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+
+	if len(password) > maxLength {
+		add(ReasonTooLong, fmt.Sprintf("must be at most %d bytes long", maxLength))
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || r == ' ':
+			symbol = true
+		}
+	}
+
+	if p.RequireLower && !lower {
+		add(ReasonNoLower, "must contain lowercase letter")
+	}
+	if p.RequireUpper && !upper {
+		add(ReasonNoUpper, "must contain uppercase letter")
+	}
+	if p.RequireDigit && !digit {
+		add(ReasonNoDigit, "must contain digit")
+	}
+	if p.RequireSymbol && !symbol {
+		add(ReasonNoSymbol, "must contain symbol")
+	}
+
+	if p.DisallowEmail && containsEmail(password, email) {
+		add(ReasonContainsMail, "must not contain email")
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		add(ReasonBreached, "is too common or was exposed in data breach")
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
+
+// minEmailPart is length of local part of email too short to look for in passwords
+const minEmailPart = 3
+
+func containsEmail(password string, email string) bool {
+	password = strings.ToLower(password)
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" {
+		return false
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+
+	return strings.Contains(password, email) ||
+		(len(local) >= minEmailPart && strings.Contains(password, local))
+}
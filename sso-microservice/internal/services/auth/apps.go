@@ -0,0 +1,180 @@
+package auth
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// CreateApp saves app and returns it together with its secret.
+// Only hash of secret is kept, so it can't be shown again.
+// Caller needs "apps:manage" permission.
+func (a *Auth) CreateApp(ctx context.Context, token string, app models.App) (models.App, string, error) {
+	const op = "services.auth.CreateApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("name", app.Name),
+	)
+
+	log.Info("creating app")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageApps)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return app, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.TokenTTL > a.tokenTTL {
+		log.Info("token ttl is too long", slog.Duration("token_ttl", app.TokenTTL))
+		return app, "", fmt.Errorf("%s: %w", op, ErrInvalidTokenTTL)
+	}
+
+	app.RedirectURIs = slices.Compact(slices.Sorted(slices.Values(app.RedirectURIs)))
+	app.Scopes = slices.Compact(slices.Sorted(slices.Values(app.Scopes)))
+
+	secret, hash, err := newOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate secret", ll.Err(err))
+		return app, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err = a.appSaver.SaveApp(ctx, app, hex.EncodeToString(hash))
+	if err != nil {
+		if errors.Is(err, storage.ErrAppExists) {
+			return app, "", fmt.Errorf("%s: %w", op, ErrAppExists)
+		}
+
+		log.Error("failed to save app", ll.Err(err))
+		return app, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("created app", slog.Int("app_id", app.ID), slog.Int64("caller_id", callerID))
+
+	return app, secret, nil
+}
+
+// ListApps returns every app sorted by id, caller needs "apps:manage" permission
+func (a *Auth) ListApps(ctx context.Context, token string) ([]models.App, error) {
+	const op = "services.auth.ListApps"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("listing apps")
+
+	if _, err := a.authorize(ctx, token, models.PermissionManageApps); err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	apps, err := a.appProvider.Apps(ctx)
+	if err != nil {
+		log.Error("failed to get apps", ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("listed apps", slog.Int("count", len(apps)))
+
+	return apps, nil
+}
+
+// RotateAppSecret replaces stored hash of app secret with hash of a new one and returns it.
+// Sso itself doesn't authenticate apps by secret yet. Caller needs "apps:manage" permission.
+func (a *Auth) RotateAppSecret(ctx context.Context, token string, appID int64) (string, error) {
+	const op = "services.auth.RotateAppSecret"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("app_id", appID),
+	)
+
+	log.Info("rotating app secret")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageApps)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	secret, hash, err := newOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate secret", ll.Err(err))
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.appSaver.UpdateAppSecret(ctx, appID, hex.EncodeToString(hash)); err != nil {
+		if !errors.Is(err, storage.ErrAppNotFound) {
+			log.Error("failed to save app secret", ll.Err(err))
+		}
+		return "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("rotated app secret", slog.Int64("caller_id", callerID))
+
+	return secret, nil
+}
+
+// DisableApp stops users from logging in to app and refreshing its tokens.
+// Access tokens already issued stay valid until they expire.
+// Caller needs "apps:manage" permission.
+func (a *Auth) DisableApp(ctx context.Context, token string, appID int64) error {
+	const op = "services.auth.DisableApp"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("app_id", appID),
+	)
+
+	log.Info("disabling app")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageApps)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.appSaver.DisableApp(ctx, appID); err != nil {
+		if !errors.Is(err, storage.ErrAppNotFound) {
+			log.Error("failed to disable app", ll.Err(err))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("disabled app", slog.Int64("caller_id", callerID))
+
+	return nil
+}
+
+// HashLegacyAppSecrets hashes plain text secrets of apps inserted by hand,
+// so these apps keep their secrets while only hashes are used
+func (a *Auth) HashLegacyAppSecrets(ctx context.Context) error {
+	const op = "services.auth.HashLegacyAppSecrets"
+
+	log := a.log.With(slog.String("op", op))
+
+	secrets, err := a.appSaver.UnhashedLegacySecrets(ctx)
+	if err != nil {
+		log.Error("failed to get legacy app secrets", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for id, secret := range secrets {
+		if err := a.appSaver.SetLegacySecretHash(ctx, id, hex.EncodeToString(hashOpaqueToken(secret))); err != nil {
+			log.Error("failed to save legacy app secret hash", slog.Int64("app_id", id), ll.Err(err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if len(secrets) > 0 {
+		log.Info("hashed legacy app secrets", slog.Int("count", len(secrets)))
+	}
+
+	return nil
+}
@@ -7,84 +7,439 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/Kry0z1/e-commerce/authtoken"
 	"github.com/Kry0z1/e-commerce/logger/ll"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/jwt"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/password"
 	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
-	"golang.org/x/crypto/bcrypt"
+	gojwt "github.com/golang-jwt/jwt/v5"
 )
 
 var (
 	ErrInvalidCredentials = errors.New("invalid credentials")
 	ErrUserExists         = errors.New("user exists")
+	ErrInvalidToken       = errors.New("token is invalid")
+	ErrTokenExpired       = errors.New("token is expired")
+	ErrTokenRevoked       = errors.New("token is revoked")
+	ErrPermissionDenied   = errors.New("user is not authorized for this action")
+	ErrInvalidRefresh     = errors.New("refresh token is invalid")
+	ErrInvalidResetToken  = errors.New("password reset token is invalid")
+	ErrInvalidVerifyToken = errors.New("email verification token is invalid")
+	ErrEmailNotVerified   = errors.New("email is not verified")
+	ErrTOTPEnabled        = errors.New("two-factor authentication is already enabled")
+	ErrTOTPNotEnrolled    = errors.New("two-factor authentication is not enabled")
+	ErrInvalidTOTPCode    = errors.New("two-factor authentication code is invalid")
+	ErrInvalidChallenge   = errors.New("login challenge is invalid")
+	ErrAppExists          = errors.New("app exists")
+	ErrAppDisabled        = errors.New("app is disabled")
+	ErrInvalidTokenTTL    = errors.New("token ttl is longer than global one")
+	ErrUserDisabled       = errors.New("user is disabled")
+	ErrSelfManagement     = errors.New("users can't disable, delete or demote themselves")
+	ErrInvalidPageToken   = errors.New("invalid page token")
 )
 
 type UserSaver interface {
-	SaveUser(ctx context.Context, email string, hashedPassword []byte) (int64, error)
+	// SaveUser saves user with roles atomically
+	SaveUser(ctx context.Context, email string, hashedPassword []byte, roles []string) (int64, error)
+	UpdatePasswordHash(ctx context.Context, userID int64, oldHash []byte, newHash []byte) error
+	// DisableUser also revokes every session of user
+	DisableUser(ctx context.Context, id int64) error
+	EnableUser(ctx context.Context, id int64) error
+	DeleteUser(ctx context.Context, id int64) error
 }
 
 type UserProvider interface {
 	User(ctx context.Context, email string) (models.User, error)
+	UserByID(ctx context.Context, id int64) (models.User, error)
 	IsAdmin(ctx context.Context, id int64) (bool, error)
+	// Users returns users with id less than beforeID, newest first
+	Users(ctx context.Context, filter models.UserFilter, beforeID int64, limit int) ([]models.UserProfile, error)
+}
+
+type AppSaver interface {
+	SaveApp(ctx context.Context, app models.App, secretHash string) (models.App, error)
+	UpdateAppSecret(ctx context.Context, id int64, secretHash string) error
+	DisableApp(ctx context.Context, id int64) error
+	// UnhashedLegacySecrets returns plain text secrets of apps inserted by hand by app id
+	UnhashedLegacySecrets(ctx context.Context) (map[int64]string, error)
+	SetLegacySecretHash(ctx context.Context, id int64, secretHash string) error
 }
 
 type AppProvider interface {
 	App(ctx context.Context, id int64) (models.App, error)
+	Apps(ctx context.Context) ([]models.App, error)
+}
+
+type RoleSaver interface {
+	AssignRole(ctx context.Context, userID int64, role string) error
+	RevokeRole(ctx context.Context, userID int64, role string) error
+}
+
+type RoleProvider interface {
+	UserRoles(ctx context.Context, userID int64) ([]models.Role, error)
+	HasPermission(ctx context.Context, userID int64, permission string) (bool, error)
+}
+
+type RefreshTokenSaver interface {
+	SaveRefreshToken(ctx context.Context, token models.RefreshToken) error
+	RotateRefreshToken(ctx context.Context, oldHash []byte, newHash []byte, expiresAt time.Time) (models.RefreshToken, error)
+}
+
+type TokenRevoker interface {
+	RevokeToken(ctx context.Context, jti string, userID int64, expiresAt time.Time) error
+	RevokeRefreshFamily(ctx context.Context, tokenHash []byte, userID int64) error
+	RevokeUserSessions(ctx context.Context, userID int64) error
+	IsTokenRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error)
+}
+
+type EmailVerificationStore interface {
+	SaveEmailVerification(ctx context.Context, verification models.EmailVerification) error
+	// VerifyEmail returns id of user whose email is verified
+	VerifyEmail(ctx context.Context, tokenHash []byte) (int64, error)
+}
+
+type PasswordResetStore interface {
+	SavePasswordReset(ctx context.Context, reset models.PasswordReset) error
+	PasswordReset(ctx context.Context, tokenHash []byte) (models.PasswordReset, error)
+	// ResetPassword also revokes every session of user, returns id of user
+	ResetPassword(ctx context.Context, tokenHash []byte, hashedPassword []byte) (int64, error)
+}
+
+type TOTPStore interface {
+	SaveTOTP(ctx context.Context, userID int64, secret []byte) error
+	TOTP(ctx context.Context, userID int64) (models.TOTP, error)
+	ConfirmTOTP(ctx context.Context, userID int64, step int64, recoveryHashes [][]byte) error
+	UseTOTPStep(ctx context.Context, userID int64, step int64) error
+	UseRecoveryCode(ctx context.Context, userID int64, codeHash []byte) error
+	DeleteTOTP(ctx context.Context, userID int64) error
+	SaveLoginChallenge(ctx context.Context, challenge models.LoginChallenge) error
+	LoginChallenge(ctx context.Context, tokenHash []byte) (models.LoginChallenge, error)
+	FailLoginChallenge(ctx context.Context, id int64, maxAttempts int) error
+	CompleteLoginChallenge(ctx context.Context, id int64) error
+}
+
+// LoginGuard limits failed logins per account and per peer address
+type LoginGuard interface {
+	// Check throws *lockout.BlockedError if attempt must be rejected without checking password
+	Check(ctx context.Context, email string, peer string) error
+	Fail(ctx context.Context, email string, peer string) error
+	Succeed(ctx context.Context, email string) error
+	Unlock(ctx context.Context, email string) error
+}
+
+type KeyProvider interface {
+	authtoken.KeySource
+	SigningKey(ctx context.Context) (models.SigningKey, error)
+	PublicKeys(ctx context.Context) ([]models.SigningKey, error)
 }
 
 type Auth struct {
 	log          *slog.Logger
 	userSaver    UserSaver
 	userProvider UserProvider
+	appSaver     AppSaver
 	appProvider  AppProvider
+	roleSaver    RoleSaver
+	roleProvider RoleProvider
+	refreshSaver RefreshTokenSaver
+	tokenRevoker TokenRevoker
+	verifyStore  EmailVerificationStore
+	resetStore   PasswordResetStore
+	totpStore    TOTPStore
+	loginGuard   LoginGuard
+	keyProvider  KeyProvider
+	notifier     Notifier
+	passwords    password.Policy
+	hasher       password.Hasher
+	verifier     *authtoken.Verifier
+	issuer       string
+	totpIssuer   string
 	tokenTTL     time.Duration
+	refreshTTL   time.Duration
+	verifyTTL    time.Duration
+	resetTTL     time.Duration
+	challengeTTL time.Duration
 }
 
-func New(log *slog.Logger, userSaver UserSaver, userProvider UserProvider, appProvider AppProvider, tokenTTL time.Duration) *Auth {
+// Deps are collaborators of Auth, sqlite storage implements all storages among them
+type Deps struct {
+	UserSaver    UserSaver
+	UserProvider UserProvider
+	AppSaver     AppSaver
+	AppProvider  AppProvider
+	RoleSaver    RoleSaver
+	RoleProvider RoleProvider
+	RefreshSaver RefreshTokenSaver
+	TokenRevoker TokenRevoker
+	VerifyStore  EmailVerificationStore
+	ResetStore   PasswordResetStore
+	TOTPStore    TOTPStore
+	LoginGuard   LoginGuard
+	KeyProvider  KeyProvider
+	Notifier     Notifier
+}
+
+// Config describes passwords Auth accepts and tokens it issues
+type Config struct {
+	Passwords password.Policy
+	Hasher    password.Hasher
+	// "iss" claim of issued access tokens
+	Issuer string
+	// Issuer shown in authenticator apps
+	TOTPIssuer string
+	// Lifetime of access tokens of apps without own token ttl
+	TokenTTL     time.Duration
+	RefreshTTL   time.Duration
+	VerifyTTL    time.Duration
+	ResetTTL     time.Duration
+	ChallengeTTL time.Duration
+}
+
+func New(log *slog.Logger, deps Deps, cfg Config) *Auth {
 	return &Auth{
 		log:          log,
-		userSaver:    userSaver,
-		userProvider: userProvider,
-		appProvider:  appProvider,
-		tokenTTL:     tokenTTL,
+		userSaver:    deps.UserSaver,
+		userProvider: deps.UserProvider,
+		appSaver:     deps.AppSaver,
+		appProvider:  deps.AppProvider,
+		roleSaver:    deps.RoleSaver,
+		roleProvider: deps.RoleProvider,
+		refreshSaver: deps.RefreshSaver,
+		tokenRevoker: deps.TokenRevoker,
+		verifyStore:  deps.VerifyStore,
+		resetStore:   deps.ResetStore,
+		totpStore:    deps.TOTPStore,
+		loginGuard:   deps.LoginGuard,
+		keyProvider:  deps.KeyProvider,
+		notifier:     deps.Notifier,
+		passwords:    cfg.Passwords,
+		hasher:       cfg.Hasher,
+		verifier:     authtoken.NewVerifier(deps.KeyProvider, authtoken.WithIssuer(cfg.Issuer)),
+		issuer:       cfg.Issuer,
+		totpIssuer:   cfg.TOTPIssuer,
+		tokenTTL:     cfg.TokenTTL,
+		refreshTTL:   cfg.RefreshTTL,
+		verifyTTL:    cfg.VerifyTTL,
+		resetTTL:     cfg.ResetTTL,
+		challengeTTL: cfg.ChallengeTTL,
 	}
 }
 
-func (a *Auth) Login(ctx context.Context, email, password string, appId int64) (string, error) {
+// Login checks credentials of user. Users with 2FA get challenge token
+// that must be completed with LoginVerify instead of tokens.
+//
+// Failed attempts are counted per email and per peer address,
+// too many of them delay and then lock out next attempts.
+// Empty peer is not counted.
+func (a *Auth) Login(ctx context.Context, email, password string, appId int64, peer string) (models.LoginResult, error) {
 	const op = "services.auth.Login"
 
 	log := a.log.With(
 		slog.String("op", op),
 		slog.String("email", email),
+		slog.String("peer", peer),
 	)
 
 	log.Info("started login")
 
+	var result models.LoginResult
+
+	if err := a.loginGuard.Check(ctx, email, peer); err != nil {
+		log.Info("login attempt rejected", ll.Err(err))
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
 	user, err := a.userProvider.User(ctx, email)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
-			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+			a.failLogin(ctx, log, email, peer)
+			return result, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
-		return "", fmt.Errorf("%s: %w", op, err)
+		return result, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := bcrypt.CompareHashAndPassword(user.HashedPassword, []byte(password)); err != nil {
-		return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	ok, rehash, err := a.hasher.Verify(password, user.HashedPassword)
+	if err != nil {
+		log.Error("failed to verify password", ll.Err(err))
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !ok {
+		a.failLogin(ctx, log, email, peer)
+		return result, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	if user.Disabled() {
+		log.Info("user is disabled")
+		return result, fmt.Errorf("%s: %w", op, ErrUserDisabled)
+	}
+
+	if rehash {
+		a.upgradeHash(ctx, log, user, password)
+	}
+
+	if err := a.loginGuard.Succeed(ctx, email); err != nil {
+		log.Warn("failed to reset login failures", ll.Err(err))
 	}
 
 	app, err := a.appProvider.App(ctx, appId)
 	if err != nil {
-		return "", fmt.Errorf("%s: %w", op, err)
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.Disabled() {
+		log.Info("app is disabled", slog.Int("app_id", app.ID))
+		return result, fmt.Errorf("%s: %w", op, ErrAppDisabled)
 	}
 
-	token, err := jwt.NewToken(user, app, a.tokenTTL)
+	if app.RequireVerifiedEmail && !user.EmailVerified {
+		log.Info("email is not verified")
+		return result, fmt.Errorf("%s: %w", op, ErrEmailNotVerified)
+	}
+
+	twoFactor, err := a.hasTOTP(ctx, user.ID)
 	if err != nil {
-		log.Error("failed to generate token", ll.Err(err))
-		return "", fmt.Errorf("%s: %w", op, err)
+		log.Error("failed to check totp", ll.Err(err))
+		return result, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if twoFactor {
+		result.ChallengeToken, err = a.newLoginChallenge(ctx, user, app)
+		if err != nil {
+			log.Error("failed to generate login challenge", ll.Err(err))
+			return result, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("login challenge issued")
+		return result, nil
+	}
+
+	result.Tokens, err = a.issueTokens(ctx, user, app)
+	if err != nil {
+		log.Error("failed to generate tokens", ll.Err(err))
+		return result, fmt.Errorf("%s: %w", op, err)
 	}
 
 	log.Info("finished login")
-	return token, nil
+	return result, nil
+}
+
+// failLogin counts failed attempt, failing to count it doesn't change login outcome
+func (a *Auth) failLogin(ctx context.Context, log *slog.Logger, email string, peer string) {
+	log.Info("invalid credentials")
+
+	if err := a.loginGuard.Fail(ctx, email, peer); err != nil {
+		log.Error("failed to count login failure", ll.Err(err))
+	}
+}
+
+// upgradeHash replaces outdated hash of user's password with hash made with current parameters.
+// Login goes on even if it fails: old hash still works.
+func (a *Auth) upgradeHash(ctx context.Context, log *slog.Logger, user models.User, password string) {
+	hashed, err := a.hasher.Hash(password)
+	if err != nil {
+		log.Error("failed to rehash password", ll.Err(err))
+		return
+	}
+
+	if err := a.userSaver.UpdatePasswordHash(ctx, user.ID, user.HashedPassword, hashed); err != nil {
+		log.Error("failed to save rehashed password", ll.Err(err))
+		return
+	}
+
+	log.Info("upgraded password hash")
+}
+
+// UnlockAccount forgets failed logins of user, lifting delay or lockout of their account.
+// Caller needs "users:manage" permission.
+func (a *Auth) UnlockAccount(ctx context.Context, token string, userID int64) error {
+	const op = "services.auth.UnlockAccount"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("unlocking account")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageUsers)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.userProvider.UserByID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("failed to get user", ll.Err(err))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.loginGuard.Unlock(ctx, user.Email); err != nil {
+		log.Error("failed to unlock account", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("unlocked account", slog.Int64("caller_id", callerID))
+	return nil
+}
+
+// issueTokens starts new session of user in app
+func (a *Auth) issueTokens(ctx context.Context, user models.User, app models.App) (models.TokenPair, error) {
+	var (
+		pair models.TokenPair
+		err  error
+	)
+
+	pair.AccessToken, err = a.accessToken(ctx, user, app)
+	if err != nil {
+		return pair, err
+	}
+
+	pair.RefreshToken, err = a.newRefreshFamily(ctx, user, app)
+	if err != nil {
+		return pair, err
+	}
+
+	return pair, nil
+}
+
+func (a *Auth) accessToken(ctx context.Context, user models.User, app models.App) (string, error) {
+	roles, err := a.roleProvider.UserRoles(ctx, user.ID)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := a.keyProvider.SigningKey(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	claims := authtoken.Claims{
+		RegisteredClaims: gojwt.RegisteredClaims{
+			Issuer:   a.issuer,
+			Audience: gojwt.ClaimStrings{authtoken.Audience(int64(app.ID))},
+		},
+		UserID:        user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		AppID:         int64(app.ID),
+		Roles:         roleNames(roles),
+	}
+
+	return jwt.NewToken(claims, a.appTokenTTL(app), key)
+}
+
+// appTokenTTL returns lifetime of access tokens issued for app.
+// Apps can only shorten it: signing keys are published for global TTL after rotation.
+func (a *Auth) appTokenTTL(app models.App) time.Duration {
+	if app.TokenTTL > 0 && app.TokenTTL < a.tokenTTL {
+		return app.TokenTTL
+	}
+
+	return a.tokenTTL
 }
 
 func (a *Auth) Register(ctx context.Context, email, password string) (int64, error) {
@@ -97,13 +452,19 @@ func (a *Auth) Register(ctx context.Context, email, password string) (int64, err
 
 	log.Info("started register")
 
-	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := a.passwords.Check(password, email); err != nil {
+		log.Info("weak password", ll.Err(err))
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	hashed, err := a.hasher.Hash(password)
 	if err != nil {
 		log.Error("failed to generate hashed password", ll.Err(err))
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
-	id, err := a.userSaver.SaveUser(ctx, email, hashed)
+	// user without default role can't do anything, so both are saved at once
+	id, err := a.userSaver.SaveUser(ctx, email, hashed, []string{models.RoleBuyer})
 
 	if err != nil {
 		if errors.Is(err, storage.ErrUserExists) {
@@ -114,11 +475,25 @@ func (a *Auth) Register(ctx context.Context, email, password string) (int64, err
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
+	// user is registered already, missing emails are not worth failing:
+	// verification email can be requested again
+	if err := a.notifier.Welcome(ctx, email); err != nil {
+		log.Warn("failed to send welcome email", ll.Err(err))
+	}
+
+	token, expiresAt, err := a.issueVerification(ctx, id)
+	if err != nil {
+		log.Warn("failed to issue verification token", ll.Err(err))
+	} else if err := a.notifier.EmailVerification(ctx, email, token, expiresAt); err != nil {
+		log.Warn("failed to send verification email", ll.Err(err))
+	}
+
 	log.Info("finished register successfully")
 
 	return id, nil
 }
 
+// IsAdmin is kept for compatibility, it reports if user has admin role
 func (a *Auth) IsAdmin(ctx context.Context, id int64) (bool, error) {
 	const op = "Auth.IsAdmin"
 
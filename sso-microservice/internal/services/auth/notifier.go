@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Notifier sends emails to users. Failures never fail calls that trigger emails.
+type Notifier interface {
+	Welcome(ctx context.Context, email string) error
+	EmailVerification(ctx context.Context, email string, token string, expiresAt time.Time) error
+	PasswordReset(ctx context.Context, email string, token string, expiresAt time.Time) error
+}
+
+// LogNotifier is used when sso runs without notification service:
+// emails are only logged, reset tokens included, which is enough for local runs
+type LogNotifier struct {
+	log *slog.Logger
+}
+
+func NewLogNotifier(log *slog.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Welcome(_ context.Context, email string) error {
+	n.log.Info("welcome email", slog.String("email", email))
+	return nil
+}
+
+func (n *LogNotifier) EmailVerification(_ context.Context, email string, token string, expiresAt time.Time) error {
+	n.log.Info("email verification email",
+		slog.String("email", email), slog.String("token", token), slog.Time("expires_at", expiresAt))
+	return nil
+}
+
+func (n *LogNotifier) PasswordReset(_ context.Context, email string, token string, expiresAt time.Time) error {
+	n.log.Info("password reset email",
+		slog.String("email", email), slog.String("token", token), slog.Time("expires_at", expiresAt))
+	return nil
+}
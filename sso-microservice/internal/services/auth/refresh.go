@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// Refresh exchanges refresh token for a new pair of tokens.
+// Each refresh token can be used only once: presenting already used token
+// revokes every token obtained from the same login. Token of disabled user
+// or app is rejected without being used up.
+func (a *Auth) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	const op = "services.auth.Refresh"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started refresh")
+
+	var pair models.TokenPair
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate refresh token", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rotated, err := a.refreshSaver.RotateRefreshToken(ctx, hashOpaqueToken(refreshToken), hash, time.Now().Add(a.refreshTTL))
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrRefreshTokenReused):
+			log.Warn("refresh token reuse detected, token family revoked")
+			return pair, fmt.Errorf("%s: %w", op, ErrInvalidRefresh)
+		case errors.Is(err, storage.ErrRefreshTokenNotFound),
+			errors.Is(err, storage.ErrRefreshTokenExpired),
+			errors.Is(err, storage.ErrRefreshTokenRevoked):
+			log.Info("refresh token rejected", ll.Err(err))
+			return pair, fmt.Errorf("%s: %w", op, ErrInvalidRefresh)
+		case errors.Is(err, storage.ErrUserDisabled):
+			log.Info("user is disabled")
+			return pair, fmt.Errorf("%s: %w", op, ErrUserDisabled)
+		case errors.Is(err, storage.ErrAppDisabled):
+			log.Info("app is disabled")
+			return pair, fmt.Errorf("%s: %w", op, ErrAppDisabled)
+		}
+
+		log.Error("failed to rotate refresh token", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", rotated.UserID))
+
+	user, err := a.userProvider.UserByID(ctx, rotated.UserID)
+	if err != nil {
+		log.Error("failed to get user", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app, err := a.appProvider.App(ctx, rotated.AppID)
+	if err != nil {
+		log.Error("failed to get app", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	pair.AccessToken, err = a.accessToken(ctx, user, app)
+	if err != nil {
+		log.Error("failed to generate token", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+	pair.RefreshToken = raw
+
+	log.Info("finished refresh")
+	return pair, nil
+}
+
+// newRefreshFamily saves first refresh token of a new family and returns it
+func (a *Auth) newRefreshFamily(ctx context.Context, user models.User, app models.App) (string, error) {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	family := make([]byte, 16)
+	if _, err := rand.Read(family); err != nil {
+		return "", err
+	}
+
+	err = a.refreshSaver.SaveRefreshToken(ctx, models.RefreshToken{
+		TokenHash: hash,
+		UserID:    user.ID,
+		AppID:     int64(app.ID),
+		FamilyID:  hex.EncodeToString(family),
+		ExpiresAt: time.Now().Add(a.refreshTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// newOpaqueToken returns random token given to the client and its hash kept in storage.
+// Refresh and password reset tokens are made this way
+func newOpaqueToken() (string, []byte, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+
+	raw := base64.RawURLEncoding.EncodeToString(b)
+
+	return raw, hashOpaqueToken(raw), nil
+}
+
+func hashOpaqueToken(raw string) []byte {
+	hash := sha256.Sum256([]byte(raw))
+	return hash[:]
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// RequestPasswordReset sends password reset token to user with email.
+// It succeeds whether user exists or not, so callers can't probe emails
+func (a *Auth) RequestPasswordReset(ctx context.Context, email string) error {
+	const op = "services.auth.RequestPasswordReset"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	log.Info("started password reset request")
+
+	user, err := a.userProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found, nothing to send")
+			return nil
+		}
+
+		log.Error("failed to get user", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		log.Error("failed to generate reset token", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	expiresAt := time.Now().Add(a.resetTTL)
+
+	err = a.resetStore.SavePasswordReset(ctx, models.PasswordReset{
+		TokenHash: hash,
+		UserID:    user.ID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		log.Error("failed to save reset token", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// failing here would tell caller that user exists
+	if err := a.notifier.PasswordReset(ctx, email, raw, expiresAt); err != nil {
+		log.Warn("failed to send password reset email", ll.Err(err))
+	}
+
+	log.Info("finished password reset request")
+	return nil
+}
+
+// ResetPassword replaces password of user who requested token
+// and revokes every token issued to them so far
+func (a *Auth) ResetPassword(ctx context.Context, token string, newPassword string) error {
+	const op = "services.auth.ResetPassword"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started password reset")
+
+	reset, err := a.resetStore.PasswordReset(ctx, hashOpaqueToken(token))
+	if err != nil {
+		if errors.Is(err, storage.ErrResetTokenNotFound) ||
+			errors.Is(err, storage.ErrResetTokenUsed) ||
+			errors.Is(err, storage.ErrResetTokenExpired) {
+			log.Info("reset token rejected", ll.Err(err))
+			return fmt.Errorf("%s: %w", op, ErrInvalidResetToken)
+		}
+
+		log.Error("failed to get reset token", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.userProvider.UserByID(ctx, reset.UserID)
+	if err != nil {
+		log.Error("failed to get user", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.passwords.Check(newPassword, user.Email); err != nil {
+		log.Info("weak password", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	hashed, err := a.hasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate hashed password", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	userID, err := a.resetStore.ResetPassword(ctx, hashOpaqueToken(token), hashed)
+	if err != nil {
+		if errors.Is(err, storage.ErrResetTokenNotFound) ||
+			errors.Is(err, storage.ErrResetTokenUsed) ||
+			errors.Is(err, storage.ErrResetTokenExpired) {
+			log.Info("reset token rejected", ll.Err(err))
+			return fmt.Errorf("%s: %w", op, ErrInvalidResetToken)
+		}
+
+		log.Error("failed to reset password", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("finished password reset", slog.Int64("user_id", userID))
+	return nil
+}
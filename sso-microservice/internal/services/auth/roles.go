@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+func (a *Auth) AssignRole(ctx context.Context, token string, userID int64, role string) error {
+	const op = "Auth.AssignRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.String("role", role),
+	)
+
+	log.Info("assigning role")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageRoles)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.roleSaver.AssignRole(ctx, userID, role); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("assigned role", slog.Int64("caller_id", callerID))
+
+	return nil
+}
+
+func (a *Auth) RevokeRole(ctx context.Context, token string, userID int64, role string) error {
+	const op = "Auth.RevokeRole"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.String("role", role),
+	)
+
+	log.Info("revoking role")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageRoles)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.roleSaver.RevokeRole(ctx, userID, role); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("revoked role", slog.Int64("caller_id", callerID))
+
+	return nil
+}
+
+func (a *Auth) ListUserRoles(ctx context.Context, userID int64) ([]models.Role, error) {
+	const op = "Auth.ListUserRoles"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("listing user roles")
+
+	roles, err := a.roleProvider.UserRoles(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("listed user roles", slog.Int("count", len(roles)))
+
+	return roles, nil
+}
+
+func (a *Auth) CheckPermission(ctx context.Context, userID int64, permission string) (bool, error) {
+	const op = "Auth.CheckPermission"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.String("permission", permission),
+	)
+
+	log.Info("checking permission")
+
+	allowed, err := a.roleProvider.HasPermission(ctx, userID, permission)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("checked permission", slog.Bool("allowed", allowed))
+
+	return allowed, nil
+}
+
+// authorize verifies token of caller and checks they have permission.
+// Returns id of caller.
+func (a *Auth) authorize(ctx context.Context, token string, permission string) (int64, error) {
+	claims, err := a.verifyToken(ctx, token)
+	if err != nil {
+		return -1, err
+	}
+
+	allowed, err := a.roleProvider.HasPermission(ctx, claims.UserID, permission)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return -1, ErrInvalidToken
+		}
+
+		return -1, err
+	}
+
+	if !allowed {
+		return -1, ErrPermissionDenied
+	}
+
+	return claims.UserID, nil
+}
+
+func roleNames(roles []models.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,124 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+)
+
+// Logout revokes access token and, if not empty, refresh token family
+// obtained with the same login
+func (a *Auth) Logout(ctx context.Context, token string, refreshToken string) error {
+	const op = "services.auth.Logout"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started logout")
+
+	claims, err := a.verifyToken(ctx, token)
+	if err != nil {
+		log.Info("token rejected", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", claims.UserID))
+
+	if err := a.tokenRevoker.RevokeToken(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		log.Error("failed to revoke token", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if refreshToken != "" {
+		if err := a.tokenRevoker.RevokeRefreshFamily(ctx, hashOpaqueToken(refreshToken), claims.UserID); err != nil {
+			log.Error("failed to revoke refresh token", ll.Err(err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	log.Info("finished logout")
+	return nil
+}
+
+// RevokeAllSessions revokes every token issued to user so far,
+// caller needs "sessions:revoke" permission
+func (a *Auth) RevokeAllSessions(ctx context.Context, token string, userID int64) error {
+	const op = "services.auth.RevokeAllSessions"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("revoking all sessions")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionRevokeSessions)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.tokenRevoker.RevokeUserSessions(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("revoked all sessions", slog.Int64("caller_id", callerID))
+	return nil
+}
+
+// ValidateToken checks signature, expiration and revocation status of token.
+// Returns claims of valid token.
+func (a *Auth) ValidateToken(ctx context.Context, token string) (*authtoken.Claims, error) {
+	const op = "services.auth.ValidateToken"
+
+	claims, err := a.verifyToken(ctx, token)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+// verifyToken parses token and checks it wasn't revoked.
+// Throws ErrInvalidToken, ErrTokenExpired and ErrTokenRevoked.
+func (a *Auth) verifyToken(ctx context.Context, token string) (*authtoken.Claims, error) {
+	claims, err := a.verifier.Verify(ctx, token)
+	if err != nil {
+		switch {
+		case errors.Is(err, authtoken.ErrTokenExpired):
+			return nil, ErrTokenExpired
+		case errors.Is(err, authtoken.ErrTokenInvalid):
+			return nil, ErrInvalidToken
+		}
+
+		return nil, err
+	}
+
+	revoked, err := a.tokenRevoker.IsTokenRevoked(ctx, claims.ID, claims.UserID, claims.IssueTime())
+	if err != nil {
+		return nil, err
+	}
+
+	if revoked {
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// SigningKeys returns public keys that tokens can be verified with
+func (a *Auth) SigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	const op = "services.auth.SigningKeys"
+
+	published, err := a.keyProvider.PublicKeys(ctx)
+	if err != nil {
+		a.log.Error("failed to get public keys", slog.String("op", op), ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return published, nil
+}
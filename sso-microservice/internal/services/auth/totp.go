@@ -0,0 +1,349 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/totp"
+)
+
+const (
+	recoveryCodesCount = 10
+	// steps before and after current one codes are accepted from
+	totpSkew = 1
+	// wrong codes after which login challenge stops working
+	maxChallengeAttempts = 5
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// EnrollTOTP generates secret for authenticator app of caller.
+// 2FA is not on until caller confirms it with code from the app.
+// Returns secret encoded for typing in and otpauth:// URI for QR code.
+func (a *Auth) EnrollTOTP(ctx context.Context, token string) (string, string, error) {
+	const op = "services.auth.EnrollTOTP"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started totp enrollment")
+
+	claims, err := a.verifyToken(ctx, token)
+	if err != nil {
+		log.Info("token rejected", ll.Err(err))
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", claims.UserID))
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		log.Error("failed to generate secret", ll.Err(err))
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.totpStore.SaveTOTP(ctx, claims.UserID, secret); err != nil {
+		if errors.Is(err, storage.ErrTOTPConfirmed) {
+			log.Info("totp is already enabled")
+			return "", "", fmt.Errorf("%s: %w", op, ErrTOTPEnabled)
+		}
+
+		log.Error("failed to save secret", ll.Err(err))
+		return "", "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("finished totp enrollment")
+	return totp.EncodeSecret(secret), totp.URI(a.totpIssuer, claims.Email, secret), nil
+}
+
+// ConfirmTOTP turns 2FA of caller on once they enter code from authenticator app.
+// Returns recovery codes, each can replace code from the app once.
+func (a *Auth) ConfirmTOTP(ctx context.Context, token string, code string) ([]string, error) {
+	const op = "services.auth.ConfirmTOTP"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started totp confirmation")
+
+	claims, err := a.verifyToken(ctx, token)
+	if err != nil {
+		log.Info("token rejected", ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", claims.UserID))
+
+	enrolled, err := a.totpStore.TOTP(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrTOTPNotFound) {
+			log.Info("totp is not enrolled")
+			return nil, fmt.Errorf("%s: %w", op, ErrTOTPNotEnrolled)
+		}
+
+		log.Error("failed to get totp", ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if enrolled.Confirmed {
+		log.Info("totp is already enabled")
+		return nil, fmt.Errorf("%s: %w", op, ErrTOTPEnabled)
+	}
+
+	step, ok := totp.Validate(enrolled.Secret, code, time.Now(), totpSkew)
+	if !ok {
+		log.Info("wrong code")
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidTOTPCode)
+	}
+
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([][]byte, 0, recoveryCodesCount)
+	for range recoveryCodesCount {
+		code, err := newRecoveryCode()
+		if err != nil {
+			log.Error("failed to generate recovery code", ll.Err(err))
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	if err := a.totpStore.ConfirmTOTP(ctx, claims.UserID, step, hashes); err != nil {
+		if errors.Is(err, storage.ErrTOTPConfirmed) {
+			log.Info("totp is already enabled")
+			return nil, fmt.Errorf("%s: %w", op, ErrTOTPEnabled)
+		}
+
+		log.Error("failed to confirm totp", ll.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("finished totp confirmation")
+	return codes, nil
+}
+
+// DisableTOTP turns 2FA of caller off, caller proves it is them
+// with code from authenticator app or recovery code
+func (a *Auth) DisableTOTP(ctx context.Context, token string, code string) error {
+	const op = "services.auth.DisableTOTP"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started disabling totp")
+
+	claims, err := a.verifyToken(ctx, token)
+	if err != nil {
+		log.Info("token rejected", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", claims.UserID))
+
+	enabled, err := a.totpStore.TOTP(ctx, claims.UserID)
+	if err != nil && !errors.Is(err, storage.ErrTOTPNotFound) {
+		log.Error("failed to get totp", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err != nil || !enabled.Confirmed {
+		log.Info("totp is not enabled")
+		return fmt.Errorf("%s: %w", op, ErrTOTPNotEnrolled)
+	}
+
+	if err := a.checkSecondFactor(ctx, enabled, code); err != nil {
+		if errors.Is(err, ErrInvalidTOTPCode) {
+			log.Info("wrong code")
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Error("failed to check code", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.totpStore.DeleteTOTP(ctx, claims.UserID); err != nil {
+		log.Error("failed to delete totp", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("finished disabling totp")
+	return nil
+}
+
+// LoginVerify completes login of user with 2FA: challenge token returned by Login
+// is exchanged for tokens with code from authenticator app or recovery code.
+// Challenge stops working after few wrong codes.
+func (a *Auth) LoginVerify(ctx context.Context, challengeToken string, code string) (models.TokenPair, error) {
+	const op = "services.auth.LoginVerify"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started login verification")
+
+	var pair models.TokenPair
+
+	challenge, err := a.totpStore.LoginChallenge(ctx, hashOpaqueToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrChallengeNotFound) ||
+			errors.Is(err, storage.ErrChallengeUsed) ||
+			errors.Is(err, storage.ErrChallengeExpired) {
+			log.Info("challenge rejected", ll.Err(err))
+			return pair, fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to get challenge", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.Int64("user_id", challenge.UserID))
+
+	enabled, err := a.totpStore.TOTP(ctx, challenge.UserID)
+	if err != nil {
+		// 2FA was turned off after login started, user needs to log in again
+		if errors.Is(err, storage.ErrTOTPNotFound) {
+			log.Info("totp is not enabled anymore")
+			return pair, fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to get totp", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.checkSecondFactor(ctx, enabled, code); err != nil {
+		if !errors.Is(err, ErrInvalidTOTPCode) {
+			log.Error("failed to check code", ll.Err(err))
+			return pair, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("wrong code")
+		if err := a.totpStore.FailLoginChallenge(ctx, challenge.ID, maxChallengeAttempts); err != nil {
+			log.Error("failed to count wrong code", ll.Err(err))
+		}
+
+		return pair, fmt.Errorf("%s: %w", op, ErrInvalidTOTPCode)
+	}
+
+	if err := a.totpStore.CompleteLoginChallenge(ctx, challenge.ID); err != nil {
+		if errors.Is(err, storage.ErrChallengeUsed) {
+			log.Info("challenge was completed concurrently")
+			return pair, fmt.Errorf("%s: %w", op, ErrInvalidChallenge)
+		}
+
+		log.Error("failed to complete challenge", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.userProvider.UserByID(ctx, challenge.UserID)
+	if err != nil {
+		log.Error("failed to get user", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Disabled() {
+		log.Info("user is disabled")
+		return pair, fmt.Errorf("%s: %w", op, ErrUserDisabled)
+	}
+
+	app, err := a.appProvider.App(ctx, challenge.AppID)
+	if err != nil {
+		log.Error("failed to get app", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if app.Disabled() {
+		log.Info("app is disabled", slog.Int("app_id", app.ID))
+		return pair, fmt.Errorf("%s: %w", op, ErrAppDisabled)
+	}
+
+	pair, err = a.issueTokens(ctx, user, app)
+	if err != nil {
+		log.Error("failed to generate tokens", ll.Err(err))
+		return pair, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("finished login verification")
+	return pair, nil
+}
+
+// hasTOTP reports if user has 2FA on
+func (a *Auth) hasTOTP(ctx context.Context, userID int64) (bool, error) {
+	enabled, err := a.totpStore.TOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrTOTPNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return enabled.Confirmed, nil
+}
+
+// newLoginChallenge saves challenge user with 2FA completes login with and returns its token
+func (a *Auth) newLoginChallenge(ctx context.Context, user models.User, app models.App) (string, error) {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+
+	err = a.totpStore.SaveLoginChallenge(ctx, models.LoginChallenge{
+		TokenHash: hash,
+		UserID:    user.ID,
+		AppID:     int64(app.ID),
+		ExpiresAt: time.Now().Add(a.challengeTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+// checkSecondFactor accepts code from authenticator app, each code only once,
+// or unused recovery code.
+// Throws ErrInvalidTOTPCode.
+func (a *Auth) checkSecondFactor(ctx context.Context, enabled models.TOTP, code string) error {
+	if step, ok := totp.Validate(enabled.Secret, code, time.Now(), totpSkew); ok {
+		if err := a.totpStore.UseTOTPStep(ctx, enabled.UserID, step); err != nil {
+			if errors.Is(err, storage.ErrTOTPStepUsed) {
+				return ErrInvalidTOTPCode
+			}
+			return err
+		}
+
+		return nil
+	}
+
+	if err := a.totpStore.UseRecoveryCode(ctx, enabled.UserID, hashRecoveryCode(code)); err != nil {
+		if errors.Is(err, storage.ErrRecoveryCodeNotFound) {
+			return ErrInvalidTOTPCode
+		}
+		return err
+	}
+
+	return nil
+}
+
+// newRecoveryCode returns code like "abcd-efgh-ijkl-mnop"
+func newRecoveryCode() (string, error) {
+	b := make([]byte, 10)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	raw := strings.ToLower(recoveryEncoding.EncodeToString(b))
+
+	return raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16], nil
+}
+
+// hashRecoveryCode hashes code ignoring case and dashes users may mistype
+func hashRecoveryCode(code string) []byte {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	return hashOpaqueToken(normalized)
+}
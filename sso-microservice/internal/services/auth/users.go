@@ -0,0 +1,269 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+// userCursor points at the last user of previous page
+type userCursor struct {
+	ID int64 `json:"id"`
+}
+
+// ListUsers returns page of users matching filter, newest first, and token of the next page.
+// Empty next page token means there are no more users.
+// Caller needs "users:read" permission.
+func (a *Auth) ListUsers(
+	ctx context.Context,
+	token string,
+	filter models.UserFilter,
+	pageSize int,
+	pageToken string,
+) ([]models.UserProfile, string, error) {
+	const op = "services.auth.ListUsers"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("listing users")
+
+	if _, err := a.authorize(ctx, token, models.PermissionReadUsers); err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+
+	var cursor userCursor
+	if pageToken != "" {
+		if err := decodePageToken(pageToken, &cursor); err != nil || cursor.ID <= 0 {
+			log.Info("invalid page token")
+			return nil, "", fmt.Errorf("%s: %w", op, ErrInvalidPageToken)
+		}
+	}
+
+	// one extra user tells whether there is next page
+	users, err := a.userProvider.Users(ctx, filter, cursor.ID, pageSize+1)
+	if err != nil {
+		log.Error("failed to get users", ll.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	var nextPageToken string
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		nextPageToken = encodePageToken(userCursor{ID: users[pageSize-1].ID})
+	}
+
+	log.Info("listed users", slog.Int("count", len(users)))
+
+	return users, nextPageToken, nil
+}
+
+// GetUser returns user with their roles, caller needs "users:read" permission
+func (a *Auth) GetUser(ctx context.Context, token string, userID int64) (models.UserProfile, error) {
+	const op = "services.auth.GetUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("getting user")
+
+	var profile models.UserProfile
+
+	if _, err := a.authorize(ctx, token, models.PermissionReadUsers); err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return profile, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := a.userProvider.UserByID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("failed to get user", ll.Err(err))
+		}
+		return profile, fmt.Errorf("%s: %w", op, err)
+	}
+
+	roles, err := a.roleProvider.UserRoles(ctx, userID)
+	if err != nil {
+		log.Error("failed to get roles", ll.Err(err))
+		return profile, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return models.UserProfile{User: user, Roles: roleNames(roles)}, nil
+}
+
+// DisableUser stops user from logging in and revokes every token issued to them.
+// Caller needs "users:manage" permission and can't disable themselves.
+func (a *Auth) DisableUser(ctx context.Context, token string, userID int64) error {
+	const op = "services.auth.DisableUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("disabling user")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageUsers)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if callerID == userID {
+		log.Info("caller tried to disable themselves")
+		return fmt.Errorf("%s: %w", op, ErrSelfManagement)
+	}
+
+	if err := a.userSaver.DisableUser(ctx, userID); err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("failed to disable user", ll.Err(err))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("disabled user", slog.Int64("caller_id", callerID))
+
+	return nil
+}
+
+// EnableUser lets disabled user log in again, caller needs "users:manage" permission
+func (a *Auth) EnableUser(ctx context.Context, token string, userID int64) error {
+	const op = "services.auth.EnableUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("enabling user")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageUsers)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := a.userSaver.EnableUser(ctx, userID); err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("failed to enable user", ll.Err(err))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("enabled user", slog.Int64("caller_id", callerID))
+
+	return nil
+}
+
+// SetAdmin assigns admin role to user or revokes it.
+// Caller needs "roles:manage" permission and can't revoke their own admin role.
+func (a *Auth) SetAdmin(ctx context.Context, token string, userID int64, isAdmin bool) error {
+	const op = "services.auth.SetAdmin"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+		slog.Bool("is_admin", isAdmin),
+	)
+
+	log.Info("setting admin role")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageRoles)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if callerID == userID && !isAdmin {
+		log.Info("caller tried to revoke their own admin role")
+		return fmt.Errorf("%s: %w", op, ErrSelfManagement)
+	}
+
+	if isAdmin {
+		err = a.roleSaver.AssignRole(ctx, userID, models.RoleAdmin)
+	} else {
+		err = a.roleSaver.RevokeRole(ctx, userID, models.RoleAdmin)
+	}
+	if err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("failed to set admin role", ll.Err(err))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("set admin role", slog.Int64("caller_id", callerID))
+
+	return nil
+}
+
+// DeleteUser deletes user with their roles, tokens and 2FA.
+// Caller needs "users:manage" permission and can't delete themselves.
+func (a *Auth) DeleteUser(ctx context.Context, token string, userID int64) error {
+	const op = "services.auth.DeleteUser"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.Int64("user_id", userID),
+	)
+
+	log.Info("deleting user")
+
+	callerID, err := a.authorize(ctx, token, models.PermissionManageUsers)
+	if err != nil {
+		log.Info("caller not authorized", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if callerID == userID {
+		log.Info("caller tried to delete themselves")
+		return fmt.Errorf("%s: %w", op, ErrSelfManagement)
+	}
+
+	if err := a.userSaver.DeleteUser(ctx, userID); err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			log.Error("failed to delete user", ll.Err(err))
+		}
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("deleted user", slog.Int64("caller_id", callerID))
+
+	return nil
+}
+
+// encodePageToken makes opaque token out of cursor struct
+func encodePageToken(cursor any) string {
+	// cursors are plain structs, marshalling can't fail
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(token string, cursor any) error {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, cursor)
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// VerifyEmail marks email of user who was sent token as verified.
+// Tokens issued before keep old "email_verified" claim until refreshed.
+func (a *Auth) VerifyEmail(ctx context.Context, token string) error {
+	const op = "services.auth.VerifyEmail"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("started email verification")
+
+	userID, err := a.verifyStore.VerifyEmail(ctx, hashOpaqueToken(token))
+	if err != nil {
+		if errors.Is(err, storage.ErrVerificationTokenNotFound) ||
+			errors.Is(err, storage.ErrVerificationTokenUsed) ||
+			errors.Is(err, storage.ErrVerificationTokenExpired) {
+			log.Info("verification token rejected", ll.Err(err))
+			return fmt.Errorf("%s: %w", op, ErrInvalidVerifyToken)
+		}
+
+		log.Error("failed to verify email", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("finished email verification", slog.Int64("user_id", userID))
+	return nil
+}
+
+// ResendVerification sends new verification token to user with email
+// if their email is not verified yet.
+// It succeeds either way, so callers can't probe emails.
+func (a *Auth) ResendVerification(ctx context.Context, email string) error {
+	const op = "services.auth.ResendVerification"
+
+	log := a.log.With(
+		slog.String("op", op),
+		slog.String("email", email),
+	)
+
+	log.Info("started resending verification")
+
+	user, err := a.userProvider.User(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found, nothing to send")
+			return nil
+		}
+
+		log.Error("failed to get user", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.EmailVerified {
+		log.Info("email is verified already, nothing to send")
+		return nil
+	}
+
+	token, expiresAt, err := a.issueVerification(ctx, user.ID)
+	if err != nil {
+		log.Error("failed to issue verification token", ll.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// failing here would tell caller that user exists
+	if err := a.notifier.EmailVerification(ctx, email, token, expiresAt); err != nil {
+		log.Warn("failed to send verification email", ll.Err(err))
+	}
+
+	log.Info("finished resending verification")
+	return nil
+}
+
+// issueVerification saves new verification token of user and returns it.
+// Tokens issued to user before stop working.
+func (a *Auth) issueVerification(ctx context.Context, userID int64) (string, time.Time, error) {
+	raw, hash, err := newOpaqueToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expiresAt := time.Now().Add(a.verifyTTL)
+
+	err = a.verifyStore.SaveEmailVerification(ctx, models.EmailVerification{
+		TokenHash: hash,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return raw, expiresAt, nil
+}
@@ -0,0 +1,220 @@
+package keys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/jwt"
+)
+
+type KeyStorage interface {
+	SaveSigningKey(ctx context.Context, key models.SigningKey) error
+	// SigningKeys returns keys that are not expired yet, newest first
+	SigningKeys(ctx context.Context) ([]models.SigningKey, error)
+}
+
+// Manager hands out key for signing new tokens and rotates it
+// once it gets older than rotation period.
+//
+// Rotated keys stay published for verification
+// until tokens signed by them expire.
+type Manager struct {
+	log       *slog.Logger
+	storage   KeyStorage
+	algorithm string
+	rotation  time.Duration
+	tokenTTL  time.Duration
+
+	mu   sync.RWMutex
+	keys []models.SigningKey
+}
+
+func New(log *slog.Logger, storage KeyStorage, algorithm string, rotation time.Duration, tokenTTL time.Duration) *Manager {
+	return &Manager{
+		log:       log,
+		storage:   storage,
+		algorithm: algorithm,
+		rotation:  rotation,
+		tokenTTL:  tokenTTL,
+	}
+}
+
+// SigningKey returns current key for signing, rotating it if needed
+func (m *Manager) SigningKey(ctx context.Context) (models.SigningKey, error) {
+	const op = "services.keys.SigningKey"
+
+	m.mu.RLock()
+	if key, ok := m.current(); ok {
+		m.mu.RUnlock()
+		return key, nil
+	}
+	m.mu.RUnlock()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.load(ctx); err != nil {
+		return models.SigningKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if key, ok := m.current(); ok {
+		return key, nil
+	}
+
+	key, err := m.rotate(ctx)
+	if err != nil {
+		return key, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+// Key returns published key by its id, it makes Manager an authtoken.KeySource
+func (m *Manager) Key(ctx context.Context, kid string) (authtoken.PublicKey, error) {
+	const op = "services.keys.Key"
+
+	m.mu.RLock()
+	key, ok := m.find(kid)
+	m.mu.RUnlock()
+
+	if !ok {
+		// key might have been created by another instance
+		m.mu.Lock()
+		err := m.load(ctx)
+		if err == nil {
+			key, ok = m.find(kid)
+		}
+		m.mu.Unlock()
+
+		if err != nil {
+			return authtoken.PublicKey{}, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if !ok || key.ExpiresAt.Before(time.Now()) {
+		return authtoken.PublicKey{}, fmt.Errorf("%s: %w", op, authtoken.ErrKeyNotFound)
+	}
+
+	public, err := x509.ParsePKIXPublicKey(key.PublicKey)
+	if err != nil {
+		return authtoken.PublicKey{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return authtoken.PublicKey{
+		ID:        key.ID,
+		Algorithm: key.Algorithm,
+		Key:       public,
+	}, nil
+}
+
+// PublicKeys returns every published key without its private part
+func (m *Manager) PublicKeys(ctx context.Context) ([]models.SigningKey, error) {
+	const op = "services.keys.PublicKeys"
+
+	// makes sure current key is published
+	if _, err := m.SigningKey(ctx); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	now := time.Now()
+
+	keys := make([]models.SigningKey, 0, len(m.keys))
+	for _, key := range m.keys {
+		if key.ExpiresAt.Before(now) {
+			continue
+		}
+
+		key.PrivateKey = nil
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// current returns newest key if it is young enough to sign new tokens
+// with configured algorithm. Must be called with mu held.
+func (m *Manager) current() (models.SigningKey, bool) {
+	if len(m.keys) == 0 {
+		return models.SigningKey{}, false
+	}
+
+	key := m.keys[0]
+	if key.Algorithm != m.algorithm || time.Since(key.CreatedAt) >= m.rotation {
+		return key, false
+	}
+
+	return key, true
+}
+
+// Must be called with mu held.
+func (m *Manager) find(kid string) (models.SigningKey, bool) {
+	for _, key := range m.keys {
+		if key.ID == kid {
+			return key, true
+		}
+	}
+
+	return models.SigningKey{}, false
+}
+
+// Must be called with mu locked.
+func (m *Manager) load(ctx context.Context) error {
+	keys, err := m.storage.SigningKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	m.keys = keys
+	return nil
+}
+
+// rotate generates new key and makes it current. Must be called with mu locked.
+func (m *Manager) rotate(ctx context.Context) (models.SigningKey, error) {
+	log := m.log.With(slog.String("algorithm", m.algorithm))
+
+	private, public, err := jwt.GenerateKey(m.algorithm)
+	if err != nil {
+		log.Error("failed to generate signing key", ll.Err(err))
+		return models.SigningKey{}, err
+	}
+
+	kid := make([]byte, 8)
+	if _, err := rand.Read(kid); err != nil {
+		return models.SigningKey{}, err
+	}
+
+	now := time.Now()
+
+	key := models.SigningKey{
+		ID:         hex.EncodeToString(kid),
+		Algorithm:  m.algorithm,
+		PrivateKey: private,
+		PublicKey:  public,
+		CreatedAt:  now,
+		// last token signed by key expires after tokenTTL since rotation
+		ExpiresAt: now.Add(m.rotation + m.tokenTTL),
+	}
+
+	if err := m.storage.SaveSigningKey(ctx, key); err != nil {
+		log.Error("failed to save signing key", ll.Err(err))
+		return models.SigningKey{}, err
+	}
+
+	m.keys = append([]models.SigningKey{key}, m.keys...)
+
+	log.Info("rotated signing key", slog.String("kid", key.ID))
+
+	return key, nil
+}
@@ -0,0 +1,273 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+)
+
+var (
+	// ErrThrottled means login is delayed after few failed attempts
+	ErrThrottled = errors.New("too many failed login attempts, try again later")
+	// ErrLocked means login is locked out after too many failed attempts
+	ErrLocked = errors.New("login is temporarily locked")
+)
+
+// BlockedError wraps ErrThrottled or ErrLocked with time left until next attempt is accepted
+type BlockedError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("%s: retry after %s", e.Err, e.RetryAfter)
+}
+
+func (e *BlockedError) Unwrap() error {
+	return e.Err
+}
+
+type FailureStorage interface {
+	// LoginFailures returns failures of key, zero failures if there are none
+	LoginFailures(ctx context.Context, key string) (models.LoginFailures, error)
+	SaveLoginFailures(ctx context.Context, failures models.LoginFailures) error
+	DeleteLoginFailures(ctx context.Context, key string) error
+	DeleteStaleLoginFailures(ctx context.Context, before time.Time) error
+}
+
+// Policy describes how failed attempts are punished.
+//
+// First FreeAttempts failures cost nothing, each next one delays
+// following attempt twice as long as previous, starting at BaseDelay up to MaxDelay.
+// After MaxFailures failures attempts are locked out for Lockout.
+// Failures older than Window are forgotten.
+type Policy struct {
+	FreeAttempts int
+	MaxFailures  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Lockout      time.Duration
+	Window       time.Duration
+}
+
+// blockedUntil returns time before which next attempt is rejected and error it is rejected with
+func (p Policy) blockedUntil(failures models.LoginFailures) (time.Time, error) {
+	switch {
+	case failures.Failures >= p.MaxFailures:
+		return failures.LastFailureAt.Add(p.Lockout), ErrLocked
+	case failures.Failures > p.FreeAttempts:
+		delay := p.BaseDelay << (failures.Failures - p.FreeAttempts - 1)
+		if delay > p.MaxDelay || delay <= 0 {
+			delay = p.MaxDelay
+		}
+		return failures.LastFailureAt.Add(delay), ErrThrottled
+	}
+
+	return time.Time{}, nil
+}
+
+// pruneSize is size of cache after which forgotten failures are dropped
+const pruneSize = 10000
+
+// Guard counts failed logins per account and per peer address.
+//
+// Failures are kept in storage, so they survive restarts,
+// and cached in memory to spare storage lookups on every login.
+type Guard struct {
+	log     *slog.Logger
+	storage FailureStorage
+	account Policy
+	peer    Policy
+
+	mu    sync.Mutex
+	cache map[string]models.LoginFailures
+}
+
+func New(log *slog.Logger, storage FailureStorage, account Policy, peer Policy) *Guard {
+	return &Guard{
+		log:     log,
+		storage: storage,
+		account: account,
+		peer:    peer,
+		cache:   make(map[string]models.LoginFailures),
+	}
+}
+
+// Check tells if login attempt with email from peer may proceed.
+// Empty peer is not checked.
+// Throws *BlockedError.
+func (g *Guard) Check(ctx context.Context, email string, peer string) error {
+	const op = "services.lockout.Check"
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+
+	var blocked *BlockedError
+	for _, k := range g.keys(email, peer) {
+		failures, err := g.failures(ctx, k.key, k.policy, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		until, reason := k.policy.blockedUntil(failures)
+		if !until.After(now) {
+			continue
+		}
+
+		// the longest block wins
+		if blocked == nil || until.Sub(now) > blocked.RetryAfter {
+			blocked = &BlockedError{Err: reason, RetryAfter: until.Sub(now)}
+		}
+	}
+
+	g.prune(ctx, now)
+
+	if blocked != nil {
+		return fmt.Errorf("%s: %w", op, blocked)
+	}
+
+	return nil
+}
+
+// Fail counts failed attempt with email from peer
+func (g *Guard) Fail(ctx context.Context, email string, peer string) error {
+	const op = "services.lockout.Fail"
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := time.Now()
+
+	for _, k := range g.keys(email, peer) {
+		failures, err := g.failures(ctx, k.key, k.policy, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		failures.Failures++
+		failures.LastFailureAt = now
+
+		if err := g.storage.SaveLoginFailures(ctx, failures); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		g.cache[k.key] = failures
+	}
+
+	g.prune(ctx, now)
+
+	return nil
+}
+
+// Succeed forgets failures of account after successful login.
+// Failures of peer stay, so one valid account doesn't cover guessing others.
+func (g *Guard) Succeed(ctx context.Context, email string) error {
+	const op = "services.lockout.Succeed"
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	key := accountKey(email)
+
+	if failures, ok := g.cache[key]; ok && failures.Failures == 0 {
+		return nil
+	}
+
+	if err := g.forget(ctx, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// Unlock forgets failures of account, lifting its delay or lockout
+func (g *Guard) Unlock(ctx context.Context, email string) error {
+	const op = "services.lockout.Unlock"
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.forget(ctx, accountKey(email)); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+type policyKey struct {
+	key    string
+	policy Policy
+}
+
+func (g *Guard) keys(email string, peer string) []policyKey {
+	keys := []policyKey{{key: accountKey(email), policy: g.account}}
+	if peer != "" {
+		keys = append(keys, policyKey{key: "peer:" + peer, policy: g.peer})
+	}
+	return keys
+}
+
+func accountKey(email string) string {
+	return "email:" + strings.ToLower(email)
+}
+
+// failures returns failures of key from cache or storage, forgetting them if they are too old.
+// Must be called with mu held.
+func (g *Guard) failures(ctx context.Context, key string, policy Policy, now time.Time) (models.LoginFailures, error) {
+	failures, ok := g.cache[key]
+	if !ok {
+		var err error
+		failures, err = g.storage.LoginFailures(ctx, key)
+		if err != nil {
+			return failures, err
+		}
+	}
+
+	if failures.Failures > 0 && now.Sub(failures.LastFailureAt) > policy.Window {
+		failures = models.LoginFailures{Key: key}
+	}
+
+	g.cache[key] = failures
+
+	return failures, nil
+}
+
+// forget drops failures of key. Must be called with mu held.
+func (g *Guard) forget(ctx context.Context, key string) error {
+	if err := g.storage.DeleteLoginFailures(ctx, key); err != nil {
+		return err
+	}
+
+	g.cache[key] = models.LoginFailures{Key: key}
+
+	return nil
+}
+
+// prune drops failures older than both windows once cache grows big.
+// Must be called with mu held.
+func (g *Guard) prune(ctx context.Context, now time.Time) {
+	if len(g.cache) < pruneSize {
+		return
+	}
+
+	window := max(g.account.Window, g.peer.Window)
+
+	for key, failures := range g.cache {
+		if now.Sub(failures.LastFailureAt) > window {
+			delete(g.cache, key)
+		}
+	}
+
+	if err := g.storage.DeleteStaleLoginFailures(ctx, now.Add(-window)); err != nil {
+		g.log.Warn("failed to delete stale login failures", ll.Err(err))
+	}
+}
@@ -0,0 +1,310 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+const appColumns = `id, name, require_verified_email, token_ttl, created_at, disabled_at`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanApp(row scanner) (models.App, error) {
+	var (
+		app        models.App
+		tokenTTL   int64
+		createdAt  int64
+		disabledAt sql.NullInt64
+	)
+
+	if err := row.Scan(&app.ID, &app.Name, &app.RequireVerifiedEmail, &tokenTTL, &createdAt, &disabledAt); err != nil {
+		return app, err
+	}
+
+	app.TokenTTL = time.Duration(tokenTTL) * time.Second
+	// apps inserted by hand before creation time was kept have none
+	if createdAt != 0 {
+		app.CreatedAt = time.Unix(createdAt, 0)
+	}
+	if disabledAt.Valid {
+		app.DisabledAt = time.Unix(disabledAt.Int64, 0)
+	}
+
+	return app, nil
+}
+
+func (s *Storage) App(ctx context.Context, id int64) (models.App, error) {
+	const op = "storage.sqlite.App"
+
+	app, err := scanApp(s.db.QueryRowContext(ctx, `
+		SELECT `+appColumns+`
+		FROM apps
+		WHERE id == ?
+	`, id))
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return app, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+		}
+
+		return app, fmt.Errorf("%s: %w", op, err)
+	}
+
+	redirectURIs, err := appStrings(ctx, s.db, `
+		SELECT app_id, uri FROM app_redirect_uris WHERE app_id == ? ORDER BY uri
+	`, id)
+	if err != nil {
+		return app, fmt.Errorf("%s: %w", op, err)
+	}
+
+	scopes, err := appStrings(ctx, s.db, `
+		SELECT app_id, scope FROM app_scopes WHERE app_id == ? ORDER BY scope
+	`, id)
+	if err != nil {
+		return app, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.RedirectURIs = redirectURIs[app.ID]
+	app.Scopes = scopes[app.ID]
+
+	return app, nil
+}
+
+// Apps returns every app sorted by id
+func (s *Storage) Apps(ctx context.Context) ([]models.App, error) {
+	const op = "storage.sqlite.Apps"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+appColumns+`
+		FROM apps
+		ORDER BY id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var apps []models.App
+
+	for rows.Next() {
+		app, err := scanApp(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		apps = append(apps, app)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	redirectURIs, err := appStrings(ctx, s.db, `
+		SELECT app_id, uri FROM app_redirect_uris ORDER BY uri
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	scopes, err := appStrings(ctx, s.db, `
+		SELECT app_id, scope FROM app_scopes ORDER BY scope
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for i := range apps {
+		apps[i].RedirectURIs = redirectURIs[apps[i].ID]
+		apps[i].Scopes = scopes[apps[i].ID]
+	}
+
+	return apps, nil
+}
+
+// SaveApp saves new app with hash of its secret and returns it with id and creation time set.
+// Throws ErrAppExists if name is taken.
+func (s *Storage) SaveApp(ctx context.Context, app models.App, secretHash string) (models.App, error) {
+	const op = "storage.sqlite.SaveApp"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return app, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO apps(name, secret, require_verified_email, token_ttl, created_at)
+		VALUES(?, ?, ?, ?, ?)
+	`, app.Name, secretHash, app.RequireVerifiedEmail, int64(app.TokenTTL/time.Second), now.Unix())
+	if err != nil {
+		var sqliteErr sqlite3.Error
+
+		if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+			return app, fmt.Errorf("%s: %w", op, storage.ErrAppExists)
+		}
+
+		return app, fmt.Errorf("%s: %w", op, err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return app, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, uri := range app.RedirectURIs {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO app_redirect_uris(app_id, uri) VALUES(?, ?)
+		`, id, uri); err != nil {
+			return app, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	for _, scope := range app.Scopes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO app_scopes(app_id, scope) VALUES(?, ?)
+		`, id, scope); err != nil {
+			return app, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return app, fmt.Errorf("%s: %w", op, err)
+	}
+
+	app.ID = int(id)
+	app.CreatedAt = time.Unix(now.Unix(), 0)
+
+	return app, nil
+}
+
+// UpdateAppSecret replaces hash of app's secret, legacy plain text secret is forgotten
+func (s *Storage) UpdateAppSecret(ctx context.Context, id int64, secretHash string) error {
+	const op = "storage.sqlite.UpdateAppSecret"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE apps SET secret = ?, legacy_secret = NULL WHERE id == ?
+	`, secretHash, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// UnhashedLegacySecrets returns plain text secrets of apps inserted by hand
+// that were not hashed yet by app id
+func (s *Storage) UnhashedLegacySecrets(ctx context.Context) (map[int64]string, error) {
+	const op = "storage.sqlite.UnhashedLegacySecrets"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, legacy_secret FROM apps WHERE legacy_secret IS NOT NULL AND secret == ''
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	secrets := make(map[int64]string)
+	for rows.Next() {
+		var (
+			id     int64
+			secret string
+		)
+
+		if err := rows.Scan(&id, &secret); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		secrets[id] = secret
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return secrets, nil
+}
+
+// SetLegacySecretHash saves hash of app's legacy secret keeping legacy secret itself
+func (s *Storage) SetLegacySecretHash(ctx context.Context, id int64, secretHash string) error {
+	const op = "storage.sqlite.SetLegacySecretHash"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE apps SET secret = ? WHERE id == ? AND secret == ''
+	`, secretHash, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DisableApp marks app disabled, disabling it again keeps the first time
+func (s *Storage) DisableApp(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DisableApp"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE apps SET disabled_at = COALESCE(disabled_at, ?) WHERE id == ?
+	`, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+	}
+
+	return nil
+}
+
+// appStrings runs query returning pairs of app id and value and groups values by app
+func appStrings(ctx context.Context, db *sql.DB, query string, args ...any) (map[int][]string, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make(map[int][]string)
+
+	for rows.Next() {
+		var (
+			appID int
+			value string
+		)
+
+		if err := rows.Scan(&appID, &value); err != nil {
+			return nil, err
+		}
+
+		values[appID] = append(values[appID], value)
+	}
+
+	return values, rows.Err()
+}
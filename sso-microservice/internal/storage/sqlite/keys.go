@@ -0,0 +1,63 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+)
+
+func (s *Storage) SaveSigningKey(ctx context.Context, key models.SigningKey) error {
+	const op = "storage.sqlite.SaveSigningKey"
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO signing_keys(kid, algorithm, private_key, public_key, created_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?)
+	`, key.ID, key.Algorithm, key.PrivateKey, key.PublicKey, key.CreatedAt.Unix(), key.ExpiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// SigningKeys returns keys that are not expired yet, newest first
+func (s *Storage) SigningKeys(ctx context.Context) ([]models.SigningKey, error) {
+	const op = "storage.sqlite.SigningKeys"
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT kid, algorithm, private_key, public_key, created_at, expires_at
+		FROM signing_keys
+		WHERE expires_at > ?
+		ORDER BY created_at DESC
+	`, time.Now().Unix())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []models.SigningKey
+
+	for rows.Next() {
+		var (
+			key                  models.SigningKey
+			createdAt, expiresAt int64
+		)
+
+		if err := rows.Scan(&key.ID, &key.Algorithm, &key.PrivateKey, &key.PublicKey, &createdAt, &expiresAt); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		key.CreatedAt = time.Unix(createdAt, 0)
+		key.ExpiresAt = time.Unix(expiresAt, 0)
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,76 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+)
+
+// LoginFailures returns failures counted for key, zero failures if there are none
+func (s *Storage) LoginFailures(ctx context.Context, key string) (models.LoginFailures, error) {
+	const op = "storage.sqlite.LoginFailures"
+
+	failures := models.LoginFailures{Key: key}
+	var lastTs int64
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT failures, last_failure_at
+		FROM login_failures
+		WHERE key == ?
+	`, key).Scan(&failures.Failures, &lastTs)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return failures, nil
+		}
+
+		return failures, fmt.Errorf("%s: %w", op, err)
+	}
+
+	failures.LastFailureAt = time.Unix(lastTs, 0)
+
+	return failures, nil
+}
+
+func (s *Storage) SaveLoginFailures(ctx context.Context, failures models.LoginFailures) error {
+	const op = "storage.sqlite.SaveLoginFailures"
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO login_failures(key, failures, last_failure_at)
+		VALUES(?, ?, ?)
+		ON CONFLICT(key) DO UPDATE
+		SET failures = excluded.failures, last_failure_at = excluded.last_failure_at
+	`, failures.Key, failures.Failures, failures.LastFailureAt.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) DeleteLoginFailures(ctx context.Context, key string) error {
+	const op = "storage.sqlite.DeleteLoginFailures"
+
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM login_failures WHERE key == ?
+	`, key); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// DeleteStaleLoginFailures removes failures last counted before time
+func (s *Storage) DeleteStaleLoginFailures(ctx context.Context, before time.Time) error {
+	const op = "storage.sqlite.DeleteStaleLoginFailures"
+
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM login_failures WHERE last_failure_at < ?
+	`, before.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+func (s *Storage) SaveRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	const op = "storage.sqlite.SaveRefreshToken"
+
+	_, err := s.db.ExecContext(ctx, `
+		INSERT INTO refresh_tokens(token_hash, user_id, app_id, family_id, created_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?)
+	`, token.TokenHash, token.UserID, token.AppID, token.FamilyID, time.Now().Unix(), token.ExpiresAt.Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RotateRefreshToken marks token with oldHash as used and saves its successor
+// with newHash in the same family. Returns successor.
+//
+// If token with oldHash was already used, whole family is revoked
+// and ErrRefreshTokenReused is returned. Tokens of disabled users and apps
+// are not rotated, so they stay usable once user or app is enabled again.
+func (s *Storage) RotateRefreshToken(
+	ctx context.Context,
+	oldHash []byte,
+	newHash []byte,
+	expiresAt time.Time,
+) (models.RefreshToken, error) {
+	const op = "storage.sqlite.RotateRefreshToken"
+
+	var (
+		old          models.RefreshToken
+		expiresTs    int64
+		usedAt       sql.NullInt64
+		revokedAt    sql.NullInt64
+		userDisabled bool
+		appDisabled  bool
+	)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return old, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT t.id, t.user_id, t.app_id, t.family_id, t.expires_at, t.used_at, t.revoked_at,
+			u.disabled_at IS NOT NULL, a.disabled_at IS NOT NULL
+		FROM refresh_tokens t
+		JOIN users u ON u.id == t.user_id
+		JOIN apps a ON a.id == t.app_id
+		WHERE t.token_hash == ?
+	`, oldHash).Scan(
+		&old.ID, &old.UserID, &old.AppID, &old.FamilyID, &expiresTs, &usedAt, &revokedAt,
+		&userDisabled, &appDisabled,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return old, fmt.Errorf("%s: %w", op, storage.ErrRefreshTokenNotFound)
+		}
+
+		return old, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+
+	switch {
+	case revokedAt.Valid:
+		return old, fmt.Errorf("%s: %w", op, storage.ErrRefreshTokenRevoked)
+	case usedAt.Valid:
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE refresh_tokens
+			SET revoked_at = ?
+			WHERE family_id == ? AND revoked_at IS NULL
+		`, now.Unix(), old.FamilyID); err != nil {
+			return old, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return old, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return old, fmt.Errorf("%s: %w", op, storage.ErrRefreshTokenReused)
+	case expiresTs <= now.Unix():
+		return old, fmt.Errorf("%s: %w", op, storage.ErrRefreshTokenExpired)
+	case userDisabled:
+		return old, fmt.Errorf("%s: %w", op, storage.ErrUserDisabled)
+	case appDisabled:
+		return old, fmt.Errorf("%s: %w", op, storage.ErrAppDisabled)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE refresh_tokens
+		SET used_at = ?
+		WHERE id == ?
+	`, now.Unix(), old.ID); err != nil {
+		return old, fmt.Errorf("%s: %w", op, err)
+	}
+
+	next := models.RefreshToken{
+		TokenHash: newHash,
+		UserID:    old.UserID,
+		AppID:     old.AppID,
+		FamilyID:  old.FamilyID,
+		ExpiresAt: expiresAt,
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO refresh_tokens(token_hash, user_id, app_id, family_id, created_at, expires_at)
+		VALUES(?, ?, ?, ?, ?, ?)
+	`, next.TokenHash, next.UserID, next.AppID, next.FamilyID, now.Unix(), next.ExpiresAt.Unix())
+	if err != nil {
+		return old, fmt.Errorf("%s: %w", op, err)
+	}
+
+	next.ID, err = res.LastInsertId()
+	if err != nil {
+		return old, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return old, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return next, nil
+}
@@ -0,0 +1,141 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// SavePasswordReset saves reset token of user.
+// Unused tokens requested by user before stop working.
+func (s *Storage) SavePasswordReset(ctx context.Context, reset models.PasswordReset) error {
+	const op = "storage.sqlite.SavePasswordReset"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM password_resets WHERE user_id == ? AND used_at IS NULL
+	`, reset.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO password_resets(token_hash, user_id, created_at, expires_at)
+		VALUES(?, ?, ?, ?)
+	`, reset.TokenHash, reset.UserID, time.Now().Unix(), reset.ExpiresAt.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// PasswordReset returns reset token that can still be used
+func (s *Storage) PasswordReset(ctx context.Context, tokenHash []byte) (models.PasswordReset, error) {
+	const op = "storage.sqlite.PasswordReset"
+
+	var (
+		reset     = models.PasswordReset{TokenHash: tokenHash}
+		expiresTs int64
+		usedAt    sql.NullInt64
+	)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT user_id, expires_at, used_at
+		FROM password_resets
+		WHERE token_hash == ?
+	`, tokenHash).Scan(&reset.UserID, &expiresTs, &usedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return reset, fmt.Errorf("%s: %w", op, storage.ErrResetTokenNotFound)
+		}
+
+		return reset, fmt.Errorf("%s: %w", op, err)
+	}
+
+	reset.ExpiresAt = time.Unix(expiresTs, 0)
+
+	switch {
+	case usedAt.Valid:
+		return reset, fmt.Errorf("%s: %w", op, storage.ErrResetTokenUsed)
+	case expiresTs <= time.Now().Unix():
+		return reset, fmt.Errorf("%s: %w", op, storage.ErrResetTokenExpired)
+	}
+
+	return reset, nil
+}
+
+// ResetPassword uses reset token with tokenHash to replace password of its user
+// and revokes every session of that user. Returns id of user.
+func (s *Storage) ResetPassword(ctx context.Context, tokenHash []byte, hashedPassword []byte) (int64, error) {
+	const op = "storage.sqlite.ResetPassword"
+
+	var (
+		id        int64
+		userID    int64
+		expiresTs int64
+		usedAt    sql.NullInt64
+	)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id, expires_at, used_at
+		FROM password_resets
+		WHERE token_hash == ?
+	`, tokenHash).Scan(&id, &userID, &expiresTs, &usedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, fmt.Errorf("%s: %w", op, storage.ErrResetTokenNotFound)
+		}
+
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+
+	switch {
+	case usedAt.Valid:
+		return -1, fmt.Errorf("%s: %w", op, storage.ErrResetTokenUsed)
+	case expiresTs <= now.Unix():
+		return -1, fmt.Errorf("%s: %w", op, storage.ErrResetTokenExpired)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE password_resets SET used_at = ? WHERE id == ?
+	`, now.Unix(), id); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users SET pass_hash = ? WHERE id == ?
+	`, hashedPassword, userID); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := revokeUserSessions(ctx, tx, userID, now); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
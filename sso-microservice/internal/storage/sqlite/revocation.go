@@ -0,0 +1,123 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// RevokeToken revokes single access token until it expires
+func (s *Storage) RevokeToken(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	const op = "storage.sqlite.RevokeToken"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// expired tokens are rejected anyway, no need to keep them
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM revoked_tokens WHERE expires_at < ?
+	`, time.Now().Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO revoked_tokens(jti, user_id, expires_at) VALUES(?, ?, ?)
+	`, jti, userID, expiresAt.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeRefreshFamily revokes refresh token with hash and every token of its family.
+// Token must belong to user, unknown token is no-op.
+func (s *Storage) RevokeRefreshFamily(ctx context.Context, tokenHash []byte, userID int64) error {
+	const op = "storage.sqlite.RevokeRefreshFamily"
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = ?
+		WHERE revoked_at IS NULL AND family_id IN (
+			SELECT family_id
+			FROM refresh_tokens
+			WHERE token_hash == ? AND user_id == ?
+		)
+	`, time.Now().Unix(), tokenHash, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeUserSessions revokes every access and refresh token issued to user so far
+func (s *Storage) RevokeUserSessions(ctx context.Context, userID int64) error {
+	const op = "storage.sqlite.RevokeUserSessions"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := userExists(ctx, tx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := revokeUserSessions(ctx, tx, userID, time.Now()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func revokeUserSessions(ctx context.Context, tx *sql.Tx, userID int64, now time.Time) error {
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO session_revocations(user_id, revoked_before) VALUES(?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET revoked_before = excluded.revoked_before
+	`, userID, now.UnixMilli()); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = ?
+		WHERE user_id == ? AND revoked_at IS NULL
+	`, now.Unix(), userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IsTokenRevoked reports if access token was revoked by itself
+// or together with all sessions of user after it was issued.
+// Tokens of deleted users are revoked too, even if their id is given to new user.
+func (s *Storage) IsTokenRevoked(ctx context.Context, jti string, userID int64, issuedAt time.Time) (bool, error) {
+	const op = "storage.sqlite.IsTokenRevoked"
+
+	var revoked bool
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM revoked_tokens WHERE jti == ?)
+			OR EXISTS(SELECT 1 FROM session_revocations WHERE user_id == ? AND revoked_before > ?)
+			OR NOT EXISTS(SELECT 1 FROM users WHERE id == ? AND created_at <= ?)
+	`, jti, userID, issuedAt.UnixMilli(), userID, issuedAt.Unix()).Scan(&revoked)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return revoked, nil
+}
@@ -0,0 +1,198 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// AssignRole gives role to user, assigning already owned role is no-op
+func (s *Storage) AssignRole(ctx context.Context, userID int64, role string) error {
+	const op = "storage.sqlite.AssignRole"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := userExists(ctx, tx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	roleID, err := roleID(ctx, tx, role)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT OR IGNORE INTO user_roles(user_id, role_id) VALUES(?, ?)
+	`, userID, roleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeRole takes role from user, revoking not owned role is no-op
+func (s *Storage) RevokeRole(ctx context.Context, userID int64, role string) error {
+	const op = "storage.sqlite.RevokeRole"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if err := userExists(ctx, tx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	roleID, err := roleID(ctx, tx, role)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM user_roles
+		WHERE user_id == ? AND role_id == ?
+	`, userID, roleID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UserRoles returns roles of user sorted by name
+func (s *Storage) UserRoles(ctx context.Context, userID int64) ([]models.Role, error) {
+	const op = "storage.sqlite.UserRoles"
+
+	if err := userExists(ctx, s.db, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT r.name, p.name
+		FROM user_roles ur
+		JOIN roles r ON r.id = ur.role_id
+		LEFT JOIN role_permissions rp ON rp.role_id = r.id
+		LEFT JOIN permissions p ON p.id = rp.permission_id
+		WHERE ur.user_id == ?
+		ORDER BY r.name, p.name
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var roles []models.Role
+
+	for rows.Next() {
+		var (
+			role       string
+			permission sql.NullString
+		)
+
+		if err := rows.Scan(&role, &permission); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		if len(roles) == 0 || roles[len(roles)-1].Name != role {
+			roles = append(roles, models.Role{Name: role})
+		}
+
+		if permission.Valid {
+			last := &roles[len(roles)-1]
+			last.Permissions = append(last.Permissions, permission.String)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return roles, nil
+}
+
+// HasPermission reports if any role of user grants them permission
+func (s *Storage) HasPermission(ctx context.Context, userID int64, permission string) (bool, error) {
+	const op = "storage.sqlite.HasPermission"
+
+	var allowed bool
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1
+			FROM user_roles ur
+			JOIN role_permissions rp ON rp.role_id = ur.role_id
+			JOIN permissions p ON p.id = rp.permission_id
+			WHERE ur.user_id = u.id AND p.name == ?
+		)
+		FROM users u
+		WHERE u.id == ?
+	`, permission, userID).Scan(&allowed)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return allowed, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+		}
+
+		return allowed, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, nil
+}
+
+type querier interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func userExists(ctx context.Context, q querier, userID int64) error {
+	var id int64
+
+	err := q.QueryRowContext(ctx, `
+		SELECT id FROM users WHERE id == ?
+	`, userID).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return storage.ErrUserNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func roleID(ctx context.Context, q querier, role string) (int64, error) {
+	var id int64
+
+	err := q.QueryRowContext(ctx, `
+		SELECT id FROM roles WHERE name == ?
+	`, role).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, storage.ErrRoleNotFound
+		}
+
+		return -1, err
+	}
+
+	return id, nil
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/mattn/go-sqlite3"
 
@@ -19,7 +21,8 @@ type Storage struct {
 func New(storagePath string) (*Storage, error) {
 	const op = "storage.sqlite.New"
 
-	db, err := sql.Open("sqlite3", storagePath)
+	// cascades declared in migrations take effect only with foreign keys on
+	db, err := sql.Open("sqlite3", withForeignKeys(storagePath))
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
@@ -31,12 +34,30 @@ func (s *Storage) Stop() error {
 	return s.db.Close()
 }
 
-func (s *Storage) SaveUser(ctx context.Context, email string, hashedPassword []byte) (int64, error) {
+// withForeignKeys adds option turning foreign keys on for every connection to dsn
+func withForeignKeys(dsn string) string {
+	if strings.Contains(dsn, "?") {
+		return dsn + "&_foreign_keys=on"
+	}
+
+	return dsn + "?_foreign_keys=on"
+}
+
+// SaveUser saves user together with given roles, either both or none of them are saved
+func (s *Storage) SaveUser(ctx context.Context, email string, hashedPassword []byte, roles []string) (int64, error) {
 	const op = "storage.sqlite.SaveUser"
 
-	res, err := s.db.ExecContext(ctx, `
-		INSERT INTO users(email, pass_hash) VALUES(?, ?)
-	`, email, hashedPassword)
+	now := time.Now().Unix()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO users(email, pass_hash, created_at, updated_at) VALUES(?, ?, ?, ?)
+	`, email, hashedPassword, now, now)
 
 	if err != nil {
 		var sqliteErr sqlite3.Error
@@ -53,19 +74,50 @@ func (s *Storage) SaveUser(ctx context.Context, email string, hashedPassword []b
 		return -1, fmt.Errorf("%s: %w", op, err)
 	}
 
+	for _, role := range roles {
+		roleID, err := roleID(ctx, tx, role)
+		if err != nil {
+			return -1, fmt.Errorf("%s: %w", op, err)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO user_roles(user_id, role_id) VALUES(?, ?)
+		`, id, roleID)
+		if err != nil {
+			return -1, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
 	return id, nil
 }
 
+// UpdatePasswordHash replaces hash of user's password with hash of the same password.
+// Does nothing if hash was changed since oldHash was read.
+// Hashes may be stored as text, they are compared as bytes.
+func (s *Storage) UpdatePasswordHash(ctx context.Context, userID int64, oldHash []byte, newHash []byte) error {
+	const op = "storage.sqlite.UpdatePasswordHash"
+
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE users SET pass_hash = ? WHERE id == ? AND CAST(pass_hash AS BLOB) == ?
+	`, newHash, userID, oldHash); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
 func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 	const op = "storage.sqlite.User"
 
-	var user models.User
-
-	err := s.db.QueryRowContext(ctx, `
-		SELECT id, email, pass_hash
+	user, err := scanUser(s.db.QueryRowContext(ctx, `
+		SELECT `+userColumns+`
 		FROM users
 		WHERE email == ?
-	`, email).Scan(&user.ID, &user.Email, &user.HashedPassword)
+	`, email))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -78,47 +130,50 @@ func (s *Storage) User(ctx context.Context, email string) (models.User, error) {
 	return user, nil
 }
 
-func (s *Storage) IsAdmin(ctx context.Context, id int64) (bool, error) {
-	const op = "storage.sqlite.IsAdmin"
-
-	var isAdmin bool
+func (s *Storage) UserByID(ctx context.Context, id int64) (models.User, error) {
+	const op = "storage.sqlite.UserByID"
 
-	err := s.db.QueryRowContext(ctx, `
-		SELECT is_admin
+	user, err := scanUser(s.db.QueryRowContext(ctx, `
+		SELECT `+userColumns+`
 		FROM users
 		WHERE id == ?
-	`, id).Scan(&isAdmin)
+	`, id))
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return isAdmin, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+			return user, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
 		}
 
-		return isAdmin, fmt.Errorf("%s: %w", op, err)
+		return user, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return isAdmin, nil
+	return user, nil
 }
 
-func (s *Storage) App(ctx context.Context, id int64) (models.App, error) {
-
-	const op = "storage.sqlite.App"
+// IsAdmin reports if user has admin role
+func (s *Storage) IsAdmin(ctx context.Context, id int64) (bool, error) {
+	const op = "storage.sqlite.IsAdmin"
 
-	var app models.App
+	var isAdmin bool
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, name, secret
-		FROM apps
-		WHERE id == ?
-	`, id).Scan(&app.ID, &app.Name, &app.SecretKey)
+		SELECT EXISTS(
+			SELECT 1
+			FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+			WHERE ur.user_id = u.id AND r.name == ?
+		)
+		FROM users u
+		WHERE u.id == ?
+	`, models.RoleAdmin, id).Scan(&isAdmin)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return app, fmt.Errorf("%s: %w", op, storage.ErrAppNotFound)
+			return isAdmin, fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
 		}
 
-		return app, fmt.Errorf("%s: %w", op, err)
+		return isAdmin, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return app, nil
+	return isAdmin, nil
 }
@@ -0,0 +1,284 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// SaveTOTP saves unconfirmed secret of user replacing previous unconfirmed one.
+// Throws ErrTOTPConfirmed if user has 2FA on already.
+func (s *Storage) SaveTOTP(ctx context.Context, userID int64, secret []byte) error {
+	const op = "storage.sqlite.SaveTOTP"
+
+	res, err := s.db.ExecContext(ctx, `
+		INSERT INTO user_totp(user_id, secret, created_at)
+		VALUES(?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE
+		SET secret = excluded.secret, created_at = excluded.created_at, last_used_step = 0
+		WHERE user_totp.confirmed_at IS NULL
+	`, userID, secret, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTOTPConfirmed)
+	}
+
+	return nil
+}
+
+func (s *Storage) TOTP(ctx context.Context, userID int64) (models.TOTP, error) {
+	const op = "storage.sqlite.TOTP"
+
+	totp := models.TOTP{UserID: userID}
+	var confirmedAt sql.NullInt64
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT secret, confirmed_at, last_used_step
+		FROM user_totp
+		WHERE user_id == ?
+	`, userID).Scan(&totp.Secret, &confirmedAt, &totp.LastUsedStep)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return totp, fmt.Errorf("%s: %w", op, storage.ErrTOTPNotFound)
+		}
+
+		return totp, fmt.Errorf("%s: %w", op, err)
+	}
+
+	totp.Confirmed = confirmedAt.Valid
+
+	return totp, nil
+}
+
+// ConfirmTOTP turns 2FA of user on, remembering step of code user confirmed it with,
+// and replaces recovery codes of user
+func (s *Storage) ConfirmTOTP(ctx context.Context, userID int64, step int64, recoveryHashes [][]byte) error {
+	const op = "storage.sqlite.ConfirmTOTP"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE user_totp SET confirmed_at = ?, last_used_step = ?
+		WHERE user_id == ? AND confirmed_at IS NULL
+	`, time.Now().Unix(), step, userID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTOTPConfirmed)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM recovery_codes WHERE user_id == ?
+	`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, hash := range recoveryHashes {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO recovery_codes(user_id, code_hash) VALUES(?, ?)
+		`, userID, hash); err != nil {
+			return fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep remembers that code of step was accepted.
+// Throws ErrTOTPStepUsed if code of this or later step was accepted before.
+func (s *Storage) UseTOTPStep(ctx context.Context, userID int64, step int64) error {
+	const op = "storage.sqlite.UseTOTPStep"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE user_totp SET last_used_step = ?
+		WHERE user_id == ? AND last_used_step < ?
+	`, step, userID, step)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTOTPStepUsed)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks unused recovery code of user with hash as used
+func (s *Storage) UseRecoveryCode(ctx context.Context, userID int64, codeHash []byte) error {
+	const op = "storage.sqlite.UseRecoveryCode"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE recovery_codes SET used_at = ?
+		WHERE user_id == ? AND code_hash == ? AND used_at IS NULL
+	`, time.Now().Unix(), userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrRecoveryCodeNotFound)
+	}
+
+	return nil
+}
+
+// DeleteTOTP turns 2FA of user off, removing their secret and recovery codes
+func (s *Storage) DeleteTOTP(ctx context.Context, userID int64) error {
+	const op = "storage.sqlite.DeleteTOTP"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM user_totp WHERE user_id == ?
+	`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM recovery_codes WHERE user_id == ?
+	`, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) SaveLoginChallenge(ctx context.Context, challenge models.LoginChallenge) error {
+	const op = "storage.sqlite.SaveLoginChallenge"
+
+	if _, err := s.db.ExecContext(ctx, `
+		INSERT INTO login_challenges(token_hash, user_id, app_id, expires_at)
+		VALUES(?, ?, ?, ?)
+	`, challenge.TokenHash, challenge.UserID, challenge.AppID, challenge.ExpiresAt.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// LoginChallenge returns challenge that can still be completed
+func (s *Storage) LoginChallenge(ctx context.Context, tokenHash []byte) (models.LoginChallenge, error) {
+	const op = "storage.sqlite.LoginChallenge"
+
+	var (
+		challenge models.LoginChallenge
+		expiresTs int64
+		usedAt    sql.NullInt64
+	)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, token_hash, user_id, app_id, expires_at, attempts, used_at
+		FROM login_challenges
+		WHERE token_hash == ?
+	`, tokenHash).Scan(
+		&challenge.ID, &challenge.TokenHash, &challenge.UserID, &challenge.AppID,
+		&expiresTs, &challenge.Attempts, &usedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return challenge, fmt.Errorf("%s: %w", op, storage.ErrChallengeNotFound)
+		}
+
+		return challenge, fmt.Errorf("%s: %w", op, err)
+	}
+
+	challenge.ExpiresAt = time.Unix(expiresTs, 0)
+
+	switch {
+	case usedAt.Valid:
+		return challenge, fmt.Errorf("%s: %w", op, storage.ErrChallengeUsed)
+	case expiresTs <= time.Now().Unix():
+		return challenge, fmt.Errorf("%s: %w", op, storage.ErrChallengeExpired)
+	}
+
+	return challenge, nil
+}
+
+// FailLoginChallenge counts wrong code entered,
+// challenge stops working once maxAttempts wrong codes are entered
+func (s *Storage) FailLoginChallenge(ctx context.Context, id int64, maxAttempts int) error {
+	const op = "storage.sqlite.FailLoginChallenge"
+
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE login_challenges
+		SET attempts = attempts + 1,
+		    used_at = CASE WHEN attempts + 1 >= ? THEN ? ELSE used_at END
+		WHERE id == ?
+	`, maxAttempts, time.Now().Unix(), id); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// CompleteLoginChallenge marks challenge used.
+// Throws ErrChallengeUsed if it was completed concurrently.
+func (s *Storage) CompleteLoginChallenge(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.CompleteLoginChallenge"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE login_challenges SET used_at = ?
+		WHERE id == ? AND used_at IS NULL
+	`, time.Now().Unix(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrChallengeUsed)
+	}
+
+	return nil
+}
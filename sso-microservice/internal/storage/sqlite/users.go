@@ -0,0 +1,217 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+const userColumns = `id, email, pass_hash, email_verified, created_at, updated_at, disabled_at`
+
+func scanUser(row scanner, extra ...any) (models.User, error) {
+	var (
+		user       models.User
+		createdAt  int64
+		updatedAt  int64
+		disabledAt sql.NullInt64
+	)
+
+	dest := append([]any{
+		&user.ID, &user.Email, &user.HashedPassword, &user.EmailVerified, &createdAt, &updatedAt, &disabledAt,
+	}, extra...)
+
+	if err := row.Scan(dest...); err != nil {
+		return user, err
+	}
+
+	if createdAt != 0 {
+		user.CreatedAt = time.Unix(createdAt, 0)
+	}
+	if updatedAt != 0 {
+		user.UpdatedAt = time.Unix(updatedAt, 0)
+	}
+	if disabledAt.Valid {
+		user.DisabledAt = time.Unix(disabledAt.Int64, 0)
+	}
+
+	return user, nil
+}
+
+// Users returns users matching filter with id less than beforeID, newest first.
+// Zero beforeID means from the newest user.
+func (s *Storage) Users(ctx context.Context, filter models.UserFilter, beforeID int64, limit int) ([]models.UserProfile, error) {
+	const op = "storage.sqlite.Users"
+
+	var (
+		conds []string
+		args  []any
+	)
+
+	if filter.EmailPrefix != "" {
+		conds = append(conds, `u.email LIKE ? ESCAPE '\'`)
+		args = append(args, escapeLike(filter.EmailPrefix)+"%")
+	}
+	if filter.Role != "" {
+		conds = append(conds, `EXISTS(
+			SELECT 1
+			FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+			WHERE ur.user_id = u.id AND r.name == ?
+		)`)
+		args = append(args, filter.Role)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		conds = append(conds, "u.created_at >= ?")
+		args = append(args, filter.CreatedFrom.Unix())
+	}
+	if !filter.CreatedTo.IsZero() {
+		conds = append(conds, "u.created_at < ?")
+		args = append(args, filter.CreatedTo.Unix())
+	}
+	if beforeID > 0 {
+		conds = append(conds, "u.id < ?")
+		args = append(args, beforeID)
+	}
+
+	query := `
+		SELECT u.id, u.email, u.pass_hash, u.email_verified, u.created_at, u.updated_at, u.disabled_at, (
+			SELECT group_concat(r.name)
+			FROM user_roles ur
+			JOIN roles r ON r.id = ur.role_id
+			WHERE ur.user_id = u.id
+		)
+		FROM users u`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += ` ORDER BY u.id DESC LIMIT ?`
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var users []models.UserProfile
+
+	for rows.Next() {
+		var roles sql.NullString
+
+		user, err := scanUser(rows, &roles)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		profile := models.UserProfile{User: user}
+		if roles.Valid {
+			profile.Roles = strings.Split(roles.String, ",")
+			slices.Sort(profile.Roles)
+		}
+
+		users = append(users, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return users, nil
+}
+
+// DisableUser marks user disabled and revokes every token issued to them.
+// Disabling user again keeps the first time.
+func (s *Storage) DisableUser(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DisableUser"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE users SET disabled_at = COALESCE(disabled_at, ?) WHERE id == ?
+	`, now.Unix(), id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	if err := revokeUserSessions(ctx, tx, id, now); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// EnableUser lets disabled user log in again, enabling enabled user is no-op
+func (s *Storage) EnableUser(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.EnableUser"
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE users SET disabled_at = NULL WHERE id == ?
+	`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// DeleteUser deletes user together with their roles, tokens and 2FA
+func (s *Storage) DeleteUser(ctx context.Context, id int64) error {
+	const op = "storage.sqlite.DeleteUser"
+
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM users WHERE id == ?
+	`, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if affected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// escapeLike escapes wildcards of LIKE pattern, "\" is the escape character
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
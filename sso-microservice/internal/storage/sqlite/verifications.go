@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/domain/models"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/storage"
+)
+
+// SaveEmailVerification saves verification token of user.
+// Unused tokens issued to user before stop working.
+func (s *Storage) SaveEmailVerification(ctx context.Context, verification models.EmailVerification) error {
+	const op = "storage.sqlite.SaveEmailVerification"
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM email_verifications WHERE user_id == ? AND used_at IS NULL
+	`, verification.UserID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO email_verifications(token_hash, user_id, created_at, expires_at)
+		VALUES(?, ?, ?, ?)
+	`, verification.TokenHash, verification.UserID, time.Now().Unix(), verification.ExpiresAt.Unix()); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// VerifyEmail uses verification token with tokenHash to mark email of its user verified.
+// Returns id of user.
+func (s *Storage) VerifyEmail(ctx context.Context, tokenHash []byte) (int64, error) {
+	const op = "storage.sqlite.VerifyEmail"
+
+	var (
+		id        int64
+		userID    int64
+		expiresTs int64
+		usedAt    sql.NullInt64
+	)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id, expires_at, used_at
+		FROM email_verifications
+		WHERE token_hash == ?
+	`, tokenHash).Scan(&id, &userID, &expiresTs, &usedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return -1, fmt.Errorf("%s: %w", op, storage.ErrVerificationTokenNotFound)
+		}
+
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	now := time.Now()
+
+	switch {
+	case usedAt.Valid:
+		return -1, fmt.Errorf("%s: %w", op, storage.ErrVerificationTokenUsed)
+	case expiresTs <= now.Unix():
+		return -1, fmt.Errorf("%s: %w", op, storage.ErrVerificationTokenExpired)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE email_verifications SET used_at = ? WHERE id == ?
+	`, now.Unix(), id); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE users SET email_verified = TRUE WHERE id == ?
+	`, userID); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return -1, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return userID, nil
+}
@@ -3,7 +3,28 @@ package storage
 import "errors"
 
 var (
-	ErrUserNotFound = errors.New("user not found")
-	ErrAppNotFound  = errors.New("app not found")
-	ErrUserExists   = errors.New("user with such email already exists")
+	ErrUserNotFound              = errors.New("user not found")
+	ErrAppNotFound               = errors.New("app not found")
+	ErrAppExists                 = errors.New("app with such name already exists")
+	ErrUserExists                = errors.New("user with such email already exists")
+	ErrRoleNotFound              = errors.New("role not found")
+	ErrRefreshTokenNotFound      = errors.New("refresh token not found")
+	ErrRefreshTokenExpired       = errors.New("refresh token is expired")
+	ErrRefreshTokenRevoked       = errors.New("refresh token is revoked")
+	ErrRefreshTokenReused        = errors.New("refresh token was already used")
+	ErrUserDisabled              = errors.New("user is disabled")
+	ErrAppDisabled               = errors.New("app is disabled")
+	ErrResetTokenNotFound        = errors.New("password reset token not found")
+	ErrResetTokenExpired         = errors.New("password reset token is expired")
+	ErrResetTokenUsed            = errors.New("password reset token was already used")
+	ErrVerificationTokenNotFound = errors.New("email verification token not found")
+	ErrVerificationTokenExpired  = errors.New("email verification token is expired")
+	ErrVerificationTokenUsed     = errors.New("email verification token was already used")
+	ErrTOTPNotFound              = errors.New("totp is not enrolled")
+	ErrTOTPConfirmed             = errors.New("totp is already confirmed")
+	ErrTOTPStepUsed              = errors.New("totp code was already used")
+	ErrRecoveryCodeNotFound      = errors.New("recovery code not found")
+	ErrChallengeNotFound         = errors.New("login challenge not found")
+	ErrChallengeExpired          = errors.New("login challenge is expired")
+	ErrChallengeUsed             = errors.New("login challenge was already used")
 )
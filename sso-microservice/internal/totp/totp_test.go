@@ -0,0 +1,91 @@
+package totp_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/totp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secret of RFC 4226 and RFC 6238 test vectors
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode_RFC4226(t *testing.T) {
+	expected := []string{
+		"755224", "287082", "359152", "969429", "338314",
+		"254676", "287922", "162583", "399871", "520489",
+	}
+
+	for counter, code := range expected {
+		assert.Equal(t, code, totp.Code(rfcSecret, int64(counter)), "counter %d", counter)
+	}
+}
+
+func TestCode_RFC6238(t *testing.T) {
+	// RFC lists 8 digit codes, 6 digit codes are their last digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.code, totp.Code(rfcSecret, totp.Step(time.Unix(tt.unix, 0))), "time %d", tt.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := totp.Step(now)
+
+	tests := []struct {
+		name  string
+		code  string
+		ok    bool
+		match int64
+	}{
+		{name: "current step", code: totp.Code(rfcSecret, step), ok: true, match: step},
+		{name: "previous step", code: totp.Code(rfcSecret, step-1), ok: true, match: step - 1},
+		{name: "next step", code: totp.Code(rfcSecret, step+1), ok: true, match: step + 1},
+		{name: "too old", code: totp.Code(rfcSecret, step-2)},
+		{name: "too new", code: totp.Code(rfcSecret, step+2)},
+		{name: "wrong length", code: "12345"},
+		{name: "empty", code: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, ok := totp.Validate(rfcSecret, tt.code, now, 1)
+			require.Equal(t, tt.ok, ok)
+			if ok {
+				assert.Equal(t, tt.match, matched)
+			}
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	secret, err := totp.NewSecret()
+	require.NoError(t, err)
+	require.Len(t, secret, totp.SecretSize)
+
+	u, err := url.Parse(totp.URI("e-commerce", "user@example.com", secret))
+	require.NoError(t, err)
+
+	assert.Equal(t, "otpauth", u.Scheme)
+	assert.Equal(t, "totp", u.Host)
+	assert.Equal(t, "/e-commerce:user@example.com", u.Path)
+	assert.Equal(t, totp.EncodeSecret(secret), u.Query().Get("secret"))
+	assert.Equal(t, "e-commerce", u.Query().Get("issuer"))
+	assert.Equal(t, "6", u.Query().Get("digits"))
+	assert.Equal(t, "30", u.Query().Get("period"))
+}
@@ -23,7 +23,23 @@ func main() {
 
 	logger := setupLogger(cfg.Env)
 
-	application := app.New(logger, cfg.GRPC.Port, cfg.StoragePath, cfg.TokenTTL)
+	application := app.New(
+		logger,
+		cfg.GRPC.Port,
+		cfg.StoragePath,
+		cfg.TokenTTL,
+		cfg.RefreshTokenTTL,
+		cfg.EmailVerificationTTL,
+		cfg.PasswordResetTTL,
+		cfg.Signing.Issuer,
+		cfg.Signing.Algorithm,
+		cfg.Signing.RotationPeriod,
+		cfg.Notifications,
+		cfg.TOTP,
+		cfg.Lockout,
+		cfg.Password,
+		cfg.PasswordHash,
+	)
 
 	go func() {
 		application.GRPCServer.MustRun()
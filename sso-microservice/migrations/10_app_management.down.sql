@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS app_scopes;
+DROP TABLE IF EXISTS app_redirect_uris;
+ALTER TABLE apps DROP COLUMN disabled_at;
+ALTER TABLE apps DROP COLUMN created_at;
+ALTER TABLE apps DROP COLUMN token_ttl;
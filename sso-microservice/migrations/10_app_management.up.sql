@@ -0,0 +1,22 @@
+-- Apps created or rotated through API keep hex SHA-256 of secret in "secret":
+-- secret itself is shown once. Secrets of apps inserted by hand stay as is until rotated.
+
+-- Lifetime of access tokens issued for app in seconds, 0 means global token TTL
+ALTER TABLE apps ADD COLUMN token_ttl INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE apps ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+-- Users can't log in to disabled apps
+ALTER TABLE apps ADD COLUMN disabled_at INTEGER;
+
+CREATE TABLE IF NOT EXISTS app_redirect_uris
+(
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    uri    TEXT    NOT NULL,
+    PRIMARY KEY (app_id, uri)
+);
+
+CREATE TABLE IF NOT EXISTS app_scopes
+(
+    app_id INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    scope  TEXT    NOT NULL,
+    PRIMARY KEY (app_id, scope)
+);
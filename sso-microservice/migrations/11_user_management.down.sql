@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS user_roles_delete_updated_at;
+DROP TRIGGER IF EXISTS user_roles_insert_updated_at;
+DROP TRIGGER IF EXISTS users_updated_at;
+DROP INDEX IF EXISTS idx_users_created_at;
+ALTER TABLE users DROP COLUMN disabled_at;
+ALTER TABLE users DROP COLUMN updated_at;
+ALTER TABLE users DROP COLUMN created_at;
@@ -0,0 +1,24 @@
+-- Times are unix seconds, 0 for users registered before they were recorded
+ALTER TABLE users ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN updated_at INTEGER NOT NULL DEFAULT 0;
+-- Disabled users can't log in
+ALTER TABLE users ADD COLUMN disabled_at INTEGER;
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users (created_at);
+
+-- Any change of user or their roles bumps updated_at,
+-- updates setting it themselves are left as is
+CREATE TRIGGER IF NOT EXISTS users_updated_at AFTER UPDATE ON users
+    WHEN new.updated_at == old.updated_at
+BEGIN
+    UPDATE users SET updated_at = CAST(strftime('%s', 'now') AS INTEGER) WHERE id == new.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS user_roles_insert_updated_at AFTER INSERT ON user_roles
+BEGIN
+    UPDATE users SET updated_at = CAST(strftime('%s', 'now') AS INTEGER) WHERE id == new.user_id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS user_roles_delete_updated_at AFTER DELETE ON user_roles
+BEGIN
+    UPDATE users SET updated_at = CAST(strftime('%s', 'now') AS INTEGER) WHERE id == old.user_id;
+END;
@@ -0,0 +1 @@
+UPDATE session_revocations SET revoked_before = (revoked_before + 999) / 1000;
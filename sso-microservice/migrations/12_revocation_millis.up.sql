@@ -0,0 +1,4 @@
+-- revoked_before is kept in unix milliseconds and tokens issued strictly before it are revoked,
+-- so login right after revocation is not revoked with it.
+-- Second precision revoked whole second, it stays revoked.
+UPDATE session_revocations SET revoked_before = (revoked_before + 1) * 1000;
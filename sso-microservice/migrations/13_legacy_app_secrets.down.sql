@@ -0,0 +1,3 @@
+UPDATE apps SET secret = legacy_secret WHERE legacy_secret IS NOT NULL;
+
+ALTER TABLE apps DROP COLUMN legacy_secret;
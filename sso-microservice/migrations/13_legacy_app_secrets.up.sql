@@ -0,0 +1,9 @@
+-- Secrets of apps inserted by hand were kept as plain text next to hex SHA-256 of generated ones.
+-- SQLite can't hash them, so they are moved into legacy_secret and sso hashes them into "secret"
+-- on start: such apps keep their secret. Plain text stays in legacy_secret for down migration
+-- until app secret is rotated.
+ALTER TABLE apps ADD COLUMN legacy_secret TEXT;
+
+UPDATE apps
+SET legacy_secret = secret, secret = ''
+WHERE length(secret) != 64 OR secret GLOB '*[^0-9a-f]*';
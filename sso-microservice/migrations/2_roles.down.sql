@@ -0,0 +1,11 @@
+UPDATE users
+SET is_admin = EXISTS(SELECT 1
+                      FROM user_roles ur
+                               JOIN roles r ON r.id = ur.role_id
+                      WHERE ur.user_id = users.id
+                        AND r.name = 'admin');
+
+DROP TABLE user_roles;
+DROP TABLE role_permissions;
+DROP TABLE permissions;
+DROP TABLE roles;
@@ -0,0 +1,61 @@
+CREATE TABLE IF NOT EXISTS roles
+(
+    id   INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS permissions
+(
+    id   INTEGER PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions
+(
+    role_id       INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    permission_id INTEGER NOT NULL REFERENCES permissions (id) ON DELETE CASCADE,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles
+(
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    role_id INTEGER NOT NULL REFERENCES roles (id) ON DELETE CASCADE,
+    PRIMARY KEY (user_id, role_id)
+);
+CREATE INDEX IF NOT EXISTS idx_user_roles_role ON user_roles (role_id);
+
+INSERT INTO roles(name)
+VALUES ('buyer'),
+       ('seller'),
+       ('support'),
+       ('admin');
+
+INSERT INTO permissions(name)
+VALUES ('orders:place'),
+       ('listings:create'),
+       ('orders:fulfil'),
+       ('listings:moderate'),
+       ('users:read'),
+       ('sessions:revoke'),
+       ('users:manage'),
+       ('roles:manage'),
+       ('apps:manage');
+
+INSERT INTO role_permissions(role_id, permission_id)
+SELECT r.id, p.id
+FROM roles r,
+     permissions p
+WHERE (r.name = 'buyer' AND p.name IN ('orders:place'))
+   OR (r.name = 'seller' AND p.name IN ('listings:create', 'orders:fulfil'))
+   OR (r.name = 'support' AND p.name IN ('listings:moderate', 'users:read', 'sessions:revoke'))
+   OR (r.name = 'admin');
+
+-- Every existing user becomes a buyer, former is_admin users also get admin role.
+-- users.is_admin is kept for compatibility but is no longer read.
+INSERT INTO user_roles(user_id, role_id)
+SELECT u.id, r.id
+FROM users u,
+     roles r
+WHERE r.name = 'buyer'
+   OR (r.name = 'admin' AND u.is_admin);
@@ -0,0 +1,2 @@
+DROP TABLE session_revocations;
+DROP TABLE revoked_tokens;
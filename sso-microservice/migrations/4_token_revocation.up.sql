@@ -0,0 +1,15 @@
+-- Single access tokens revoked by logout, kept until they expire on their own
+CREATE TABLE IF NOT EXISTS revoked_tokens
+(
+    jti        TEXT PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    expires_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires ON revoked_tokens (expires_at);
+
+-- Every token of user issued not after revoked_before is revoked
+CREATE TABLE IF NOT EXISTS session_revocations
+(
+    user_id        INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    revoked_before INTEGER NOT NULL
+);
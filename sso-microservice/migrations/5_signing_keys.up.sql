@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS signing_keys
+(
+    kid         TEXT PRIMARY KEY,
+    algorithm   TEXT    NOT NULL,
+    -- PKCS #8 DER
+    private_key BLOB    NOT NULL,
+    -- PKIX DER
+    public_key  BLOB    NOT NULL,
+    created_at  INTEGER NOT NULL,
+    -- key is published for verification until this moment
+    expires_at  INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_signing_keys_created ON signing_keys (created_at);
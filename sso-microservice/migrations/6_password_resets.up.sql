@@ -0,0 +1,12 @@
+-- Only hash of reset token sent to user is kept
+CREATE TABLE IF NOT EXISTS password_resets
+(
+    id         INTEGER PRIMARY KEY,
+    token_hash BLOB    NOT NULL UNIQUE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    -- set when password is reset with token
+    used_at    INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_password_resets_user ON password_resets (user_id);
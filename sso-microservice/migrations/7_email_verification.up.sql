@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+-- users registered before verification was introduced are trusted
+UPDATE users SET email_verified = TRUE;
+
+-- Apps with this flag don't let users with unverified email log in
+ALTER TABLE apps ADD COLUMN require_verified_email BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Only hash of verification token sent to user is kept
+CREATE TABLE IF NOT EXISTS email_verifications
+(
+    id         INTEGER PRIMARY KEY,
+    token_hash BLOB    NOT NULL UNIQUE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    created_at INTEGER NOT NULL,
+    expires_at INTEGER NOT NULL,
+    -- set when email is verified with token
+    used_at    INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_email_verifications_user ON email_verifications (user_id);
@@ -0,0 +1,36 @@
+-- Secret of user's authenticator app, 2FA is on once it is confirmed
+CREATE TABLE IF NOT EXISTS user_totp
+(
+    user_id        INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    secret         BLOB    NOT NULL,
+    created_at     INTEGER NOT NULL,
+    -- NULL until user proves their app generates codes
+    confirmed_at   INTEGER,
+    -- time step of last accepted code, codes can't be replayed
+    last_used_step INTEGER NOT NULL DEFAULT 0
+);
+
+-- Only hashes of recovery codes shown to user are kept
+CREATE TABLE IF NOT EXISTS recovery_codes
+(
+    id        INTEGER PRIMARY KEY,
+    user_id   INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    code_hash BLOB    NOT NULL UNIQUE,
+    used_at   INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user ON recovery_codes (user_id);
+
+-- Issued by login of user with 2FA, completed with code by LoginVerify
+CREATE TABLE IF NOT EXISTS login_challenges
+(
+    id         INTEGER PRIMARY KEY,
+    token_hash BLOB    NOT NULL UNIQUE,
+    user_id    INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    app_id     INTEGER NOT NULL REFERENCES apps (id) ON DELETE CASCADE,
+    expires_at INTEGER NOT NULL,
+    -- wrong codes entered
+    attempts   INTEGER NOT NULL DEFAULT 0,
+    -- set when challenge is completed or has no attempts left
+    used_at    INTEGER
+);
+CREATE INDEX IF NOT EXISTS idx_login_challenges_user ON login_challenges (user_id);
@@ -0,0 +1,8 @@
+-- Consecutive failed logins per key: "email:<email>" or "peer:<address>"
+CREATE TABLE IF NOT EXISTS login_failures
+(
+    key             TEXT PRIMARY KEY,
+    failures        INTEGER NOT NULL,
+    last_failure_at INTEGER NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_login_failures_last ON login_failures (last_failure_at);
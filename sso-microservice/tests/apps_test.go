@@ -0,0 +1,290 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func createApp(st suite.Suite, adminToken string, req *ssov1.CreateAppRequest) *ssov1.CreateAppResponse {
+	st.Helper()
+
+	req.Token = adminToken
+	if req.Name == "" {
+		req.Name = gofakeit.AppName() + " " + gofakeit.UUID()
+	}
+
+	resp, err := st.Auth.CreateApp(st.Context(), req)
+	require.NoError(st, err)
+
+	return resp
+}
+
+func TestApps_CreateList(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+
+	created := createApp(st, adminToken, &ssov1.CreateAppRequest{
+		RedirectUris: []string{"https://shop.test/callback", "https://admin.shop.test/callback"},
+		Scopes:       []string{"orders", "listings", "orders"},
+	})
+	require.NotEmpty(st, created.GetSecret())
+
+	app := created.GetApp()
+	require.NotZero(st, app.GetId())
+	assert.Equal(st, []string{"https://admin.shop.test/callback", "https://shop.test/callback"}, app.GetRedirectUris())
+	assert.Equal(st, []string{"listings", "orders"}, app.GetScopes())
+	assert.Zero(st, app.GetTokenTtl())
+	assert.Zero(st, app.GetDisabledAt())
+	assert.InDelta(st, time.Now().Unix(), app.GetCreatedAt(), 5)
+
+	other := createApp(st, adminToken, &ssov1.CreateAppRequest{})
+	assert.NotEqual(st, created.GetSecret(), other.GetSecret())
+
+	list, err := st.Auth.ListApps(ctx, &ssov1.ListAppsRequest{Token: adminToken})
+	require.NoError(st, err)
+
+	var found *ssov1.App
+	for _, listed := range list.GetApps() {
+		if listed.GetId() == app.GetId() {
+			found = listed
+		}
+	}
+	require.NotNil(st, found)
+	assert.Equal(st, app.GetName(), found.GetName())
+	assert.Equal(st, app.GetRedirectUris(), found.GetRedirectUris())
+	assert.Equal(st, app.GetScopes(), found.GetScopes())
+
+	_, err = st.Auth.CreateApp(ctx, &ssov1.CreateAppRequest{Token: adminToken, Name: app.GetName()})
+	require.Error(st, err)
+	assert.Equal(st, codes.AlreadyExists, status.Code(err))
+}
+
+func TestApps_TokenTTL(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+	email, password := gofakeit.Email(), randomPassword()
+	registerLogin(st, email, password)
+
+	app := createApp(st, adminToken, &ssov1.CreateAppRequest{TokenTtl: 60}).GetApp()
+	assert.Equal(st, int64(60), app.GetTokenTtl())
+
+	loggedIn := time.Now()
+	resp, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: app.GetId()})
+	require.NoError(st, err)
+
+	claims, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: resp.GetToken()})
+	require.NoError(st, err)
+	require.True(st, claims.GetValid())
+	assert.Equal(st, app.GetId(), claims.GetAppId())
+	assert.InDelta(st, loggedIn.Add(time.Minute).Unix(), claims.GetExpiresAt(), 2)
+
+	// refreshed tokens get the same lifetime
+	refreshed, err := st.Auth.Refresh(ctx, &ssov1.RefreshRequest{RefreshToken: resp.GetRefreshToken()})
+	require.NoError(st, err)
+
+	claims, err = st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: refreshed.GetToken()})
+	require.NoError(st, err)
+	assert.InDelta(st, time.Now().Add(time.Minute).Unix(), claims.GetExpiresAt(), 2)
+
+	_, err = st.Auth.CreateApp(ctx, &ssov1.CreateAppRequest{
+		Token:    adminToken,
+		Name:     gofakeit.UUID(),
+		TokenTtl: int64((st.Cfg.TokenTTL + time.Second) / time.Second),
+	})
+	require.Error(st, err)
+	assert.Equal(st, codes.InvalidArgument, status.Code(err))
+}
+
+func TestApps_Disable(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+	email, password := gofakeit.Email(), randomPassword()
+	registerLogin(st, email, password)
+
+	app := createApp(st, adminToken, &ssov1.CreateAppRequest{}).GetApp()
+
+	resp, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: app.GetId()})
+	require.NoError(st, err)
+
+	disabled, err := st.Auth.DisableApp(ctx, &ssov1.DisableAppRequest{Token: adminToken, AppId: app.GetId()})
+	require.NoError(st, err)
+	assert.True(st, disabled.GetSucceeded())
+
+	_, err = st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: app.GetId()})
+	require.Error(st, err)
+	assert.Equal(st, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(st, err.Error(), "app is disabled")
+
+	_, err = st.Auth.Refresh(ctx, &ssov1.RefreshRequest{RefreshToken: resp.GetRefreshToken()})
+	require.Error(st, err)
+	assert.Equal(st, codes.FailedPrecondition, status.Code(err))
+
+	// rejected refresh doesn't use token up, so it isn't taken for reuse
+	_, err = st.Auth.Refresh(ctx, &ssov1.RefreshRequest{RefreshToken: resp.GetRefreshToken()})
+	require.Error(st, err)
+	assert.Equal(st, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(st, err.Error(), "app is disabled")
+
+	// other apps are not affected
+	login(st, email, password)
+
+	// disabling again is no-op
+	_, err = st.Auth.DisableApp(ctx, &ssov1.DisableAppRequest{Token: adminToken, AppId: app.GetId()})
+	require.NoError(st, err)
+
+	list, err := st.Auth.ListApps(ctx, &ssov1.ListAppsRequest{Token: adminToken})
+	require.NoError(st, err)
+	for _, listed := range list.GetApps() {
+		if listed.GetId() == app.GetId() {
+			assert.NotZero(st, listed.GetDisabledAt())
+		}
+	}
+}
+
+func TestApps_RotateSecret(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+	created := createApp(st, adminToken, &ssov1.CreateAppRequest{})
+
+	rotated, err := st.Auth.RotateAppSecret(ctx, &ssov1.RotateAppSecretRequest{
+		Token: adminToken,
+		AppId: created.GetApp().GetId(),
+	})
+	require.NoError(st, err)
+	require.NotEmpty(st, rotated.GetSecret())
+	assert.NotEqual(st, created.GetSecret(), rotated.GetSecret())
+
+	_, err = st.Auth.RotateAppSecret(ctx, &ssov1.RotateAppSecretRequest{Token: adminToken, AppId: 1e10})
+	require.Error(st, err)
+	assert.Equal(st, codes.NotFound, status.Code(err))
+}
+
+func TestApps_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+	_, userToken := registerLogin(st, gofakeit.Email(), randomPassword())
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected string
+	}{
+		{
+			name: "create not admin",
+			call: func() error {
+				_, err := st.Auth.CreateApp(ctx, &ssov1.CreateAppRequest{Token: userToken, Name: gofakeit.UUID()})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "list not admin",
+			call: func() error {
+				_, err := st.Auth.ListApps(ctx, &ssov1.ListAppsRequest{Token: userToken})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "rotate not admin",
+			call: func() error {
+				_, err := st.Auth.RotateAppSecret(ctx, &ssov1.RotateAppSecretRequest{Token: userToken, AppId: appID})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "disable not admin",
+			call: func() error {
+				_, err := st.Auth.DisableApp(ctx, &ssov1.DisableAppRequest{Token: userToken, AppId: appID})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "invalid token",
+			call: func() error {
+				_, err := st.Auth.ListApps(ctx, &ssov1.ListAppsRequest{Token: "not a token"})
+				return err
+			},
+			expected: "token is invalid",
+		},
+		{
+			name: "empty name",
+			call: func() error {
+				_, err := st.Auth.CreateApp(ctx, &ssov1.CreateAppRequest{Token: adminToken})
+				return err
+			},
+			expected: "name is required",
+		},
+		{
+			name: "negative token ttl",
+			call: func() error {
+				_, err := st.Auth.CreateApp(ctx, &ssov1.CreateAppRequest{Token: adminToken, Name: gofakeit.UUID(), TokenTtl: -1})
+				return err
+			},
+			expected: "token_ttl must not be negative",
+		},
+		{
+			name: "relative redirect uri",
+			call: func() error {
+				_, err := st.Auth.CreateApp(ctx, &ssov1.CreateAppRequest{
+					Token:        adminToken,
+					Name:         gofakeit.UUID(),
+					RedirectUris: []string{"/callback"},
+				})
+				return err
+			},
+			expected: "must be absolute",
+		},
+		{
+			name: "scope with space",
+			call: func() error {
+				_, err := st.Auth.CreateApp(ctx, &ssov1.CreateAppRequest{
+					Token:  adminToken,
+					Name:   gofakeit.UUID(),
+					Scopes: []string{"read orders"},
+				})
+				return err
+			},
+			expected: "must be non-empty and have no spaces",
+		},
+		{
+			name: "disable unknown app",
+			call: func() error {
+				_, err := st.Auth.DisableApp(ctx, &ssov1.DisableAppRequest{Token: adminToken, AppId: 1e10})
+				return err
+			},
+			expected: "app not found",
+		},
+		{
+			name: "disable empty app id",
+			call: func() error {
+				_, err := st.Auth.DisableApp(ctx, &ssov1.DisableAppRequest{Token: adminToken})
+				return err
+			},
+			expected: "app_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
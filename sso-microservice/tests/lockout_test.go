@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// loginCode tries to log in and returns status code of response
+// together with seconds from "retry-after" header, 0 if there is none
+func loginCode(st suite.Suite, email, password string) (codes.Code, int) {
+	st.Helper()
+
+	var header metadata.MD
+	_, err := st.Auth.Login(st.Context(), &ssov1.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    appID,
+	}, grpc.Header(&header))
+
+	retryAfter := 0
+	if values := header.Get("retry-after"); len(values) > 0 {
+		var convErr error
+		retryAfter, convErr = strconv.Atoi(values[0])
+		require.NoError(st, convErr)
+	}
+
+	return status.Code(err), retryAfter
+}
+
+func TestLogin_Lockout(t *testing.T) {
+	ctx, st := suite.New(t)
+	policy := st.Cfg.Lockout
+
+	adminToken := login(st, adminEmail, adminPassword)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	id, token := registerLogin(st, email, password)
+
+	for i := 0; i <= policy.FreeAttempts; i++ {
+		code, _ := loginCode(st, email, "wrong")
+		require.Equal(st, codes.InvalidArgument, code, "attempt %d", i+1)
+	}
+
+	// right password doesn't help while login is delayed
+	code, retryAfter := loginCode(st, email, password)
+	require.Equal(st, codes.Unavailable, code)
+	require.Positive(st, retryAfter)
+
+	for failures := policy.FreeAttempts + 1; failures < policy.AccountMaxFailures; failures++ {
+		time.Sleep(time.Duration(retryAfter) * time.Second)
+
+		code, _ := loginCode(st, email, "wrong")
+		require.Equal(st, codes.InvalidArgument, code, "attempt %d", failures+1)
+
+		_, retryAfter = loginCode(st, email, password)
+	}
+
+	code, retryAfter = loginCode(st, email, password)
+	require.Equal(st, codes.ResourceExhausted, code)
+	assert.InDelta(st, policy.Duration.Seconds(), retryAfter, 2)
+
+	_, err := st.Auth.UnlockAccount(ctx, &ssov1.UnlockAccountRequest{Token: token, UserId: id})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "permission denied")
+
+	resp, err := st.Auth.UnlockAccount(ctx, &ssov1.UnlockAccountRequest{Token: adminToken, UserId: id})
+	require.NoError(st, err)
+	assert.True(st, resp.GetSucceeded())
+
+	code, _ = loginCode(st, email, password)
+	assert.Equal(st, codes.OK, code)
+}
+
+func TestLogin_SuccessResetsFailures(t *testing.T) {
+	_, st := suite.New(t)
+	policy := st.Cfg.Lockout
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	registerLogin(st, email, password)
+
+	for range 2 {
+		for i := 0; i < policy.FreeAttempts; i++ {
+			code, _ := loginCode(st, email, "wrong")
+			require.Equal(st, codes.InvalidArgument, code)
+		}
+
+		code, _ := loginCode(st, email, password)
+		require.Equal(st, codes.OK, code)
+	}
+}
+
+func TestLogin_UnknownEmailThrottled(t *testing.T) {
+	_, st := suite.New(t)
+	policy := st.Cfg.Lockout
+
+	// unknown emails are throttled the same way, so they can't be told apart
+	email := gofakeit.Email()
+
+	for i := 0; i <= policy.FreeAttempts; i++ {
+		code, _ := loginCode(st, email, "wrong")
+		require.Equal(st, codes.InvalidArgument, code)
+	}
+
+	code, retryAfter := loginCode(st, email, "wrong")
+	require.Equal(st, codes.Unavailable, code)
+	assert.Positive(st, retryAfter)
+}
+
+func TestUnlockAccount_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+
+	tests := []struct {
+		name     string
+		token    string
+		userID   int64
+		expected string
+	}{
+		{
+			name:     "empty user",
+			token:    adminToken,
+			userID:   0,
+			expected: "user_id is required",
+		},
+		{
+			name:     "unknown user",
+			token:    adminToken,
+			userID:   1 << 40,
+			expected: "user not found",
+		},
+		{
+			name:     "bad token",
+			token:    "not a token",
+			userID:   1,
+			expected: "token is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.Auth.UnlockAccount(ctx, &ssov1.UnlockAccountRequest{Token: tt.token, UserId: tt.userID})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -1,13 +1,15 @@
 package tests
 
 import (
+	"context"
+	"crypto/x509"
 	"testing"
 	"time"
 
+	"github.com/Kry0z1/e-commerce/authtoken"
 	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
 	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
 	"github.com/brianvoe/gofakeit/v6"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -15,11 +17,51 @@ import (
 var (
 	emptyAppID int64 = 0
 	appID      int64 = 1
-	appSecret        = "test-secret"
 )
 
+// randomPassword returns password satisfying password policy of test config
 func randomPassword() string {
-	return gofakeit.Password(true, true, true, true, false, 10)
+	// one character of every class, random part may miss some
+	return gofakeit.Password(true, true, true, true, false, 10) + "aA1!"
+}
+
+// parseToken verifies token with keys published by sso and returns its claims
+func parseToken(st suite.Suite, token string) *authtoken.Claims {
+	st.Helper()
+
+	verifier := authtoken.NewVerifier(
+		authtoken.NewCachedKeySet(keyFetcher{st.Auth}, time.Minute),
+		authtoken.WithIssuer(st.Cfg.Signing.Issuer),
+		authtoken.WithAudience(authtoken.Audience(appID)),
+	)
+
+	claims, err := verifier.Verify(st.Context(), token)
+	require.NoError(st, err)
+
+	return claims
+}
+
+type keyFetcher struct {
+	auth ssov1.AuthClient
+}
+
+func (f keyFetcher) SigningKeys(ctx context.Context) ([]authtoken.PublicKey, error) {
+	resp, err := f.auth.GetSigningKeys(ctx, &ssov1.GetSigningKeysRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []authtoken.PublicKey
+	for _, key := range resp.GetKeys() {
+		public, err := x509.ParsePKIXPublicKey(key.GetPublicKey())
+		if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, authtoken.PublicKey{ID: key.GetKid(), Algorithm: key.GetAlgorithm(), Key: public})
+	}
+
+	return keys, nil
 }
 
 func TestRegisterLogin_HappyPath(t *testing.T) {
@@ -48,21 +90,15 @@ func TestRegisterLogin_HappyPath(t *testing.T) {
 
 	loginTime := time.Now()
 
-	tokenParsed, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-		return []byte(appSecret), nil
-	})
-	require.NoError(t, err)
-
-	claims, ok := tokenParsed.Claims.(jwt.MapClaims)
-	require.True(t, ok)
+	claims := parseToken(st, token)
 
-	assert.Equal(t, respReg.GetId(), int64(claims["uid"].(float64)))
-	assert.Equal(t, email, claims["email"].(string))
-	assert.Equal(t, appID, int64(claims["app_id"].(float64)))
+	assert.Equal(t, respReg.GetId(), claims.UserID)
+	assert.Equal(t, email, claims.Email)
+	assert.Equal(t, appID, claims.AppID)
 
 	const deltaSeconds = 1
 
-	assert.InDelta(t, loginTime.Add(st.Cfg.TokenTTL).Unix(), claims["exp"].(float64), deltaSeconds)
+	assert.InDelta(t, loginTime.Add(st.Cfg.TokenTTL).Unix(), claims.ExpiresAt.Unix(), deltaSeconds)
 }
 
 func TestRegister_DoubleRegister(t *testing.T) {
@@ -0,0 +1,9 @@
+-- password is "admin-password"
+INSERT INTO users(id, email, pass_hash)
+VALUES (1, 'admin@test.local', '$2a$10$2WSusLhWx.8ovbbYBODnDeF51DkVT11mQq6YQOa1OeGmTos5dpkV.')
+ON CONFLICT DO NOTHING;
+
+INSERT OR IGNORE INTO user_roles(user_id, role_id)
+SELECT 1, id
+FROM roles
+WHERE name IN ('buyer', 'admin');
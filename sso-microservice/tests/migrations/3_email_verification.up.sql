@@ -0,0 +1,5 @@
+UPDATE users SET email_verified = TRUE WHERE email == 'admin@test.local';
+
+INSERT INTO apps(id, name, secret, require_verified_email)
+VALUES (2, 'test-verified', 'test-verified-secret', TRUE)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,9 @@
+-- password is "Legacy-bcrypt-1", hashed before argon2id
+INSERT INTO users(email, pass_hash, email_verified)
+VALUES ('legacy-bcrypt@test.local', '$2a$10$v1yIgyPki2OQG42.XLMZHelJU98uRbu/i9Y8qFRxINLxXxtg3k3My', TRUE)
+ON CONFLICT DO NOTHING;
+
+-- password is "Outdated-argon2-1", hashed with weaker parameters than configured
+INSERT INTO users(email, pass_hash, email_verified)
+VALUES ('outdated-argon2@test.local', '$argon2id$v=19$m=8192,t=1,p=1$/ltnRBFEs1FDb90tb9UDYA$vGnwqots7SMjNlO5rj1TMEHpyzzY3k2HUxCEekE/KHc', TRUE)
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,3 @@
+-- test apps keep hex SHA-256 of their secrets as apps created through API do
+UPDATE apps SET secret = '9caf06bb4436cdbfa20af9121a626bc1093c4f54b31c0fa937957856135345b6' WHERE secret == 'test-secret';
+UPDATE apps SET secret = 'ad0b4741d2c4112392a8fa29e16e88c5cceef417cb3843e2792130ae26036d37' WHERE secret == 'test-verified-secret';
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"testing"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogin_OutdatedHashes(t *testing.T) {
+	_, st := suite.New(t)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+	}{
+		{
+			name:     "bcrypt",
+			email:    "legacy-bcrypt@test.local",
+			password: "Legacy-bcrypt-1",
+		},
+		{
+			name:     "argon2id with old parameters",
+			email:    "outdated-argon2@test.local",
+			password: "Outdated-argon2-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// first login replaces hash, the next ones check the new one
+			for range 2 {
+				login(st, tt.email, tt.password)
+
+				_, err := st.Auth.Login(st.Context(), &ssov1.LoginRequest{
+					Email:    tt.email,
+					Password: "Wrong-password-1",
+					AppId:    appID,
+				})
+				require.Error(t, err)
+				require.Contains(t, err.Error(), "invalid email or password")
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// violations returns reasons of field violations listed in details of err
+func violations(st suite.Suite, err error, field string) []string {
+	st.Helper()
+
+	s, ok := status.FromError(err)
+	require.True(st, ok)
+	require.Equal(st, codes.InvalidArgument, s.Code())
+
+	var reasons []string
+	for _, detail := range s.Details() {
+		badRequest, ok := detail.(*errdetails.BadRequest)
+		if !ok {
+			continue
+		}
+
+		for _, v := range badRequest.GetFieldViolations() {
+			assert.Equal(st, field, v.GetField())
+			assert.NotEmpty(st, v.GetDescription())
+			reasons = append(reasons, v.GetReason())
+		}
+	}
+
+	return reasons
+}
+
+func TestRegister_PasswordPolicy(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name     string
+		email    string
+		password string
+		expected []string
+	}{
+		{
+			name:     "single digit",
+			email:    gofakeit.Email(),
+			password: "1",
+			expected: []string{"TOO_SHORT", "NO_LOWER", "NO_UPPER", "NO_SYMBOL"},
+		},
+		{
+			name:     "longer than bcrypt uses",
+			email:    gofakeit.Email(),
+			password: randomPassword() + strings.Repeat("x", 72),
+			expected: []string{"TOO_LONG"},
+		},
+		{
+			name:     "contains email",
+			email:    "policy.holder@example.com",
+			password: "Policy.Holder-1",
+			expected: []string{"CONTAINS_EMAIL"},
+		},
+		{
+			name:     "common",
+			email:    gofakeit.Email(),
+			password: "P@ssw0rd",
+			expected: []string{"BREACHED"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.Auth.RegisterUser(ctx, &ssov1.RegisterUserRequest{
+				Email:    tt.email,
+				Password: tt.password,
+			})
+			require.Error(t, err)
+			assert.Equal(t, tt.expected, violations(st, err, "password"))
+		})
+	}
+}
+
+func TestResetPassword_PasswordPolicy(t *testing.T) {
+	ctx, st := suite.New(t)
+	outbox := st.Outbox()
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	registerLogin(st, email, password)
+
+	_, err := st.Auth.RequestPasswordReset(ctx, &ssov1.RequestPasswordResetRequest{Email: email})
+	require.NoError(st, err)
+
+	reset := outbox.WaitPasswordReset(t, email, 1)
+
+	_, err = st.Auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{
+		Token:       reset.GetToken(),
+		NewPassword: "qwerty",
+	})
+	require.Error(st, err)
+	assert.Equal(st, []string{"TOO_SHORT", "NO_UPPER", "NO_DIGIT", "NO_SYMBOL", "BREACHED"}, violations(st, err, "new_password"))
+
+	// rejected password doesn't use token up
+	_, err = st.Auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{
+		Token:       reset.GetToken(),
+		NewPassword: randomPassword(),
+	})
+	require.NoError(st, err)
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPasswordReset_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+	outbox := st.Outbox()
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	_, token := registerLogin(st, email, password)
+
+	_, err := st.Auth.RequestPasswordReset(ctx, &ssov1.RequestPasswordResetRequest{Email: email})
+	require.NoError(st, err)
+
+	reset := outbox.WaitPasswordReset(t, email, 1)
+	require.NotEmpty(st, reset.GetToken())
+	assert.Greater(st, reset.GetExpiresAt(), time.Now().Unix())
+
+	newPassword := randomPassword()
+	_, err = st.Auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{
+		Token:       reset.GetToken(),
+		NewPassword: newPassword,
+	})
+	require.NoError(st, err)
+
+	resp, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: token})
+	require.NoError(st, err)
+	assert.False(st, resp.GetValid())
+	assert.Equal(st, "revoked", resp.GetReason())
+
+	_, err = st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid email or password")
+
+	// logging in right after reset works
+	newToken := login(st, email, newPassword)
+	resp, err = st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: newToken})
+	require.NoError(st, err)
+	assert.True(st, resp.GetValid())
+
+	_, err = st.Auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{
+		Token:       reset.GetToken(),
+		NewPassword: randomPassword(),
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid password reset token")
+}
+
+func TestPasswordReset_NewTokenReplacesOld(t *testing.T) {
+	ctx, st := suite.New(t)
+	outbox := st.Outbox()
+
+	email := gofakeit.Email()
+	registerLogin(st, email, randomPassword())
+
+	for i := 0; i < 2; i++ {
+		_, err := st.Auth.RequestPasswordReset(ctx, &ssov1.RequestPasswordResetRequest{Email: email})
+		require.NoError(st, err)
+	}
+
+	first := outbox.WaitPasswordReset(t, email, 1)
+	second := outbox.WaitPasswordReset(t, email, 2)
+
+	_, err := st.Auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{
+		Token:       first.GetToken(),
+		NewPassword: randomPassword(),
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid password reset token")
+
+	_, err = st.Auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{
+		Token:       second.GetToken(),
+		NewPassword: randomPassword(),
+	})
+	require.NoError(st, err)
+}
+
+func TestRequestPasswordReset_UnknownEmail(t *testing.T) {
+	ctx, st := suite.New(t)
+	outbox := st.Outbox()
+
+	email := gofakeit.Email()
+
+	resp, err := st.Auth.RequestPasswordReset(ctx, &ssov1.RequestPasswordResetRequest{Email: email})
+	require.NoError(st, err)
+	assert.NotNil(st, resp)
+	assert.Empty(st, outbox.PasswordResets(email))
+}
+
+func TestPasswordReset_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.Auth.RequestPasswordReset(ctx, &ssov1.RequestPasswordResetRequest{Email: ""})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "email is required")
+
+	tests := []struct {
+		name        string
+		token       string
+		newPassword string
+		expected    string
+	}{
+		{
+			name:        "empty token",
+			token:       "",
+			newPassword: randomPassword(),
+			expected:    "token is required",
+		},
+		{
+			name:        "empty password",
+			token:       gofakeit.UUID(),
+			newPassword: "",
+			expected:    "new_password is required",
+		},
+		{
+			name:        "unknown token",
+			token:       gofakeit.UUID(),
+			newPassword: randomPassword(),
+			expected:    "invalid password reset token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.Auth.ResetPassword(ctx, &ssov1.ResetPasswordRequest{
+				Token:       tt.token,
+				NewPassword: tt.newPassword,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"testing"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	registerLogin(st, email, password)
+
+	respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    appID,
+	})
+	require.NoError(st, err)
+	require.NotEmpty(st, respLogin.GetRefreshToken())
+
+	respRefresh, err := st.Auth.Refresh(ctx, &ssov1.RefreshRequest{
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.NoError(st, err)
+	assert.NotEmpty(st, respRefresh.GetToken())
+	assert.NotEmpty(st, respRefresh.GetRefreshToken())
+	assert.NotEqual(st, respLogin.GetRefreshToken(), respRefresh.GetRefreshToken())
+
+	_, err = st.Auth.Refresh(ctx, &ssov1.RefreshRequest{
+		RefreshToken: respRefresh.GetRefreshToken(),
+	})
+	require.NoError(st, err)
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	registerLogin(st, email, password)
+
+	respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    appID,
+	})
+	require.NoError(st, err)
+
+	respRefresh, err := st.Auth.Refresh(ctx, &ssov1.RefreshRequest{
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.NoError(st, err)
+
+	_, err = st.Auth.Refresh(ctx, &ssov1.RefreshRequest{
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid refresh token")
+
+	// successor of reused token is revoked as well
+	_, err = st.Auth.Refresh(ctx, &ssov1.RefreshRequest{
+		RefreshToken: respRefresh.GetRefreshToken(),
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid refresh token")
+}
+
+func TestRefresh_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	tests := []struct {
+		name     string
+		token    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			token:    "",
+			expected: "refresh_token is required",
+		},
+		{
+			name:     "unknown",
+			token:    gofakeit.UUID(),
+			expected: "invalid refresh token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.Auth.Refresh(ctx, &ssov1.RefreshRequest{RefreshToken: tt.token})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
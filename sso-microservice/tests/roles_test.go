@@ -0,0 +1,162 @@
+package tests
+
+import (
+	"testing"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	adminEmail    = "admin@test.local"
+	adminPassword = "admin-password"
+)
+
+func registerLogin(st suite.Suite, email, password string) (int64, string) {
+	st.Helper()
+
+	respReg, err := st.Auth.RegisterUser(st.Context(), &ssov1.RegisterUserRequest{
+		Email:    email,
+		Password: password,
+	})
+	require.NoError(st, err)
+
+	return respReg.GetId(), login(st, email, password)
+}
+
+func login(st suite.Suite, email, password string) string {
+	st.Helper()
+
+	respLogin, err := st.Auth.Login(st.Context(), &ssov1.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    appID,
+	})
+	require.NoError(st, err)
+
+	return respLogin.GetToken()
+}
+
+func TestRoles_NewUserIsBuyer(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	id, token := registerLogin(st, gofakeit.Email(), randomPassword())
+
+	respRoles, err := st.Auth.ListUserRoles(ctx, &ssov1.ListUserRolesRequest{UserId: id})
+	require.NoError(st, err)
+	require.Len(st, respRoles.GetRoles(), 1)
+	assert.Equal(st, "buyer", respRoles.GetRoles()[0].GetName())
+	assert.Contains(st, respRoles.GetRoles()[0].GetPermissions(), "orders:place")
+
+	claims := parseToken(st, token)
+	assert.Equal(st, []string{"buyer"}, claims.Roles)
+}
+
+func TestRoles_AssignRevoke(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+	id, _ := registerLogin(st, gofakeit.Email(), randomPassword())
+
+	respPerm, err := st.Auth.CheckPermission(ctx, &ssov1.CheckPermissionRequest{
+		UserId:     id,
+		Permission: "listings:moderate",
+	})
+	require.NoError(st, err)
+	assert.False(st, respPerm.GetAllowed())
+
+	_, err = st.Auth.AssignRole(ctx, &ssov1.AssignRoleRequest{
+		Token:  adminToken,
+		UserId: id,
+		Role:   "admin",
+	})
+	require.NoError(st, err)
+
+	respAdm, err := st.Auth.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: id})
+	require.NoError(st, err)
+	assert.True(st, respAdm.GetIsAdmin())
+
+	respPerm, err = st.Auth.CheckPermission(ctx, &ssov1.CheckPermissionRequest{
+		UserId:     id,
+		Permission: "listings:moderate",
+	})
+	require.NoError(st, err)
+	assert.True(st, respPerm.GetAllowed())
+
+	_, err = st.Auth.RevokeRole(ctx, &ssov1.RevokeRoleRequest{
+		Token:  adminToken,
+		UserId: id,
+		Role:   "admin",
+	})
+	require.NoError(st, err)
+
+	respAdm, err = st.Auth.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: id})
+	require.NoError(st, err)
+	assert.False(st, respAdm.GetIsAdmin())
+}
+
+func TestRoles_AssignFails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+	id, userToken := registerLogin(st, gofakeit.Email(), randomPassword())
+
+	tests := []struct {
+		name     string
+		token    string
+		userID   int64
+		role     string
+		expected string
+	}{
+		{
+			name:     "not admin",
+			token:    userToken,
+			userID:   id,
+			role:     "admin",
+			expected: "permission denied",
+		},
+		{
+			name:     "invalid token",
+			token:    "not a token",
+			userID:   id,
+			role:     "admin",
+			expected: "token is invalid",
+		},
+		{
+			name:     "unknown role",
+			token:    adminToken,
+			userID:   id,
+			role:     "wizard",
+			expected: "role not found",
+		},
+		{
+			name:     "unknown user",
+			token:    adminToken,
+			userID:   1e10,
+			role:     "seller",
+			expected: "user not found",
+		},
+		{
+			name:     "empty role",
+			token:    adminToken,
+			userID:   id,
+			role:     "",
+			expected: "role is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.Auth.AssignRole(ctx, &ssov1.AssignRoleRequest{
+				Token:  tt.token,
+				UserId: tt.userID,
+				Role:   tt.role,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
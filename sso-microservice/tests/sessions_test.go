@@ -0,0 +1,115 @@
+package tests
+
+import (
+	"testing"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateToken_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	id, token := registerLogin(st, email, randomPassword())
+
+	resp, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: token})
+	require.NoError(st, err)
+	assert.True(st, resp.GetValid())
+	assert.Equal(st, id, resp.GetUserId())
+	assert.Equal(st, appID, resp.GetAppId())
+	assert.Equal(st, email, resp.GetEmail())
+	assert.Equal(st, []string{"buyer"}, resp.GetRoles())
+}
+
+func TestValidateToken_Invalid(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	resp, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: "not a token"})
+	require.NoError(st, err)
+	assert.False(st, resp.GetValid())
+	assert.Equal(st, "invalid", resp.GetReason())
+}
+
+func TestLogout_RevokesTokens(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	registerLogin(st, email, password)
+
+	respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{
+		Email:    email,
+		Password: password,
+		AppId:    appID,
+	})
+	require.NoError(st, err)
+
+	_, err = st.Auth.Logout(ctx, &ssov1.LogoutRequest{
+		Token:        respLogin.GetToken(),
+		RefreshToken: respLogin.GetRefreshToken(),
+	})
+	require.NoError(st, err)
+
+	resp, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: respLogin.GetToken()})
+	require.NoError(st, err)
+	assert.False(st, resp.GetValid())
+	assert.Equal(st, "revoked", resp.GetReason())
+
+	_, err = st.Auth.Refresh(ctx, &ssov1.RefreshRequest{RefreshToken: respLogin.GetRefreshToken()})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid refresh token")
+
+	_, err = st.Auth.Logout(ctx, &ssov1.LogoutRequest{Token: respLogin.GetToken()})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "token is revoked")
+}
+
+func TestRevokeAllSessions(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	id, token := registerLogin(st, email, password)
+
+	_, err := st.Auth.RevokeAllSessions(ctx, &ssov1.RevokeAllSessionsRequest{
+		Token:  token,
+		UserId: id,
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "permission denied")
+
+	_, err = st.Auth.RevokeAllSessions(ctx, &ssov1.RevokeAllSessionsRequest{
+		Token:  adminToken,
+		UserId: id,
+	})
+	require.NoError(st, err)
+
+	resp, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: token})
+	require.NoError(st, err)
+	assert.False(st, resp.GetValid())
+	assert.Equal(st, "revoked", resp.GetReason())
+
+	// login right after revocation is not revoked with it
+	resp, err = st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: login(st, email, password)})
+	require.NoError(st, err)
+	assert.True(st, resp.GetValid())
+}
+
+func TestGetSigningKeys(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	resp, err := st.Auth.GetSigningKeys(ctx, &ssov1.GetSigningKeysRequest{})
+	require.NoError(st, err)
+	require.NotEmpty(st, resp.GetKeys())
+
+	current := resp.GetKeys()[0]
+	assert.NotEmpty(st, current.GetKid())
+	assert.Equal(st, st.Cfg.Signing.Algorithm, current.GetAlgorithm())
+	assert.NotEmpty(st, current.GetPublicKey())
+}
@@ -0,0 +1,120 @@
+package suite
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	notificationsv1 "github.com/Kry0z1/e-commerce/protos/gen/go/notifications"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/config"
+	"google.golang.org/grpc"
+)
+
+// Outbox stands in for notification service and keeps every email sso queues
+type Outbox struct {
+	notificationsv1.UnimplementedNotificationsServer
+
+	mu       sync.Mutex
+	requests []*notificationsv1.SendNotificationRequest
+}
+
+var (
+	outboxOnce sync.Once
+	outbox     *Outbox
+	outboxErr  error
+)
+
+// startOutbox starts fake notification service on address from config once per test run,
+// before any test makes sso send emails
+func startOutbox(cfg *config.Config) {
+	outboxOnce.Do(func() {
+		lis, err := net.Listen("tcp", cfg.Notifications.Address)
+		if err != nil {
+			outboxErr = err
+			return
+		}
+
+		outbox = &Outbox{}
+		srv := grpc.NewServer()
+		notificationsv1.RegisterNotificationsServer(srv, outbox)
+
+		go func() { _ = srv.Serve(lis) }()
+	})
+}
+
+// Outbox returns emails queued by sso.
+// Test is skipped if fake notification service couldn't start, e.g. real one is running.
+func (s Suite) Outbox() *Outbox {
+	s.Helper()
+
+	if outboxErr != nil {
+		s.Skipf("can't start fake notification service: %v", outboxErr)
+	}
+
+	return outbox
+}
+
+func (o *Outbox) SendNotification(
+	_ context.Context,
+	req *notificationsv1.SendNotificationRequest,
+) (*notificationsv1.SendNotificationResponse, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.requests = append(o.requests, req)
+	return &notificationsv1.SendNotificationResponse{Id: int64(len(o.requests))}, nil
+}
+
+// PasswordResets returns reset emails sent to recipient so far, oldest first
+func (o *Outbox) PasswordResets(recipient string) []*notificationsv1.PasswordReset {
+	return collect(o, recipient, (*notificationsv1.SendNotificationRequest).GetPasswordReset)
+}
+
+// EmailVerifications returns verification emails sent to recipient so far, oldest first
+func (o *Outbox) EmailVerifications(recipient string) []*notificationsv1.EmailVerification {
+	return collect(o, recipient, (*notificationsv1.SendNotificationRequest).GetEmailVerification)
+}
+
+// WaitPasswordReset waits for reset email number n (counting from 1) sent to recipient
+func (o *Outbox) WaitPasswordReset(t *testing.T, recipient string, n int) *notificationsv1.PasswordReset {
+	t.Helper()
+	return wait(t, recipient, n, o.PasswordResets)
+}
+
+// WaitEmailVerification waits for verification email number n (counting from 1) sent to recipient
+func (o *Outbox) WaitEmailVerification(t *testing.T, recipient string, n int) *notificationsv1.EmailVerification {
+	t.Helper()
+	return wait(t, recipient, n, o.EmailVerifications)
+}
+
+// collect returns payloads picked out of emails sent to recipient
+func collect[T any](o *Outbox, recipient string, pick func(*notificationsv1.SendNotificationRequest) *T) []*T {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var res []*T
+	for _, req := range o.requests {
+		if payload := pick(req); req.GetRecipient() == recipient && payload != nil {
+			res = append(res, payload)
+		}
+	}
+
+	return res
+}
+
+func wait[T any](t *testing.T, recipient string, n int, sent func(string) []*T) *T {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if payloads := sent(recipient); len(payloads) >= n {
+			return payloads[n-1]
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	t.Fatalf("email #%d to %s was not sent", n, recipient)
+	return nil
+}
@@ -24,6 +24,7 @@ func New(t *testing.T) (context.Context, Suite) {
 	t.Parallel()
 
 	cfg := config.MustLoadPath(configPath())
+	startOutbox(cfg)
 
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.GRPC.Timeout)
 	t.Cleanup(func() {
@@ -0,0 +1,228 @@
+package tests
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/internal/totp"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// enableTOTP turns 2FA on for user with token and returns their secret,
+// step of code it was confirmed with and recovery codes
+func enableTOTP(st suite.Suite, token string) ([]byte, int64, []string) {
+	st.Helper()
+
+	ctx := st.Context()
+
+	resp, err := st.Auth.EnrollTOTP(ctx, &ssov1.EnrollTOTPRequest{Token: token})
+	require.NoError(st, err)
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(resp.GetSecret())
+	require.NoError(st, err)
+
+	step := totp.Step(time.Now())
+
+	respConfirm, err := st.Auth.ConfirmTOTP(ctx, &ssov1.ConfirmTOTPRequest{
+		Token: token,
+		Code:  totp.Code(secret, step),
+	})
+	require.NoError(st, err)
+
+	return secret, step, respConfirm.GetRecoveryCodes()
+}
+
+func TestTOTP_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	_, token := registerLogin(st, email, password)
+
+	respEnroll, err := st.Auth.EnrollTOTP(ctx, &ssov1.EnrollTOTPRequest{Token: token})
+	require.NoError(st, err)
+
+	uri, err := url.Parse(respEnroll.GetUri())
+	require.NoError(st, err)
+	assert.Equal(st, "otpauth", uri.Scheme)
+	assert.Equal(st, respEnroll.GetSecret(), uri.Query().Get("secret"))
+	assert.True(st, strings.HasSuffix(uri.Path, ":"+email))
+
+	_, err = st.Auth.ConfirmTOTP(ctx, &ssov1.ConfirmTOTPRequest{Token: token, Code: "000000"})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid code")
+
+	// enrolling again before confirmation replaces secret
+	secret, step, recoveryCodes := enableTOTP(st, token)
+	assert.Len(st, recoveryCodes, 10)
+
+	respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(st, err)
+	assert.True(st, respLogin.GetTotpRequired())
+	assert.Empty(st, respLogin.GetToken())
+	assert.Empty(st, respLogin.GetRefreshToken())
+	require.NotEmpty(st, respLogin.GetChallengeToken())
+
+	// code that confirmed 2FA can't be used again
+	_, err = st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{
+		ChallengeToken: respLogin.GetChallengeToken(),
+		Code:           totp.Code(secret, step),
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid code")
+
+	respVerify, err := st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{
+		ChallengeToken: respLogin.GetChallengeToken(),
+		Code:           totp.Code(secret, step+1),
+	})
+	require.NoError(st, err)
+	require.NotEmpty(st, respVerify.GetRefreshToken())
+
+	respValidate, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: respVerify.GetToken()})
+	require.NoError(st, err)
+	assert.True(st, respValidate.GetValid())
+	assert.Equal(st, email, respValidate.GetEmail())
+
+	_, err = st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{
+		ChallengeToken: respLogin.GetChallengeToken(),
+		Code:           recoveryCodes[0],
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid login challenge")
+}
+
+func TestTOTP_RecoveryCodes(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	_, token := registerLogin(st, email, password)
+
+	_, _, recoveryCodes := enableTOTP(st, token)
+
+	challenge := func() string {
+		respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+		require.NoError(st, err)
+		require.True(st, respLogin.GetTotpRequired())
+		return respLogin.GetChallengeToken()
+	}
+
+	_, err := st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{ChallengeToken: challenge(), Code: recoveryCodes[0]})
+	require.NoError(st, err)
+
+	_, err = st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{ChallengeToken: challenge(), Code: recoveryCodes[0]})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid code")
+
+	// case and dashes of recovery codes don't matter
+	typed := strings.ToUpper(strings.ReplaceAll(recoveryCodes[1], "-", ""))
+	_, err = st.Auth.DisableTOTP(ctx, &ssov1.DisableTOTPRequest{Token: token, Code: typed})
+	require.NoError(st, err)
+
+	respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(st, err)
+	assert.False(st, respLogin.GetTotpRequired())
+	assert.NotEmpty(st, respLogin.GetToken())
+
+	// recovery codes are gone along with 2FA
+	_, err = st.Auth.DisableTOTP(ctx, &ssov1.DisableTOTPRequest{Token: token, Code: recoveryCodes[2]})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "two-factor authentication is not enabled")
+}
+
+func TestLoginVerify_TooManyWrongCodes(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	_, token := registerLogin(st, email, password)
+
+	_, _, recoveryCodes := enableTOTP(st, token)
+
+	respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(st, err)
+
+	for i := 0; i < 5; i++ {
+		_, err := st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{
+			ChallengeToken: respLogin.GetChallengeToken(),
+			Code:           "wrong",
+		})
+		require.Error(st, err)
+		require.Contains(st, err.Error(), "invalid code")
+	}
+
+	_, err = st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{
+		ChallengeToken: respLogin.GetChallengeToken(),
+		Code:           recoveryCodes[0],
+	})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid login challenge")
+}
+
+func TestTOTP_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, token := registerLogin(st, gofakeit.Email(), randomPassword())
+
+	_, err := st.Auth.ConfirmTOTP(ctx, &ssov1.ConfirmTOTPRequest{Token: token, Code: "123456"})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "two-factor authentication is not enabled")
+
+	_, err = st.Auth.DisableTOTP(ctx, &ssov1.DisableTOTPRequest{Token: token, Code: "123456"})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "two-factor authentication is not enabled")
+
+	enableTOTP(st, token)
+
+	_, err = st.Auth.EnrollTOTP(ctx, &ssov1.EnrollTOTPRequest{Token: token})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "two-factor authentication is already enabled")
+
+	_, err = st.Auth.EnrollTOTP(ctx, &ssov1.EnrollTOTPRequest{Token: "not a token"})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "token is invalid")
+
+	tests := []struct {
+		name      string
+		challenge string
+		code      string
+		expected  string
+	}{
+		{
+			name:      "empty challenge",
+			challenge: "",
+			code:      "123456",
+			expected:  "challenge_token is required",
+		},
+		{
+			name:      "empty code",
+			challenge: gofakeit.UUID(),
+			code:      "",
+			expected:  "code is required",
+		},
+		{
+			name:      "unknown challenge",
+			challenge: gofakeit.UUID(),
+			code:      "123456",
+			expected:  "invalid login challenge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.Auth.LoginVerify(ctx, &ssov1.LoginVerifyRequest{
+				ChallengeToken: tt.challenge,
+				Code:           tt.code,
+			})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const adminID int64 = 1
+
+func listUserIDs(st suite.Suite, req *ssov1.ListUsersRequest) ([]int64, string) {
+	st.Helper()
+
+	resp, err := st.Auth.ListUsers(st.Context(), req)
+	require.NoError(st, err)
+
+	ids := make([]int64, 0, len(resp.GetUsers()))
+	for _, user := range resp.GetUsers() {
+		ids = append(ids, user.GetId())
+	}
+
+	return ids, resp.GetNextPageToken()
+}
+
+func TestUsers_GetList(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+
+	prefix := "list-" + gofakeit.UUID()
+	var ids []int64
+	for i := 0; i < 3; i++ {
+		id, _ := registerLogin(st, prefix+"-"+gofakeit.Email(), randomPassword())
+		ids = append(ids, id)
+	}
+
+	user, err := st.Auth.GetUser(ctx, &ssov1.GetUserRequest{Token: adminToken, UserId: ids[0]})
+	require.NoError(st, err)
+	assert.Equal(st, ids[0], user.GetUser().GetId())
+	assert.Equal(st, []string{"buyer"}, user.GetUser().GetRoles())
+	assert.InDelta(st, time.Now().Unix(), user.GetUser().GetCreatedAt(), 5)
+	assert.GreaterOrEqual(st, user.GetUser().GetUpdatedAt(), user.GetUser().GetCreatedAt())
+	assert.Zero(st, user.GetUser().GetDisabledAt())
+
+	// newest first, split into pages
+	page, next := listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, PageSize: 2})
+	assert.Equal(st, []int64{ids[2], ids[1]}, page)
+	require.NotEmpty(st, next)
+
+	page, next = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, PageSize: 2, PageToken: next})
+	assert.Equal(st, []int64{ids[0]}, page)
+	assert.Empty(st, next)
+
+	// email prefix is case-insensitive, wildcards are matched literally
+	page, _ = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: "LIST-" + prefix[len("list-"):]})
+	assert.Len(st, page, 3)
+	page, _ = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: "list-%"})
+	assert.Empty(st, page)
+
+	_, err = st.Auth.SetAdmin(ctx, &ssov1.SetAdminRequest{Token: adminToken, UserId: ids[1], IsAdmin: true})
+	require.NoError(st, err)
+
+	page, _ = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, Role: "admin"})
+	assert.Equal(st, []int64{ids[1]}, page)
+
+	resp, err := st.Auth.ListUsers(ctx, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, Role: "admin"})
+	require.NoError(st, err)
+	assert.Equal(st, []string{"admin", "buyer"}, resp.GetUsers()[0].GetRoles())
+
+	_, err = st.Auth.SetAdmin(ctx, &ssov1.SetAdminRequest{Token: adminToken, UserId: ids[1], IsAdmin: false})
+	require.NoError(st, err)
+
+	page, _ = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, Role: "admin"})
+	assert.Empty(st, page)
+
+	// created range
+	hourAgo, inHour := time.Now().Add(-time.Hour).Unix(), time.Now().Add(time.Hour).Unix()
+
+	page, _ = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, CreatedFrom: hourAgo, CreatedTo: inHour})
+	assert.Len(st, page, 3)
+	page, _ = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, CreatedFrom: inHour})
+	assert.Empty(st, page)
+	page, _ = listUserIDs(st, &ssov1.ListUsersRequest{Token: adminToken, EmailPrefix: prefix, CreatedTo: hourAgo})
+	assert.Empty(st, page)
+}
+
+func TestUsers_DisableEnable(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+
+	email, password := gofakeit.Email(), randomPassword()
+	id, _ := registerLogin(st, email, password)
+
+	resp, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.NoError(st, err)
+
+	disabled, err := st.Auth.DisableUser(ctx, &ssov1.DisableUserRequest{Token: adminToken, UserId: id})
+	require.NoError(st, err)
+	assert.True(st, disabled.GetSucceeded())
+
+	_, err = st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.Error(st, err)
+	assert.Equal(st, codes.FailedPrecondition, status.Code(err))
+	assert.Contains(st, err.Error(), "user is disabled")
+
+	// tokens issued before are revoked
+	validated, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: resp.GetToken()})
+	require.NoError(st, err)
+	assert.False(st, validated.GetValid())
+	assert.Equal(st, "revoked", validated.GetReason())
+
+	_, err = st.Auth.Refresh(ctx, &ssov1.RefreshRequest{RefreshToken: resp.GetRefreshToken()})
+	require.Error(st, err)
+
+	user, err := st.Auth.GetUser(ctx, &ssov1.GetUserRequest{Token: adminToken, UserId: id})
+	require.NoError(st, err)
+	assert.NotZero(st, user.GetUser().GetDisabledAt())
+
+	enabled, err := st.Auth.EnableUser(ctx, &ssov1.EnableUserRequest{Token: adminToken, UserId: id})
+	require.NoError(st, err)
+	assert.True(st, enabled.GetSucceeded())
+
+	// login right after enabling is not revoked by disabling
+	validated, err = st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: login(st, email, password)})
+	require.NoError(st, err)
+	assert.True(st, validated.GetValid())
+
+	user, err = st.Auth.GetUser(ctx, &ssov1.GetUserRequest{Token: adminToken, UserId: id})
+	require.NoError(st, err)
+	assert.Zero(st, user.GetUser().GetDisabledAt())
+}
+
+func TestUsers_Delete(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+
+	email, password := gofakeit.Email(), randomPassword()
+	id, token := registerLogin(st, email, password)
+
+	deleted, err := st.Auth.DeleteUser(ctx, &ssov1.DeleteUserRequest{Token: adminToken, UserId: id})
+	require.NoError(st, err)
+	assert.True(st, deleted.GetSucceeded())
+
+	_, err = st.Auth.GetUser(ctx, &ssov1.GetUserRequest{Token: adminToken, UserId: id})
+	require.Error(st, err)
+	assert.Equal(st, codes.NotFound, status.Code(err))
+
+	validated, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: token})
+	require.NoError(st, err)
+	assert.False(st, validated.GetValid())
+
+	_, err = st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: appID})
+	require.Error(st, err)
+	assert.Equal(st, codes.InvalidArgument, status.Code(err))
+
+	// email is free again
+	registerLogin(st, email, password)
+}
+
+func TestUsers_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	adminToken := login(st, adminEmail, adminPassword)
+	buyerID, buyerToken := registerLogin(st, gofakeit.Email(), randomPassword())
+	supportID, supportToken := registerLogin(st, gofakeit.Email(), randomPassword())
+
+	_, err := st.Auth.AssignRole(ctx, &ssov1.AssignRoleRequest{Token: adminToken, UserId: supportID, Role: "support"})
+	require.NoError(st, err)
+
+	// support staff can look users up
+	_, err = st.Auth.GetUser(ctx, &ssov1.GetUserRequest{Token: supportToken, UserId: buyerID})
+	require.NoError(st, err)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected string
+	}{
+		{
+			name: "list not staff",
+			call: func() error {
+				_, err := st.Auth.ListUsers(ctx, &ssov1.ListUsersRequest{Token: buyerToken})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "get not staff",
+			call: func() error {
+				_, err := st.Auth.GetUser(ctx, &ssov1.GetUserRequest{Token: buyerToken, UserId: supportID})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "disable by support",
+			call: func() error {
+				_, err := st.Auth.DisableUser(ctx, &ssov1.DisableUserRequest{Token: supportToken, UserId: buyerID})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "delete by support",
+			call: func() error {
+				_, err := st.Auth.DeleteUser(ctx, &ssov1.DeleteUserRequest{Token: supportToken, UserId: buyerID})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "set admin by support",
+			call: func() error {
+				_, err := st.Auth.SetAdmin(ctx, &ssov1.SetAdminRequest{Token: supportToken, UserId: supportID, IsAdmin: true})
+				return err
+			},
+			expected: "permission denied",
+		},
+		{
+			name: "disable themselves",
+			call: func() error {
+				_, err := st.Auth.DisableUser(ctx, &ssov1.DisableUserRequest{Token: adminToken, UserId: adminID})
+				return err
+			},
+			expected: "themselves",
+		},
+		{
+			name: "delete themselves",
+			call: func() error {
+				_, err := st.Auth.DeleteUser(ctx, &ssov1.DeleteUserRequest{Token: adminToken, UserId: adminID})
+				return err
+			},
+			expected: "themselves",
+		},
+		{
+			name: "revoke own admin",
+			call: func() error {
+				_, err := st.Auth.SetAdmin(ctx, &ssov1.SetAdminRequest{Token: adminToken, UserId: adminID, IsAdmin: false})
+				return err
+			},
+			expected: "themselves",
+		},
+		{
+			name: "disable unknown user",
+			call: func() error {
+				_, err := st.Auth.DisableUser(ctx, &ssov1.DisableUserRequest{Token: adminToken, UserId: 1e10})
+				return err
+			},
+			expected: "user not found",
+		},
+		{
+			name: "enable unknown user",
+			call: func() error {
+				_, err := st.Auth.EnableUser(ctx, &ssov1.EnableUserRequest{Token: adminToken, UserId: 1e10})
+				return err
+			},
+			expected: "user not found",
+		},
+		{
+			name: "delete unknown user",
+			call: func() error {
+				_, err := st.Auth.DeleteUser(ctx, &ssov1.DeleteUserRequest{Token: adminToken, UserId: 1e10})
+				return err
+			},
+			expected: "user not found",
+		},
+		{
+			name: "set admin of unknown user",
+			call: func() error {
+				_, err := st.Auth.SetAdmin(ctx, &ssov1.SetAdminRequest{Token: adminToken, UserId: 1e10, IsAdmin: true})
+				return err
+			},
+			expected: "user not found",
+		},
+		{
+			name: "get empty user id",
+			call: func() error {
+				_, err := st.Auth.GetUser(ctx, &ssov1.GetUserRequest{Token: adminToken})
+				return err
+			},
+			expected: "user_id is required",
+		},
+		{
+			name: "invalid page token",
+			call: func() error {
+				_, err := st.Auth.ListUsers(ctx, &ssov1.ListUsersRequest{Token: adminToken, PageToken: "not a token"})
+				return err
+			},
+			expected: "invalid page token",
+		},
+		{
+			name: "invalid token",
+			call: func() error {
+				_, err := st.Auth.ListUsers(ctx, &ssov1.ListUsersRequest{Token: "not a token"})
+				return err
+			},
+			expected: "token is invalid",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	"github.com/Kry0z1/e-commerce/sso-microservice/tests/suite"
+	"github.com/brianvoe/gofakeit/v6"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// verifiedAppID is test app that requires verified email to log in
+const verifiedAppID int64 = 2
+
+func TestVerifyEmail_HappyPath(t *testing.T) {
+	ctx, st := suite.New(t)
+	outbox := st.Outbox()
+
+	email := gofakeit.Email()
+	password := randomPassword()
+	_, token := registerLogin(st, email, password)
+
+	verification := outbox.WaitEmailVerification(t, email, 1)
+	require.NotEmpty(st, verification.GetToken())
+	assert.Greater(st, verification.GetExpiresAt(), time.Now().Unix())
+
+	resp, err := st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: token})
+	require.NoError(st, err)
+	assert.False(st, resp.GetEmailVerified())
+
+	_, err = st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: verifiedAppID})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "email is not verified")
+
+	_, err = st.Auth.VerifyEmail(ctx, &ssov1.VerifyEmailRequest{Token: verification.GetToken()})
+	require.NoError(st, err)
+
+	respLogin, err := st.Auth.Login(ctx, &ssov1.LoginRequest{Email: email, Password: password, AppId: verifiedAppID})
+	require.NoError(st, err)
+
+	resp, err = st.Auth.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: respLogin.GetToken()})
+	require.NoError(st, err)
+	assert.True(st, resp.GetValid())
+	assert.True(st, resp.GetEmailVerified())
+	assert.Equal(st, verifiedAppID, resp.GetAppId())
+
+	_, err = st.Auth.VerifyEmail(ctx, &ssov1.VerifyEmailRequest{Token: verification.GetToken()})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid email verification token")
+}
+
+func TestResendVerification(t *testing.T) {
+	ctx, st := suite.New(t)
+	outbox := st.Outbox()
+
+	email := gofakeit.Email()
+	registerLogin(st, email, randomPassword())
+
+	first := outbox.WaitEmailVerification(t, email, 1)
+
+	_, err := st.Auth.ResendVerification(ctx, &ssov1.ResendVerificationRequest{Email: email})
+	require.NoError(st, err)
+
+	second := outbox.WaitEmailVerification(t, email, 2)
+
+	_, err = st.Auth.VerifyEmail(ctx, &ssov1.VerifyEmailRequest{Token: first.GetToken()})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "invalid email verification token")
+
+	_, err = st.Auth.VerifyEmail(ctx, &ssov1.VerifyEmailRequest{Token: second.GetToken()})
+	require.NoError(st, err)
+
+	// verified email gets nothing, but response is the same
+	_, err = st.Auth.ResendVerification(ctx, &ssov1.ResendVerificationRequest{Email: email})
+	require.NoError(st, err)
+	assert.Len(st, outbox.EmailVerifications(email), 2)
+}
+
+func TestResendVerification_UnknownEmail(t *testing.T) {
+	ctx, st := suite.New(t)
+	outbox := st.Outbox()
+
+	email := gofakeit.Email()
+
+	_, err := st.Auth.ResendVerification(ctx, &ssov1.ResendVerificationRequest{Email: email})
+	require.NoError(st, err)
+	assert.Empty(st, outbox.EmailVerifications(email))
+}
+
+func TestVerifyEmail_Fails(t *testing.T) {
+	ctx, st := suite.New(t)
+
+	_, err := st.Auth.ResendVerification(ctx, &ssov1.ResendVerificationRequest{Email: ""})
+	require.Error(st, err)
+	require.Contains(st, err.Error(), "email is required")
+
+	tests := []struct {
+		name     string
+		token    string
+		expected string
+	}{
+		{
+			name:     "empty",
+			token:    "",
+			expected: "token is required",
+		},
+		{
+			name:     "unknown",
+			token:    gofakeit.UUID(),
+			expected: "invalid email verification token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := st.Auth.VerifyEmail(ctx, &ssov1.VerifyEmailRequest{Token: tt.token})
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package ssoclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type AdminChecker interface {
+	IsAdmin(ctx context.Context, userID int64) (bool, error)
+}
+
+// CachedAdminChecker remembers answers of checker for ttl,
+// so that admin revocation in sso takes effect after at most ttl
+type CachedAdminChecker struct {
+	checker AdminChecker
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[int64]adminEntry
+}
+
+type adminEntry struct {
+	isAdmin   bool
+	checkedAt time.Time
+}
+
+func NewCachedAdminChecker(checker AdminChecker, ttl time.Duration) *CachedAdminChecker {
+	return &CachedAdminChecker{
+		checker: checker,
+		ttl:     ttl,
+		entries: make(map[int64]adminEntry),
+	}
+}
+
+func (c *CachedAdminChecker) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[userID]
+	c.mu.Unlock()
+
+	if ok && time.Since(entry.checkedAt) < c.ttl {
+		return entry.isAdmin, nil
+	}
+
+	isAdmin, err := c.checker.IsAdmin(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, e := range c.entries {
+		if now.Sub(e.checkedAt) >= c.ttl {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[userID] = adminEntry{isAdmin: isAdmin, checkedAt: now}
+
+	return isAdmin, nil
+}
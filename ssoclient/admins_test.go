@@ -0,0 +1,73 @@
+package ssoclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/ssoclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAdmins answers from admins set and counts calls
+type fakeAdmins struct {
+	admins map[int64]bool
+	err    error
+	calls  int
+}
+
+func (f *fakeAdmins) IsAdmin(_ context.Context, userID int64) (bool, error) {
+	f.calls++
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.admins[userID], nil
+}
+
+const ttl = 100 * time.Millisecond
+
+func TestCachedAdminChecker_CachesForTTL(t *testing.T) {
+	fake := &fakeAdmins{admins: map[int64]bool{1: true}}
+	admins := ssoclient.NewCachedAdminChecker(fake, ttl)
+	ctx := context.Background()
+
+	for range 3 {
+		isAdmin, err := admins.IsAdmin(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, isAdmin)
+	}
+	assert.Equal(t, 1, fake.calls)
+
+	// admin revoked in sso is still admin until ttl passes
+	fake.admins[1] = false
+
+	isAdmin, err := admins.IsAdmin(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	time.Sleep(ttl)
+
+	isAdmin, err = admins.IsAdmin(ctx, 1)
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+	assert.Equal(t, 2, fake.calls)
+}
+
+func TestCachedAdminChecker_ErrorsAreNotCached(t *testing.T) {
+	unavailable := errors.New("sso is unavailable")
+	fake := &fakeAdmins{admins: map[int64]bool{1: true}, err: unavailable}
+	admins := ssoclient.NewCachedAdminChecker(fake, ttl)
+	ctx := context.Background()
+
+	_, err := admins.IsAdmin(ctx, 1)
+	require.ErrorIs(t, err, unavailable)
+
+	fake.err = nil
+
+	isAdmin, err := admins.IsAdmin(ctx, 1)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+	assert.Equal(t, 2, fake.calls)
+}
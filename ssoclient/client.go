@@ -0,0 +1,112 @@
+package ssoclient
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	ssov1 "github.com/Kry0z1/e-commerce/protos/gen/go/sso"
+	grpclog "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	grpcretry "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/retry"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client talks to Auth service of sso
+type Client struct {
+	api ssov1.AuthClient
+	log *slog.Logger
+}
+
+func New(log *slog.Logger, addr string, timeout time.Duration, retriesCount int) (*Client, error) {
+	const op = "ssoclient.New"
+
+	retryOpts := []grpcretry.CallOption{
+		grpcretry.WithCodes(codes.Unavailable, codes.DeadlineExceeded),
+		grpcretry.WithMax(uint(retriesCount)),
+		grpcretry.WithPerRetryTimeout(timeout),
+	}
+
+	// token validation requests carry tokens, so only calls are logged
+	logOpts := []grpclog.Option{
+		grpclog.WithLogOnEvents(grpclog.StartCall, grpclog.FinishCall),
+	}
+
+	cc, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(
+			grpclog.UnaryClientInterceptor(InterceptorLogger(log), logOpts...),
+			grpcretry.UnaryClientInterceptor(retryOpts...),
+		),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &Client{
+		api: ssov1.NewAuthClient(cc),
+		log: log,
+	}, nil
+}
+
+// SigningKeys fetches public keys that sso signs tokens with
+func (c *Client) SigningKeys(ctx context.Context) ([]authtoken.PublicKey, error) {
+	const op = "ssoclient.SigningKeys"
+
+	resp, err := c.api.GetSigningKeys(ctx, &ssov1.GetSigningKeysRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	keys := make([]authtoken.PublicKey, 0, len(resp.GetKeys()))
+	for _, key := range resp.GetKeys() {
+		parsed, err := x509.ParsePKIXPublicKey(key.GetPublicKey())
+		if err != nil {
+			return nil, fmt.Errorf("%s: key %s: %w", op, key.GetKid(), err)
+		}
+
+		keys = append(keys, authtoken.PublicKey{
+			ID:        key.GetKid(),
+			Algorithm: key.GetAlgorithm(),
+			Key:       parsed,
+		})
+	}
+
+	return keys, nil
+}
+
+func (c *Client) IsAdmin(ctx context.Context, userID int64) (bool, error) {
+	const op = "ssoclient.IsAdmin"
+
+	resp, err := c.api.IsAdmin(ctx, &ssov1.IsAdminRequest{UserId: userID})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return resp.GetIsAdmin(), nil
+}
+
+// IsTokenRevoked asks sso whether token is still valid. Token that sso doesn't
+// accept anymore is reported revoked: logged out, revoked with all sessions of user,
+// or issued to user that is deleted since.
+func (c *Client) IsTokenRevoked(ctx context.Context, token string, _ *authtoken.Claims) (bool, error) {
+	const op = "ssoclient.IsTokenRevoked"
+
+	resp, err := c.api.ValidateToken(ctx, &ssov1.ValidateTokenRequest{Token: token})
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return !resp.GetValid(), nil
+}
+
+// yoinked
+func InterceptorLogger(l *slog.Logger) grpclog.Logger {
+	return grpclog.LoggerFunc(func(ctx context.Context, lvl grpclog.Level, msg string, fields ...any) {
+		l.Log(ctx, slog.Level(lvl), msg, fields...)
+	})
+}
@@ -0,0 +1,61 @@
+package ssoclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+)
+
+// CachedRevocationChecker remembers answers of checker per token for ttl,
+// so that revoked token is rejected after at most ttl since revocation
+// and sso is asked about each token at most once per ttl
+type CachedRevocationChecker struct {
+	checker authtoken.RevocationChecker
+	ttl     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]revocationEntry
+}
+
+type revocationEntry struct {
+	revoked   bool
+	checkedAt time.Time
+}
+
+func NewCachedRevocationChecker(checker authtoken.RevocationChecker, ttl time.Duration) *CachedRevocationChecker {
+	return &CachedRevocationChecker{
+		checker: checker,
+		ttl:     ttl,
+		entries: make(map[string]revocationEntry),
+	}
+}
+
+func (c *CachedRevocationChecker) IsTokenRevoked(ctx context.Context, token string, claims *authtoken.Claims) (bool, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[claims.ID]
+	c.mu.Unlock()
+
+	if ok && time.Since(entry.checkedAt) < c.ttl {
+		return entry.revoked, nil
+	}
+
+	revoked, err := c.checker.IsTokenRevoked(ctx, token, claims)
+	if err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for id, e := range c.entries {
+		if now.Sub(e.checkedAt) >= c.ttl {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[claims.ID] = revocationEntry{revoked: revoked, checkedAt: now}
+
+	return revoked, nil
+}
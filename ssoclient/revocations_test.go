@@ -0,0 +1,84 @@
+package ssoclient_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/authtoken"
+	"github.com/Kry0z1/e-commerce/ssoclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRevocations answers from set of revoked token ids and counts calls
+type fakeRevocations struct {
+	revoked map[string]bool
+	err     error
+	calls   int
+}
+
+func (f *fakeRevocations) IsTokenRevoked(_ context.Context, _ string, claims *authtoken.Claims) (bool, error) {
+	f.calls++
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.revoked[claims.ID], nil
+}
+
+func claims(jti string) *authtoken.Claims {
+	c := &authtoken.Claims{}
+	c.ID = jti
+	return c
+}
+
+func TestCachedRevocationChecker_CachesForTTL(t *testing.T) {
+	fake := &fakeRevocations{revoked: map[string]bool{}}
+	revocations := ssoclient.NewCachedRevocationChecker(fake, ttl)
+	ctx := context.Background()
+
+	for range 3 {
+		revoked, err := revocations.IsTokenRevoked(ctx, "token", claims("a"))
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	}
+	assert.Equal(t, 1, fake.calls)
+
+	// other token is asked about separately
+	fake.revoked["b"] = true
+	revoked, err := revocations.IsTokenRevoked(ctx, "token", claims("b"))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	assert.Equal(t, 2, fake.calls)
+
+	// token revoked in sso is accepted until ttl passes
+	fake.revoked["a"] = true
+
+	revoked, err = revocations.IsTokenRevoked(ctx, "token", claims("a"))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	time.Sleep(ttl)
+
+	revoked, err = revocations.IsTokenRevoked(ctx, "token", claims("a"))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestCachedRevocationChecker_ErrorsAreNotCached(t *testing.T) {
+	unavailable := errors.New("sso is unavailable")
+	fake := &fakeRevocations{err: unavailable}
+	revocations := ssoclient.NewCachedRevocationChecker(fake, ttl)
+	ctx := context.Background()
+
+	_, err := revocations.IsTokenRevoked(ctx, "token", claims("a"))
+	require.ErrorIs(t, err, unavailable)
+
+	fake.err = nil
+
+	revoked, err := revocations.IsTokenRevoked(ctx, "token", claims("a"))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+	assert.Equal(t, 2, fake.calls)
+}
@@ -0,0 +1,65 @@
+package sweeper
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Kry0z1/e-commerce/logger/ll"
+)
+
+// App periodically calls sweep until stopped
+type App struct {
+	log      *slog.Logger
+	sweep    func(ctx context.Context) error
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+func New(log *slog.Logger, sweep func(ctx context.Context) error, interval time.Duration) *App {
+	return &App{
+		log:      log,
+		sweep:    sweep,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run blocks until Stop is called, errors of sweep are only logged
+func (a *App) Run() {
+	const op = "sweeper.Run"
+
+	log := a.log.With(slog.String("op", op))
+
+	log.Info("sweeper started", slog.Duration("interval", a.interval))
+
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), a.interval)
+			if err := a.sweep(ctx); err != nil {
+				log.Warn("sweep failed", ll.Err(err))
+			}
+			cancel()
+		}
+	}
+}
+
+// Stop stops sweeper and waits for current sweep to finish
+func (a *App) Stop() {
+	const op = "sweeper.Stop"
+
+	a.log.With(slog.String("op", op)).Info("stopping sweeper")
+
+	close(a.stop)
+	<-a.done
+}